@@ -0,0 +1,69 @@
+package apihandler
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AccessLogFunc 访问日志记录函数，在响应输出后调用
+type AccessLogFunc func(c *gin.Context, info RequestInfo)
+
+// WithAccessLog 设置访问日志记录函数，记录请求方法、路径、路由模板、HTTP 状态码、业务代码、耗时和响应大小
+//
+// 与 gin 的访问日志中间件不同，访问日志能够记录业务代码。
+func WithAccessLog(fn AccessLogFunc) Option {
+	return WithObserver(accessLogObserver(fn))
+}
+
+// accessLogObserver 将访问日志记录函数适配为请求观察者
+type accessLogObserver AccessLogFunc
+
+// ObserveStart 实现 RequestObserver 接口
+func (accessLogObserver) ObserveStart(c *gin.Context) {}
+
+// ObserveEnd 实现 RequestObserver 接口，未被日志采样的成功请求不记录
+func (o accessLogObserver) ObserveEnd(c *gin.Context, info RequestInfo) {
+	if info.Err == nil && c.GetBool(logSkippedContextKey) {
+		return
+	}
+	o(c, info)
+}
+
+// SlogAccessLog 使用 slog 记录访问日志，5xx 响应为 Error 级别，4xx 为 Warn 级别，其余为 Info 级别，logger 为 nil 时使用 slog.Default()
+func SlogAccessLog(logger *slog.Logger) AccessLogFunc {
+	return func(c *gin.Context, info RequestInfo) {
+		l := logger
+		if l == nil {
+			l = slog.Default()
+		}
+		level := slog.LevelInfo
+		switch {
+		case info.HTTPCode >= 500:
+			level = slog.LevelError
+		case info.HTTPCode >= 400:
+			level = slog.LevelWarn
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", info.Method),
+			slog.String("path", info.Path),
+			slog.String("route", info.Route),
+			slog.Int("status", info.HTTPCode),
+			slog.Duration("latency", info.Latency),
+			slog.Int("size", info.Size),
+		}
+		if info.Code != nil {
+			attrs = append(attrs, slog.String("code", fmt.Sprint(info.Code)))
+		}
+		if info.Err != nil {
+			attrs = append(attrs, slog.String("error", info.Err.Error()))
+		}
+		if id := RequestIDFromContext(c.Request.Context()); id != "" {
+			attrs = append(attrs, slog.String("request_id", id))
+		}
+		l.LogAttrs(context.WithoutCancel(c.Request.Context()), level, "access", attrs...)
+	}
+}
@@ -0,0 +1,64 @@
+package apihandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试访问日志记录业务代码
+func TestAccessLog(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		if req.ID == 0 {
+			return nil, ErrNotFound(40400, "用户不存在")
+		}
+		return &testResponse{ID: req.ID}, nil
+	}
+	var infos []RequestInfo
+	r.GET("/users/:id", Handler(handleFunc, WithAccessLog(func(c *gin.Context, info RequestInfo) {
+		infos = append(infos, info)
+	})))
+
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/0", nil))
+	if len(infos) != 1 {
+		t.Fatalf("期望记录 1 条访问日志, 实际得到 %d", len(infos))
+	}
+	if info := infos[0]; info.Route != "/users/:id" || info.HTTPCode != http.StatusNotFound || info.Code != 40400 {
+		t.Errorf("期望访问日志包含路由、状态码和业务错误码, 实际得到 %+v", info)
+	}
+}
+
+// 测试使用 slog 记录访问日志
+func TestSlogAccessLog(t *testing.T) {
+	r := gin.New()
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return nil, ErrInternalServer(50000, "服务器内部错误")
+	}
+	r.GET("/users/:id", Handler(handleFunc, WithRequestID(nil), WithAccessLog(SlogAccessLog(logger))))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users/1", nil))
+
+	var entry map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatalf("解析日志失败: %v, 日志 %s", err, buf.String())
+	}
+	if entry["level"] != "ERROR" || entry["msg"] != "access" {
+		t.Errorf("期望 5xx 响应记录为 ERROR 级别的 access 日志, 实际得到 %v", entry)
+	}
+	if entry["route"] != "/users/:id" || entry["path"] != "/users/1" || entry["status"] != float64(500) || entry["code"] != "50000" {
+		t.Errorf("期望日志包含路由、路径、状态码和业务错误码, 实际得到 %v", entry)
+	}
+	if entry["request_id"] == nil || entry["size"] == float64(0) {
+		t.Errorf("期望日志包含请求 ID 和响应大小, 实际得到 %v", entry)
+	}
+}
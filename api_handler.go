@@ -3,11 +3,17 @@ package apihandler
 import (
 	"context"
 	"errors"
+	"maps"
 	"net/http"
 	"reflect"
+	"runtime/debug"
+	"slices"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	ut "github.com/go-playground/universal-translator"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -19,6 +25,16 @@ const (
 // RequestLogger 请求日志记录函数类型
 type RequestLogger func(r *http.Request, req any)
 
+// SuccessCodeFunc 成功业务代码生成函数，req 和 resp 为请求对象指针和业务处理函数的返回值
+type SuccessCodeFunc func(ctx context.Context, req any, resp any) any
+
+// PanicHandler 业务处理函数 panic 时的回调函数，recovered 为 recover() 的返回值，stack 为调用栈
+type PanicHandler func(c *gin.Context, recovered any, stack []byte)
+
+// ErrorHandler 输出错误响应前的回调函数，req 为请求对象指针（参数绑定失败时可能只绑定了部分字段），
+// err 为转换后的业务错误，可通过 errors.Is/errors.As 获取原始错误
+type ErrorHandler func(c *gin.Context, req any, err error)
+
 // HandleFunc 通用处理函数类型
 type HandleFunc[T any, R any] func(ctx context.Context, req *T) (*R, error)
 
@@ -35,35 +51,180 @@ type BizError interface {
 
 // ErrorResponse 错误响应结构
 type ErrorResponse struct {
-	Code    any    `json:"code"`
-	Message string `json:"message"`
-	Errors  []any  `json:"errors,omitempty"`
+	Code           any      `json:"code"`
+	Message        string   `json:"message"`
+	Errors         []any    `json:"errors,omitempty"`
+	RequestID      string   `json:"-" codec:"request_id,omitempty"` // 请求 ID，JSON 中的字段名由 RequestIDField 指定
+	RequestIDField string   `json:"-" codec:"-"`                    // 请求 ID 的字段名，为空时使用 request_id，"-" 表示不输出
+	Stack          string   `json:"stack,omitempty"`                // 调用栈，仅在调试模式下输出
+	CauseChain     []string `json:"cause_chain,omitempty"`          // 错误链，仅在调试模式下输出
 }
 
 // SuccessResponse 成功响应结构
 type SuccessResponse[R any] struct {
-	Code any `json:"code"`
-	Data *R  `json:"data"`
+	Code      any    `json:"code"`
+	Data      *R     `json:"data"`
+	Meta      any    `json:"meta,omitempty"`
+	Links     Links  `json:"links,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
 }
 
 // HandlerConfig 处理器配置
 type HandlerConfig struct {
-	SuccessCode     any
-	SuccessHTTPCode int
-	BindErrorCode   any
-	RequestLogger   RequestLogger // 请求日志记录函数
-	Translator      Translator    // 翻译器
-	LocaleFunc      LocaleFunc    // 语言环境函数
+	SuccessCode            any
+	SuccessHTTPCode        int
+	BindErrorCode          any
+	RequestLogger          RequestLogger      // 请求日志记录函数
+	Translator             Translator         // 翻译器
+	LocaleFunc             LocaleFunc         // 语言环境函数
+	LocaleKey              any                // 上游中间件保存语言环境的 gin.Context 或请求 context 的键，优先于 LocaleFunc
+	Envelope               Envelope           // 响应封装
+	NoContentOnNil         bool               // 业务返回 nil 时响应 204 No Content
+	SSEHeartbeat           time.Duration      // SSE 心跳间隔，小于等于 0 表示不发送心跳
+	MaxUploadSize          int64              // 上传处理器中单个文件的最大字节数，小于等于 0 表示不限制
+	ResponseFormat         Format             // 固定响应格式，设置后不进行内容协商
+	Formats                []Format           // 参与内容协商的格式，第一个为默认格式
+	JSONPCallback          string             // JSONP 回调函数名的 query 参数，为空表示不支持 JSONP
+	ETag                   bool               // 为 GET/HEAD 成功响应生成 ETag 并处理 If-None-Match
+	FieldsParam            string             // 字段选择的 query 参数，为空表示不支持按需返回字段
+	NilData                NilDataMode        // 业务返回 nil 时 data 字段的输出方式（默认响应封装）
+	EmptyContainers        bool               // 将 data 中为 nil 的切片和 map 输出为 [] 和 {}
+	JSONCodec              JSONCodec          // JSON 编解码器，用于请求解码和响应编码
+	IndentJSON             bool               // 输出缩进格式的 JSON，便于调试
+	ResponseCache          CacheStore         // 成功响应缓存存储，为空表示不缓存
+	CacheTTL               time.Duration      // 响应缓存有效期
+	CacheKeyFunc           CacheKeyFunc       // 响应缓存键生成函数，为空时使用请求方法、URI、Accept 头和凭据的摘要
+	CacheStats             *CacheStats        // 响应缓存命中统计
+	Compression            []Compression      // 响应压缩算法，按优先级排列，为空表示不压缩
+	CompressionMinSize     int                // 启用压缩的最小响应体字节数
+	FlattenData            bool               // 将 data 的字段合并到与 code 同级的顶层对象（默认响应封装）
+	SuccessCodeFunc        SuccessCodeFunc    // 按请求和响应动态生成成功业务代码，返回 nil 时使用 SuccessCode
+	ErrorMappers           []ErrorMapper      // 错误映射函数，先于全局注册的映射函数执行
+	OnPanic                PanicHandler       // 业务处理函数 panic 时的回调函数
+	ProblemDetails         bool               // 使用 RFC 7807 Problem Details 格式输出错误响应
+	OnError                ErrorHandler       // 输出错误响应前的回调函数
+	MaskInternalErrors     bool               // 隐藏非业务错误的原始消息，替换为带错误编号的通用消息
+	RequestIDField         string             // 错误响应中请求 ID 的字段名，"-" 表示不输出
+	RequestIDFunc          RequestIDFunc      // 获取请求 ID 的函数，为空时使用 X-Request-Id 或 traceparent 请求头
+	SentinelErrors         []SentinelError    // 哨兵错误映射，为空时不开启
+	CodeRanges             []CodeRange        // 业务错误码区间到 HTTP 状态码的映射
+	ErrorRecorder          ErrorRecorder      // 错误响应计数器
+	Debug                  bool               // 调试模式，非业务错误的错误响应中包含调用栈和错误链
+	ErrorRenderer          ErrorRenderer      // 错误响应体构造函数，优先于 ProblemDetails 和 Envelope
+	BeforeBind             []BindHook         // 参数绑定前的钩子函数
+	AfterBind              []BindHook         // 参数绑定成功后的钩子函数
+	BeforeRespond          []RespondHook      // 输出响应前的钩子函数
+	AfterRespond           []RespondHook      // 输出响应后的钩子函数
+	Interceptors           []any              // 业务处理函数的拦截器（Interceptor[T, R]），通过 WithInterceptors 添加
+	Auditors               []any              // 审计配置，通过 WithAudit 添加，请求和响应类型与处理器一致
+	Timeout                time.Duration      // 业务处理函数的超时时间，小于等于 0 表示不限制
+	OnLateResult           LateResultHandler  // 处理超时后才返回的业务处理结果的回调函数
+	RateLimiter            Limiter            // 限流器，为空表示不限流
+	RateKeyFunc            RateKeyFunc        // 生成限流键的函数，为空时按客户端 IP 限流
+	IdempotencyStore       IdempotencyStore   // 幂等键存储，为空表示不支持幂等键
+	IdempotencyTTL         time.Duration      // 幂等请求响应的保存时间
+	RequestIDGenerator     RequestIDGenerator // 生成请求 ID 的函数，设置后每个请求都有请求 ID 并输出在成功响应中
+	ClaimsExtractor        ClaimsExtractor    // 认证声明的获取函数，用于填充带有 claim tag 的字段
+	RequiredScopes         []string           // 调用处理器所需的权限范围
+	ScopesFunc             ScopesFunc         // 获取请求已授权的权限范围的函数，为空时读取 scope/scp 声明
+	Authorizers            []any              // 依赖请求参数的授权函数，元素类型为 func(context.Context, *T) error
+	Meta                   Meta               // 处理器元数据
+	Registry               *Registry          // 记录路由的注册表，为 nil 时不记录
+	DeclaredErrors         []BizError         // 处理器可能返回的业务错误，用于注册表和文档
+	ValidateOnlyHeader     string             // 仅验证请求头，为空时不启用仅验证模式
+	CircuitBreaker         CircuitBreaker     // 熔断器
+	Retry                  *RetryPolicy       // 业务处理函数的重试策略
+	Singleflight           bool               // 是否合并相同的并发 GET 和 HEAD 请求
+	SingleflightKeyFunc    CacheKeyFunc       // 合并键生成函数，为空时使用请求方法、URI 和 Authorization 头
+	Observers              []RequestObserver  // 请求观察者
+	PanicAlert             PanicAlertFunc     // 业务处理函数 panic 时的告警函数
+	LogSampler             LogSampler         // 请求日志和访问日志的采样函数，为空时记录所有请求
+	Deprecation            *Deprecation       // 路由的废弃信息，为 nil 时未废弃
+	APIVersion             int                // 路由的 API 版本，由 Versioned 分组设置，0 表示未版本化
+	Mock                   bool               // 是否启用模拟模式，返回根据响应类型生成的模拟数据
+	ValidationScenario     string             // 验证场景，不为空时额外按 binding_<scenario> tag 验证请求
+	ValidationTranslations bool               // 是否使用 universal-translator 翻译验证消息
+	ValidationMessages     map[string]string  // 处理器的验证消息模板，键为字段路径或验证规则名称
+	ValidationFuncs        []any              // 依赖外部数据的验证函数，元素类型为 func(context.Context, *T) []FieldError
 }
 
 // DefaultConfig 默认配置
 var DefaultConfig = &HandlerConfig{
-	SuccessCode:     0,
-	SuccessHTTPCode: http.StatusOK,
-	BindErrorCode:   http.StatusBadRequest,
-	RequestLogger:   nil,        // 默认不记录
-	Translator:      nil,        // 默认使用中文
-	LocaleFunc:      nil,        // 默认使用 Accept-Language
+	SuccessCode:            0,
+	SuccessHTTPCode:        http.StatusOK,
+	BindErrorCode:          http.StatusBadRequest,
+	RequestLogger:          nil, // 默认不记录
+	Translator:             nil, // 默认使用中文
+	LocaleFunc:             nil, // 默认使用 Accept-Language
+	LocaleKey:              nil, // 默认不从 context 读取
+	Envelope:               nil, // 默认使用 {code, data} 结构
+	NoContentOnNil:         false,
+	SSEHeartbeat:           15 * time.Second,
+	MaxUploadSize:          0,   // 默认不限制
+	ResponseFormat:         "",  // 默认根据 Accept 头协商
+	Formats:                nil, // 默认只使用 JSON，XML、MessagePack 需要通过 WithFormats 开启
+	JSONPCallback:          "",  // 默认不支持 JSONP
+	ETag:                   false,
+	FieldsParam:            "", // 默认返回全部字段
+	NilData:                NilDataNull,
+	EmptyContainers:        false,
+	JSONCodec:              nil, // 默认使用 encoding/json
+	IndentJSON:             false,
+	ResponseCache:          nil, // 默认不缓存
+	CacheTTL:               0,
+	CacheKeyFunc:           nil,
+	CacheStats:             nil,
+	Compression:            nil, // 默认不压缩
+	CompressionMinSize:     0,
+	FlattenData:            false,
+	SuccessCodeFunc:        nil, // 默认使用 SuccessCode
+	ErrorMappers:           nil,
+	OnPanic:                nil,
+	ProblemDetails:         false,
+	OnError:                nil,
+	MaskInternalErrors:     false,
+	RequestIDField:         DefaultRequestIDField,
+	RequestIDFunc:          nil,
+	SentinelErrors:         nil,
+	CodeRanges:             nil,
+	ErrorRecorder:          nil,
+	Debug:                  false,
+	ErrorRenderer:          nil,
+	BeforeBind:             nil,
+	AfterBind:              nil,
+	BeforeRespond:          nil,
+	AfterRespond:           nil,
+	Interceptors:           nil,
+	Auditors:               nil,
+	Timeout:                0,
+	OnLateResult:           nil,
+	RateLimiter:            nil,
+	RateKeyFunc:            nil,
+	IdempotencyStore:       nil,
+	IdempotencyTTL:         0,
+	RequestIDGenerator:     nil,
+	ClaimsExtractor:        nil,
+	RequiredScopes:         nil,
+	ScopesFunc:             nil,
+	Authorizers:            nil,
+	Meta:                   Meta{},
+	Registry:               DefaultRegistry,
+	DeclaredErrors:         nil,
+	ValidateOnlyHeader:     "",
+	CircuitBreaker:         nil,
+	Retry:                  nil,
+	Singleflight:           false,
+	SingleflightKeyFunc:    nil,
+	Observers:              nil,
+	PanicAlert:             nil,
+	LogSampler:             nil,
+	Deprecation:            nil,
+	APIVersion:             0,
+	Mock:                   false,
+	ValidationScenario:     "",
+	ValidationTranslations: false,
+	ValidationMessages:     nil, // 默认使用登记或翻译的验证消息
+	ValidationFuncs:        nil,
 }
 
 // Option 处理器选项函数
@@ -76,6 +237,13 @@ func WithSuccessCode(code any) Option {
 	}
 }
 
+// WithSuccessCodeFunc 设置成功业务代码生成函数，同一接口可按结果返回不同的成功代码（如新建和已存在）
+func WithSuccessCodeFunc(fn SuccessCodeFunc) Option {
+	return func(c *HandlerConfig) {
+		c.SuccessCodeFunc = fn
+	}
+}
+
 // WithSuccessHTTPCode 设置成功响应的 HTTP 状态码
 func WithSuccessHTTPCode(code int) Option {
 	return func(c *HandlerConfig) {
@@ -111,101 +279,574 @@ func WithLocaleFunc(localeFunc LocaleFunc) Option {
 	}
 }
 
-// Handler 创建 Gin 处理器
-func Handler[T any, R any](handleFunc HandleFunc[T, R], opts ...Option) gin.HandlerFunc {
-	config := &HandlerConfig{
-		SuccessCode:     DefaultConfig.SuccessCode,
-		SuccessHTTPCode: DefaultConfig.SuccessHTTPCode,
-		BindErrorCode:   DefaultConfig.BindErrorCode,
-		RequestLogger:   DefaultConfig.RequestLogger,
-		Translator:      DefaultConfig.Translator,
-		LocaleFunc:      DefaultConfig.LocaleFunc,
+// WithLocaleKey 从上游中间件（如认证中间件按用户设置）保存的键读取语言环境，优先于 LocaleFunc
+//
+// 依次读取 gin.Context 的键（c.Set）和请求 context 的值（context.WithValue），值必须是非空字符串，
+// 都没有时使用 LocaleFunc：
+//
+//	r.Use(func(c *gin.Context) {
+//		c.Set("locale", currentUser(c).Language)
+//	})
+//	handler.SetDefaults(handler.WithLocaleKey("locale"))
+func WithLocaleKey(key any) Option {
+	return func(c *HandlerConfig) {
+		c.LocaleKey = key
+	}
+}
+
+// WithEnvelope 设置响应封装
+func WithEnvelope(envelope Envelope) Option {
+	return func(c *HandlerConfig) {
+		c.Envelope = envelope
+	}
+}
+
+// WithNoContentOnNil 业务返回 nil 时响应 204 No Content，不输出响应体
+func WithNoContentOnNil() Option {
+	return func(c *HandlerConfig) {
+		c.NoContentOnNil = true
+	}
+}
+
+// WithSSEHeartbeat 设置 SSE 心跳间隔
+func WithSSEHeartbeat(interval time.Duration) Option {
+	return func(c *HandlerConfig) {
+		c.SSEHeartbeat = interval
+	}
+}
+
+// WithResponseFormat 设置固定的响应格式，如 FormatXML，设置后不进行内容协商
+func WithResponseFormat(format Format) Option {
+	return func(c *HandlerConfig) {
+		c.ResponseFormat = format
+	}
+}
+
+// WithFormats 设置参与内容协商的格式，第一个为 Accept 头无法匹配时使用的默认格式
+func WithFormats(defaultFormat Format, others ...Format) Option {
+	return func(c *HandlerConfig) {
+		c.Formats = append([]Format{defaultFormat}, others...)
+	}
+}
+
+// WithJSONP 支持 JSONP，请求携带指定的 query 参数（如 callback）时以 JSONP 格式输出 JSON 响应
+func WithJSONP(callbackParam string) Option {
+	return func(c *HandlerConfig) {
+		c.JSONPCallback = callbackParam
+	}
+}
+
+// WithETag 为 GET/HEAD 成功响应生成 ETag，If-None-Match 匹配时返回 304
+func WithETag() Option {
+	return func(c *HandlerConfig) {
+		c.ETag = true
+	}
+}
+
+// WithSparseFields 支持客户端通过 query 参数（如 fields=id,user.name）选择返回的字段
+func WithSparseFields(param string) Option {
+	return func(c *HandlerConfig) {
+		c.FieldsParam = param
+	}
+}
+
+// WithNilData 设置业务返回 nil 时 data 字段的输出方式
+func WithNilData(mode NilDataMode) Option {
+	return func(c *HandlerConfig) {
+		c.NilData = mode
+	}
+}
+
+// WithEmptyContainers 将 data 中为 nil 的切片和 map 输出为 [] 和 {}，而不是 null
+func WithEmptyContainers() Option {
+	return func(c *HandlerConfig) {
+		c.EmptyContainers = true
+	}
+}
+
+// WithJSONCodec 设置 JSON 编解码器，如基于 sonic、go-json 的实现
+func WithJSONCodec(codec JSONCodec) Option {
+	return func(c *HandlerConfig) {
+		c.JSONCodec = codec
+	}
+}
+
+// WithIndentedJSON 输出缩进格式的 JSON，便于开发环境和 curl 调试
+func WithIndentedJSON() Option {
+	return func(c *HandlerConfig) {
+		c.IndentJSON = true
+	}
+}
+
+// WithResponseCache 为 GET/HEAD 请求缓存成功响应及处理器设置的响应头，keyFunc 为空时使用请求方法、URI、Accept 头和凭据（Authorization、Cookie 头）的摘要作为缓存键
+//
+// 开启 WithRequestID 时成功响应中带有每个请求不同的请求 ID，不使用响应缓存。
+func WithResponseCache(store CacheStore, ttl time.Duration, keyFunc CacheKeyFunc) Option {
+	return func(c *HandlerConfig) {
+		c.ResponseCache = store
+		c.CacheTTL = ttl
+		c.CacheKeyFunc = keyFunc
+	}
+}
+
+// WithCacheStats 设置响应缓存命中统计，多个处理器可共享同一个统计对象
+func WithCacheStats(stats *CacheStats) Option {
+	return func(c *HandlerConfig) {
+		c.CacheStats = stats
+	}
+}
+
+// WithCompression 在客户端支持时压缩不小于 minSize 字节的响应体，algorithms 按优先级排列，为空时使用 gzip
+func WithCompression(minSize int, algorithms ...Compression) Option {
+	return func(c *HandlerConfig) {
+		if len(algorithms) == 0 {
+			algorithms = []Compression{CompressionGzip}
+		}
+		c.Compression = algorithms
+		c.CompressionMinSize = minSize
 	}
-	for _, opt := range opts {
-		opt(config)
+}
+
+// WithFlattenedData 将 data 的字段合并到与 code 同级的顶层对象，如 {"code":0,"user_id":1,"name":"x"}
+func WithFlattenedData() Option {
+	return func(c *HandlerConfig) {
+		c.FlattenData = true
 	}
+}
+
+// WithErrorMapper 添加错误映射函数，先于全局注册的映射函数执行
+func WithErrorMapper(mappers ...ErrorMapper) Option {
+	return func(c *HandlerConfig) {
+		c.ErrorMappers = append(c.ErrorMappers[:len(c.ErrorMappers):len(c.ErrorMappers)], mappers...)
+	}
+}
+
+// WithOnPanic 设置业务处理函数 panic 时的回调函数，用于记录调用栈和告警
+func WithOnPanic(fn PanicHandler) Option {
+	return func(c *HandlerConfig) {
+		c.OnPanic = fn
+	}
+}
+
+// WithProblemDetails 使用 RFC 7807 Problem Details（application/problem+json）格式输出错误响应
+func WithProblemDetails() Option {
+	return func(c *HandlerConfig) {
+		c.ProblemDetails = true
+	}
+}
+
+// WithMaskedInternalErrors 隐藏非业务错误的原始消息，替换为带错误编号的通用消息，适用于生产环境
+//
+// 原始错误仍可在 OnError 中通过 errors.Unwrap 获取，错误编号可通过 ErrorReference 获取。
+func WithMaskedInternalErrors() Option {
+	return func(c *HandlerConfig) {
+		c.MaskInternalErrors = true
+	}
+}
+
+// WithOnError 设置输出错误响应前的回调函数，用于日志、监控和告警
+func WithOnError(fn ErrorHandler) Option {
+	return func(c *HandlerConfig) {
+		c.OnError = fn
+	}
+}
+
+// WithRequestIDField 设置错误响应中请求 ID 的字段名，如 trace_id，"-" 表示不输出
+func WithRequestIDField(field string) Option {
+	return func(c *HandlerConfig) {
+		c.RequestIDField = field
+	}
+}
+
+// WithRequestIDFunc 设置获取请求 ID 的函数，替代默认的 X-Request-Id 和 traceparent 请求头
+func WithRequestIDFunc(fn RequestIDFunc) Option {
+	return func(c *HandlerConfig) {
+		c.RequestIDFunc = fn
+	}
+}
+
+// Clone 复制配置，切片类型的字段也会被复制，修改副本不会影响原配置
+func (c *HandlerConfig) Clone() *HandlerConfig {
+	cp := *c
+	cp.Formats = slices.Clone(c.Formats)
+	cp.Compression = slices.Clone(c.Compression)
+	cp.ErrorMappers = slices.Clone(c.ErrorMappers)
+	cp.SentinelErrors = slices.Clone(c.SentinelErrors)
+	cp.CodeRanges = slices.Clone(c.CodeRanges)
+	cp.BeforeBind = slices.Clone(c.BeforeBind)
+	cp.AfterBind = slices.Clone(c.AfterBind)
+	cp.BeforeRespond = slices.Clone(c.BeforeRespond)
+	cp.AfterRespond = slices.Clone(c.AfterRespond)
+	cp.Interceptors = slices.Clone(c.Interceptors)
+	cp.Auditors = slices.Clone(c.Auditors)
+	cp.RequiredScopes = slices.Clone(c.RequiredScopes)
+	cp.Authorizers = slices.Clone(c.Authorizers)
+	cp.Meta.Tags = slices.Clone(c.Meta.Tags)
+	cp.DeclaredErrors = slices.Clone(c.DeclaredErrors)
+	cp.Observers = slices.Clone(c.Observers)
+	cp.ValidationMessages = maps.Clone(c.ValidationMessages)
+	cp.ValidationFuncs = slices.Clone(c.ValidationFuncs)
+	return &cp
+}
+
+// envelope 返回生效的响应封装
+func (c *HandlerConfig) envelope() Envelope {
+	if c.Envelope != nil {
+		return c.Envelope
+	}
+	return DefaultEnvelope{
+		NilData:        c.NilData,
+		Flatten:        c.FlattenData,
+		RequestIDField: c.RequestIDField,
+		RequestIDFunc:  c.RequestIDFunc,
+		Debug:          c.Debug,
+		SuccessID:      c.RequestIDGenerator != nil,
+	}
+}
+
+// successCode 返回成功响应的业务代码
+func (c *HandlerConfig) successCode(ctx context.Context, req any, resp any) any {
+	if c.SuccessCodeFunc != nil {
+		if code := c.SuccessCodeFunc(ctx, req, resp); code != nil {
+			return code
+		}
+	}
+	return c.SuccessCode
+}
+
+// Handler 创建 Gin 处理器
+func Handler[T any, R any](handleFunc HandleFunc[T, R], opts ...Option) gin.HandlerFunc {
+	config := NewConfig(opts...)
+	return HandlerWithConfig(handleFunc, config)
+}
+
+// RawHandler 创建不带响应封装的 Gin 处理器，成功时直接返回 R 的 JSON 编码
+//
+// 参数绑定、验证和业务错误的处理方式与 Handler 相同。
+func RawHandler[T any, R any](handleFunc HandleFunc[T, R], opts ...Option) gin.HandlerFunc {
+	config := NewConfig(opts...)
+	config.Envelope = rawEnvelope{Envelope: config.envelope()}
 	return HandlerWithConfig(handleFunc, config)
 }
 
 // extractValidationErrors 从验证错误中提取详细信息
-func extractValidationErrors(err error, translator Translator) []any {
+//
+// trans 不为 nil 时使用 universal-translator 翻译验证规则的消息；字段名由 names 转换为客户端发送的完整路径。
+func extractValidationErrors(err error, translator Translator, trans ut.Translator, names fieldNamer) []any {
 	var details []any
-	
+
 	// 检查是否为验证错误
 	if validationErrors, ok := err.(validator.ValidationErrors); ok {
 		for _, e := range validationErrors {
-			var message string
-			// 对于有参数的验证标签，添加参数信息
-			if e.Param() != "" {
-				message = translator.Translate(MsgFieldValidationFailedWithParam, e.Tag(), e.Param())
-			} else {
-				message = translator.Translate(MsgFieldValidationFailed, e.Tag())
-			}
-			details = append(details, map[string]string{
-				"field":   e.Field(),
-				"message": message,
+			field := names.path(e.StructNamespace(), e.Field())
+			message := validationMessage(e, field, translator, trans)
+			details = append(details, FieldError{
+				Field:   field,
+				Rule:    e.Tag(),
+				Param:   e.Param(),
+				Message: message,
+				Value:   fieldValue(e.Value()),
 			})
 		}
 	}
-	
+
 	return details
 }
 
 // HandlerWithConfig 使用指定配置创建 Gin 处理器
 func HandlerWithConfig[T any, R any](handleFunc HandleFunc[T, R], config *HandlerConfig) gin.HandlerFunc {
+	if config.Mock {
+		handleFunc = mockHandleFunc[T, R]()
+	}
+	if len(config.Interceptors) > 0 {
+		handleFunc = intercept(handleFunc, config.Interceptors)
+	}
+	// 在创建处理器时判断请求类型是否有请求体字段
+	isBodyless(reflect.TypeFor[*T]())
+	checks := authorizers[T](config.Authorizers)
+	validations := validationFuncs[T](config.ValidationFuncs)
+	audits := auditHooks[T, R](config.Auditors)
+	flight := newSingleflightGroup[R](config)
+	deprecation := deprecationHeaders(config.Deprecation)
+
 	return func(c *gin.Context) {
-		// 创建请求对象
-		req := new(T)
+		if len(config.Observers) > 0 {
+			defer observeStart(c, config)()
+		}
+		setRequestID(c, config)
+		if deprecation != nil {
+			setDeprecationHeaders(c, deprecation)
+		}
+
+		// 获取翻译器，并将语言环境和翻译器保存到请求 context 中供业务处理函数使用
+		translator := setRequestLocale(c, config)
 
-		// 获取翻译器
-		translator := config.Translator
-		if translator == nil {
-			// 如果未设置翻译器，根据请求获取语言环境
-			locale := "zh"
-			if config.LocaleFunc != nil {
-				locale = config.LocaleFunc(c.Request)
-			} else if DefaultLocaleFunc != nil {
-				locale = DefaultLocaleFunc(c.Request)
+		// 创建请求对象，超过限流时直接返回错误
+		req := new(T)
+		if config.RateLimiter != nil {
+			if err := checkRateLimit(c, config, translator); err != nil {
+				respond(c, config, req, nil, err)
+				return
 			}
-			translator = NewSimpleTranslator(locale)
 		}
 
-		// 绑定 JSON/Query 参数
-		if err := c.ShouldBind(req); err != nil {
-			// 提取验证错误详情
-			details := extractValidationErrors(err, translator)
-			if len(details) > 0 {
-				handleError(c, NewBizErrorWithDetails(config.BindErrorCode, translator.Translate(MsgBindError), http.StatusBadRequest, details))
-			} else {
-				handleError(c, NewBizError(config.BindErrorCode, translator.Translate(MsgBindErrorDetail, err), http.StatusBadRequest))
+		// 检查权限范围，缓存的响应同样只返回给有权限的请求
+		if len(config.RequiredScopes) > 0 {
+			if err := checkScopes(c, config, translator); err != nil {
+				respond(c, config, req, nil, err)
+				return
 			}
+		}
+
+		// 命中响应缓存时直接返回，仅验证请求不使用响应缓存；依赖请求参数的授权检查需要先绑定请求，设置了授权函数时不使用响应缓存
+		validateOnly := isValidateOnly(c, config)
+		if !validateOnly && len(checks) == 0 && serveCachedResponse(c, config) {
 			return
 		}
 
-		// 绑定路径参数
-		if err := bindPathParams(c, req, translator); err != nil {
-			handleError(c, NewBizError(config.BindErrorCode, translator.Translate(MsgPathBindError, err), http.StatusBadRequest))
+		// 重复的幂等请求直接重放已保存的响应
+		if config.IdempotencyStore != nil && !validateOnly {
+			replayed, err := beginIdempotentRequest(c, config, translator)
+			if replayed {
+				return
+			}
+			if err != nil {
+				respond(c, config, req, nil, err)
+				return
+			}
+			defer endIdempotentRequest(c, config)
+		}
+
+		// 绑定请求对象，并执行依赖请求参数的授权检查和依赖外部数据的验证
+		err := bindWithHooks(c, config, translator, req)
+		if len(config.Observers) > 0 {
+			c.Set(requestContextKey, req)
+		}
+		if err != nil {
+			respond(c, config, req, nil, err)
+			return
+		}
+		if err := checkAuthorize(c, translator, checks, req); err != nil {
+			respond(c, config, req, nil, err)
+			return
+		}
+		if err := runValidationFuncs(c, config, translator, validations, req); err != nil {
+			respond(c, config, req, nil, err)
 			return
 		}
 
-		// 记录请求日志（如果配置了日志函数）
-		if config.RequestLogger != nil {
+		// 记录请求日志（如果配置了日志函数），未被采样的请求只在业务处理失败时记录
+		logged := sampleLog(c, config, req)
+		if config.RequestLogger != nil && logged {
 			config.RequestLogger(c.Request, req)
 		}
 
-		// 调用业务处理函数
-		resp, err := handleFunc(c.Request.Context(), req)
+		// 仅验证请求不调用业务处理函数
+		if validateOnly {
+			respond(c, config, req, &ValidationResult{Valid: true}, nil)
+			return
+		}
+
+		// 调用业务处理函数并返回响应
+		resp, err := invokeSingleflight(c, config, translator, flight, handleFunc, req)
+		if len(audits) > 0 {
+			resp, err = writeAudit(c.Request.Context(), audits, req, resp, err)
+		}
 		if err != nil {
-			handleError(c, err)
+			if config.RequestLogger != nil && !logged {
+				config.RequestLogger(c.Request, req)
+			}
+			respond(c, config, req, nil, err)
 			return
 		}
+		respond(c, config, req, resp, nil)
+	}
+}
 
-		// 返回成功响应
-		c.JSON(config.SuccessHTTPCode, SuccessResponse[R]{
-			Code: config.SuccessCode,
-			Data: resp,
-		})
+// invokeHandleFunc 调用业务处理函数，配置了熔断器时通过熔断器调用
+func invokeHandleFunc[T any, R any](c *gin.Context, config *HandlerConfig, translator Translator, handleFunc HandleFunc[T, R], req *T) (*R, error) {
+	if config.CircuitBreaker != nil {
+		return invokeWithCircuitBreaker(c, config, translator, handleFunc, req)
 	}
+	return executeHandleFunc(c, config, translator, handleFunc, req)
+}
+
+// executeHandleFunc 执行业务处理函数，配置了重试策略时按策略重试
+func executeHandleFunc[T any, R any](c *gin.Context, config *HandlerConfig, translator Translator, handleFunc HandleFunc[T, R], req *T) (*R, error) {
+	if config.Retry != nil && config.Retry.allows(c.Request.Method) {
+		return executeWithRetry(c, config, translator, handleFunc, req)
+	}
+	return attemptHandleFunc(c, config, translator, handleFunc, req)
+}
+
+// attemptHandleFunc 调用一次业务处理函数，业务处理函数 panic 时调用 OnPanic 并返回内部服务器错误
+func attemptHandleFunc[T any, R any](c *gin.Context, config *HandlerConfig, translator Translator, handleFunc HandleFunc[T, R], req *T) (*R, error) {
+	if config.Timeout > 0 {
+		return invokeWithTimeout(c, config, translator, handleFunc, req)
+	}
+	result := callHandleFunc(c.Request.Context(), handleFunc, req)
+	if result.panic != nil {
+		return nil, recoverPanic(c, config, translator, req, result.panic)
+	}
+	return result.resp, result.err
+}
+
+// handleResult 业务处理函数的调用结果
+type handleResult[R any] struct {
+	resp  *R
+	err   error
+	panic *panicError
+}
+
+// callHandleFunc 调用业务处理函数，捕获 panic 的值和调用栈
+func callHandleFunc[T any, R any](ctx context.Context, handleFunc HandleFunc[T, R], req *T) (result handleResult[R]) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			result = handleResult[R]{panic: &panicError{value: recovered, stack: debug.Stack()}}
+		}
+	}()
+	resp, err := handleFunc(ctx, req)
+	return handleResult[R]{resp: resp, err: err}
+}
+
+// recoverPanic 处理业务处理函数的 panic，调用 OnPanic 和 PanicAlert 并返回内部服务器错误
+func recoverPanic(c *gin.Context, config *HandlerConfig, translator Translator, req any, p *panicError) error {
+	// 与 net/http 保持一致，http.ErrAbortHandler 用于主动中断响应
+	if p.value == http.ErrAbortHandler {
+		panic(p.value)
+	}
+
+	c.Error(p)
+	if config.OnPanic != nil {
+		config.OnPanic(c, p.value, p.stack)
+	}
+	alertPanic(c, config, req, p)
+	return WrapBizError(http.StatusInternalServerError, translator.Translate(MsgInternalError), http.StatusInternalServerError, p)
+}
+
+// requestTranslator 获取当前请求使用的翻译器
+func requestTranslator(c *gin.Context, config *HandlerConfig) Translator {
+	if lt, ok := config.Translator.(LocaleTranslator); ok {
+		return lt.ForLocale(requestLocale(c, config))
+	}
+	if config.Translator != nil {
+		return config.Translator
+	}
+
+	// 如果未设置翻译器，根据请求获取语言环境
+	return NewSimpleTranslator(requestLocale(c, config))
+}
+
+// requestLocale 获取当前请求的语言环境，已保存在请求 context 中时直接使用
+func requestLocale(c *gin.Context, config *HandlerConfig) string {
+	if locale := LocaleFromContext(c.Request.Context()); locale != "" {
+		return locale
+	}
+	if locale := contextLocale(c, config.LocaleKey); locale != "" {
+		return locale
+	}
+	locale := "zh"
+	if config.LocaleFunc != nil {
+		locale = config.LocaleFunc(c.Request)
+	} else if DefaultLocaleFunc != nil {
+		locale = DefaultLocaleFunc(c.Request)
+	}
+	return locale
+}
+
+// bindRequest 绑定路径参数和 JSON/Query 参数，失败时返回参数绑定错误
+func bindRequest(c *gin.Context, config *HandlerConfig, translator Translator, req any) error {
+	// 请求类型实现 RequestBinder（通常由 apihandlergen 生成）时不通过反射绑定
+	var err error
+	if binder, ok := req.(RequestBinder); ok && (generatedBindable(c) || skipBody(c, req)) {
+		err = bindGenerated(c, config, translator, binder)
+	} else {
+		err = bindReflect(c, config, translator, req)
+	}
+	if err != nil {
+		return err
+	}
+
+	// 调用请求类型的 Validate 方法
+	return validateRequest(c, config, translator, req)
+}
+
+// bindReflect 通过反射绑定路径参数和 JSON/Query 参数并验证
+func bindReflect(c *gin.Context, config *HandlerConfig, translator Translator, req any) error {
+	// 先绑定路径参数，使 binding tag 的验证规则同样作用于路径参数
+	if _, err := bindPathParams(c, req, translator); err != nil {
+		return NewBizError(config.BindErrorCode, translator.Translate(MsgPathBindError, err), http.StatusBadRequest)
+	}
+
+	// 绑定 JSON/Query 参数，配置了 JSON 编解码器时使用其解码 JSON 请求体；请求类型没有请求体字段时只绑定查询参数
+	var err error
+	switch {
+	case skipBody(c, req):
+		err = c.ShouldBindWith(req, binding.Query)
+	case config.JSONCodec != nil && c.ContentType() == binding.MIMEJSON:
+		err = c.ShouldBindWith(req, jsonBinding{codec: config.JSONCodec})
+	default:
+		err = c.ShouldBind(req)
+	}
+	var validationErrs validator.ValidationErrors
+	if err == nil || errors.As(err, &validationErrs) {
+		// 请求体或查询参数中的同名字段不能覆盖路径参数，覆盖时按路径参数的值重新验证
+		changed, _ := bindPathParams(c, req, translator)
+		// 填充认证声明，claim 字段按声明的值重新验证
+		if config.ClaimsExtractor != nil {
+			claimed, claimErr := bindClaims(c, config, translator, req)
+			if claimErr != nil {
+				return claimErr
+			}
+			changed = changed || claimed
+		}
+		if changed && binding.Validator != nil {
+			err = binding.Validator.ValidateStruct(req)
+		}
+	}
+	if err == nil && config.ValidationScenario != "" {
+		err = validateScenario(req, config.ValidationScenario)
+	}
+	if err != nil {
+		return bindError(c, config, translator, req, err)
+	}
+	return nil
+}
+
+// bindError 将绑定和验证的错误转换为参数绑定失败的业务错误，验证错误和 FieldError 作为错误详情
+func bindError(c *gin.Context, config *HandlerConfig, translator Translator, req any, err error) error {
+	if bizErr := mapBindSentinelError(config, translator, err); bizErr != nil {
+		return bizErr
+	}
+
+	// 提取验证错误详情
+	var trans ut.Translator
+	if config.ValidationTranslations {
+		trans = validationTranslator(requestLocale(c, config))
+	}
+	details := extractValidationErrors(err, translator, trans, newFieldNamer(req, isFormRequest(c)))
+	for _, detail := range collectFieldErrors(err) {
+		fe := detail.(FieldError)
+		if fe.Message == "" {
+			fe.Message = fieldErrorMessage(fe, translator)
+		}
+		fe.Value = fieldValue(fe.Value)
+		details = append(details, fe)
+	}
+	details = applyValidationMessages(details, config.ValidationMessages)
+	if len(details) > 0 {
+		return NewBizErrorWithDetails(config.BindErrorCode, translator.Translate(MsgBindError), http.StatusBadRequest, details)
+	}
+	return NewBizError(config.BindErrorCode, translator.Translate(MsgBindErrorDetail, err), http.StatusBadRequest)
+}
+
+// isFormRequest 判断请求参数是否从表单或查询参数绑定
+func isFormRequest(c *gin.Context) bool {
+	switch c.ContentType() {
+	case binding.MIMEPOSTForm, binding.MIMEMultipartPOSTForm:
+		return true
+	}
+	return c.Request.Method == http.MethodGet
 }
 
 // HandlerWithCode 创建 Gin 处理器，可指定成功响应的 code、HTTP 状态码和参数绑定错误的 code
@@ -221,8 +862,8 @@ func HandlerWithCode[T any, R any](handleFunc HandleFunc[T, R], successCode any,
 	return HandlerWithConfig(handleFunc, config)
 }
 
-// bindPathParams 绑定路径参数
-func bindPathParams(c *gin.Context, req any, translator Translator) error {
+// bindPathParams 绑定路径参数，changed 表示是否修改了字段原来的值
+func bindPathParams(c *gin.Context, req any, translator Translator) (changed bool, err error) {
 	reqType := reflect.TypeOf(req).Elem()
 	reqValue := reflect.ValueOf(req).Elem()
 
@@ -245,43 +886,136 @@ func bindPathParams(c *gin.Context, req any, translator Translator) error {
 			continue
 		}
 
+		old := fieldValue.Interface()
 		switch field.Type.Kind() {
 		case reflect.String:
 			fieldValue.SetString(paramValue)
 		case reflect.Int64:
 			val, err := strconv.ParseInt(paramValue, 10, 64)
 			if err != nil {
-				return errors.New(translator.Translate(MsgFieldParseFailed, field.Name, err))
+				return changed, errors.New(translator.Translate(MsgFieldParseFailed, field.Name, err))
 			}
 			fieldValue.SetInt(val)
 		case reflect.Uint64:
 			val, err := strconv.ParseUint(paramValue, 10, 64)
 			if err != nil {
-				return errors.New(translator.Translate(MsgFieldParseFailed, field.Name, err))
+				return changed, errors.New(translator.Translate(MsgFieldParseFailed, field.Name, err))
 			}
 			fieldValue.SetUint(val)
 		default:
-			return errors.New(translator.Translate(MsgFieldTypeNotSupported, field.Name, field.Type.Kind()))
+			return changed, errors.New(translator.Translate(MsgFieldTypeNotSupported, field.Name, field.Type.Kind()))
 		}
+		changed = changed || fieldValue.Interface() != old
 	}
-	return nil
+	return changed, nil
 }
 
-// handleError 处理错误
-func handleError(c *gin.Context, err error) {
-	// 检查是否是业务错误
-	if bizErr, ok := err.(BizError); ok {
-		c.JSON(bizErr.HTTPCode(), ErrorResponse{
-			Code:    bizErr.Code(),
-			Message: bizErr.Error(),
-			Errors:  bizErr.Errors(),
-		})
+// handleSuccess 处理成功响应
+func handleSuccess(c *gin.Context, config *HandlerConfig, req any, resp any) {
+	if config.NoContentOnNil && isNil(resp) {
+		if config.IdempotencyStore != nil {
+			storeIdempotentResponse(c, config, http.StatusNoContent, "", nil)
+		}
+		c.Status(http.StatusNoContent)
+		c.Writer.WriteHeaderNow()
+		return
+	}
+
+	httpCode := config.SuccessHTTPCode
+	if sc, ok := resp.(StatusCoder); ok && !isNil(resp) {
+		httpCode = sc.HTTPStatus()
+	}
+	if hs, ok := resp.(HeaderSetter); ok && !isNil(resp) {
+		setHeaders(c, hs.Headers())
+	}
+	if hs, ok := resp.(contextHeaderSetter); ok && !isNil(resp) {
+		hs.setContextHeaders(c)
+	}
+	if rd, ok := resp.(Responder); ok && !isNil(resp) {
+		if err := rd.Respond(c, httpCode); err != nil && !c.Writer.Written() {
+			handleError(c, config, req, err)
+		}
 		return
 	}
 
-	// 默认内部服务器错误
-	c.JSON(http.StatusInternalServerError, ErrorResponse{
-		Code:    http.StatusInternalServerError,
-		Message: err.Error(),
-	})
+	code := config.successCode(c.Request.Context(), req, resp)
+	c.Set(responseCodeContextKey, code)
+	resp = transformResponse(c.Request.Context(), resp)
+	if config.EmptyContainers {
+		resp = fillEmptyContainers(resp)
+	}
+	if config.FieldsParam != "" {
+		if fields := c.Query(config.FieldsParam); fields != "" {
+			resp = sparseResponse(resp, fields)
+		}
+	}
+	render(c, config, httpCode, config.envelope().Success(c, code, resp))
+}
+
+// setHeaders 写入响应头
+func setHeaders(c *gin.Context, header http.Header) {
+	for key, values := range header {
+		c.Writer.Header().Del(key)
+		for _, value := range values {
+			c.Writer.Header().Add(key, value)
+		}
+	}
+}
+
+// handleError 处理错误
+func handleError(c *gin.Context, config *HandlerConfig, req any, err error) {
+	bizErr := resolveError(c, config, req, err)
+	c.Set(responseCodeContextKey, bizErr.Code())
+	c.Set(responseErrorContextKey, bizErr)
+	setHeaders(c, errorHeaders(bizErr))
+	render(c, config, bizErr.HTTPCode(), errorBody(c, config, bizErr))
+}
+
+// resolveError 将错误转换为业务错误，并在输出错误响应前调用 OnError 和 ErrorRecorder
+func resolveError(c *gin.Context, config *HandlerConfig, req any, err error) BizError {
+	bizErr := localizeError(c, config, toBizError(config, err))
+	if len(config.SentinelErrors) > 0 {
+		bizErr = mapSentinelError(c, config, bizErr)
+	}
+	if len(config.CodeRanges) > 0 {
+		bizErr = inferHTTPCode(config, bizErr)
+	}
+	if config.MaskInternalErrors {
+		bizErr = maskInternalError(c, config, bizErr)
+	}
+	if config.OnError != nil {
+		config.OnError(c, req, bizErr)
+	}
+	recordError(c, config, bizErr)
+	return bizErr
+}
+
+// errorBody 构造错误响应体，依次使用 ErrorRenderer、Problem Details 和 Envelope
+func errorBody(c *gin.Context, config *HandlerConfig, bizErr BizError) any {
+	if config.ErrorRenderer != nil {
+		return renderErrorBody(c, config, bizErr)
+	}
+	if config.ProblemDetails {
+		return newProblemDetails(c, config, bizErr)
+	}
+	return config.envelope().Error(c, bizErr)
+}
+
+// toBizError 将错误转换为业务错误，无法识别的错误视为内部服务器错误
+//
+// 错误链中包含业务错误时（如 fmt.Errorf("create user: %w", ErrNotFound(...))），
+// 使用该业务错误的代码和状态码，消息保留外层的上下文；否则依次尝试 ErrorMappers 和全局注册的错误映射函数。
+// 转换后的业务错误可通过 errors.Is/errors.As 获取原始错误。
+func toBizError(config *HandlerConfig, err error) BizError {
+	if bizErr, ok := err.(BizError); ok {
+		return bizErr
+	}
+	var bizErr BizError
+	if errors.As(err, &bizErr) {
+		return &wrappedBizError{BizError: bizErr, err: err}
+	}
+	if bizErr, ok := mapError(config, err); ok {
+		return &causedBizError{BizError: bizErr, cause: err}
+	}
+	return &internalError{cause: err}
 }
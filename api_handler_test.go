@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -185,6 +186,64 @@ func TestHandlerStringPath(t *testing.T) {
 	}
 }
 
+// 测试路径参数同样执行 binding tag 的验证
+func TestHandlerPathValidation(t *testing.T) {
+	type pathRequest struct {
+		ID   int64  `path:"id" binding:"required,min=1"`
+		Name string `json:"name"`
+	}
+
+	type pathResponse struct {
+		ID int64 `json:"id"`
+	}
+
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *pathRequest) (*pathResponse, error) {
+		return &pathResponse{ID: req.ID}, nil
+	}
+
+	r.GET("/items/:id", Handler(handleFunc))
+	r.PUT("/items/:id", Handler(handleFunc))
+
+	req := httptest.NewRequest("GET", "/items/5", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("期望路径参数满足验证规则时状态码 %d, 实际得到 %d", http.StatusOK, w.Code)
+	}
+
+	req = httptest.NewRequest("GET", "/items/-1", nil)
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("期望状态码 %d, 实际得到 %d", http.StatusBadRequest, w.Code)
+	}
+	var errResp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	if fe := errResp.FieldErrors(); len(fe) != 1 || fe[0].Field != "id" || fe[0].Rule != "min" || fe[0].Param != "1" {
+		t.Errorf("期望路径参数 id 的 min 验证错误, 实际得到 %+v", fe)
+	}
+
+	// 请求体中的同名字段不能覆盖路径参数
+	req = httptest.NewRequest("PUT", "/items/7", bytes.NewBufferString(`{"id":0,"name":"a"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("期望状态码 %d, 实际得到 %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var resp SuccessResponse[pathResponse]
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	if resp.Data.ID != 7 {
+		t.Errorf("期望 ID 为路径参数 7, 实际得到 %d", resp.Data.ID)
+	}
+}
+
 // 测试自定义业务错误
 func TestCustomBizError(t *testing.T) {
 	customErr := NewBizError(10001, "自定义错误", http.StatusBadRequest)
@@ -506,8 +565,8 @@ func TestBindValidationErrorMinMax(t *testing.T) {
 		}
 
 		field, ok := errorDetail["field"].(string)
-		if !ok || field != "Age" {
-			t.Errorf("期望 field 为 'Age', 实际得到 '%v'", errorDetail["field"])
+		if !ok || field != "age" {
+			t.Errorf("期望 field 为 'age', 实际得到 '%v'", errorDetail["field"])
 		}
 
 		message, ok := errorDetail["message"].(string)
@@ -556,3 +615,198 @@ func TestBindValidationErrorMinMax(t *testing.T) {
 		}
 	}
 }
+
+// 测试业务返回 nil 时响应 204
+func TestHandlerNoContentOnNil(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return nil, nil
+	}
+
+	r.DELETE("/test/:id", Handler(handleFunc, WithNoContentOnNil()))
+	r.DELETE("/legacy/:id", Handler(handleFunc))
+
+	req := httptest.NewRequest("DELETE", "/test/1", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNoContent {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusNoContent, w.Code)
+	}
+
+	if w.Body.Len() != 0 {
+		t.Errorf("期望响应体为空, 实际得到 '%s'", w.Body.String())
+	}
+
+	// 未开启时保持原有行为
+	req = httptest.NewRequest("DELETE", "/legacy/1", nil)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Body.String() != `{"code":0,"data":null}` {
+		t.Errorf(`期望响应为 {"code":0,"data":null}, 实际得到 '%s'`, w.Body.String())
+	}
+}
+
+// 测试按结果动态生成成功业务代码
+func TestSuccessCodeFunc(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*upsertResponse, error) {
+		return &upsertResponse{ID: req.ID, Created: req.ID > 100}, nil
+	}
+
+	codeFunc := func(ctx context.Context, req any, resp any) any {
+		if resp.(*upsertResponse).Created {
+			return 20100
+		}
+		if req.(*testRequest).Name == "default" {
+			return nil // 使用 SuccessCode
+		}
+		return 20001
+	}
+
+	r.PUT("/users/:id", Handler(handleFunc, WithSuccessCode(20000), WithSuccessCodeFunc(codeFunc)))
+
+	cases := []struct {
+		path string
+		code float64
+	}{
+		{"/users/101", 20100},
+		{"/users/1", 20001},
+		{"/users/1?name=default", 20000},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest("PUT", tc.path, nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		var resp SuccessResponse[upsertResponse]
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("解析响应失败: %v", err)
+		}
+
+		if resp.Code != tc.code {
+			t.Errorf("%s: 期望 code 为 %v, 实际得到 %v", tc.path, tc.code, resp.Code)
+		}
+	}
+}
+
+// 测试业务处理函数 panic 时返回标准错误响应并调用 OnPanic
+func TestHandlerPanicRecovery(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		var m map[string]int
+		m["boom"] = req.Age // nil map 写入触发 panic
+		return &testResponse{}, nil
+	}
+
+	var recovered any
+	var stack []byte
+	onPanic := func(c *gin.Context, v any, s []byte) {
+		recovered, stack = v, s
+	}
+
+	r.GET("/test/:id", Handler(handleFunc, WithOnPanic(onPanic)))
+
+	req := httptest.NewRequest("GET", "/test/1", nil)
+	req.Header.Set("Accept-Language", "en")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusInternalServerError, w.Code)
+	}
+
+	expected := `{"code":500,"message":"Internal server error"}`
+	if w.Body.String() != expected {
+		t.Errorf("期望响应为 %s, 实际得到 %s", expected, w.Body.String())
+	}
+
+	if recovered == nil {
+		t.Fatalf("期望调用 OnPanic")
+	}
+
+	if !bytes.Contains(stack, []byte("TestHandlerPanicRecovery")) {
+		t.Errorf("期望调用栈包含触发 panic 的函数, 实际得到 %s", stack)
+	}
+}
+
+// 测试 http.ErrAbortHandler 不被恢复
+func TestHandlerPanicAbort(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		panic(http.ErrAbortHandler)
+	}
+
+	r.GET("/test/:id", Handler(handleFunc))
+
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Errorf("期望继续抛出 http.ErrAbortHandler, 实际得到 %v", v)
+		}
+	}()
+
+	req := httptest.NewRequest("GET", "/test/1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+}
+
+// 测试输出错误响应前调用 OnError
+func TestHandlerOnError(t *testing.T) {
+	r := gin.New()
+
+	errDB := errors.New("db: connection refused")
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		if req.ID == 1 {
+			return nil, ErrNotFound(40400, "资源不存在")
+		}
+		return nil, errDB
+	}
+
+	type call struct {
+		req *testRequest
+		err error
+	}
+	var calls []call
+	onError := func(c *gin.Context, req any, err error) {
+		calls = append(calls, call{req: req.(*testRequest), err: err})
+	}
+
+	r.GET("/test/:id", Handler(handleFunc, WithOnError(onError)))
+
+	for _, path := range []string{"/test/1?name=a", "/test/2?name=b"} {
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+	}
+
+	if len(calls) != 2 {
+		t.Fatalf("期望 OnError 被调用 2 次, 实际得到 %d", len(calls))
+	}
+
+	if calls[0].req.ID != 1 || calls[0].req.Name != "a" {
+		t.Errorf("期望回调拿到绑定后的请求, 实际得到 %+v", calls[0].req)
+	}
+
+	var bizErr BizError
+	if !errors.As(calls[0].err, &bizErr) || bizErr.HTTPCode() != http.StatusNotFound {
+		t.Errorf("期望回调拿到 404 业务错误, 实际得到 %v", calls[0].err)
+	}
+
+	if !errors.As(calls[1].err, &bizErr) || bizErr.HTTPCode() != http.StatusInternalServerError {
+		t.Errorf("期望非业务错误转换为 500 业务错误, 实际得到 %v", calls[1].err)
+	}
+
+	if !errors.Is(calls[1].err, errDB) {
+		t.Errorf("期望可以通过 errors.Is 获取原始错误")
+	}
+}
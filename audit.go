@@ -0,0 +1,105 @@
+package apihandler
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// 审计事件的结果
+const (
+	AuditSuccess = "success"
+	AuditFailure = "failure"
+)
+
+// AuditEvent 结构化的审计事件
+type AuditEvent struct {
+	Time       time.Time      `json:"time"`                  // 发生时间，为空时使用业务处理函数返回时的时间
+	Actor      string         `json:"actor"`                 // 操作者，如用户 ID
+	Action     string         `json:"action"`                // 操作，如 user.update
+	Resource   string         `json:"resource"`              // 资源类型，如 user
+	ResourceID string         `json:"resource_id,omitempty"` // 资源 ID
+	Before     any            `json:"before,omitempty"`      // 操作前的状态
+	After      any            `json:"after,omitempty"`       // 操作后的状态
+	Outcome    string         `json:"outcome"`               // 结果，为空时根据错误设置为 success 或 failure
+	Error      string         `json:"error,omitempty"`       // 失败原因，为空时使用业务处理函数返回的错误消息
+	RequestID  string         `json:"request_id,omitempty"`  // 请求 ID，为空时使用请求 context 中的请求 ID
+	Metadata   map[string]any `json:"metadata,omitempty"`    // 其他信息，如客户端 IP
+}
+
+// Auditor 审计事件的写入接口，可对接数据库、消息队列或审计服务
+type Auditor interface {
+	Audit(ctx context.Context, event AuditEvent) error
+}
+
+// AuditorFunc 函数形式的 Auditor
+type AuditorFunc func(ctx context.Context, event AuditEvent) error
+
+// Audit 实现 Auditor 接口
+func (f AuditorFunc) Audit(ctx context.Context, event AuditEvent) error {
+	return f(ctx, event)
+}
+
+// AuditBuilder 根据请求和业务处理结果构造审计事件的函数
+type AuditBuilder[T any, R any] func(ctx context.Context, req *T, resp *R, err error) AuditEvent
+
+// WithAudit 为写操作添加审计，业务处理函数返回后构造审计事件并写入 auditor
+//
+// 每个请求只写入一个审计事件：重试、超时和 panic 都在业务处理的最终结果确定后记录，panic 记为失败。
+// 无论业务处理成功或失败都会写入审计事件；业务处理成功但审计事件写入失败时返回内部服务器错误，
+// 保证每个成功的写操作都有审计记录。请求和响应类型必须与处理器一致，否则创建处理器时 panic。
+func WithAudit[T any, R any](auditor Auditor, build AuditBuilder[T, R]) Option {
+	return func(c *HandlerConfig) {
+		c.Auditors = append(c.Auditors[:len(c.Auditors):len(c.Auditors)], auditHook[T, R]{auditor: auditor, build: build})
+	}
+}
+
+// auditHook 通过 WithAudit 添加的审计配置
+type auditHook[T any, R any] struct {
+	auditor Auditor
+	build   AuditBuilder[T, R]
+}
+
+// auditHooks 将配置的审计转换为处理器请求和响应类型的审计，类型不匹配时 panic
+func auditHooks[T any, R any](items []any) []auditHook[T, R] {
+	hooks := make([]auditHook[T, R], len(items))
+	for i, item := range items {
+		hook, ok := item.(auditHook[T, R])
+		if !ok {
+			panic(fmt.Sprintf("apihandler: audit %T does not match handler types %T and %T", item, new(T), new(R)))
+		}
+		hooks[i] = hook
+	}
+	return hooks
+}
+
+// writeAudit 根据业务处理的最终结果构造并写入审计事件，业务处理成功但审计事件写入失败时返回错误
+func writeAudit[T any, R any](ctx context.Context, hooks []auditHook[T, R], req *T, resp *R, err error) (*R, error) {
+	var auditErr error
+	for _, hook := range hooks {
+		event := hook.build(ctx, req, resp, err)
+		if event.Time.IsZero() {
+			event.Time = time.Now()
+		}
+		if event.Outcome == "" {
+			event.Outcome = AuditSuccess
+			if err != nil {
+				event.Outcome = AuditFailure
+			}
+		}
+		if event.Error == "" && err != nil {
+			event.Error = err.Error()
+		}
+		if event.RequestID == "" {
+			event.RequestID = RequestIDFromContext(ctx)
+		}
+
+		if e := hook.auditor.Audit(context.WithoutCancel(ctx), event); e != nil && auditErr == nil {
+			auditErr = e
+		}
+	}
+	if auditErr != nil && err == nil {
+		return nil, fmt.Errorf("write audit event: %w", auditErr)
+	}
+	return resp, err
+}
@@ -0,0 +1,150 @@
+package apihandler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试写操作的审计事件
+func TestAudit(t *testing.T) {
+	r := gin.New()
+
+	type updateRequest struct {
+		ID   int64  `path:"id"`
+		Name string `form:"name"`
+	}
+	handleFunc := func(ctx context.Context, req *updateRequest) (*testResponse, error) {
+		if req.Name == "" {
+			return nil, ErrBadRequest(40000, "名称不能为空")
+		}
+		return &testResponse{ID: req.ID, Name: req.Name}, nil
+	}
+
+	var events []AuditEvent
+	auditor := AuditorFunc(func(ctx context.Context, event AuditEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	build := func(ctx context.Context, req *updateRequest, resp *testResponse, err error) AuditEvent {
+		return AuditEvent{Actor: "alice", Action: "user.update", Resource: "user", After: resp}
+	}
+	r.PUT("/users/:id", Handler(handleFunc, WithRequestID(nil), WithAudit(auditor, build)))
+
+	for _, url := range []string{"/users/1?name=bob", "/users/1"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("PUT", url, nil))
+	}
+
+	if len(events) != 2 {
+		t.Fatalf("期望写入 2 个审计事件, 实际得到 %d", len(events))
+	}
+	success := events[0]
+	if success.Outcome != AuditSuccess || success.Actor != "alice" || success.Time.IsZero() || success.RequestID == "" {
+		t.Errorf("期望成功的审计事件填充结果、时间和请求 ID, 实际得到 %+v", success)
+	}
+	if resp, ok := success.After.(*testResponse); !ok || resp.Name != "bob" {
+		t.Errorf("期望审计事件记录操作后的状态, 实际得到 %v", success.After)
+	}
+	if failure := events[1]; failure.Outcome != AuditFailure || failure.Error != "名称不能为空" {
+		t.Errorf("期望失败的审计事件记录失败原因, 实际得到 %+v", failure)
+	}
+}
+
+// 测试审计事件写入失败时返回内部服务器错误
+func TestAuditWriteFailure(t *testing.T) {
+	r := gin.New()
+
+	var handleErr error
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return &testResponse{}, handleErr
+	}
+	auditor := AuditorFunc(func(ctx context.Context, event AuditEvent) error {
+		return errors.New("audit sink unavailable")
+	})
+	build := func(ctx context.Context, req *testRequest, resp *testResponse, err error) AuditEvent {
+		return AuditEvent{Action: "test"}
+	}
+	r.POST("/test", Handler(handleFunc, WithAudit(auditor, build)))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/test", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("期望审计写入失败时状态码 %d, 实际得到 %d", http.StatusInternalServerError, w.Code)
+	}
+
+	handleErr = ErrConflict(40900, "资源冲突")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("POST", "/test", nil))
+	if w.Code != http.StatusConflict {
+		t.Errorf("期望保留业务错误的状态码 %d, 实际得到 %d", http.StatusConflict, w.Code)
+	}
+}
+
+// 测试重试和 panic 时每个请求只写入一个审计事件
+func TestAuditOncePerRequest(t *testing.T) {
+	r := gin.New()
+
+	var calls int
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		calls++
+		switch {
+		case req.Name == "panic":
+			panic("boom")
+		case calls < 3:
+			return nil, Retryable(errors.New("upstream unavailable"))
+		}
+		return &testResponse{Name: req.Name}, nil
+	}
+
+	var events []AuditEvent
+	auditor := AuditorFunc(func(ctx context.Context, event AuditEvent) error {
+		events = append(events, event)
+		return nil
+	})
+	build := func(ctx context.Context, req *testRequest, resp *testResponse, err error) AuditEvent {
+		return AuditEvent{Action: "test.update"}
+	}
+	r.PUT("/test", Handler(handleFunc,
+		WithRetry(RetryPolicy{MaxAttempts: 3, Backoff: func(int) time.Duration { return 0 }}),
+		WithAudit(auditor, build),
+	))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("PUT", "/test?name=bob", nil))
+	if w.Code != http.StatusOK || calls != 3 {
+		t.Fatalf("期望重试后成功, 实际得到状态码 %d、调用 %d 次", w.Code, calls)
+	}
+	if len(events) != 1 || events[0].Outcome != AuditSuccess {
+		t.Fatalf("期望重试后只写入 1 个成功的审计事件, 实际得到 %+v", events)
+	}
+
+	events = nil
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("PUT", "/test?name=panic", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Fatalf("期望 panic 时状态码 %d, 实际得到 %d", http.StatusInternalServerError, w.Code)
+	}
+	if len(events) != 1 || events[0].Outcome != AuditFailure || events[0].Error == "" {
+		t.Errorf("期望 panic 时写入 1 个失败的审计事件, 实际得到 %+v", events)
+	}
+}
+
+// 测试审计的类型与处理器不一致时创建处理器 panic
+func TestAuditTypeMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("期望创建处理器时 panic")
+		}
+	}()
+	build := func(ctx context.Context, req *testRequest, resp *testRequest, err error) AuditEvent {
+		return AuditEvent{}
+	}
+	Handler(func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return nil, nil
+	}, WithAudit(AuditorFunc(func(ctx context.Context, event AuditEvent) error { return nil }), build))
+}
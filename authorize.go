@@ -0,0 +1,125 @@
+package apihandler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ScopesFunc 获取请求已授权的权限范围的函数，返回错误表示请求未认证
+type ScopesFunc func(c *gin.Context) ([]string, error)
+
+// WithRequiredScopes 添加调用处理器所需的权限范围，请求必须拥有全部权限范围，否则返回 403 错误响应
+//
+// 权限范围由 ScopesFunc 获取，为空时读取 ClaimsExtractor 返回的 scope 或 scp 声明
+// （空格分隔的字符串或字符串列表）。
+func WithRequiredScopes(scopes ...string) Option {
+	return func(c *HandlerConfig) {
+		c.RequiredScopes = append(c.RequiredScopes[:len(c.RequiredScopes):len(c.RequiredScopes)], scopes...)
+	}
+}
+
+// WithScopesFunc 设置获取请求已授权的权限范围的函数
+func WithScopesFunc(fn ScopesFunc) Option {
+	return func(c *HandlerConfig) {
+		c.ScopesFunc = fn
+	}
+}
+
+// WithAuthorize 添加依赖请求参数的授权检查（如校验 :id 对应资源的归属），在参数绑定后、业务处理函数前执行
+//
+// 返回业务错误时直接使用，返回其他错误时输出 403 错误响应。授权函数的请求类型必须与处理器一致，否则创建处理器时 panic。
+func WithAuthorize[T any](authorize func(ctx context.Context, req *T) error) Option {
+	return func(c *HandlerConfig) {
+		c.Authorizers = append(c.Authorizers[:len(c.Authorizers):len(c.Authorizers)], authorize)
+	}
+}
+
+// checkScopes 检查请求是否拥有全部所需的权限范围
+func checkScopes(c *gin.Context, config *HandlerConfig, translator Translator) error {
+	scopes, err := requestScopes(c, config)
+	if err != nil {
+		var bizErr BizError
+		if errors.As(err, &bizErr) {
+			return err
+		}
+		return WrapBizError(http.StatusUnauthorized, translator.Translate(MsgUnauthorized), http.StatusUnauthorized, err)
+	}
+
+	granted := make(map[string]bool, len(scopes))
+	for _, scope := range scopes {
+		granted[scope] = true
+	}
+	var missing []string
+	for _, scope := range config.RequiredScopes {
+		if !granted[scope] {
+			missing = append(missing, scope)
+		}
+	}
+	if len(missing) > 0 {
+		return ErrForbidden(http.StatusForbidden, translator.Translate(MsgInsufficientScope, strings.Join(missing, " ")))
+	}
+	return nil
+}
+
+// requestScopes 获取请求已授权的权限范围
+func requestScopes(c *gin.Context, config *HandlerConfig) ([]string, error) {
+	if config.ScopesFunc != nil {
+		return config.ScopesFunc(c)
+	}
+	if config.ClaimsExtractor == nil {
+		return nil, errors.New("no ScopesFunc or ClaimsExtractor configured")
+	}
+	claims, err := config.ClaimsExtractor(c)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range []string{"scope", "scp"} {
+		switch v := claims[name].(type) {
+		case string:
+			return strings.Fields(v), nil
+		case []string:
+			return v, nil
+		case []any:
+			scopes := make([]string, 0, len(v))
+			for _, s := range v {
+				scopes = append(scopes, fmt.Sprint(s))
+			}
+			return scopes, nil
+		}
+	}
+	return nil, nil
+}
+
+// authorizers 将配置的授权函数转换为处理器请求类型的授权函数，类型不匹配时 panic
+func authorizers[T any](items []any) []func(context.Context, *T) error {
+	checks := make([]func(context.Context, *T) error, len(items))
+	for i, item := range items {
+		check, ok := item.(func(context.Context, *T) error)
+		if !ok {
+			panic(fmt.Sprintf("apihandler: authorizer %T does not match request type %T", item, new(T)))
+		}
+		checks[i] = check
+	}
+	return checks
+}
+
+// checkAuthorize 依次执行授权函数，非业务错误转换为 403 错误
+func checkAuthorize[T any](c *gin.Context, translator Translator, checks []func(context.Context, *T) error, req *T) error {
+	for _, check := range checks {
+		err := check(c.Request.Context(), req)
+		if err == nil {
+			continue
+		}
+		var bizErr BizError
+		if errors.As(err, &bizErr) {
+			return err
+		}
+		return WrapBizError(http.StatusForbidden, translator.Translate(MsgForbidden), http.StatusForbidden, err)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试检查调用处理器所需的权限范围
+func TestRequiredScopes(t *testing.T) {
+	r := gin.New()
+
+	called := false
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		called = true
+		return &testResponse{}, nil
+	}
+	r.POST("/users", func(c *gin.Context) {
+		if scope := c.GetHeader("X-Scope"); scope != "" {
+			c.Set("claims", map[string]any{"scope": scope})
+		}
+	}, Handler(handleFunc, WithClaims(ClaimsFromContextKey("claims")), WithRequiredScopes("user:read", "user:write")))
+
+	tests := []struct {
+		name       string
+		scope      string
+		wantStatus int
+		wantMsg    string
+	}{
+		{name: "拥有全部权限范围", scope: "user:read user:write", wantStatus: http.StatusOK},
+		{name: "缺少权限范围", scope: "user:read", wantStatus: http.StatusForbidden, wantMsg: "缺少访问权限: user:write"},
+		{name: "未认证", wantStatus: http.StatusUnauthorized, wantMsg: "未登录或登录已过期"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called = false
+			req := httptest.NewRequest("POST", "/users", nil)
+			if tt.scope != "" {
+				req.Header.Set("X-Scope", tt.scope)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("期望状态码 %d, 实际得到 %d, 响应 %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if called != (tt.wantStatus == http.StatusOK) {
+				t.Errorf("期望业务处理函数调用状态为 %v, 实际得到 %v", tt.wantStatus == http.StatusOK, called)
+			}
+			if tt.wantMsg == "" {
+				return
+			}
+			var resp ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("解析响应失败: %v", err)
+			}
+			if resp.Message != tt.wantMsg {
+				t.Errorf("期望错误消息 '%s', 实际得到 '%s'", tt.wantMsg, resp.Message)
+			}
+		})
+	}
+}
+
+// 测试自定义获取权限范围的函数
+func TestScopesFunc(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return &testResponse{}, nil
+	}
+	scopes := func(c *gin.Context) ([]string, error) {
+		return []string{c.GetHeader("X-Scope")}, nil
+	}
+	r.GET("/admin", Handler(handleFunc, WithScopesFunc(scopes), WithRequiredScopes("admin")))
+
+	for scope, want := range map[string]int{"admin": http.StatusOK, "user": http.StatusForbidden} {
+		req := httptest.NewRequest("GET", "/admin", nil)
+		req.Header.Set("X-Scope", scope)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != want {
+			t.Errorf("期望权限范围 %s 的状态码 %d, 实际得到 %d", scope, want, w.Code)
+		}
+	}
+}
+
+// 测试依赖请求参数的授权检查
+func TestAuthorize(t *testing.T) {
+	r := gin.New()
+
+	type ownedRequest struct {
+		ID    int64  `path:"id"`
+		Owner string `form:"owner"`
+	}
+	handleFunc := func(ctx context.Context, req *ownedRequest) (*testResponse, error) {
+		return &testResponse{ID: req.ID}, nil
+	}
+	authorize := func(ctx context.Context, req *ownedRequest) error {
+		switch {
+		case req.ID == 0:
+			return ErrNotFound(404, "资源不存在")
+		case req.Owner != "alice":
+			return errors.New("not owner")
+		}
+		return nil
+	}
+	r.GET("/docs/:id", Handler(handleFunc, WithAuthorize(authorize)))
+
+	tests := []struct {
+		name       string
+		url        string
+		wantStatus int
+		wantMsg    string
+	}{
+		{name: "授权通过", url: "/docs/1?owner=alice", wantStatus: http.StatusOK},
+		{name: "非资源所有者", url: "/docs/1?owner=bob", wantStatus: http.StatusForbidden, wantMsg: "没有访问权限"},
+		{name: "业务错误原样返回", url: "/docs/0?owner=alice", wantStatus: http.StatusNotFound, wantMsg: "资源不存在"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest("GET", tt.url, nil))
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("期望状态码 %d, 实际得到 %d, 响应 %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if tt.wantMsg == "" {
+				return
+			}
+			var resp ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("解析响应失败: %v", err)
+			}
+			if resp.Message != tt.wantMsg {
+				t.Errorf("期望错误消息 '%s', 实际得到 '%s'", tt.wantMsg, resp.Message)
+			}
+		})
+	}
+}
+
+// 测试授权函数请求类型与处理器不匹配时 panic
+func TestAuthorizeTypeMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("期望授权函数类型不匹配时 panic")
+		}
+	}()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return &testResponse{}, nil
+	}
+	authorize := func(ctx context.Context, req *claimsRequest) error { return nil }
+	Handler(handleFunc, WithAuthorize(authorize))
+}
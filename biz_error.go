@@ -1,6 +1,10 @@
 package apihandler
 
-import "net/http"
+import (
+	"fmt"
+	"io"
+	"net/http"
+)
 
 // BaseBizError 基础业务错误
 type BaseBizError struct {
@@ -8,6 +12,7 @@ type BaseBizError struct {
 	message  string
 	httpCode int
 	errors   []any
+	cause    error
 }
 
 // NewBizError 创建业务错误
@@ -29,6 +34,19 @@ func NewBizErrorWithDetails(code any, message string, httpCode int, errors []any
 	}
 }
 
+// WrapBizError 创建包装原始错误的业务错误
+//
+// 原始错误可通过 errors.Unwrap/errors.Is/errors.As 获取，也会在使用 %+v 格式化时输出，
+// 便于日志记录根因；响应中只包含 message，不会泄露原始错误。
+func WrapBizError(code any, message string, httpCode int, cause error) BizError {
+	return &BaseBizError{
+		code:     code,
+		message:  message,
+		httpCode: httpCode,
+		cause:    cause,
+	}
+}
+
 // Error 实现 error 接口
 func (e *BaseBizError) Error() string {
 	return e.message
@@ -49,6 +67,80 @@ func (e *BaseBizError) Errors() []any {
 	return e.errors
 }
 
+// Unwrap 返回被包装的原始错误
+func (e *BaseBizError) Unwrap() error {
+	return e.cause
+}
+
+// Format 实现 fmt.Formatter 接口，%+v 时输出原始错误，其他格式只输出消息
+func (e *BaseBizError) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+') && e.cause != nil:
+		fmt.Fprintf(s, "%s: %+v", e.message, e.cause)
+	case verb == 'q':
+		fmt.Fprintf(s, "%q", e.message)
+	default:
+		io.WriteString(s, e.message)
+	}
+}
+
+// wrappedBizError 被包装在错误链中的业务错误
+type wrappedBizError struct {
+	BizError
+	err error
+}
+
+// Error 实现 error 接口，返回包含外层上下文的完整消息
+func (e *wrappedBizError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap 返回原始错误链
+func (e *wrappedBizError) Unwrap() error {
+	return e.err
+}
+
+// internalError 非业务错误转换得到的内部服务器错误
+type internalError struct {
+	cause error
+}
+
+// Error 实现 error 接口
+func (e *internalError) Error() string {
+	return e.cause.Error()
+}
+
+// Code 返回业务错误码
+func (e *internalError) Code() any {
+	return http.StatusInternalServerError
+}
+
+// HTTPCode 返回 HTTP 状态码
+func (e *internalError) HTTPCode() int {
+	return http.StatusInternalServerError
+}
+
+// Errors 返回详细错误列表
+func (e *internalError) Errors() []any {
+	return nil
+}
+
+// Unwrap 返回原始错误
+func (e *internalError) Unwrap() error {
+	return e.cause
+}
+
+// causedBizError 由错误映射函数转换得到的业务错误，保留原始错误
+type causedBizError struct {
+	BizError
+	cause error
+}
+
+// Unwrap 返回映射得到的业务错误和原始错误
+func (e *causedBizError) Unwrap() []error {
+	return []error{e.BizError, e.cause}
+}
+
 // 预定义的常见业务错误
 var (
 	// ErrBadRequest 请求参数错误
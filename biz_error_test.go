@@ -0,0 +1,132 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试错误链中的业务错误
+func TestWrappedBizError(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		switch req.ID {
+		case 1:
+			return nil, fmt.Errorf("create user: %w", ErrNotFound(40400, "资源不存在"))
+		case 2:
+			return nil, fmt.Errorf("handler: %w", fmt.Errorf("repo: %w", ErrConflict(40900, "用户已存在")))
+		}
+		return nil, errors.New("db: connection refused")
+	}
+
+	r.GET("/test/:id", Handler(handleFunc))
+
+	cases := []struct {
+		path     string
+		httpCode int
+		code     float64
+		message  string
+	}{
+		{"/test/1", http.StatusNotFound, 40400, "create user: 资源不存在"},
+		{"/test/2", http.StatusConflict, 40900, "handler: repo: 用户已存在"},
+		{"/test/3", http.StatusInternalServerError, 500, "db: connection refused"},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", tc.path, nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.httpCode {
+			t.Errorf("%s: 期望状态码 %d, 实际得到 %d", tc.path, tc.httpCode, w.Code)
+		}
+
+		var resp ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("解析响应失败: %v", err)
+		}
+
+		if resp.Code != tc.code {
+			t.Errorf("%s: 期望 code 为 %v, 实际得到 %v", tc.path, tc.code, resp.Code)
+		}
+
+		if resp.Message != tc.message {
+			t.Errorf("%s: 期望 message 为 '%s', 实际得到 '%s'", tc.path, tc.message, resp.Message)
+		}
+	}
+}
+
+// 测试包装后的业务错误仍可通过 errors.As 和 errors.Is 获取原始错误
+func TestWrappedBizErrorUnwrap(t *testing.T) {
+	inner := ErrNotFound(40400, "资源不存在")
+	bizErr := toBizError(DefaultConfig, fmt.Errorf("create user: %w", inner))
+
+	if !errors.Is(bizErr, inner) {
+		t.Errorf("期望 errors.Is 能找到原始业务错误")
+	}
+
+	if len(bizErr.Errors()) != 0 || bizErr.HTTPCode() != http.StatusNotFound {
+		t.Errorf("期望沿用原始业务错误的状态码和详情, 实际得到 %d, %v", bizErr.HTTPCode(), bizErr.Errors())
+	}
+}
+
+// 测试包装原始错误的业务错误
+func TestWrapBizError(t *testing.T) {
+	r := gin.New()
+
+	errDB := errors.New("pq: duplicate key value violates unique constraint \"users_email_key\"")
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return nil, WrapBizError(40900, "邮箱已被注册", http.StatusConflict, errDB)
+	}
+
+	var logged string
+	onError := func(c *gin.Context, req any, err error) {
+		logged = fmt.Sprintf("%+v", err)
+	}
+
+	r.POST("/users", Handler(handleFunc, WithOnError(onError)))
+
+	req := httptest.NewRequest("POST", "/users", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusConflict, w.Code)
+	}
+
+	expected := `{"code":40900,"message":"邮箱已被注册"}`
+	if w.Body.String() != expected {
+		t.Errorf("期望响应为 %s, 实际得到 %s", expected, w.Body.String())
+	}
+
+	if logged != "邮箱已被注册: "+errDB.Error() {
+		t.Errorf("期望 %%+v 输出原始错误, 实际得到 '%s'", logged)
+	}
+}
+
+// 测试 WrapBizError 的 Unwrap 和格式化
+func TestWrapBizErrorUnwrap(t *testing.T) {
+	cause := errors.New("timeout")
+	bizErr := WrapBizError(50300, "服务暂不可用", http.StatusServiceUnavailable, cause)
+
+	if !errors.Is(bizErr, cause) {
+		t.Errorf("期望 errors.Is 能找到原始错误")
+	}
+
+	if got := fmt.Sprintf("%v", bizErr); got != "服务暂不可用" {
+		t.Errorf("期望 %%v 只输出消息, 实际得到 '%s'", got)
+	}
+
+	if got := fmt.Sprintf("%+v", NewBizError(40000, "参数错误", http.StatusBadRequest)); got != "参数错误" {
+		t.Errorf("期望没有原始错误时 %%+v 只输出消息, 实际得到 '%s'", got)
+	}
+}
@@ -0,0 +1,274 @@
+package apihandler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CacheStore 响应缓存存储接口，可基于内存、Redis 等实现
+type CacheStore interface {
+	// Get 获取缓存值，不存在或已过期时 ok 为 false
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	// Set 写入缓存值，ttl 小于等于 0 表示不过期
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// CacheKeyFunc 响应缓存键生成函数，返回空字符串表示不缓存该请求
+type CacheKeyFunc func(c *gin.Context) string
+
+// CacheStatusHeader 标记缓存结果的响应头，取值为 HIT、MISS 或 BYPASS
+const CacheStatusHeader = "X-Cache"
+
+// 缓存结果
+const (
+	cacheHit    = "HIT"
+	cacheMiss   = "MISS"
+	cacheBypass = "BYPASS"
+)
+
+// cacheKeyContextKey 未命中缓存时在 gin.Context 中保存缓存键
+const cacheKeyContextKey = "apihandler.cacheKey"
+
+// CacheStats 响应缓存命中统计，可并发使用
+type CacheStats struct {
+	hits     atomic.Int64
+	misses   atomic.Int64
+	bypasses atomic.Int64
+}
+
+// Hits 返回命中次数
+func (s *CacheStats) Hits() int64 {
+	return s.hits.Load()
+}
+
+// Misses 返回未命中次数
+func (s *CacheStats) Misses() int64 {
+	return s.misses.Load()
+}
+
+// Bypasses 返回客户端绕过缓存的次数
+func (s *CacheStats) Bypasses() int64 {
+	return s.bypasses.Load()
+}
+
+// HitRate 返回命中率（命中次数 / (命中次数 + 未命中次数)），没有请求时返回 0
+func (s *CacheStats) HitRate() float64 {
+	hits, misses := s.Hits(), s.Misses()
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
+
+// record 记录一次缓存结果
+func (s *CacheStats) record(result string) {
+	if s == nil {
+		return
+	}
+	switch result {
+	case cacheHit:
+		s.hits.Add(1)
+	case cacheMiss:
+		s.misses.Add(1)
+	case cacheBypass:
+		s.bypasses.Add(1)
+	}
+}
+
+// cachedResponse 缓存的响应
+type cachedResponse struct {
+	Status      int         `json:"status"`
+	ContentType string      `json:"content_type"`
+	Header      http.Header `json:"header,omitempty"` // 处理器设置的响应头，如 ListHandler 的 X-Total-Count 和 Link
+	Body        []byte      `json:"body"`
+}
+
+// uncachedHeaders 不写入缓存的响应头，这些响应头每个请求单独生成或与凭据相关
+var uncachedHeaders = map[string]bool{
+	CacheStatusHeader:  true,
+	RequestIDHeader:    true,
+	"Content-Type":     true,
+	"Content-Length":   true,
+	"Content-Encoding": true,
+	"Vary":             true,
+	"Set-Cookie":       true,
+}
+
+// cacheableHeaders 返回写入缓存的响应头
+func cacheableHeaders(c *gin.Context) http.Header {
+	var header http.Header
+	for key, values := range c.Writer.Header() {
+		if uncachedHeaders[http.CanonicalHeaderKey(key)] {
+			continue
+		}
+		if header == nil {
+			header = make(http.Header)
+		}
+		header[key] = append([]string(nil), values...)
+	}
+	return header
+}
+
+// defaultCacheKey 默认缓存键，同一 URI 不同 Accept 头协商出的格式不同，不同凭据的响应也不同
+//
+// 携带 Authorization 或 Cookie 头的请求按凭据的摘要分别缓存，凭据本身不写入缓存存储。
+func defaultCacheKey(c *gin.Context) string {
+	key := c.Request.Method + " " + c.Request.URL.RequestURI() + " " + c.GetHeader("Accept")
+	if digest := credentialDigest(c); digest != "" {
+		key += " " + digest
+	}
+	return key
+}
+
+// credentialDigest 返回请求的 Authorization 和 Cookie 头的摘要，都没有时返回空，用于按调用方区分缓存和幂等键
+func credentialDigest(c *gin.Context) string {
+	authorization := c.GetHeader("Authorization")
+	cookie := strings.Join(c.Request.Header.Values("Cookie"), "; ")
+	if authorization == "" && cookie == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(authorization + "\n" + cookie))
+	return hex.EncodeToString(sum[:])
+}
+
+// serveCachedResponse 命中缓存时输出缓存的响应并返回 true
+//
+// 请求头 Cache-Control: no-cache 跳过读取缓存但会刷新缓存，no-store 既不读取也不写入缓存。
+// 开启 WithRequestID 时成功响应中带有每个请求不同的请求 ID，不使用响应缓存。
+func serveCachedResponse(c *gin.Context, config *HandlerConfig) bool {
+	if config.ResponseCache == nil || config.RequestIDGenerator != nil {
+		return false
+	}
+	if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		return false
+	}
+
+	keyFunc := config.CacheKeyFunc
+	if keyFunc == nil {
+		keyFunc = defaultCacheKey
+	}
+	key := keyFunc(c)
+	if key == "" {
+		return false
+	}
+
+	noCache, noStore := cacheBypassDirectives(c.GetHeader("Cache-Control"))
+	if noStore || noCache {
+		config.CacheStats.record(cacheBypass)
+		c.Header(CacheStatusHeader, cacheBypass)
+		if !noStore {
+			c.Set(cacheKeyContextKey, key)
+		}
+		return false
+	}
+
+	value, ok, err := config.ResponseCache.Get(c.Request.Context(), key)
+	if err != nil {
+		c.Error(err)
+	}
+	var cached cachedResponse
+	if err == nil && ok && json.Unmarshal(value, &cached) == nil {
+		config.CacheStats.record(cacheHit)
+		c.Header(CacheStatusHeader, cacheHit)
+		responseFormat(c, config) // 与未命中时一致地输出 Vary: Accept
+		for key, values := range cached.Header {
+			c.Writer.Header()[key] = values
+		}
+		writeBody(c, config, cached.Status, cached.Status, cached.ContentType, cached.Body)
+		return true
+	}
+
+	config.CacheStats.record(cacheMiss)
+	c.Header(CacheStatusHeader, cacheMiss)
+	c.Set(cacheKeyContextKey, key)
+	return false
+}
+
+// storeCachedResponse 将编码后的成功响应写入缓存
+func storeCachedResponse(c *gin.Context, config *HandlerConfig, status int, contentType string, data []byte) {
+	if config.ResponseCache == nil {
+		return
+	}
+	key := c.GetString(cacheKeyContextKey)
+	if key == "" {
+		return
+	}
+	value, err := json.Marshal(cachedResponse{Status: status, ContentType: contentType, Header: cacheableHeaders(c), Body: data})
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if err := config.ResponseCache.Set(c.Request.Context(), key, value, config.CacheTTL); err != nil {
+		c.Error(err)
+	}
+}
+
+// cacheBypassDirectives 解析请求的 Cache-Control 头
+func cacheBypassDirectives(header string) (noCache, noStore bool) {
+	for _, directive := range strings.Split(header, ",") {
+		switch strings.ToLower(strings.TrimSpace(directive)) {
+		case "no-cache":
+			noCache = true
+		case "no-store":
+			noStore = true
+		}
+	}
+	return noCache, noStore
+}
+
+// MemoryCacheStore 基于内存的响应缓存存储，过期的缓存在读取时清除
+type MemoryCacheStore struct {
+	mu      sync.RWMutex
+	entries map[string]memoryCacheEntry
+}
+
+// memoryCacheEntry 内存缓存项
+type memoryCacheEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewMemoryCacheStore 创建内存响应缓存存储
+func NewMemoryCacheStore() *MemoryCacheStore {
+	return &MemoryCacheStore{entries: make(map[string]memoryCacheEntry)}
+}
+
+// Get 实现 CacheStore 接口
+func (s *MemoryCacheStore) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	s.mu.RLock()
+	entry, ok := s.entries[key]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, false, nil
+	}
+	if !entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt) {
+		s.mu.Lock()
+		if current, ok := s.entries[key]; ok && current.expiresAt.Equal(entry.expiresAt) {
+			delete(s.entries, key)
+		}
+		s.mu.Unlock()
+		return nil, false, nil
+	}
+	return entry.value, true, nil
+}
+
+// Set 实现 CacheStore 接口
+func (s *MemoryCacheStore) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	entry := memoryCacheEntry{value: value}
+	if ttl > 0 {
+		entry.expiresAt = time.Now().Add(ttl)
+	}
+	s.mu.Lock()
+	s.entries[key] = entry
+	s.mu.Unlock()
+	return nil
+}
@@ -0,0 +1,379 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试响应缓存命中时不再调用业务处理函数
+func TestResponseCache(t *testing.T) {
+	r := gin.New()
+
+	var calls atomic.Int64
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		n := calls.Add(1)
+		return &testResponse{ID: req.ID, Age: int(n)}, nil
+	}
+
+	stats := &CacheStats{}
+	r.GET("/test/:id", Handler(handleFunc,
+		WithResponseCache(NewMemoryCacheStore(), time.Minute, nil),
+		WithCacheStats(stats),
+	))
+
+	cases := []struct {
+		path   string
+		status string
+		body   string
+	}{
+		{"/test/1", "MISS", `{"code":0,"data":{"id":1,"name":"","age":1,"message":""}}`},
+		{"/test/1", "HIT", `{"code":0,"data":{"id":1,"name":"","age":1,"message":""}}`},
+		{"/test/2", "MISS", `{"code":0,"data":{"id":2,"name":"","age":2,"message":""}}`},
+		{"/test/1", "HIT", `{"code":0,"data":{"id":1,"name":"","age":1,"message":""}}`},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", tc.path, nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Errorf("%s: 期望状态码 %d, 实际得到 %d", tc.path, http.StatusOK, w.Code)
+		}
+
+		if got := w.Header().Get(CacheStatusHeader); got != tc.status {
+			t.Errorf("%s: 期望 X-Cache 为 '%s', 实际得到 '%s'", tc.path, tc.status, got)
+		}
+
+		if w.Body.String() != tc.body {
+			t.Errorf("%s: 期望响应为 %s, 实际得到 %s", tc.path, tc.body, w.Body.String())
+		}
+	}
+
+	if calls.Load() != 2 {
+		t.Errorf("期望业务处理函数被调用 2 次, 实际得到 %d", calls.Load())
+	}
+
+	if stats.Hits() != 2 || stats.Misses() != 2 || stats.HitRate() != 0.5 {
+		t.Errorf("期望命中 2 次、未命中 2 次、命中率 0.5, 实际得到 %d, %d, %v", stats.Hits(), stats.Misses(), stats.HitRate())
+	}
+}
+
+// 测试 Cache-Control 请求头绕过缓存
+func TestResponseCacheBypass(t *testing.T) {
+	r := gin.New()
+
+	var calls atomic.Int64
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		n := calls.Add(1)
+		return &testResponse{ID: req.ID, Age: int(n)}, nil
+	}
+
+	stats := &CacheStats{}
+	r.GET("/test/:id", Handler(handleFunc,
+		WithResponseCache(NewMemoryCacheStore(), time.Minute, nil),
+		WithCacheStats(stats),
+	))
+
+	cases := []struct {
+		cacheControl string
+		status       string
+		age          int
+	}{
+		{"", "MISS", 1},
+		{"no-store", "BYPASS", 2}, // 不写入缓存
+		{"", "HIT", 1},
+		{"no-cache", "BYPASS", 3}, // 刷新缓存
+		{"", "HIT", 3},
+	}
+
+	for i, tc := range cases {
+		req := httptest.NewRequest("GET", "/test/1", nil)
+		if tc.cacheControl != "" {
+			req.Header.Set("Cache-Control", tc.cacheControl)
+		}
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if got := w.Header().Get(CacheStatusHeader); got != tc.status {
+			t.Errorf("第 %d 个请求: 期望 X-Cache 为 '%s', 实际得到 '%s'", i+1, tc.status, got)
+		}
+
+		expected := `{"code":0,"data":{"id":1,"name":"","age":` + strconv.Itoa(tc.age) + `,"message":""}}`
+		if w.Body.String() != expected {
+			t.Errorf("第 %d 个请求: 期望响应为 %s, 实际得到 %s", i+1, expected, w.Body.String())
+		}
+	}
+
+	if stats.Bypasses() != 2 {
+		t.Errorf("期望绕过缓存 2 次, 实际得到 %d", stats.Bypasses())
+	}
+}
+
+// 测试错误响应和非 GET 请求不缓存
+func TestResponseCacheSkipsErrors(t *testing.T) {
+	r := gin.New()
+
+	var calls atomic.Int64
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		calls.Add(1)
+		if req.ID == 0 {
+			return nil, ErrNotFound(40400, "资源不存在")
+		}
+		return &testResponse{ID: req.ID}, nil
+	}
+
+	opt := WithResponseCache(NewMemoryCacheStore(), time.Minute, nil)
+	r.GET("/test/:id", Handler(handleFunc, opt))
+	r.POST("/test/:id", Handler(handleFunc, opt))
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest("GET", "/test/0", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNotFound {
+			t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusNotFound, w.Code)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest("POST", "/test/1", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Header().Get(CacheStatusHeader) != "" {
+			t.Errorf("期望 POST 请求不输出 X-Cache, 实际得到 '%s'", w.Header().Get(CacheStatusHeader))
+		}
+	}
+
+	if calls.Load() != 4 {
+		t.Errorf("期望业务处理函数被调用 4 次, 实际得到 %d", calls.Load())
+	}
+}
+
+// 测试自定义缓存键和缓存过期
+func TestResponseCacheKeyFuncAndTTL(t *testing.T) {
+	r := gin.New()
+
+	var calls atomic.Int64
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		calls.Add(1)
+		return &testResponse{ID: req.ID}, nil
+	}
+
+	// 按 ID 缓存，忽略查询参数
+	keyFunc := func(c *gin.Context) string {
+		return "test:" + c.Param("id")
+	}
+	r.GET("/test/:id", Handler(handleFunc, WithResponseCache(NewMemoryCacheStore(), 20*time.Millisecond, keyFunc)))
+
+	for _, path := range []string{"/test/1?name=a", "/test/1?name=b"} {
+		req := httptest.NewRequest("GET", path, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("期望业务处理函数被调用 1 次, 实际得到 %d", calls.Load())
+	}
+
+	time.Sleep(30 * time.Millisecond)
+
+	req := httptest.NewRequest("GET", "/test/1", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if got := w.Header().Get(CacheStatusHeader); got != "MISS" {
+		t.Errorf("期望缓存过期后 X-Cache 为 'MISS', 实际得到 '%s'", got)
+	}
+
+	if calls.Load() != 2 {
+		t.Errorf("期望业务处理函数被调用 2 次, 实际得到 %d", calls.Load())
+	}
+}
+
+// 测试默认缓存键区分不同凭据的请求
+func TestResponseCacheCredentials(t *testing.T) {
+	r := gin.New()
+
+	var calls atomic.Int64
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		calls.Add(1)
+		return &testResponse{ID: req.ID}, nil
+	}
+	store := NewMemoryCacheStore()
+	r.GET("/test/:id", Handler(handleFunc, WithResponseCache(store, time.Minute, nil)))
+
+	cases := []struct {
+		name   string
+		header string
+		value  string
+		status string
+	}{
+		{"用户 a 填充缓存", "Authorization", "Bearer a", "MISS"},
+		{"用户 b 不命中用户 a 的缓存", "Authorization", "Bearer b", "MISS"},
+		{"用户 a 命中自己的缓存", "Authorization", "Bearer a", "HIT"},
+		{"Cookie 会话不命中其他凭据的缓存", "Cookie", "session=a", "MISS"},
+		{"没有凭据的请求不命中其他凭据的缓存", "", "", "MISS"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/test/1", nil)
+			if tc.header != "" {
+				req.Header.Set(tc.header, tc.value)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if got := w.Header().Get(CacheStatusHeader); got != tc.status {
+				t.Errorf("期望 X-Cache 为 '%s', 实际得到 '%s'", tc.status, got)
+			}
+		})
+	}
+	if calls.Load() != 4 {
+		t.Errorf("期望业务处理函数被调用 4 次, 实际得到 %d", calls.Load())
+	}
+
+	for key := range store.entries {
+		if strings.Contains(key, "Bearer") || strings.Contains(key, "session") {
+			t.Errorf("期望缓存键中不包含凭据, 实际得到 %q", key)
+		}
+	}
+}
+
+// 测试缓存的响应不返回给权限不足的请求
+func TestResponseCacheChecksScopes(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return &testResponse{ID: req.ID, Name: "secret"}, nil
+	}
+	scopes := func(c *gin.Context) ([]string, error) {
+		return []string{c.GetHeader("X-Scope")}, nil
+	}
+	owner := func(ctx context.Context, req *testRequest) error {
+		if req.Name != "owner" {
+			return ErrForbidden(40300, "forbidden")
+		}
+		return nil
+	}
+	r.GET("/scoped/:id", Handler(handleFunc,
+		WithResponseCache(NewMemoryCacheStore(), time.Minute, nil),
+		WithScopesFunc(scopes),
+		WithRequiredScopes("admin"),
+	))
+	r.GET("/owned/:id", Handler(handleFunc,
+		WithResponseCache(NewMemoryCacheStore(), time.Minute, func(c *gin.Context) string { return c.Request.URL.Path }),
+		WithAuthorize(owner),
+	))
+
+	cases := []struct {
+		name   string
+		target string
+		scope  string
+		code   int
+	}{
+		{"有权限的请求填充缓存", "/scoped/1", "admin", http.StatusOK},
+		{"权限不足的请求不命中缓存", "/scoped/1", "user", http.StatusForbidden},
+		{"授权通过的请求", "/owned/1?name=owner", "", http.StatusOK},
+		{"授权函数拒绝的请求不使用缓存", "/owned/1?name=other", "", http.StatusForbidden},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tc.target, nil)
+			req.Header.Set("X-Scope", tc.scope)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tc.code {
+				t.Errorf("期望状态码 %d, 实际得到 %d: %s", tc.code, w.Code, w.Body.String())
+			}
+		})
+	}
+}
+
+// 测试命中缓存时输出处理器设置的响应头
+func TestResponseCacheHeaders(t *testing.T) {
+	r := gin.New()
+
+	var calls atomic.Int64
+	listFunc := func(ctx context.Context, req *listUsersRequest) ([]testResponse, int64, error) {
+		calls.Add(1)
+		return []testResponse{{ID: 1}, {ID: 2}}, 95, nil
+	}
+	r.GET("/users", ListHandler(listFunc, WithResponseCache(NewMemoryCacheStore(), time.Minute, nil), WithETag()))
+
+	var first *httptest.ResponseRecorder
+	for _, status := range []string{"MISS", "HIT"} {
+		req := httptest.NewRequest("GET", "/users?page=2&per_page=10", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if got := w.Header().Get(CacheStatusHeader); got != status {
+			t.Fatalf("期望 X-Cache 为 '%s', 实际得到 '%s'", status, got)
+		}
+		if first == nil {
+			first = w
+			continue
+		}
+		for _, key := range []string{"X-Total-Count", "Link", "ETag"} {
+			if got, want := w.Header().Get(key), first.Header().Get(key); got == "" || got != want {
+				t.Errorf("期望命中缓存时 %s 为 %q, 实际得到 %q", key, want, got)
+			}
+		}
+		if w.Body.String() != first.Body.String() {
+			t.Errorf("期望命中缓存时响应为 %s, 实际得到 %s", first.Body.String(), w.Body.String())
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("期望业务处理函数被调用 1 次, 实际得到 %d", calls.Load())
+	}
+}
+
+// 测试开启请求 ID 时不缓存带有请求 ID 的成功响应
+func TestResponseCacheRequestID(t *testing.T) {
+	r := gin.New()
+
+	var calls atomic.Int64
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		calls.Add(1)
+		return &testResponse{ID: req.ID}, nil
+	}
+	r.GET("/test/:id", Handler(handleFunc, WithResponseCache(NewMemoryCacheStore(), time.Minute, nil), WithRequestID(nil)))
+
+	ids := map[string]bool{}
+	for range 2 {
+		req := httptest.NewRequest("GET", "/test/1", nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if got := w.Header().Get(CacheStatusHeader); got != "" {
+			t.Errorf("期望不输出 X-Cache, 实际得到 '%s'", got)
+		}
+		var resp struct {
+			RequestID string `json:"request_id"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("解析响应失败: %v", err)
+		}
+		if resp.RequestID != w.Header().Get(RequestIDHeader) {
+			t.Errorf("期望响应中的请求 ID 为 %s, 实际得到 %s", w.Header().Get(RequestIDHeader), resp.RequestID)
+		}
+		ids[resp.RequestID] = true
+	}
+	if len(ids) != 2 || calls.Load() != 2 {
+		t.Errorf("期望每个请求都有不同的请求 ID 且调用业务处理函数, 实际得到 %d 个请求 ID, 调用 %d 次", len(ids), calls.Load())
+	}
+}
@@ -0,0 +1,86 @@
+package apihandler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// ErrorCatalog 错误码目录，集中登记错误码的 HTTP 状态码和各语言的消息
+//
+// 在服务启动时登记一次错误码，业务处理函数通过 New 创建业务错误，
+// 消息按处理器为当前请求确定的语言环境翻译，避免在各处硬编码错误消息。
+type ErrorCatalog struct {
+	mu            sync.RWMutex
+	defaultLocale string
+	entries       map[any]catalogEntry
+}
+
+// catalogEntry 错误码目录中的一项
+type catalogEntry struct {
+	httpCode int
+	messages map[string]string
+}
+
+// NewErrorCatalog 创建错误码目录，defaultLocale 为找不到请求语言的消息时使用的语言
+func NewErrorCatalog(defaultLocale string) *ErrorCatalog {
+	return &ErrorCatalog{
+		defaultLocale: defaultLocale,
+		entries:       make(map[any]catalogEntry),
+	}
+}
+
+// Register 登记错误码，messages 的键为语言环境（如 "zh"、"en"），值为 fmt 格式的消息模板
+//
+// 重复登记时覆盖之前的登记。
+func (c *ErrorCatalog) Register(code any, httpCode int, messages map[string]string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[code] = catalogEntry{httpCode: httpCode, messages: messages}
+}
+
+// New 创建已登记错误码的业务错误，args 为消息模板的参数
+//
+// 消息使用 ctx 中的语言环境（见 LocaleFromContext），未登记的错误码视为内部服务器错误。
+func (c *ErrorCatalog) New(ctx context.Context, code any, args ...any) BizError {
+	c.mu.RLock()
+	entry, ok := c.entries[code]
+	c.mu.RUnlock()
+	if !ok {
+		return &internalError{cause: fmt.Errorf("apihandler: error code %v is not registered in catalog", code)}
+	}
+
+	message := c.message(entry, LocaleFromContext(ctx))
+	if message == "" {
+		message = fmt.Sprintf("error %v", code)
+	} else if len(args) > 0 {
+		message = fmt.Sprintf(message, args...)
+	}
+	return NewBizError(code, message, entry.httpCode)
+}
+
+// HTTPCode 返回已登记错误码的 HTTP 状态码，未登记时返回 500
+func (c *ErrorCatalog) HTTPCode(code any) int {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if entry, ok := c.entries[code]; ok {
+		return entry.httpCode
+	}
+	return http.StatusInternalServerError
+}
+
+// message 按语言环境选择消息模板，依次尝试完整语言标签（如 zh-TW）、语言代码（如 zh）和默认语言
+func (c *ErrorCatalog) message(entry catalogEntry, locale string) string {
+	locale = strings.ReplaceAll(locale, "_", "-")
+	if message, ok := entry.messages[locale]; ok {
+		return message
+	}
+	if lang, _, found := strings.Cut(locale, "-"); found {
+		if message, ok := entry.messages[lang]; ok {
+			return message
+		}
+	}
+	return entry.messages[c.defaultLocale]
+}
@@ -0,0 +1,95 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试用的错误码
+const (
+	codeUserNotFound = 40401
+	codeUserBanned   = 40301
+)
+
+// 测试按请求语言翻译错误码目录中的消息
+func TestErrorCatalog(t *testing.T) {
+	catalog := NewErrorCatalog("zh")
+	catalog.Register(codeUserNotFound, http.StatusNotFound, map[string]string{
+		"zh":    "用户 %d 不存在",
+		"zh-TW": "使用者 %d 不存在",
+		"en":    "User %d not found",
+	})
+	catalog.Register(codeUserBanned, http.StatusForbidden, map[string]string{
+		"zh": "用户已被封禁",
+	})
+
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		if req.Name == "banned" {
+			return nil, catalog.New(ctx, codeUserBanned)
+		}
+		return nil, catalog.New(ctx, codeUserNotFound, req.ID)
+	}
+
+	localeFunc := func(r *http.Request) string {
+		return r.Header.Get("Accept-Language")
+	}
+
+	r.GET("/users/:id", Handler(handleFunc, WithLocaleFunc(localeFunc)))
+
+	cases := []struct {
+		path     string
+		locale   string
+		httpCode int
+		message  string
+	}{
+		{"/users/7", "zh", http.StatusNotFound, "用户 7 不存在"},
+		{"/users/7", "en", http.StatusNotFound, "User 7 not found"},
+		{"/users/7", "en-GB", http.StatusNotFound, "User 7 not found"},
+		{"/users/7", "zh_TW", http.StatusNotFound, "使用者 7 不存在"},
+		{"/users/7", "ja", http.StatusNotFound, "用户 7 不存在"},
+		{"/users/7?name=banned", "en", http.StatusForbidden, "用户已被封禁"},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", tc.path, nil)
+		req.Header.Set("Accept-Language", tc.locale)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.httpCode {
+			t.Errorf("%s (%s): 期望状态码 %d, 实际得到 %d", tc.path, tc.locale, tc.httpCode, w.Code)
+		}
+
+		var resp ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("解析响应失败: %v", err)
+		}
+
+		if resp.Message != tc.message {
+			t.Errorf("%s (%s): 期望 message 为 '%s', 实际得到 '%s'", tc.path, tc.locale, tc.message, resp.Message)
+		}
+	}
+}
+
+// 测试未登记的错误码视为内部服务器错误
+func TestErrorCatalogUnregistered(t *testing.T) {
+	catalog := NewErrorCatalog("zh")
+
+	bizErr := catalog.New(context.Background(), 99999)
+
+	if bizErr.HTTPCode() != http.StatusInternalServerError {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusInternalServerError, bizErr.HTTPCode())
+	}
+
+	if catalog.HTTPCode(99999) != http.StatusInternalServerError {
+		t.Errorf("期望未登记错误码的状态码为 %d, 实际得到 %d", http.StatusInternalServerError, catalog.HTTPCode(99999))
+	}
+}
@@ -0,0 +1,215 @@
+package apihandler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CircuitBreaker 熔断器接口，与 sony/gobreaker 的 *gobreaker.CircuitBreaker 兼容
+//
+// Execute 在熔断状态下不调用 req 并直接返回错误，此时处理器返回 503 错误响应。
+type CircuitBreaker interface {
+	Execute(req func() (any, error)) (any, error)
+}
+
+// WithCircuitBreaker 设置熔断器，下游持续失败时快速返回 503 错误响应，避免请求堆积
+//
+// 只有 5xx 错误（包括 panic 和超时）计为失败，4xx 业务错误和客户端取消不影响熔断器状态。
+func WithCircuitBreaker(cb CircuitBreaker) Option {
+	return func(c *HandlerConfig) {
+		c.CircuitBreaker = cb
+	}
+}
+
+// invokeWithCircuitBreaker 通过熔断器调用业务处理函数
+func invokeWithCircuitBreaker[T any, R any](c *gin.Context, config *HandlerConfig, translator Translator, handleFunc HandleFunc[T, R], req *T) (*R, error) {
+	var (
+		called    bool
+		resp      *R
+		handleErr error
+	)
+	_, err := config.CircuitBreaker.Execute(func() (any, error) {
+		called = true
+		resp, handleErr = executeHandleFunc(c, config, translator, handleFunc, req)
+		if isBreakerFailure(c, config, handleErr) {
+			return nil, handleErr
+		}
+		return nil, nil
+	})
+	if !called {
+		msg := translator.Translate(MsgServiceUnavailable)
+		return nil, WrapBizError(http.StatusServiceUnavailable, msg, http.StatusServiceUnavailable, err)
+	}
+	return resp, handleErr
+}
+
+// isBreakerFailure 判断错误是否计为熔断器的失败
+func isBreakerFailure(c *gin.Context, config *HandlerConfig, err error) bool {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return false
+	}
+	bizErr := toBizError(config, err)
+	if len(config.SentinelErrors) > 0 {
+		bizErr = mapSentinelError(c, config, bizErr)
+	}
+	if len(config.CodeRanges) > 0 {
+		bizErr = inferHTTPCode(config, bizErr)
+	}
+	httpCode := bizErr.HTTPCode()
+	return httpCode == 0 || httpCode >= http.StatusInternalServerError
+}
+
+// BreakerState 熔断器状态
+type BreakerState int
+
+const (
+	BreakerClosed   BreakerState = iota // 关闭，请求正常通过
+	BreakerHalfOpen                     // 半开，允许一个探测请求通过
+	BreakerOpen                         // 打开，请求直接失败
+)
+
+// String 返回状态名称
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerHalfOpen:
+		return "half-open"
+	case BreakerOpen:
+		return "open"
+	}
+	return "unknown"
+}
+
+// ErrCircuitOpen 熔断器处于打开状态
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerSettings 内置熔断器的设置
+type BreakerSettings struct {
+	Name          string                                   // 熔断器名称，传给 OnStateChange
+	MaxFailures   int                                      // 连续失败次数达到该值时打开熔断器，默认 5
+	OpenTimeout   time.Duration                            // 打开状态的持续时间，之后进入半开状态，默认 30 秒
+	OnStateChange func(name string, from, to BreakerState) // 状态变化时的回调函数，用于记录日志或告警
+}
+
+// SimpleCircuitBreaker 基于连续失败次数的内置熔断器，并发安全
+//
+// 连续失败 MaxFailures 次后打开，OpenTimeout 后进入半开状态并放行一个探测请求，
+// 探测成功时关闭，失败时重新打开。状态变化之前开始的请求的结果被忽略。
+type SimpleCircuitBreaker struct {
+	settings BreakerSettings
+	now      func() time.Time
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+	// generation 每次状态变化时加一，请求结束时与开始时记录的值不同则忽略其结果
+	generation uint64
+}
+
+// NewSimpleCircuitBreaker 创建内置熔断器
+func NewSimpleCircuitBreaker(settings BreakerSettings) *SimpleCircuitBreaker {
+	if settings.MaxFailures <= 0 {
+		settings.MaxFailures = 5
+	}
+	if settings.OpenTimeout <= 0 {
+		settings.OpenTimeout = 30 * time.Second
+	}
+	return &SimpleCircuitBreaker{settings: settings, now: time.Now}
+}
+
+// State 返回熔断器的当前状态
+func (b *SimpleCircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	state, from := b.currentState()
+	b.mu.Unlock()
+	b.notify(from, state)
+	return state
+}
+
+// Execute 实现 CircuitBreaker 接口
+func (b *SimpleCircuitBreaker) Execute(req func() (any, error)) (any, error) {
+	b.mu.Lock()
+	state, from := b.currentState()
+	if state == BreakerOpen || (state == BreakerHalfOpen && b.probing) {
+		b.mu.Unlock()
+		b.notify(from, state)
+		return nil, ErrCircuitOpen
+	}
+	b.probing = state == BreakerHalfOpen
+	generation := b.generation
+	b.mu.Unlock()
+	b.notify(from, state)
+
+	failed := true
+	defer func() {
+		b.done(generation, failed)
+	}()
+	result, err := req()
+	failed = err != nil
+	return result, err
+}
+
+// done 记录请求结果并更新状态，请求开始后状态已经变化时忽略结果
+//
+// 如熔断器打开前开始的慢请求在半开状态下返回，其结果不能代替探测请求关闭熔断器或结束探测。
+func (b *SimpleCircuitBreaker) done(generation uint64, failed bool) {
+	b.mu.Lock()
+	if generation != b.generation {
+		b.mu.Unlock()
+		return
+	}
+	from := b.state
+	b.probing = false
+	switch {
+	case !failed:
+		b.failures = 0
+		if from != BreakerClosed {
+			b.state = BreakerClosed
+			b.generation++
+		}
+	case from == BreakerHalfOpen:
+		b.open()
+	default:
+		b.failures++
+		if b.failures >= b.settings.MaxFailures {
+			b.open()
+		}
+	}
+	to := b.state
+	b.mu.Unlock()
+	b.notify(from, to)
+}
+
+// open 打开熔断器，调用时需持有锁
+func (b *SimpleCircuitBreaker) open() {
+	b.state = BreakerOpen
+	b.failures = 0
+	b.openedAt = b.now()
+	b.generation++
+}
+
+// currentState 返回当前状态，打开状态超时后转换为半开状态，调用时需持有锁
+func (b *SimpleCircuitBreaker) currentState() (state, from BreakerState) {
+	from = b.state
+	if b.state == BreakerOpen && b.now().Sub(b.openedAt) >= b.settings.OpenTimeout {
+		b.state = BreakerHalfOpen
+		b.probing = false
+		b.generation++
+	}
+	return b.state, from
+}
+
+// notify 状态变化时调用 OnStateChange
+func (b *SimpleCircuitBreaker) notify(from, to BreakerState) {
+	if from != to && b.settings.OnStateChange != nil {
+		b.settings.OnStateChange(b.settings.Name, from, to)
+	}
+}
@@ -0,0 +1,165 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试熔断器打开后快速返回 503 错误响应
+func TestCircuitBreaker(t *testing.T) {
+	r := gin.New()
+
+	now := time.Unix(1700000000, 0)
+	var changes []string
+	breaker := NewSimpleCircuitBreaker(BreakerSettings{
+		Name:        "downstream",
+		MaxFailures: 2,
+		OpenTimeout: time.Minute,
+		OnStateChange: func(name string, from, to BreakerState) {
+			changes = append(changes, name+":"+from.String()+"->"+to.String())
+		},
+	})
+	breaker.now = func() time.Time { return now }
+
+	calls := 0
+	var handleErr error
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		calls++
+		return &testResponse{}, handleErr
+	}
+	r.GET("/users", Handler(handleFunc, WithCircuitBreaker(breaker)))
+
+	send := func() *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", "/users", nil))
+		return w
+	}
+
+	// 4xx 业务错误不计为失败
+	handleErr = ErrNotFound(404, "资源不存在")
+	for i := 0; i < 3; i++ {
+		send()
+	}
+	if breaker.State() != BreakerClosed {
+		t.Fatalf("期望 4xx 业务错误不打开熔断器, 实际状态 %s", breaker.State())
+	}
+
+	handleErr = errors.New("downstream unavailable")
+	send()
+	send()
+	if breaker.State() != BreakerOpen {
+		t.Fatalf("期望连续失败 2 次后熔断器打开, 实际状态 %s", breaker.State())
+	}
+
+	calls = 0
+	w := send()
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("期望状态码 %d, 实际得到 %d", http.StatusServiceUnavailable, w.Code)
+	}
+	if calls != 0 {
+		t.Errorf("期望熔断器打开时不调用业务处理函数, 实际调用 %d 次", calls)
+	}
+	var resp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	if resp.Message != "服务暂时不可用，请稍后重试" {
+		t.Errorf("期望翻译后的服务不可用消息, 实际得到 '%s'", resp.Message)
+	}
+
+	// 超过打开时间后放行探测请求，成功时关闭
+	now = now.Add(time.Minute)
+	handleErr = nil
+	if w := send(); w.Code != http.StatusOK || calls != 1 {
+		t.Errorf("期望半开状态放行探测请求, 实际状态码 %d, 调用 %d 次", w.Code, calls)
+	}
+	if breaker.State() != BreakerClosed {
+		t.Errorf("期望探测成功后熔断器关闭, 实际状态 %s", breaker.State())
+	}
+
+	want := []string{"downstream:closed->open", "downstream:open->half-open", "downstream:half-open->closed"}
+	if len(changes) != len(want) {
+		t.Fatalf("期望状态变化 %v, 实际得到 %v", want, changes)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("期望第 %d 次状态变化为 %s, 实际得到 %s", i, want[i], changes[i])
+		}
+	}
+}
+
+// 测试半开状态探测失败时重新打开，且只放行一个探测请求
+func TestSimpleCircuitBreakerHalfOpen(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	breaker := NewSimpleCircuitBreaker(BreakerSettings{MaxFailures: 1, OpenTimeout: time.Second})
+	breaker.now = func() time.Time { return now }
+
+	failure := errors.New("failure")
+	breaker.Execute(func() (any, error) { return nil, failure })
+	if _, err := breaker.Execute(func() (any, error) { return nil, nil }); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("期望熔断器打开时返回 ErrCircuitOpen, 实际得到 %v", err)
+	}
+
+	now = now.Add(time.Second)
+	_, err := breaker.Execute(func() (any, error) {
+		if _, err := breaker.Execute(func() (any, error) { return nil, nil }); !errors.Is(err, ErrCircuitOpen) {
+			t.Errorf("期望探测请求执行期间拒绝其他请求, 实际得到 %v", err)
+		}
+		return nil, failure
+	})
+	if !errors.Is(err, failure) {
+		t.Errorf("期望返回探测请求的错误, 实际得到 %v", err)
+	}
+	if breaker.State() != BreakerOpen {
+		t.Errorf("期望探测失败后熔断器重新打开, 实际状态 %s", breaker.State())
+	}
+}
+
+// 测试状态变化之前开始的请求的结果被忽略
+func TestSimpleCircuitBreakerStaleResult(t *testing.T) {
+	var clock atomic.Int64
+	breaker := NewSimpleCircuitBreaker(BreakerSettings{MaxFailures: 1, OpenTimeout: time.Second})
+	breaker.now = func() time.Time { return time.Unix(clock.Load(), 0) }
+	failure := errors.New("failure")
+
+	// 慢请求在熔断器关闭时开始
+	started, release, finished := make(chan struct{}), make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(finished)
+		breaker.Execute(func() (any, error) {
+			close(started)
+			<-release
+			return nil, nil
+		})
+	}()
+	<-started
+
+	breaker.Execute(func() (any, error) { return nil, failure })
+	clock.Add(1)
+	_, err := breaker.Execute(func() (any, error) {
+		// 慢请求在探测期间成功返回
+		close(release)
+		<-finished
+		if state := breaker.State(); state != BreakerHalfOpen {
+			t.Errorf("期望慢请求的结果不关闭熔断器, 实际状态 %s", state)
+		}
+		if _, err := breaker.Execute(func() (any, error) { return nil, nil }); !errors.Is(err, ErrCircuitOpen) {
+			t.Errorf("期望慢请求返回后仍然只放行探测请求, 实际得到 %v", err)
+		}
+		return nil, failure
+	})
+	if !errors.Is(err, failure) {
+		t.Errorf("期望返回探测请求的错误, 实际得到 %v", err)
+	}
+	if breaker.State() != BreakerOpen {
+		t.Errorf("期望探测失败后熔断器重新打开, 实际状态 %s", breaker.State())
+	}
+}
@@ -0,0 +1,164 @@
+package apihandler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClaimTag 认证声明的 tag 名称
+const ClaimTag = "claim"
+
+// ClaimsExtractor 从请求中获取认证声明的函数，如读取 JWT 中间件保存在 gin.Context 中的 claims
+//
+// 返回错误表示请求未认证，错误为业务错误时直接使用，否则返回 401 错误响应。
+type ClaimsExtractor func(c *gin.Context) (map[string]any, error)
+
+// WithClaims 设置认证声明的获取函数，请求结构中带有 claim tag 的字段（如 `claim:"sub"`）会在参数绑定后、验证前被填充
+//
+// 带有 claim tag 的字段始终由认证声明决定，客户端传入的同名参数会被覆盖；没有对应声明时字段保持零值。
+// binding tag 的验证规则（如 `claim:"sub" binding:"required"`）按声明的值验证。
+func WithClaims(extractor ClaimsExtractor) Option {
+	return func(c *HandlerConfig) {
+		c.ClaimsExtractor = extractor
+	}
+}
+
+// ClaimsFromContextKey 返回读取 gin.Context 中 key 对应的认证声明的函数，值可以是 map[string]any 或 map[string]string
+func ClaimsFromContextKey(key string) ClaimsExtractor {
+	return func(c *gin.Context) (map[string]any, error) {
+		switch claims := c.Value(key).(type) {
+		case map[string]any:
+			return claims, nil
+		case gin.H:
+			return claims, nil
+		case map[string]string:
+			result := make(map[string]any, len(claims))
+			for k, v := range claims {
+				result[k] = v
+			}
+			return result, nil
+		}
+		return nil, errors.New("no claims in context")
+	}
+}
+
+// bindClaims 将认证声明填充到请求结构中带有 claim tag 的字段，请求结构中有 claim 字段时 claimed 为 true
+func bindClaims(c *gin.Context, config *HandlerConfig, translator Translator, req any) (claimed bool, err error) {
+	reqType := reflect.TypeOf(req).Elem()
+	if reqType.Kind() != reflect.Struct {
+		return false, nil
+	}
+	reqValue := reflect.ValueOf(req).Elem()
+
+	var claims map[string]any
+	for i := 0; i < reqType.NumField(); i++ {
+		field := reqType.Field(i)
+		name := field.Tag.Get(ClaimTag)
+		if name == "" || name == "-" || !reqValue.Field(i).CanSet() {
+			continue
+		}
+
+		if claims == nil {
+			var err error
+			if claims, err = config.ClaimsExtractor(c); err != nil {
+				var bizErr BizError
+				if errors.As(err, &bizErr) {
+					return true, err
+				}
+				return true, WrapBizError(http.StatusUnauthorized, translator.Translate(MsgUnauthorized), http.StatusUnauthorized, err)
+			}
+			if claims == nil {
+				claims = map[string]any{}
+			}
+		}
+
+		// 先清空客户端通过请求体或查询参数传入的值，没有对应声明时字段为零值
+		reqValue.Field(i).SetZero()
+		value, ok := claims[name]
+		if !ok || value == nil {
+			continue
+		}
+		if err := assignClaim(reqValue.Field(i), value); err != nil {
+			return true, fmt.Errorf("claim %q: %w", name, err)
+		}
+	}
+	return claims != nil, nil
+}
+
+// assignClaim 将认证声明的值赋给字段，支持 JSON 解码得到的数字、字符串、布尔值和列表
+func assignClaim(field reflect.Value, value any) error {
+	v := reflect.ValueOf(value)
+	if v.Type().AssignableTo(field.Type()) {
+		field.Set(v)
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(field.Type().Elem())
+		if err := assignClaim(elem.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	case reflect.String:
+		if v.Kind() == reflect.String {
+			field.SetString(v.String())
+		} else {
+			field.SetString(fmt.Sprint(value))
+		}
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if isNumberKind(v.Kind()) {
+			field.Set(v.Convert(field.Type()))
+			return nil
+		}
+		if v.Kind() == reflect.String {
+			n, err := strconv.ParseFloat(v.String(), 64)
+			if err != nil {
+				return err
+			}
+			field.Set(reflect.ValueOf(n).Convert(field.Type()))
+			return nil
+		}
+	case reflect.Bool:
+		if v.Kind() == reflect.String {
+			b, err := strconv.ParseBool(v.String())
+			if err != nil {
+				return err
+			}
+			field.SetBool(b)
+			return nil
+		}
+	case reflect.Slice:
+		if v.Kind() == reflect.Slice || v.Kind() == reflect.Array {
+			slice := reflect.MakeSlice(field.Type(), v.Len(), v.Len())
+			for i := 0; i < v.Len(); i++ {
+				if err := assignClaim(slice.Index(i), v.Index(i).Interface()); err != nil {
+					return err
+				}
+			}
+			field.Set(slice)
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot assign %T to %s", value, field.Type())
+}
+
+// isNumberKind 判断是否为数字类型
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
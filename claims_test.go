@@ -0,0 +1,205 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// claimsRequest 带有认证声明字段的请求
+type claimsRequest struct {
+	UserID  int64    `claim:"sub" form:"user_id"`
+	Email   *string  `claim:"email"`
+	Scopes  []string `claim:"scope"`
+	IsAdmin bool     `claim:"admin"`
+	Name    string   `form:"name"`
+}
+
+// 测试将认证声明填充到请求结构
+func TestClaims(t *testing.T) {
+	r := gin.New()
+
+	var got claimsRequest
+	handleFunc := func(ctx context.Context, req *claimsRequest) (*testResponse, error) {
+		got = *req
+		return &testResponse{}, nil
+	}
+	r.GET("/profile", func(c *gin.Context) {
+		if c.GetHeader("Authorization") != "" {
+			c.Set("claims", map[string]any{
+				"sub":   float64(42),
+				"email": "user@example.com",
+				"scope": []any{"user:read", "user:write"},
+				"admin": "true",
+			})
+		}
+	}, Handler(handleFunc, WithClaims(ClaimsFromContextKey("claims"))))
+
+	req := httptest.NewRequest("GET", "/profile?user_id=1&name=test", nil)
+	req.Header.Set("Authorization", "Bearer token")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("期望状态码 %d, 实际得到 %d, 响应 %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if got.UserID != 42 {
+		t.Errorf("期望 UserID 由声明决定为 42, 实际得到 %d", got.UserID)
+	}
+	if got.Email == nil || *got.Email != "user@example.com" {
+		t.Errorf("期望 Email 为 user@example.com, 实际得到 %v", got.Email)
+	}
+	if strings.Join(got.Scopes, ",") != "user:read,user:write" || !got.IsAdmin || got.Name != "test" {
+		t.Errorf("期望填充 Scopes 和 IsAdmin 并保留其他参数, 实际得到 %+v", got)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/profile", nil))
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("期望没有声明时状态码 %d, 实际得到 %d", http.StatusUnauthorized, w.Code)
+	}
+	var resp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	if resp.Message != "未登录或登录已过期" {
+		t.Errorf("期望翻译后的未认证消息, 实际得到 '%s'", resp.Message)
+	}
+}
+
+// 测试认证声明类型不匹配和业务错误
+func TestClaimsErrors(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *claimsRequest) (*testResponse, error) {
+		return &testResponse{}, nil
+	}
+	r.GET("/mismatch", Handler(handleFunc, WithClaims(func(c *gin.Context) (map[string]any, error) {
+		return map[string]any{"sub": map[string]any{}}, nil
+	})))
+	r.GET("/forbidden", Handler(handleFunc, WithClaims(func(c *gin.Context) (map[string]any, error) {
+		return nil, ErrForbidden(40300, "账号已禁用")
+	})))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/mismatch", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("期望类型不匹配时状态码 %d, 实际得到 %d", http.StatusInternalServerError, w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/forbidden", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("期望直接使用业务错误, 实际状态码 %d", w.Code)
+	}
+}
+
+// 测试没有对应声明时客户端不能通过请求参数伪造声明字段
+func TestClaimsSpoofing(t *testing.T) {
+	type spoofRequest struct {
+		UserID string `claim:"user_id" json:"user_id" form:"user_id"`
+		Role   string `claim:"role" json:"role"`
+		Name   string `json:"name"`
+	}
+
+	r := gin.New()
+	var got spoofRequest
+	handleFunc := func(ctx context.Context, req *spoofRequest) (*testResponse, error) {
+		got = *req
+		return &testResponse{}, nil
+	}
+	r.POST("/orders", Handler(handleFunc, WithClaims(func(c *gin.Context) (map[string]any, error) {
+		return map[string]any{"role": nil}, nil
+	})))
+
+	req := httptest.NewRequest("POST", "/orders?user_id=root", strings.NewReader(`{"user_id":"admin","role":"admin","name":"test"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("期望状态码 %d, 实际得到 %d, 响应 %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if got.UserID != "" || got.Role != "" {
+		t.Errorf("期望没有声明的字段为零值, 实际得到 %+v", got)
+	}
+	if got.Name != "test" {
+		t.Errorf("期望保留其他参数, 实际得到 %+v", got)
+	}
+}
+
+// requiredClaimRequest 必填的认证声明字段
+type requiredClaimRequest struct {
+	UserID string `claim:"sub" json:"user_id" binding:"required"`
+	Name   string `json:"name"`
+}
+
+// boundClaimRequest 手写的 RequestBinder，与生成的代码一样在绑定后验证所有字段
+type boundClaimRequest struct {
+	UserID string `claim:"sub" json:"-" binding:"required"`
+	Name   string `json:"name"`
+}
+
+// BindRequest 实现 RequestBinder 接口
+func (r *boundClaimRequest) BindRequest(c *gin.Context) error {
+	return binding.Validator.ValidateStruct(r)
+}
+
+// 测试带有验证规则的 claim 字段按声明的值验证
+func TestClaimsValidation(t *testing.T) {
+	claims := func(c *gin.Context) (map[string]any, error) {
+		if sub := c.GetHeader("X-Sub"); sub != "" {
+			return map[string]any{"sub": sub}, nil
+		}
+		return map[string]any{}, nil
+	}
+
+	r := gin.New()
+	r.POST("/reflect", Handler(func(ctx context.Context, req *requiredClaimRequest) (*requiredClaimRequest, error) {
+		return req, nil
+	}, WithClaims(claims)))
+	r.POST("/generated", Handler(func(ctx context.Context, req *boundClaimRequest) (*requiredClaimRequest, error) {
+		return &requiredClaimRequest{UserID: req.UserID, Name: req.Name}, nil
+	}, WithClaims(claims)))
+
+	testCases := []struct {
+		name   string
+		target string
+		sub    string
+		code   int
+	}{
+		{"反射绑定时客户端未传入但有声明", "/reflect", "42", http.StatusOK},
+		{"反射绑定时没有声明", "/reflect", "", http.StatusBadRequest},
+		{"RequestBinder 绑定时客户端未传入但有声明", "/generated", "42", http.StatusOK},
+		{"RequestBinder 绑定时没有声明", "/generated", "", http.StatusBadRequest},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", tc.target, strings.NewReader(`{"name":"test"}`))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("X-Sub", tc.sub)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tc.code {
+				t.Fatalf("期望状态码 %d, 实际得到 %d, 响应 %s", tc.code, w.Code, w.Body.String())
+			}
+			if tc.code != http.StatusOK {
+				return
+			}
+			var resp SuccessResponse[requiredClaimRequest]
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("解析响应失败: %v", err)
+			}
+			if resp.Data.UserID != tc.sub || resp.Data.Name != "test" {
+				t.Errorf("期望 user_id 为 %s、name 为 test, 实际得到 %+v", tc.sub, *resp.Data)
+			}
+		})
+	}
+}
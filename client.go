@@ -0,0 +1,241 @@
+package apihandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// Client 调用 apihandler 服务的 HTTP 客户端，GenerateGoClient 生成的客户端基于它实现
+//
+// 请求字段按与服务端相同的 tag 放入路径、查询参数、请求头和请求体，成功响应从默认响应封装中
+// 取出 data，错误响应还原为 BizError（Code、HTTPCode、Errors 与服务端一致）。
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	header     http.Header
+}
+
+// ClientOption 客户端选项
+type ClientOption func(*Client)
+
+// WithHTTPClient 设置发送请求的 http.Client，默认为 http.DefaultClient
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithClientHeader 设置每个请求都携带的请求头，如 Authorization
+func WithClientHeader(key, value string) ClientOption {
+	return func(c *Client) {
+		c.header.Set(key, value)
+	}
+}
+
+// NewClient 创建客户端，baseURL 为服务地址，如 http://user-service:8080
+func NewClient(baseURL string, opts ...ClientOption) *Client {
+	c := &Client{
+		baseURL:    strings.TrimSuffix(baseURL, "/"),
+		httpClient: http.DefaultClient,
+		header:     make(http.Header),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Call 调用路由，fullPath 为注册时的完整路由路径，如 /api/user/:id
+//
+// 非 2xx 响应返回 BizError；响应不是默认的错误响应格式时，返回错误码为 HTTP 状态码的 BizError。
+func Call[T any, R any](ctx context.Context, c *Client, method, fullPath string, req *T) (*R, error) {
+	httpReq, err := c.newRequest(ctx, method, fullPath, req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, decodeBizError(resp.StatusCode, body)
+	}
+
+	var envelope struct {
+		Data *R `json:"data"`
+	}
+	if resp.StatusCode == http.StatusNoContent || len(bytes.TrimSpace(body)) == 0 {
+		return nil, nil
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("apihandler: decode response: %w", err)
+	}
+	return envelope.Data, nil
+}
+
+// newRequest 根据请求结构创建 HTTP 请求
+func (c *Client) newRequest(ctx context.Context, method, fullPath string, req any) (*http.Request, error) {
+	v := reflect.ValueOf(req)
+	for v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+
+	pathValues := make(map[string]string)
+	query := make(url.Values)
+	header := make(http.Header)
+	jsonBody := make(map[string]any)
+	form := make(url.Values)
+	if v.Kind() == reflect.Struct {
+		for _, f := range requestLayout(v.Type(), method) {
+			value := v.FieldByName(f.Field.Name)
+			for value.Kind() == reflect.Pointer && !value.IsNil() {
+				value = value.Elem()
+			}
+			_, omitempty, _ := jsonFieldName(f.Field)
+			switch {
+			case f.In == "path":
+				pathValues[f.Name] = fmt.Sprint(value.Interface())
+			case value.IsZero() && (f.In != "json" || omitempty):
+				// 未设置的参数不发送，与服务端的默认值一致
+			case f.In == "query":
+				addValues(query, f.Name, value)
+			case f.In == "header":
+				for _, s := range stringValues(value) {
+					header.Add(f.Name, s)
+				}
+			case f.In == "json":
+				jsonBody[f.Name] = value.Interface()
+			case f.In == "form":
+				addValues(form, f.Name, value)
+			}
+		}
+	}
+
+	target := c.baseURL + expandPath(fullPath, pathValues)
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	contentType := ""
+	switch {
+	case len(jsonBody) > 0:
+		data, err := json.Marshal(jsonBody)
+		if err != nil {
+			return nil, fmt.Errorf("apihandler: encode request: %w", err)
+		}
+		body, contentType = bytes.NewReader(data), "application/json"
+	case len(form) > 0:
+		body, contentType = strings.NewReader(form.Encode()), "application/x-www-form-urlencoded"
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, err
+	}
+	for key, values := range c.header {
+		httpReq.Header[key] = append([]string(nil), values...)
+	}
+	for key, values := range header {
+		httpReq.Header[key] = values
+	}
+	if contentType != "" {
+		httpReq.Header.Set("Content-Type", contentType)
+	}
+	httpReq.Header.Set("Accept", "application/json")
+	return httpReq, nil
+}
+
+// expandPath 将路由路径中的参数替换为请求中的值
+func expandPath(fullPath string, values map[string]string) string {
+	segments := strings.Split(fullPath, "/")
+	for i, segment := range segments {
+		if segment == "" {
+			continue
+		}
+		switch segment[0] {
+		case ':':
+			segments[i] = url.PathEscape(values[segment[1:]])
+		case '*':
+			segments[i] = strings.TrimPrefix(values[segment[1:]], "/")
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// addValues 将字段值添加到查询参数或表单，切片的每个元素作为一个值
+func addValues(values url.Values, name string, value reflect.Value) {
+	for _, s := range stringValues(value) {
+		values.Add(name, s)
+	}
+}
+
+// stringValues 将字段值转换为字符串，切片的每个元素转换为一个字符串
+func stringValues(value reflect.Value) []string {
+	if value.Kind() == reflect.Slice && value.Type().Elem().Kind() != reflect.Uint8 {
+		values := make([]string, 0, value.Len())
+		for i := 0; i < value.Len(); i++ {
+			values = append(values, stringValues(value.Index(i))...)
+		}
+		return values
+	}
+	if t, ok := value.Interface().(interface{ MarshalText() ([]byte, error) }); ok {
+		if text, err := t.MarshalText(); err == nil {
+			return []string{string(text)}
+		}
+	}
+	return []string{fmt.Sprint(value.Interface())}
+}
+
+// decodeBizError 将错误响应还原为业务错误
+func decodeBizError(httpCode int, body []byte) BizError {
+	var resp struct {
+		Code    json.RawMessage `json:"code"`
+		Message string          `json:"message"`
+		Errors  []FieldError    `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &resp); err != nil || len(resp.Code) == 0 {
+		message := strings.TrimSpace(string(body))
+		if message == "" {
+			message = http.StatusText(httpCode)
+		}
+		return NewBizError(httpCode, message, httpCode)
+	}
+
+	var errs []any
+	for _, fieldErr := range resp.Errors {
+		errs = append(errs, fieldErr)
+	}
+	return NewBizErrorWithDetails(decodeCode(resp.Code), resp.Message, httpCode, errs)
+}
+
+// decodeCode 还原业务错误码，整数还原为 int，其他数字为 float64，字符串为 string
+func decodeCode(raw json.RawMessage) any {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var code any
+	if err := decoder.Decode(&code); err != nil {
+		return string(raw)
+	}
+	if n, ok := code.(json.Number); ok {
+		if i, err := n.Int64(); err == nil && int64(int(i)) == i {
+			return int(i)
+		}
+		f, _ := n.Float64()
+		return f
+	}
+	return code
+}
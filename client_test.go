@@ -0,0 +1,107 @@
+package apihandler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// clientUser 测试用的用户
+type clientUser struct {
+	ID   int64    `json:"id"`
+	Name string   `json:"name"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+// clientListRequest 测试用的列表请求
+type clientListRequest struct {
+	Page int      `form:"page"`
+	Tags []string `form:"tags"`
+}
+
+// clientUpdateRequest 测试用的更新请求
+type clientUpdateRequest struct {
+	ID   int64  `path:"id"`
+	Name string `json:"name" binding:"required"`
+}
+
+// clientLoginRequest 测试用的表单请求
+type clientLoginRequest struct {
+	Username string `form:"username" binding:"required"`
+}
+
+// 测试客户端调用处理器并还原响应和业务错误
+func TestClientCall(t *testing.T) {
+	r := gin.New()
+	var authorization string
+	r.Use(func(c *gin.Context) { authorization = c.GetHeader("Authorization") })
+	api := NewGroup(r.Group("/api"), WithRegistry(nil))
+	GET(api, "/users", func(ctx context.Context, req *clientListRequest) (*[]clientUser, error) {
+		return &[]clientUser{{ID: int64(req.Page), Tags: req.Tags}}, nil
+	})
+	PUT(api, "/users/:id", func(ctx context.Context, req *clientUpdateRequest) (*clientUser, error) {
+		if req.ID == 404 {
+			return nil, NewBizError(20004, "用户不存在", http.StatusNotFound)
+		}
+		return &clientUser{ID: req.ID, Name: req.Name}, nil
+	})
+	POST(api, "/login", func(ctx context.Context, req *clientLoginRequest) (*clientUser, error) {
+		return &clientUser{Name: req.Username}, nil
+	})
+	server := httptest.NewServer(r)
+	defer server.Close()
+
+	client := NewClient(server.URL+"/", WithClientHeader("Authorization", "Bearer t"))
+	ctx := context.Background()
+
+	users, err := Call[clientListRequest, []clientUser](ctx, client, http.MethodGet, "/api/users", &clientListRequest{Page: 2, Tags: []string{"a", "b"}})
+	if err != nil || len(*users) != 1 || (*users)[0].ID != 2 || !reflect.DeepEqual((*users)[0].Tags, []string{"a", "b"}) {
+		t.Errorf("期望查询参数发送到服务端, 实际得到 %+v, %v", users, err)
+	}
+
+	user, err := Call[clientUpdateRequest, clientUser](ctx, client, http.MethodPut, "/api/users/:id", &clientUpdateRequest{ID: 7, Name: "tom"})
+	if err != nil || user.ID != 7 || user.Name != "tom" {
+		t.Errorf("期望路径参数和 JSON 请求体发送到服务端, 实际得到 %+v, %v", user, err)
+	}
+
+	user, err = Call[clientLoginRequest, clientUser](ctx, client, http.MethodPost, "/api/login", &clientLoginRequest{Username: "tom"})
+	if err != nil || user.Name != "tom" {
+		t.Errorf("期望表单请求体发送到服务端, 实际得到 %+v, %v", user, err)
+	}
+	if authorization != "Bearer t" {
+		t.Errorf("期望发送客户端的公共请求头, 实际得到 %q", authorization)
+	}
+
+	_, err = Call[clientUpdateRequest, clientUser](ctx, client, http.MethodPut, "/api/users/:id", &clientUpdateRequest{ID: 404, Name: "tom"})
+	var bizErr BizError
+	if !errors.As(err, &bizErr) || bizErr.Code() != 20004 || bizErr.HTTPCode() != http.StatusNotFound || bizErr.Error() != "用户不存在" {
+		t.Errorf("期望还原业务错误, 实际得到 %v", err)
+	}
+
+	_, err = Call[clientUpdateRequest, clientUser](ctx, client, http.MethodPut, "/api/users/:id", &clientUpdateRequest{ID: 1})
+	if !errors.As(err, &bizErr) || bizErr.HTTPCode() != http.StatusBadRequest || len(bizErr.Errors()) != 1 {
+		t.Fatalf("期望还原参数验证错误, 实际得到 %v", err)
+	}
+	if fieldErr, ok := bizErr.Errors()[0].(FieldError); !ok || fieldErr.Rule != "required" {
+		t.Errorf("期望字段错误还原为 FieldError, 实际得到 %#v", bizErr.Errors()[0])
+	}
+}
+
+// 测试非默认格式的错误响应
+func TestClientUnexpectedError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "bad gateway", http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	_, err := Call[struct{}, clientUser](context.Background(), NewClient(server.URL), http.MethodGet, "/", nil)
+	var bizErr BizError
+	if !errors.As(err, &bizErr) || bizErr.Code() != http.StatusBadGateway || bizErr.Error() != "bad gateway" {
+		t.Errorf("期望返回错误码为 HTTP 状态码的业务错误, 实际得到 %v", err)
+	}
+}
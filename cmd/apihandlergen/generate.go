@@ -0,0 +1,812 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// basicKinds 支持绑定和生成验证代码的基本类型，值为 strconv 解析使用的位数
+var basicKinds = map[string]int{
+	"string": 0, "bool": 0,
+	"int": 0, "int8": 8, "int16": 16, "int32": 32, "int64": 64, "rune": 32,
+	"uint": 0, "uint8": 8, "uint16": 16, "uint32": 32, "uint64": 64, "byte": 8,
+	"float32": 32, "float64": 64,
+}
+
+// pkgInfo 请求类型所在包的类型声明
+type pkgInfo struct {
+	name    string
+	structs map[string]*ast.StructType
+	basics  map[string]string // 底层为基本类型的命名类型，值为基本类型
+	others  map[string]bool   // 其他类型声明
+}
+
+// fieldType 字段的类型
+type fieldType struct {
+	kind       string // 基本类型，不是基本类型（或其指针、切片）时为空
+	named      string // 底层为基本类型的命名类型
+	ptr        bool
+	slice      bool
+	file       bool // *multipart.FileHeader 或其切片
+	time       bool // time.Time
+	structLike bool // 结构体等验证器会递归验证的类型
+}
+
+// elem 返回元素（去掉指针和切片）的类型名称
+func (t fieldType) elem() string {
+	if t.named != "" {
+		return t.named
+	}
+	return t.kind
+}
+
+// rule binding tag 中的一条验证规则
+type rule struct {
+	tag   string
+	param string
+}
+
+// field 请求类型中的一个字段
+type field struct {
+	name        string // Go 字段名
+	typ         fieldType
+	path        string // 路径参数名
+	header      string // 请求头名称
+	formKey     string // 表单键，为空时不从表单绑定
+	formDefault *string
+	wireForm    string // 表单请求中字段错误的字段名
+	wireJSON    string // 其他请求中字段错误的字段名
+	omitempty   bool
+	rules       []rule
+	skip        bool // binding:"-"
+	body        bool // 请求体字段，与 apihandler 判断请求类型是否有请求体字段的规则相同
+}
+
+// generate 为目录 dir 中的请求类型生成 BindRequest 方法，skip 为输出文件名，解析时忽略
+func generate(dir string, typeNames []string, skip string) ([]byte, error) {
+	pkg, err := loadPackage(dir, skip)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &generator{helpers: make(map[string]bool)}
+	for _, name := range typeNames {
+		name = strings.TrimSpace(name)
+		st, ok := pkg.structs[name]
+		if !ok {
+			return nil, fmt.Errorf("struct type %s not found in %s", name, dir)
+		}
+		fields, err := pkg.fields(name, st)
+		if err != nil {
+			return nil, err
+		}
+		if err := g.binder(name, fields); err != nil {
+			return nil, err
+		}
+	}
+	return g.source(pkg.name)
+}
+
+// loadPackage 解析目录中除测试文件和 skip 之外的 Go 文件
+func loadPackage(dir, skip string) (*pkgInfo, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	pkg := &pkgInfo{
+		structs: make(map[string]*ast.StructType),
+		basics:  make(map[string]string),
+		others:  make(map[string]bool),
+	}
+	aliases := make(map[string]string)
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_test.go") || name == skip {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		if pkg.name == "" {
+			pkg.name = file.Name.Name
+		}
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				ts := spec.(*ast.TypeSpec)
+				switch t := ts.Type.(type) {
+				case *ast.StructType:
+					pkg.structs[ts.Name.Name] = t
+				case *ast.Ident:
+					aliases[ts.Name.Name] = t.Name
+				default:
+					pkg.others[ts.Name.Name] = true
+				}
+			}
+		}
+	}
+	if pkg.name == "" {
+		return nil, fmt.Errorf("no Go files in %s", dir)
+	}
+
+	// 解析底层为基本类型的命名类型，如 type Status string、type Level Status
+	for name := range aliases {
+		underlying := name
+		for range len(aliases) + 1 {
+			next, ok := aliases[underlying]
+			if !ok {
+				break
+			}
+			underlying = next
+		}
+		switch {
+		case isBasic(underlying):
+			pkg.basics[name] = underlying
+		case pkg.structs[underlying] == nil:
+			pkg.others[name] = true
+		}
+	}
+	return pkg, nil
+}
+
+// isBasic 判断是否为支持的基本类型
+func isBasic(name string) bool {
+	_, ok := basicKinds[name]
+	return ok
+}
+
+// typeOf 返回字段类型表达式的类型
+func (p *pkgInfo) typeOf(expr ast.Expr) fieldType {
+	switch e := expr.(type) {
+	case *ast.Ident:
+		if isBasic(e.Name) {
+			return fieldType{kind: e.Name}
+		}
+		if kind, ok := p.basics[e.Name]; ok {
+			return fieldType{kind: kind, named: e.Name}
+		}
+		return fieldType{structLike: !p.others[e.Name]}
+	case *ast.StarExpr:
+		if isSelector(e.X, "multipart", "FileHeader") {
+			return fieldType{file: true, ptr: true}
+		}
+		inner := p.typeOf(e.X)
+		if inner.kind != "" && !inner.slice {
+			inner.ptr = true
+			return inner
+		}
+		return fieldType{structLike: inner.structLike || inner.time}
+	case *ast.ArrayType:
+		inner := p.typeOf(e.Elt)
+		switch {
+		case e.Len != nil:
+			return fieldType{}
+		case inner.file && !inner.slice:
+			inner.slice = true
+			return inner
+		case inner.kind != "" && !inner.ptr && !inner.slice:
+			inner.slice = true
+			return inner
+		}
+		return fieldType{}
+	case *ast.SelectorExpr:
+		if isSelector(e, "time", "Time") {
+			return fieldType{time: true}
+		}
+		return fieldType{structLike: true}
+	case *ast.StructType, *ast.InterfaceType:
+		return fieldType{structLike: true}
+	}
+	return fieldType{}
+}
+
+// isSelector 判断表达式是否为 pkg.name
+func isSelector(expr ast.Expr, pkg, name string) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	ident, ok := sel.X.(*ast.Ident)
+	return ok && ident.Name == pkg && sel.Sel.Name == name
+}
+
+// fields 返回请求类型中导出的字段
+func (p *pkgInfo) fields(typeName string, st *ast.StructType) ([]field, error) {
+	var fields []field
+	for _, f := range st.Fields.List {
+		if len(f.Names) == 0 {
+			return nil, fmt.Errorf("%s: embedded field %s is not supported", typeName, exprString(f.Type))
+		}
+		var tag reflect.StructTag
+		if f.Tag != nil {
+			value, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid tag %s", typeName, f.Tag.Value)
+			}
+			tag = reflect.StructTag(value)
+		}
+		for _, ident := range f.Names {
+			if !ident.IsExported() {
+				continue
+			}
+			fd, err := newField(ident.Name, p.typeOf(f.Type), tag)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", typeName, ident.Name, err)
+			}
+			fields = append(fields, fd)
+		}
+	}
+	return fields, nil
+}
+
+// exprString 返回类型表达式的源码
+func exprString(expr ast.Expr) string {
+	var buf bytes.Buffer
+	_ = format.Node(&buf, token.NewFileSet(), expr)
+	return buf.String()
+}
+
+// newField 按 tag 解析字段的绑定位置、字段名和验证规则
+func newField(name string, typ fieldType, tag reflect.StructTag) (field, error) {
+	f := field{name: name, typ: typ}
+	bindable := typ.kind != "" || typ.file
+
+	f.path = tagName(tag, "path")
+	if f.path != "" && (typ.kind == "" || typ.slice) {
+		return f, fmt.Errorf("unsupported path parameter type")
+	}
+	f.header = tagName(tag, "header")
+	if f.header != "" && typ.kind == "" {
+		return f, fmt.Errorf("unsupported header type")
+	}
+
+	formTag, hasForm := tag.Lookup("form")
+	formName, formOpts, _ := strings.Cut(formTag, ",")
+	f.body = isBodyField(tag)
+	if f.path == "" && f.header == "" && formName != "-" {
+		switch {
+		case bindable:
+			f.formKey = formName
+			if f.formKey == "" {
+				f.formKey = name
+			}
+			for _, opt := range strings.Split(formOpts, ",") {
+				if value, ok := strings.CutPrefix(opt, "default="); ok {
+					f.formDefault = &value
+				}
+			}
+		case hasForm && formName != "":
+			return f, fmt.Errorf("unsupported form field type")
+		}
+	}
+
+	// 与 apihandler 输出的字段错误使用相同的字段名
+	jsonName := ""
+	if jsonTag, ok := tag.Lookup("json"); ok && jsonTag != "-" && jsonTag != "" {
+		jsonName, _, _ = strings.Cut(jsonTag, ",")
+		if jsonName == "" {
+			jsonName = name
+		}
+	}
+	formName = tagName(tag, "form")
+	f.wireForm, f.wireJSON = firstNonEmpty(f.path, formName, jsonName, name), firstNonEmpty(f.path, jsonName, formName, name)
+
+	bindingTag := tag.Get("binding")
+	if bindingTag == "-" {
+		f.skip = true
+		return f, nil
+	}
+	if bindingTag != "" {
+		for _, item := range strings.Split(bindingTag, ",") {
+			tagName, param, _ := strings.Cut(item, "=")
+			if tagName == "omitempty" {
+				f.omitempty = true
+				continue
+			}
+			f.rules = append(f.rules, rule{tag: tagName, param: param})
+		}
+	}
+	return f, nil
+}
+
+// isBodyField 判断字段是否为请求体字段：路径参数、请求头和认证声明字段以及只有 form tag 的字段不是请求体字段
+func isBodyField(tag reflect.StructTag) bool {
+	for _, key := range []string{"path", "uri", "header", "claim"} {
+		if tagName(tag, key) != "" {
+			return false
+		}
+	}
+	jsonTag, hasJSON := tag.Lookup("json")
+	xmlTag, hasXML := tag.Lookup("xml")
+	_, hasForm := tag.Lookup("form")
+	return hasJSON && jsonTag != "-" || hasXML && xmlTag != "-" || !hasJSON && !hasXML && !hasForm
+}
+
+// tagName 返回 tag 中的名称，- 表示忽略
+func tagName(tag reflect.StructTag, key string) string {
+	name, _, _ := strings.Cut(tag.Get(key), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// firstNonEmpty 返回第一个非空字符串
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// generator 生成的源码
+type generator struct {
+	buf     bytes.Buffer
+	helpers map[string]bool
+}
+
+// printf 写入生成的源码
+func (g *generator) printf(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// binder 生成请求类型的 BindRequest 方法
+func (g *generator) binder(typeName string, fields []field) error {
+	fallback, reason := "", ""
+	var checks [][]check
+	for _, f := range fields {
+		cs, why, err := f.checks()
+		if err != nil {
+			return fmt.Errorf("%s.%s: %w", typeName, f.name, err)
+		}
+		if why != "" && fallback == "" {
+			fallback, reason = f.name, why
+		}
+		checks = append(checks, cs)
+	}
+
+	g.printf("// BindRequest 实现 apihandler.RequestBinder 接口，绑定路径参数、查询参数或表单和请求头并验证字段\n")
+	if fallback != "" {
+		g.printf("//\n// 字段 %s 的%s，使用 gin 的验证器验证。\n", fallback, reason)
+	}
+	g.printf("func (r *%s) BindRequest(c *gin.Context) error {\n", typeName)
+
+	body := &generator{helpers: g.helpers}
+	usesForm := false
+	hasForm := slices.ContainsFunc(fields, func(f field) bool { return f.formKey != "" })
+	if hasForm {
+		// 没有请求体字段的请求类型总是绑定查询参数或表单，与 apihandler 跳过请求体解码时只绑定查询参数一致
+		bodyless := !slices.ContainsFunc(fields, func(f field) bool { return f.body })
+		files := "_"
+		if slices.ContainsFunc(fields, func(f field) bool { return f.formKey != "" && f.typ.file }) {
+			files = "files"
+		}
+		g.helpers["form"] = true
+		if bodyless {
+			body.printf("{\n")
+		} else {
+			usesForm = true
+			body.printf("if form {\n")
+		}
+		body.printf("values, %s, err := apihandlergenForm(c)\nif err != nil {\nreturn err\n}\n", files)
+		for _, f := range fields {
+			if f.formKey != "" {
+				body.formField(f)
+			}
+		}
+		body.printf("}\n")
+	}
+	for _, f := range fields {
+		if f.path != "" {
+			body.printf("if s := c.Param(%q); s != \"\" {\n", f.path)
+			body.assign(f, f.path, false, false)
+			body.printf("}\n")
+		}
+	}
+	for _, f := range fields {
+		if f.header != "" {
+			body.printf("if vs := c.Request.Header.Values(%q); len(vs) > 0 {\n", f.header)
+			body.assign(f, f.header, true, true)
+			body.printf("}\n")
+		}
+	}
+
+	if fallback != "" {
+		body.printf("if binding.Validator == nil {\nreturn nil\n}\nreturn binding.Validator.ValidateStruct(r)\n")
+	} else {
+		var validation generator
+		validation.helpers = g.helpers
+		for i, f := range fields {
+			if len(checks[i]) == 0 {
+				continue
+			}
+			wire := strconv.Quote(f.wireJSON)
+			if f.wireForm != f.wireJSON {
+				usesForm = true
+				g.helpers["name"] = true
+				wire = fmt.Sprintf("apihandlergenName(form, %q, %q)", f.wireForm, f.wireJSON)
+			}
+			validation.validate(f, checks[i], wire)
+		}
+		if validation.buf.Len() > 0 {
+			body.printf("var errs []error\n")
+			body.buf.Write(validation.buf.Bytes())
+			body.printf("return errors.Join(errs...)\n")
+		} else {
+			body.printf("return nil\n")
+		}
+	}
+
+	if usesForm {
+		g.printf("form := c.Request.Method == http.MethodGet || c.ContentType() == binding.MIMEPOSTForm || c.ContentType() == binding.MIMEMultipartPOSTForm\n")
+	}
+	g.buf.Write(body.buf.Bytes())
+	g.printf("}\n\n")
+	return nil
+}
+
+// formField 生成从表单绑定字段的代码
+func (g *generator) formField(f field) {
+	if f.typ.file {
+		g.printf("if fhs := files[%q]; len(fhs) > 0 {\n", f.formKey)
+		if f.typ.slice {
+			g.printf("r.%s = fhs\n", f.name)
+		} else {
+			g.printf("r.%s = fhs[0]\n", f.name)
+		}
+		g.printf("}\n")
+		return
+	}
+	if f.formDefault != nil {
+		g.printf("{\nvs, ok := values[%q]\nif !ok {\nvs = []string{%q}\n}\nif len(vs) > 0 {\n", f.formKey, *f.formDefault)
+		g.assign(f, f.formKey, true, true)
+		g.printf("}\n}\n")
+		return
+	}
+	g.printf("if vs := values[%q]; len(vs) > 0 {\n", f.formKey)
+	g.assign(f, f.formKey, true, true)
+	g.printf("}\n")
+}
+
+// assign 生成将字符串解析后赋值给字段的代码，fromValues 表示值来自 vs，否则来自 s；zero 表示空字符串解析为零值
+func (g *generator) assign(f field, key string, fromValues, zero bool) {
+	t := f.typ
+	if t.slice {
+		g.printf("r.%s = make([]%s, 0, len(vs))\nfor _, s := range vs {\n", f.name, t.elem())
+		g.parse(t, key, zero)
+		g.printf("r.%s = append(r.%s, v)\n}\n", f.name, f.name)
+		return
+	}
+	if fromValues {
+		g.printf("s := vs[0]\n")
+	}
+	g.parse(t, key, zero)
+	if t.ptr {
+		g.printf("r.%s = &v\n", f.name)
+	} else {
+		g.printf("r.%s = v\n", f.name)
+	}
+}
+
+// parse 生成将字符串 s 解析为元素类型的值 v 的代码
+func (g *generator) parse(t fieldType, key string, zero bool) {
+	errorf := fmt.Sprintf("fmt.Errorf(%s, err)", strconv.Quote(strings.ReplaceAll(key, "%", "%%")+": %w"))
+	bits := basicKinds[t.kind]
+	var call, result string
+	switch t.kind {
+	case "string":
+		if t.named == "" {
+			g.printf("v := s\n")
+		} else {
+			g.printf("v := %s(s)\n", t.named)
+		}
+		return
+	case "bool":
+		call, result = "strconv.ParseBool(s)", "bool"
+		if zero {
+			g.printf("if s == \"\" {\ns = \"false\"\n}\n")
+		}
+	case "float32", "float64":
+		call, result = fmt.Sprintf("strconv.ParseFloat(s, %d)", bits), "float64"
+	case "uint", "uint8", "uint16", "uint32", "uint64", "byte":
+		call, result = fmt.Sprintf("strconv.ParseUint(s, 10, %d)", bits), "uint64"
+	default:
+		call, result = fmt.Sprintf("strconv.ParseInt(s, 10, %d)", bits), "int64"
+	}
+	if zero && t.kind != "bool" {
+		g.printf("if s == \"\" {\ns = \"0\"\n}\n")
+	}
+	g.printf("n, err := %s\nif err != nil {\nreturn %s\n}\n", call, errorf)
+	if t.elem() == result {
+		g.printf("v := n\n")
+	} else {
+		g.printf("v := %s(n)\n", t.elem())
+	}
+}
+
+// check 字段验证失败的条件
+type check struct {
+	cond string // 验证失败的条件，v 为字段（指针字段为指向的值）的值
+	rule rule
+}
+
+// checks 返回字段的验证条件，不能生成验证代码时返回原因
+func (f field) checks() (checks []check, reason string, err error) {
+	if f.skip {
+		return nil, "", nil
+	}
+	t := f.typ
+	if t.structLike {
+		return nil, "类型会被递归验证", nil
+	}
+	for _, r := range f.rules {
+		if strings.ContainsAny(r.tag, "|") || strings.Contains(r.param, "'") {
+			return nil, "验证规则 " + r.tag + " 不能生成", nil
+		}
+		cond, ok, err := f.condition(r)
+		if err != nil {
+			return nil, "", err
+		}
+		if !ok {
+			return nil, "验证规则 " + r.tag + " 不能生成", nil
+		}
+		checks = append(checks, check{cond: cond, rule: r})
+	}
+	return checks, "", nil
+}
+
+// zeroCondition 返回值 v 为零值的条件
+func (t fieldType) zeroCondition() string {
+	switch {
+	case t.slice, t.file:
+		return "v == nil"
+	case t.time:
+		return "v.IsZero()"
+	case t.kind == "string":
+		return `v == ""`
+	case t.kind == "bool":
+		return "!v"
+	}
+	return "v == 0"
+}
+
+// nonZeroCondition 返回值 v 不为零值的条件
+func (t fieldType) nonZeroCondition() string {
+	switch {
+	case t.slice, t.file:
+		return "v != nil"
+	case t.time:
+		return "!v.IsZero()"
+	case t.kind == "string":
+		return `v != ""`
+	case t.kind == "bool":
+		return "v"
+	}
+	return "v != 0"
+}
+
+// condition 返回验证规则失败的条件，不支持的规则返回 false
+func (f field) condition(r rule) (string, bool, error) {
+	t := f.typ
+	elem := t
+	elem.ptr = false
+	if r.tag == "required" {
+		if t.ptr && !t.file {
+			// 指针字段在生成的代码中单独判断 nil
+			return "false", true, nil
+		}
+		return elem.zeroCondition(), true, nil
+	}
+	if t.file || t.time || t.kind == "" {
+		return "", false, nil
+	}
+
+	ops := map[string]string{"min": "<", "max": ">", "len": "!=", "eq": "!=", "ne": "==", "gt": "<=", "gte": "<", "lt": ">=", "lte": ">"}
+	switch r.tag {
+	case "min", "max", "len", "gt", "gte", "lt", "lte", "eq", "ne":
+		op := ops[r.tag]
+		switch {
+		case t.slice || t.kind == "string" && r.tag != "eq" && r.tag != "ne":
+			if _, err := strconv.ParseUint(r.param, 10, 64); err != nil {
+				return "", false, fmt.Errorf("invalid param %q for rule %s", r.param, r.tag)
+			}
+			measure := "len(v)"
+			if !t.slice {
+				measure = "utf8.RuneCountInString(v)"
+			}
+			return fmt.Sprintf("%s %s %s", measure, op, r.param), true, nil
+		case t.kind == "string":
+			return fmt.Sprintf("v %s %q", op, r.param), true, nil
+		case t.kind == "bool":
+			if r.tag != "eq" && r.tag != "ne" {
+				return "", false, nil
+			}
+			if _, err := strconv.ParseBool(r.param); err != nil {
+				return "", false, fmt.Errorf("invalid param %q for rule %s", r.param, r.tag)
+			}
+			return fmt.Sprintf("v %s %s", op, r.param), true, nil
+		}
+		if err := numberParam(t.kind, r.param); err != nil {
+			return "", false, fmt.Errorf("invalid param %q for rule %s", r.param, r.tag)
+		}
+		return fmt.Sprintf("v %s %s", op, r.param), true, nil
+	case "oneof":
+		if t.slice || t.kind == "bool" || strings.HasPrefix(t.kind, "float") {
+			return "", false, nil
+		}
+		var conds []string
+		for _, value := range strings.Fields(r.param) {
+			if t.kind == "string" {
+				conds = append(conds, fmt.Sprintf("v != %q", value))
+				continue
+			}
+			if err := numberParam(t.kind, value); err != nil {
+				return "", false, fmt.Errorf("invalid param %q for rule %s", r.param, r.tag)
+			}
+			conds = append(conds, "v != "+value)
+		}
+		if len(conds) == 0 {
+			return "", false, fmt.Errorf("invalid param %q for rule %s", r.param, r.tag)
+		}
+		return strings.Join(conds, " && "), true, nil
+	}
+	return "", false, nil
+}
+
+// numberParam 检查验证规则的参数是否为 kind 类型的数值
+func numberParam(kind, param string) error {
+	var err error
+	switch {
+	case strings.HasPrefix(kind, "float"):
+		_, err = strconv.ParseFloat(param, 64)
+	case strings.HasPrefix(kind, "uint") || kind == "byte":
+		_, err = strconv.ParseUint(param, 10, 64)
+	default:
+		_, err = strconv.ParseInt(param, 10, 64)
+	}
+	return err
+}
+
+// validate 生成字段的验证代码，每个字段只输出第一个失败的规则
+func (g *generator) validate(f field, checks []check, wire string) {
+	fieldError := func(r rule, value string) string {
+		param := ""
+		if r.param != "" {
+			param = fmt.Sprintf(", Param: %q", r.param)
+		}
+		return fmt.Sprintf("errs = append(errs, apihandler.FieldError{Field: %s, Rule: %q%s, Value: %s})\n", wire, r.tag, param, value)
+	}
+
+	t := f.typ
+	if t.ptr && !t.file {
+		// nil 指针：required 或第一个规则失败，omitempty 时跳过
+		required := slices.ContainsFunc(f.rules, func(r rule) bool { return r.tag == "required" })
+		checks = slices.DeleteFunc(slices.Clone(checks), func(c check) bool { return c.rule.tag == "required" })
+		switch {
+		case required || !f.omitempty:
+			nilRule := rule{tag: "required"}
+			if !required {
+				nilRule = checks[0].rule
+			}
+			g.printf("if r.%s == nil {\n%s", f.name, fieldError(nilRule, "nil"))
+			if len(checks) == 0 {
+				g.printf("}\n")
+				return
+			}
+			g.printf("} else {\n")
+		case len(checks) == 0:
+			return
+		default:
+			g.printf("if r.%s != nil {\n", f.name)
+		}
+		g.printf("v := *r.%s\n", f.name)
+	} else {
+		g.printf("{\nv := r.%s\n", f.name)
+	}
+	if f.omitempty && !t.ptr {
+		g.printf("if %s {\n", t.nonZeroCondition())
+	}
+	g.printf("switch {\n")
+	for _, c := range checks {
+		g.printf("case %s:\n%s", c.cond, fieldError(c.rule, "v"))
+	}
+	g.printf("}\n")
+	if f.omitempty && !t.ptr {
+		g.printf("}\n")
+	}
+	g.printf("}\n")
+}
+
+// helperSources 生成的代码使用的辅助函数
+var helperSources = map[string]string{
+	"form": `// apihandlergenForm 与 gin 的表单绑定一样解析查询参数和表单
+func apihandlergenForm(c *gin.Context) (url.Values, map[string][]*multipart.FileHeader, error) {
+	if c.ContentType() == binding.MIMEMultipartPOSTForm {
+		form, err := c.MultipartForm()
+		if err != nil {
+			return nil, nil, err
+		}
+		return form.Value, form.File, nil
+	}
+	if err := c.Request.ParseForm(); err != nil {
+		return nil, nil, err
+	}
+	return c.Request.Form, nil, nil
+}
+`,
+	"name": `// apihandlergenName 返回字段错误的字段名，表单请求优先使用 form tag
+func apihandlergenName(form bool, formName, name string) string {
+	if form {
+		return formName
+	}
+	return name
+}
+`,
+}
+
+// imports 生成的代码可能使用的包
+var imports = []struct{ path, name string }{
+	{"errors", "errors."},
+	{"fmt", "fmt."},
+	{"mime/multipart", "multipart."},
+	{"net/http", "http."},
+	{"net/url", "url."},
+	{"strconv", "strconv."},
+	{"unicode/utf8", "utf8."},
+	{"", ""},
+	{"github.com/gin-gonic/gin", "gin."},
+	{"github.com/gin-gonic/gin/binding", "binding."},
+	{"apihandler github.com/night1008/gotools/gin-api-handler", "apihandler."},
+}
+
+// source 返回格式化后的源文件
+func (g *generator) source(pkgName string) ([]byte, error) {
+	var body bytes.Buffer
+	body.Write(g.buf.Bytes())
+	for _, name := range []string{"form", "name"} {
+		if g.helpers[name] {
+			body.WriteString(helperSources[name] + "\n")
+		}
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by apihandlergen; DO NOT EDIT.\n\npackage %s\n\nimport (\n", pkgName)
+	code := body.String()
+	for _, imp := range imports {
+		switch {
+		case imp.path == "":
+			src.WriteString("\n")
+		case strings.Contains(code, imp.name):
+			if alias, path, ok := strings.Cut(imp.path, " "); ok {
+				fmt.Fprintf(&src, "%s %q\n", alias, path)
+			} else {
+				fmt.Fprintf(&src, "%q\n", imp.path)
+			}
+		}
+	}
+	src.WriteString(")\n\n")
+	src.WriteString(code)
+
+	formatted, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated code: %w", err)
+	}
+	return formatted, nil
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// 测试生成的代码与 internal/golden 中提交的代码一致
+func TestGenerateGolden(t *testing.T) {
+	dir := filepath.Join("internal", "golden")
+	got, err := generate(dir, []string{"GetUserRequest", "CreateUserRequest", "UploadRequest", "SignupRequest"}, defaultOutput)
+	if err != nil {
+		t.Fatalf("生成代码失败: %v", err)
+	}
+	want, err := os.ReadFile(filepath.Join(dir, defaultOutput))
+	if err != nil {
+		t.Fatalf("读取已生成的代码失败: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("生成的代码与 %s 不一致，请在该目录运行 go generate", filepath.Join(dir, defaultOutput))
+	}
+}
+
+// 测试不能生成的请求类型返回错误
+func TestGenerateErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		source   string
+		typeName string
+		expected string
+	}{
+		{"类型不存在", "type Other struct{}", "Missing", "struct type Missing not found"},
+		{"嵌入字段", "type Base struct{}\ntype Req struct{ Base }", "Req", "Req: embedded field Base is not supported"},
+		{"路径参数类型", "type Req struct{ IDs []int `path:\"ids\"` }", "Req", "Req.IDs: unsupported path parameter type"},
+		{"表单字段类型", "type Req struct{ At struct{} `form:\"at\"` }", "Req", "Req.At: unsupported form field type"},
+		{"规则参数", "type Req struct{ Age int `binding:\"min=abc\"` }", "Req", `Req.Age: invalid param "abc" for rule min`},
+		{"长度参数", "type Req struct{ Name string `binding:\"max=-1\"` }", "Req", `Req.Name: invalid param "-1" for rule max`},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "req.go"), []byte("package req\n\n"+tc.source+"\n"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := generate(dir, []string{tc.typeName}, defaultOutput)
+			if err == nil || !strings.Contains(err.Error(), tc.expected) {
+				t.Errorf("期望包含 %q 的错误, 实际得到 %v", tc.expected, err)
+			}
+		})
+	}
+}
+
+// 测试不能生成的验证规则使用 gin 的验证器
+func TestGenerateFallback(t *testing.T) {
+	dir := t.TempDir()
+	source := "package req\n\ntype Req struct {\n\tEmails []string `json:\"emails\" binding:\"dive,email\"`\n}\n"
+	if err := os.WriteFile(filepath.Join(dir, "req.go"), []byte(source), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	src, err := generate(dir, []string{"Req"}, defaultOutput)
+	if err != nil {
+		t.Fatalf("生成代码失败: %v", err)
+	}
+	for _, expected := range []string{"字段 Emails 的验证规则 dive 不能生成", "binding.Validator.ValidateStruct(r)"} {
+		if !strings.Contains(string(src), expected) {
+			t.Errorf("期望生成的代码包含 %q, 实际得到:\n%s", expected, src)
+		}
+	}
+}
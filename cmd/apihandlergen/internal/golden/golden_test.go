@@ -0,0 +1,232 @@
+package golden
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	apihandler "github.com/night1008/gotools/gin-api-handler"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+	mobilePattern := regexp.MustCompile(`^1\d{10}$`)
+	err := apihandler.RegisterValidation("mobile_cn", func(fl validator.FieldLevel) bool {
+		return mobilePattern.MatchString(fl.Field().String())
+	}, map[string]string{"en": "{field} must be a valid mobile number"})
+	if err != nil {
+		panic(err)
+	}
+}
+
+// reflectCreateUserRequest 与 CreateUserRequest 字段相同但没有 BindRequest 方法，通过反射绑定
+type reflectCreateUserRequest CreateUserRequest
+
+// reflectSignupRequest 与 SignupRequest 字段相同但没有 BindRequest 方法，通过反射绑定
+type reflectSignupRequest SignupRequest
+
+// echo 返回请求对象的处理函数
+func echo[T any](ctx context.Context, req *T) (*T, error) {
+	return req, nil
+}
+
+// serve 发送请求，返回状态码和错误详情
+func serve(t *testing.T, r *gin.Engine, req *http.Request) (int, []byte) {
+	t.Helper()
+	req.Header.Set("Accept-Language", "en")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w.Code, w.Body.Bytes()
+}
+
+// 测试生成的代码绑定路径参数、查询参数和请求头
+func TestGeneratedBindQuery(t *testing.T) {
+	r := gin.New()
+	r.GET("/users/:id", apihandler.Handler(echo[GetUserRequest]))
+
+	req := httptest.NewRequest("GET", "/users/7?fields=name&fields=email&page_size=20&verbose=true&status=active", nil)
+	req.Header.Set("X-Token", "secret")
+	req.Header.Add("X-Trace", "a")
+	req.Header.Add("X-Trace", "b")
+	code, body := serve(t, r, req)
+	if code != http.StatusOK {
+		t.Fatalf("期望状态码为 200, 实际得到 %d: %s", code, body)
+	}
+	var resp apihandler.SuccessResponse[GetUserRequest]
+	if err := json.Unmarshal(body, &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	pageSize := 20
+	expected := GetUserRequest{ID: 7, Fields: []string{"name", "email"}, Page: 1, PageSize: &pageSize, Verbose: true, Status: "active", Token: "secret", Trace: []string{"a", "b"}}
+	if !reflect.DeepEqual(*resp.Data, expected) {
+		t.Errorf("期望绑定为 %+v, 实际得到 %+v", expected, *resp.Data)
+	}
+
+	testCases := []struct {
+		name     string
+		target   string
+		token    string
+		expected []apihandler.FieldError
+	}{
+		{"必填的请求头", "/users/7", "", []apihandler.FieldError{{Field: "Token", Rule: "required"}}},
+		{"多个字段", "/users/0?page=0&page_size=101&status=deleted", "secret", []apihandler.FieldError{
+			{Field: "id", Rule: "required"},
+			{Field: "page", Rule: "min", Param: "1"},
+			{Field: "page_size", Rule: "max", Param: "100", Value: float64(101)},
+			{Field: "status", Rule: "oneof", Param: "active disabled", Value: "deleted"},
+		}},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tc.target, nil)
+			if tc.token != "" {
+				req.Header.Set("X-Token", tc.token)
+			}
+			code, body := serve(t, r, req)
+			if code != http.StatusBadRequest {
+				t.Fatalf("期望状态码为 400, 实际得到 %d", code)
+			}
+			var resp apihandler.ErrorResponse
+			if err := json.Unmarshal(body, &resp); err != nil {
+				t.Fatalf("解析响应失败: %v", err)
+			}
+			fieldErrors := resp.FieldErrors()
+			if len(fieldErrors) != len(tc.expected) {
+				t.Fatalf("期望 %d 个字段错误, 实际得到 %+v", len(tc.expected), fieldErrors)
+			}
+			for i, fe := range fieldErrors {
+				want := tc.expected[i]
+				if fe.Field != want.Field || fe.Rule != want.Rule || fe.Param != want.Param || fe.Value != want.Value || fe.Message == "" {
+					t.Errorf("期望字段错误为 %+v, 实际得到 %+v", want, fe)
+				}
+			}
+		})
+	}
+
+	code, body = serve(t, r, httptest.NewRequest("GET", "/users/abc", nil))
+	if code != http.StatusBadRequest || !bytes.Contains(body, []byte(`id: strconv.ParseInt`)) {
+		t.Errorf("期望路径参数解析失败的 400 错误, 实际得到 %d %s", code, body)
+	}
+}
+
+// 测试没有请求体字段的请求类型使用生成的代码时忽略请求体，任何 Content-Type 都绑定查询参数
+func TestGeneratedSkipBody(t *testing.T) {
+	r := gin.New()
+	r.DELETE("/users/:id", apihandler.Handler(echo[GetUserRequest]))
+
+	for _, contentType := range []string{"application/json", "application/xml"} {
+		t.Run(contentType, func(t *testing.T) {
+			req := httptest.NewRequest("DELETE", "/users/7?page=2", bytes.NewBufferString("<broken"))
+			req.Header.Set("Content-Type", contentType)
+			req.Header.Set("X-Token", "secret")
+			code, body := serve(t, r, req)
+			if code != http.StatusOK {
+				t.Fatalf("期望状态码为 200, 实际得到 %d: %s", code, body)
+			}
+			var resp apihandler.SuccessResponse[GetUserRequest]
+			if err := json.Unmarshal(body, &resp); err != nil {
+				t.Fatalf("解析响应失败: %v", err)
+			}
+			// 反射绑定不绑定请求头，Token 的值说明使用了生成的代码
+			if resp.Data.ID != 7 || resp.Data.Page != 2 || resp.Data.Token != "secret" {
+				t.Errorf("期望 ID 7、page 2、token secret, 实际得到 %+v", *resp.Data)
+			}
+		})
+	}
+}
+
+// 测试生成的代码与反射绑定的结果和错误一致
+func TestGeneratedMatchesReflection(t *testing.T) {
+	r := gin.New()
+	r.POST("/generated/:tenant_id/users", apihandler.Handler(echo[CreateUserRequest]))
+	r.POST("/reflect/:tenant_id/users", apihandler.Handler(echo[reflectCreateUserRequest]))
+	r.POST("/generated/signup", apihandler.Handler(echo[SignupRequest]))
+	r.POST("/reflect/signup", apihandler.Handler(echo[reflectSignupRequest]))
+
+	testCases := []struct {
+		name        string
+		path        string
+		contentType string
+		body        string
+	}{
+		{"有效的 JSON", "/%s/3/users", "application/json", `{"name":"Alice","age":30,"score":99.5,"level":2,"tags":["a","b"],"birthday":"2000-01-02T00:00:00Z","remark":"x"}`},
+		{"无效的 JSON", "/%s/3/users", "application/json", `{"name":"A","age":200,"level":5,"tags":["a"]}`},
+		{"路径参数覆盖请求体", "/%s/0/users", "application/json", `{"name":"Alice","score":1,"level":1,"birthday":"2000-01-02T00:00:00Z"}`},
+		{"格式错误的 JSON", "/%s/3/users", "application/json", `{"name":`},
+		{"表单", "/%s/3/users", "application/x-www-form-urlencoded", `user_name=B&Age=-1&Level=3`},
+		{"自定义规则", "/%s/signup", "application/json", `{"mobile":"123","invite":{}}`},
+		{"自定义规则通过", "/%s/signup", "application/json", `{"mobile":"13800138000","invite":{"code":"x"}}`},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var codes [2]int
+			var bodies [2]string
+			for i, prefix := range []string{"generated", "reflect"} {
+				req := httptest.NewRequest("POST", fmt.Sprintf(tc.path, prefix), bytes.NewBufferString(tc.body))
+				req.Header.Set("Content-Type", tc.contentType)
+				code, body := serve(t, r, req)
+				codes[i], bodies[i] = code, string(body)
+			}
+			if codes[0] != codes[1] || bodies[0] != bodies[1] {
+				t.Errorf("期望生成的代码与反射绑定一致\n生成: %d %s\n反射: %d %s", codes[0], bodies[0], codes[1], bodies[1])
+			}
+		})
+	}
+}
+
+// 测试生成的代码绑定上传的文件
+func TestGeneratedBindMultipart(t *testing.T) {
+	type uploadResp struct {
+		Title       string   `json:"title"`
+		File        string   `json:"file"`
+		Attachments []string `json:"attachments"`
+	}
+	r := gin.New()
+	r.POST("/upload", apihandler.Handler(func(ctx context.Context, req *UploadRequest) (*uploadResp, error) {
+		resp := &uploadResp{Title: req.Title, File: req.File.Filename}
+		for _, fh := range req.Attachments {
+			resp.Attachments = append(resp.Attachments, fh.Filename)
+		}
+		return resp, nil
+	}))
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	_ = mw.WriteField("title", "report")
+	for _, file := range []struct{ field, name string }{{"file", "a.pdf"}, {"attachments", "b.png"}, {"attachments", "c.png"}} {
+		fw, _ := mw.CreateFormFile(file.field, file.name)
+		_, _ = fw.Write([]byte("data"))
+	}
+	_ = mw.Close()
+
+	req := httptest.NewRequest("POST", "/upload", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	code, respBody := serve(t, r, req)
+	var resp apihandler.SuccessResponse[uploadResp]
+	if err := json.Unmarshal(respBody, &resp); err != nil || code != http.StatusOK {
+		t.Fatalf("期望上传成功, 实际得到 %d %s", code, respBody)
+	}
+	expected := uploadResp{Title: "report", File: "a.pdf", Attachments: []string{"b.png", "c.png"}}
+	if !reflect.DeepEqual(*resp.Data, expected) {
+		t.Errorf("期望绑定为 %+v, 实际得到 %+v", expected, *resp.Data)
+	}
+
+	req = httptest.NewRequest("POST", "/upload", bytes.NewBufferString("title="))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	code, respBody = serve(t, r, req)
+	var errResp apihandler.ErrorResponse
+	_ = json.Unmarshal(respBody, &errResp)
+	if fe := errResp.FieldErrors(); code != http.StatusBadRequest || len(fe) != 2 || fe[0].Field != "title" || fe[1].Field != "file" {
+		t.Errorf("期望 title 和 file 的必填错误, 实际得到 %d %s", code, respBody)
+	}
+}
@@ -0,0 +1,59 @@
+// Package golden 包含 apihandlergen 生成代码的请求类型，测试检查生成的代码与当前的生成器一致
+package golden
+
+//go:generate go run ../.. -type GetUserRequest,CreateUserRequest,UploadRequest,SignupRequest
+
+import (
+	"mime/multipart"
+	"time"
+)
+
+// Status 用户状态
+type Status string
+
+// Level 用户等级
+type Level uint8
+
+// GetUserRequest 获取用户的请求
+type GetUserRequest struct {
+	ID       int64    `path:"id" binding:"required,gt=0"`
+	Fields   []string `form:"fields" binding:"max=5"`
+	Page     int      `form:"page,default=1" binding:"min=1"`
+	PageSize *int     `form:"page_size" binding:"omitempty,min=1,max=100"`
+	Verbose  bool     `form:"verbose"`
+	Status   Status   `form:"status" binding:"omitempty,oneof=active disabled"`
+	Token    string   `header:"X-Token" binding:"required"`
+	Trace    []string `header:"X-Trace"`
+}
+
+// CreateUserRequest 创建用户的请求
+type CreateUserRequest struct {
+	TenantID uint64    `path:"tenant_id" binding:"required"`
+	Name     string    `json:"name" form:"user_name" binding:"required,min=2,max=20"`
+	Age      int       `json:"age" binding:"gte=0,lte=150"`
+	Score    *float64  `json:"score" binding:"required,lt=100"`
+	Level    Level     `json:"level" binding:"oneof=1 2 3"`
+	Tags     []string  `json:"tags" binding:"omitempty,len=2"`
+	Birthday time.Time `json:"birthday" binding:"required"`
+	Remark   string    `json:"remark" binding:"-"`
+
+	internal string
+}
+
+// UploadRequest 上传文件的请求
+type UploadRequest struct {
+	Title       string                  `form:"title" binding:"required"`
+	File        *multipart.FileHeader   `form:"file" binding:"required"`
+	Attachments []*multipart.FileHeader `form:"attachments"`
+}
+
+// SignupRequest 注册的请求，手机号使用自定义验证规则
+type SignupRequest struct {
+	Mobile string  `json:"mobile" binding:"required,mobile_cn"`
+	Invite *Invite `json:"invite"`
+}
+
+// Invite 邀请信息
+type Invite struct {
+	Code string `json:"code" binding:"required"`
+}
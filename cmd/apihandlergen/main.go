@@ -0,0 +1,56 @@
+// Command apihandlergen 为请求类型生成不使用反射的参数绑定代码
+//
+// 生成的 BindRequest 方法实现 apihandler.RequestBinder 接口，处理器使用它绑定路径参数、查询参数或表单和请求头，
+// 并按 binding tag 验证字段，适合每个请求的反射在 CPU profile 中占比较高的服务。在请求类型所在的包中添加：
+//
+//	//go:generate go run github.com/night1008/gotools/gin-api-handler/cmd/apihandlergen -type GetUserRequest,CreateOrderRequest
+//
+// 生成的代码写入 apihandler_gen.go（可通过 -output 指定）。使用了不能生成的验证规则（如自定义规则和 dive）的类型
+// 仍然生成绑定代码，验证使用 gin 的验证器。
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultOutput 默认的输出文件名
+const defaultOutput = "apihandler_gen.go"
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("apihandlergen: ")
+
+	typeNames := flag.String("type", "", "comma-separated list of request type names; must be set")
+	output := flag.String("output", "", "output file name; default <dir>/"+defaultOutput)
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: apihandlergen -type T[,T...] [-output file] [dir]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if *typeNames == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	dir := "."
+	if args := flag.Args(); len(args) > 0 {
+		dir = args[0]
+	}
+	out := *output
+	if out == "" {
+		out = filepath.Join(dir, defaultOutput)
+	}
+
+	src, err := generate(dir, strings.Split(*typeNames, ","), filepath.Base(out))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,80 @@
+package apihandler
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+)
+
+// CodeRange 业务错误码区间到 HTTP 状态码的映射，区间包含 Min 和 Max
+type CodeRange struct {
+	Min      int64
+	Max      int64
+	HTTPCode int
+}
+
+// WithCodeRanges 设置业务错误码区间到 HTTP 状态码的映射
+//
+// 业务错误的 HTTPCode 为 0 时（如 NewBizError(40401, "用户不存在", 0)），按错误码所在的区间确定 HTTP 状态码，
+// 多个区间都包含错误码时使用范围最小的区间，如 40400–40499 优先于 40000–40999；没有匹配的区间时使用 500。
+func WithCodeRanges(ranges ...CodeRange) Option {
+	return func(c *HandlerConfig) {
+		c.CodeRanges = ranges
+	}
+}
+
+// statusBizError 由错误码区间确定 HTTP 状态码的业务错误
+type statusBizError struct {
+	BizError
+	httpCode int
+}
+
+// HTTPCode 返回 HTTP 状态码
+func (e *statusBizError) HTTPCode() int {
+	return e.httpCode
+}
+
+// Unwrap 返回原始业务错误
+func (e *statusBizError) Unwrap() error {
+	return e.BizError
+}
+
+// inferHTTPCode 为未指定 HTTP 状态码的业务错误按 CodeRanges 确定状态码，没有匹配的区间时使用 500
+func inferHTTPCode(config *HandlerConfig, bizErr BizError) BizError {
+	if bizErr.HTTPCode() != 0 {
+		return bizErr
+	}
+	code, ok := codeInt(bizErr.Code())
+	if !ok {
+		return &statusBizError{BizError: bizErr, httpCode: http.StatusInternalServerError}
+	}
+
+	var matched *CodeRange
+	for i, r := range config.CodeRanges {
+		if code < r.Min || code > r.Max {
+			continue
+		}
+		if matched == nil || r.Max-r.Min < matched.Max-matched.Min {
+			matched = &config.CodeRanges[i]
+		}
+	}
+	if matched == nil {
+		return &statusBizError{BizError: bizErr, httpCode: http.StatusInternalServerError}
+	}
+	return &statusBizError{BizError: bizErr, httpCode: matched.HTTPCode}
+}
+
+// codeInt 将整数或数字字符串类型的业务错误码转换为 int64
+func codeInt(code any) (int64, bool) {
+	v := reflect.ValueOf(code)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int(), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return int64(v.Uint()), true
+	case reflect.String:
+		n, err := strconv.ParseInt(v.String(), 10, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
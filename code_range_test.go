@@ -0,0 +1,72 @@
+package apihandler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试按错误码区间确定 HTTP 状态码
+func TestCodeRanges(t *testing.T) {
+	ranges := []CodeRange{
+		{Min: 40000, Max: 40999, HTTPCode: http.StatusBadRequest},
+		{Min: 40400, Max: 40499, HTTPCode: http.StatusNotFound},
+		{Min: 50000, Max: 59999, HTTPCode: http.StatusInternalServerError},
+	}
+
+	tests := []struct {
+		name         string
+		err          BizError
+		expectedHTTP int
+	}{
+		{"通用区间", NewBizError(40001, "参数错误", 0), http.StatusBadRequest},
+		{"范围更小的区间优先", NewBizError(40401, "用户不存在", 0), http.StatusNotFound},
+		{"字符串错误码", NewBizError("50001", "服务异常", 0), http.StatusInternalServerError},
+		{"指定了 HTTP 状态码", NewBizError(40401, "用户不存在", http.StatusGone), http.StatusGone},
+		{"带响应头的错误", NewHeaderedError(NewBizError(40402, "用户不存在", 0), http.Header{"X-Test": {"1"}}), http.StatusNotFound},
+		{"没有匹配的区间", NewBizError(30001, "未知错误", 0), http.StatusInternalServerError},
+		{"非数字错误码", NewBizError("USER_NOT_FOUND", "用户不存在", 0), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+				return nil, tt.err
+			}
+			r.GET("/test/:id", Handler(handleFunc, WithCodeRanges(ranges...)))
+
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest("GET", "/test/1", nil))
+
+			if w.Code != tt.expectedHTTP {
+				t.Errorf("期望状态码 %d, 实际得到 %d", tt.expectedHTTP, w.Code)
+			}
+		})
+	}
+}
+
+// 测试错误码转换
+func TestCodeInt(t *testing.T) {
+	tests := []struct {
+		code     any
+		expected int64
+		ok       bool
+	}{
+		{40400, 40400, true},
+		{uint16(404), 404, true},
+		{"40400", 40400, true},
+		{"USER_NOT_FOUND", 0, false},
+		{nil, 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := codeInt(tt.code)
+		if got != tt.expected || ok != tt.ok {
+			t.Errorf("codeInt(%v) 期望 (%d, %v), 实际得到 (%d, %v)", tt.code, tt.expected, tt.ok, got, ok)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package apihandler
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Compression 响应压缩算法，取值与 Content-Encoding 一致
+type Compression string
+
+// 支持的响应压缩算法
+const (
+	CompressionGzip    Compression = "gzip"
+	CompressionDeflate Compression = "deflate"
+)
+
+// compressWriter 可复用的压缩写入器
+type compressWriter interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+}
+
+// compressWriterPools 各压缩算法的写入器池
+var compressWriterPools = map[Compression]*sync.Pool{
+	CompressionGzip: {New: func() any {
+		return gzip.NewWriter(io.Discard)
+	}},
+	CompressionDeflate: {New: func() any {
+		w, _ := flate.NewWriter(io.Discard, flate.DefaultCompression)
+		return w
+	}},
+}
+
+// compressBody 按 Accept-Encoding 协商压缩算法并压缩响应体，不压缩时原样返回
+func compressBody(c *gin.Context, config *HandlerConfig, data []byte) []byte {
+	if len(config.Compression) == 0 {
+		return data
+	}
+	c.Writer.Header().Add("Vary", "Accept-Encoding")
+	if len(data) < config.CompressionMinSize || c.Writer.Header().Get("Content-Encoding") != "" {
+		return data
+	}
+
+	algorithm := negotiateEncoding(c.GetHeader("Accept-Encoding"), config.Compression)
+	pool, ok := compressWriterPools[algorithm]
+	if !ok {
+		return data
+	}
+
+	var buf bytes.Buffer
+	w := pool.Get().(compressWriter)
+	defer pool.Put(w)
+	w.Reset(&buf)
+	if _, err := w.Write(data); err != nil {
+		return data
+	}
+	if err := w.Close(); err != nil {
+		return data
+	}
+
+	c.Header("Content-Encoding", string(algorithm))
+	return buf.Bytes()
+}
+
+// negotiateEncoding 按 Accept-Encoding 头的权重选择压缩算法，无法匹配时返回空
+//
+// 通配符 * 只匹配请求头中未显式列出的算法，因此 "gzip;q=0, *" 不会选择 gzip。
+func negotiateEncoding(acceptEncoding string, offers []Compression) Compression {
+	listed := make(map[string]bool)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, _, _ := strings.Cut(part, ";")
+		listed[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+
+	for _, accepted := range parseAccept(acceptEncoding) {
+		for _, offer := range offers {
+			if accepted.value == string(offer) || (accepted.value == "*" && !listed[string(offer)]) {
+				return offer
+			}
+		}
+	}
+	return ""
+}
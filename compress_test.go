@@ -0,0 +1,127 @@
+package apihandler
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试按 Accept-Encoding 压缩响应
+func TestCompression(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return &testResponse{ID: req.ID, Message: strings.Repeat("a", 2048)}, nil
+	}
+
+	r.GET("/test/:id", Handler(handleFunc, WithCompression(1024, CompressionGzip, CompressionDeflate)))
+
+	cases := []struct {
+		acceptEncoding string
+		encoding       string
+	}{
+		{"", ""},
+		{"gzip", "gzip"},
+		{"deflate", "deflate"},
+		{"gzip, deflate", "gzip"},
+		{"gzip;q=0.5, deflate", "deflate"},
+		{"br", ""},
+		{"*", "gzip"},
+		{"gzip;q=0, *", "deflate"},
+		{"identity", ""},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", "/test/1", nil)
+		if tc.acceptEncoding != "" {
+			req.Header.Set("Accept-Encoding", tc.acceptEncoding)
+		}
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if got := w.Header().Get("Content-Encoding"); got != tc.encoding {
+			t.Errorf("Accept-Encoding '%s': 期望 Content-Encoding 为 '%s', 实际得到 '%s'", tc.acceptEncoding, tc.encoding, got)
+			continue
+		}
+
+		if vary := strings.Join(w.Header().Values("Vary"), ", "); !strings.Contains(vary, "Accept-Encoding") {
+			t.Errorf("Accept-Encoding '%s': 期望 Vary 包含 Accept-Encoding, 实际得到 '%s'", tc.acceptEncoding, vary)
+		}
+
+		var body io.Reader = w.Body
+		switch tc.encoding {
+		case "gzip":
+			gr, err := gzip.NewReader(body)
+			if err != nil {
+				t.Fatalf("创建 gzip 读取器失败: %v", err)
+			}
+			body = gr
+		case "deflate":
+			body = flate.NewReader(body)
+		}
+
+		var resp SuccessResponse[testResponse]
+		if err := json.NewDecoder(body).Decode(&resp); err != nil {
+			t.Fatalf("Accept-Encoding '%s': 解析响应失败: %v", tc.acceptEncoding, err)
+		}
+
+		if resp.Data.ID != 1 || len(resp.Data.Message) != 2048 {
+			t.Errorf("Accept-Encoding '%s': 期望解压后得到原始响应, 实际得到 id=%d, message 长度 %d", tc.acceptEncoding, resp.Data.ID, len(resp.Data.Message))
+		}
+	}
+}
+
+// 测试小于最小字节数的响应不压缩
+func TestCompressionMinSize(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return &testResponse{ID: req.ID}, nil
+	}
+
+	r.GET("/test/:id", Handler(handleFunc, WithCompression(1024)))
+
+	req := httptest.NewRequest("GET", "/test/1", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("期望小响应不压缩, 实际得到 Content-Encoding '%s'", got)
+	}
+
+	expected := `{"code":0,"data":{"id":1,"name":"","age":0,"message":""}}`
+	if w.Body.String() != expected {
+		t.Errorf("期望响应为 %s, 实际得到 %s", expected, w.Body.String())
+	}
+}
+
+// 测试未开启压缩的处理器不受影响
+func TestCompressionDisabled(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return &testResponse{ID: req.ID, Message: strings.Repeat("a", 2048)}, nil
+	}
+
+	r.GET("/test/:id", Handler(handleFunc))
+
+	req := httptest.NewRequest("GET", "/test/1", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Content-Encoding"); got != "" {
+		t.Errorf("期望不压缩, 实际得到 Content-Encoding '%s'", got)
+	}
+}
@@ -0,0 +1,510 @@
+package apihandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"mime"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContractSettings 契约测试的配置
+type ContractSettings struct {
+	Header  http.Header             // 每个请求附加的请求头，如认证信息
+	Prepare func(*http.Request)     // 发送请求前调用，可按路由设置请求头或替换路径参数
+	Skip    func(op Operation) bool // 返回 true 时跳过该操作
+}
+
+// Operation 文档中的一个操作
+type Operation struct {
+	Method string // HTTP 方法
+	Path   string // OpenAPI 路径，如 /users/{id}
+	*OpenAPIOperation
+}
+
+// ContractViolation 契约测试发现的响应与文档不一致之处
+type ContractViolation struct {
+	Method  string // HTTP 方法
+	Path    string // OpenAPI 路径
+	Status  int    // 响应的 HTTP 状态码，请求未发送时为 0
+	Message string // 不一致的描述
+}
+
+// Error 实现 error 接口
+func (v ContractViolation) Error() string {
+	return fmt.Sprintf("%s %s -> %d: %s", v.Method, v.Path, v.Status, v.Message)
+}
+
+// ContractT 契约测试使用的测试接口，*testing.T 实现了该接口
+type ContractT interface {
+	Helper()
+	Errorf(format string, args ...any)
+}
+
+// AssertContract 对文档中的每个操作执行契约测试，每个不一致之处报告为一个测试错误
+//
+//	func TestContract(t *testing.T) {
+//		spec := registry.OpenAPI(apihandler.OpenAPIInfo{Title: "API", Version: "1.0.0"})
+//		apihandler.AssertContract(t, router, spec, apihandler.ContractSettings{})
+//	}
+func AssertContract(t ContractT, handler http.Handler, spec *OpenAPI, settings ContractSettings) {
+	t.Helper()
+	for _, violation := range VerifyContract(handler, spec, settings) {
+		t.Errorf("%s", violation.Error())
+	}
+}
+
+// VerifyContract 根据文档中的示例构造请求并发送到 handler，验证响应的状态码和 JSON 结构是否与文档一致
+//
+// 参数和请求体优先使用文档中的示例值，没有示例时根据 Schema 生成：必填字段和有示例的字段才会出现在请求中。
+// 响应的状态码必须在文档中声明（或有 default 响应），JSON 响应体按声明的 Schema 验证类型、必填字段、
+// 枚举和取值范围，并检查是否出现文档中未声明的属性。生成的 Schema 不区分可空类型，null 视为符合任何类型。
+//
+// spec 可以是当前路由生成的文档，也可以是从已发布的 openapi.json 解析的文档，用于发现代码与契约的偏差。
+func VerifyContract(handler http.Handler, spec *OpenAPI, settings ContractSettings) []ContractViolation {
+	s := contractSchemas(spec.Components.Schemas)
+	var violations []ContractViolation
+	for _, op := range specOperations(spec) {
+		if settings.Skip != nil && settings.Skip(op) {
+			continue
+		}
+
+		report := func(status int, format string, args ...any) {
+			violations = append(violations, ContractViolation{
+				Method: op.Method, Path: op.Path, Status: status, Message: fmt.Sprintf(format, args...),
+			})
+		}
+
+		req, err := s.request(op)
+		if err != nil {
+			report(0, "build request: %v", err)
+			continue
+		}
+		for key, values := range settings.Header {
+			req.Header[key] = append(req.Header[key], values...)
+		}
+		if settings.Prepare != nil {
+			settings.Prepare(req)
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		for _, message := range s.response(op, w) {
+			report(w.Code, "%s", message)
+		}
+	}
+	return violations
+}
+
+// specOperations 按路径和方法排序返回文档中的所有操作
+func specOperations(spec *OpenAPI) []Operation {
+	var ops []Operation
+	for _, path := range slices.Sorted(maps.Keys(spec.Paths)) {
+		item := spec.Paths[path]
+		for _, m := range []struct {
+			method string
+			op     *OpenAPIOperation
+		}{
+			{http.MethodGet, item.Get}, {http.MethodPut, item.Put}, {http.MethodPost, item.Post},
+			{http.MethodDelete, item.Delete}, {http.MethodOptions, item.Options},
+			{http.MethodHead, item.Head}, {http.MethodPatch, item.Patch},
+		} {
+			if m.op != nil {
+				ops = append(ops, Operation{Method: m.method, Path: path, OpenAPIOperation: m.op})
+			}
+		}
+	}
+	return ops
+}
+
+// contractSchemas 文档中的具名 Schema，用于解析 $ref
+type contractSchemas map[string]*Schema
+
+// resolve 解析 $ref 引用，无法解析时返回 nil
+func (s contractSchemas) resolve(schema *Schema) *Schema {
+	for depth := 0; schema != nil && schema.Ref != ""; depth++ {
+		if depth > 32 {
+			return nil
+		}
+		schema = s[schema.Ref[strings.LastIndex(schema.Ref, "/")+1:]]
+	}
+	return schema
+}
+
+// request 根据操作的示例构造请求
+func (s contractSchemas) request(op Operation) (*http.Request, error) {
+	path := op.Path
+	query := url.Values{}
+	header := http.Header{}
+	for _, param := range op.Parameters {
+		value, ok := param.Example, param.Example != nil
+		if !ok && param.Required {
+			value, ok = s.sample(param.Schema, 0)
+		}
+		if !ok {
+			continue
+		}
+		switch param.In {
+		case "path":
+			path = strings.ReplaceAll(path, "{"+param.Name+"}", url.PathEscape(fmt.Sprint(value)))
+		case "query":
+			for _, v := range contractValues(value) {
+				query.Add(param.Name, v)
+			}
+		case "header":
+			header.Set(param.Name, fmt.Sprint(value))
+		}
+	}
+	if op.APIVersion > 0 && header.Get(DefaultVersionHeader) == "" {
+		header.Set(DefaultVersionHeader, strconv.Itoa(op.APIVersion))
+	}
+	if len(query) > 0 {
+		path += "?" + query.Encode()
+	}
+
+	var body []byte
+	if op.RequestBody != nil {
+		for _, contentType := range slices.Sorted(maps.Keys(op.RequestBody.Content)) {
+			media := op.RequestBody.Content[contentType]
+			value, _ := s.sample(media.Schema, 0)
+			if fields, ok := value.(map[string]any); ok {
+				if example, ok := media.Example.(map[string]any); ok {
+					maps.Copy(fields, example)
+				}
+			} else if media.Example != nil {
+				value = media.Example
+			}
+			var err error
+			if body, err = encodeContractBody(contentType, value); err != nil {
+				return nil, err
+			}
+			header.Set("Content-Type", contentType)
+			break
+		}
+	}
+
+	req, err := http.NewRequest(op.Method, path, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header = header
+	req.Header.Set("Accept", "application/json")
+	return req, nil
+}
+
+// encodeContractBody 按内容类型编码请求体
+func encodeContractBody(contentType string, value any) ([]byte, error) {
+	if contentType != "application/x-www-form-urlencoded" {
+		return json.Marshal(value)
+	}
+	form := url.Values{}
+	if fields, ok := value.(map[string]any); ok {
+		for name, v := range fields {
+			form[name] = contractValues(v)
+		}
+	}
+	return []byte(form.Encode()), nil
+}
+
+// contractValues 将参数的值转换为字符串，切片转换为多个值
+func contractValues(value any) []string {
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice || v.Type().Elem().Kind() == reflect.Uint8 {
+		return []string{fmt.Sprint(value)}
+	}
+	values := make([]string, v.Len())
+	for i := range values {
+		values[i] = fmt.Sprint(v.Index(i).Interface())
+	}
+	return values
+}
+
+// response 验证响应的状态码和响应体，返回不一致之处
+func (s contractSchemas) response(op Operation, w *httptest.ResponseRecorder) []string {
+	resp := op.Responses[strconv.Itoa(w.Code)]
+	if resp == nil {
+		resp = op.Responses["default"]
+	}
+	if resp == nil {
+		return []string{fmt.Sprintf("status %d is not declared", w.Code)}
+	}
+	media := resp.Content["application/json"]
+	if media == nil || (w.Body.Len() == 0 && (w.Code == http.StatusNoContent || w.Code == http.StatusNotModified)) {
+		return nil
+	}
+
+	if contentType, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type")); contentType != "application/json" && !strings.HasSuffix(contentType, "+json") {
+		return []string{fmt.Sprintf("content type %q is not application/json", w.Header().Get("Content-Type"))}
+	}
+	var body any
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		return []string{fmt.Sprintf("response is not valid JSON: %v", err)}
+	}
+	return s.validate(media.Schema, body, "$")
+}
+
+// validate 按 Schema 验证 JSON 值，返回不一致之处
+func (s contractSchemas) validate(schema *Schema, value any, path string) []string {
+	if schema != nil && schema.Ref != "" {
+		resolved := s.resolve(schema)
+		if resolved == nil {
+			return []string{fmt.Sprintf("%s: unresolved $ref %q", path, schema.Ref)}
+		}
+		schema = resolved
+	}
+	if schema == nil || value == nil {
+		return nil
+	}
+
+	if !matchesType(schema.Type, value) {
+		return []string{fmt.Sprintf("%s: expected %s, got %s", path, schema.Type, jsonType(value))}
+	}
+	var problems []string
+	report := func(format string, args ...any) {
+		problems = append(problems, path+": "+fmt.Sprintf(format, args...))
+	}
+	if len(schema.Enum) > 0 && !slices.ContainsFunc(schema.Enum, func(e any) bool { return jsonEqual(e, value) }) {
+		enum, _ := json.Marshal(schema.Enum)
+		got, _ := json.Marshal(value)
+		report("%s is not one of %s", got, enum)
+	}
+
+	switch v := value.(type) {
+	case float64:
+		if schema.Minimum != nil && v < *schema.Minimum {
+			report("%v is less than minimum %v", v, *schema.Minimum)
+		}
+		if schema.Maximum != nil && v > *schema.Maximum {
+			report("%v is greater than maximum %v", v, *schema.Maximum)
+		}
+		if schema.ExclusiveMinimum != nil && v <= *schema.ExclusiveMinimum {
+			report("%v is not greater than %v", v, *schema.ExclusiveMinimum)
+		}
+		if schema.ExclusiveMaximum != nil && v >= *schema.ExclusiveMaximum {
+			report("%v is not less than %v", v, *schema.ExclusiveMaximum)
+		}
+	case string:
+		n := utf8.RuneCountInString(v)
+		if schema.MinLength != nil && n < *schema.MinLength {
+			report("length %d is less than %d", n, *schema.MinLength)
+		}
+		if schema.MaxLength != nil && n > *schema.MaxLength {
+			report("length %d is greater than %d", n, *schema.MaxLength)
+		}
+		if schema.Pattern != "" {
+			if re, err := regexp.Compile(schema.Pattern); err == nil && !re.MatchString(v) {
+				report("%q does not match pattern %s", v, schema.Pattern)
+			}
+		}
+		if !matchesFormat(schema.Format, v) {
+			report("%q is not a valid %s", v, schema.Format)
+		}
+	case []any:
+		if schema.MinItems != nil && len(v) < *schema.MinItems {
+			report("%d items is less than %d", len(v), *schema.MinItems)
+		}
+		if schema.MaxItems != nil && len(v) > *schema.MaxItems {
+			report("%d items is greater than %d", len(v), *schema.MaxItems)
+		}
+		for i, item := range v {
+			problems = append(problems, s.validate(schema.Items, item, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case map[string]any:
+		for _, name := range schema.Required {
+			if _, ok := v[name]; !ok {
+				report("missing required property %q", name)
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(v)) {
+			property, declared := schema.Properties[name]
+			switch {
+			case declared:
+				problems = append(problems, s.validate(property, v[name], path+"."+name)...)
+			case schema.AdditionalProperties != nil:
+				problems = append(problems, s.validate(schema.AdditionalProperties, v[name], path+"."+name)...)
+			case schema.Properties != nil:
+				report("property %q is not declared", name)
+			}
+		}
+	}
+	return problems
+}
+
+// matchesType 判断 JSON 值是否为 Schema 的类型，类型为空时匹配任何值
+func matchesType(schemaType string, value any) bool {
+	switch schemaType {
+	case "":
+		return true
+	case "integer":
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n)
+	}
+	return jsonType(value) == schemaType
+}
+
+// jsonType 返回 JSON 值的类型名称
+func jsonType(value any) string {
+	switch value.(type) {
+	case bool:
+		return "boolean"
+	case float64:
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+	return "null"
+}
+
+// jsonEqual 判断两个值编码为 JSON 后是否相同，用于比较枚举值
+func jsonEqual(a, b any) bool {
+	x, err1 := json.Marshal(a)
+	y, err2 := json.Marshal(b)
+	return err1 == nil && err2 == nil && bytes.Equal(x, y)
+}
+
+// uuidPattern UUID 的格式
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// matchesFormat 判断字符串是否符合 Schema 的格式，不支持的格式视为符合
+func matchesFormat(format, v string) bool {
+	switch format {
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, v)
+		return err == nil
+	case "email":
+		_, err := mail.ParseAddress(v)
+		return err == nil
+	case "uuid":
+		return uuidPattern.MatchString(v)
+	case "uri":
+		u, err := url.Parse(v)
+		return err == nil && u.Scheme != ""
+	case "ipv4":
+		ip := net.ParseIP(v)
+		return ip != nil && ip.To4() != nil
+	case "ipv6":
+		ip := net.ParseIP(v)
+		return ip != nil && ip.To4() == nil
+	}
+	return true
+}
+
+// sample 生成符合 Schema 的示例值：优先使用 examples 和 enum，对象只包含必填和有示例的属性
+func (s contractSchemas) sample(schema *Schema, depth int) (any, bool) {
+	schema = s.resolve(schema)
+	if schema == nil || depth > 8 {
+		return nil, false
+	}
+	if len(schema.Examples) > 0 {
+		return schema.Examples[0], true
+	}
+	if len(schema.Enum) > 0 {
+		return schema.Enum[0], true
+	}
+
+	switch schema.Type {
+	case "string":
+		return sampleString(schema), true
+	case "integer", "number":
+		n := 1.0
+		switch {
+		case schema.Minimum != nil:
+			n = *schema.Minimum
+		case schema.ExclusiveMinimum != nil:
+			n = math.Floor(*schema.ExclusiveMinimum) + 1
+		case schema.Maximum != nil:
+			n = math.Min(n, *schema.Maximum)
+		case schema.ExclusiveMaximum != nil:
+			n = math.Min(n, math.Ceil(*schema.ExclusiveMaximum)-1)
+		}
+		return n, true
+	case "boolean":
+		return true, true
+	case "array":
+		item, ok := s.sample(schema.Items, depth+1)
+		n := 1
+		if schema.MinItems != nil {
+			n = max(*schema.MinItems, n)
+		}
+		if !ok || (schema.MaxItems != nil && *schema.MaxItems < n) {
+			return []any{}, true
+		}
+		items := make([]any, n)
+		for i := range items {
+			items[i] = item
+		}
+		return items, true
+	case "object":
+		object := make(map[string]any)
+		for name, property := range schema.Properties {
+			resolved := s.resolve(property)
+			if !slices.Contains(schema.Required, name) && (resolved == nil || len(resolved.Examples) == 0) {
+				continue
+			}
+			if v, ok := s.sample(property, depth+1); ok {
+				object[name] = v
+			}
+		}
+		return object, true
+	}
+	return nil, false
+}
+
+// sampleString 生成符合长度、格式和正则约束的字符串
+func sampleString(schema *Schema) string {
+	switch schema.Format {
+	case "date-time":
+		return "2024-01-02T03:04:05Z"
+	case "email":
+		return "user@example.com"
+	case "uuid":
+		return "00000000-0000-4000-8000-000000000000"
+	case "uri":
+		return "https://example.com"
+	case "ipv4":
+		return "192.0.2.1"
+	case "ipv6":
+		return "2001:db8::1"
+	case "hostname":
+		return "example.com"
+	}
+
+	lo, hi := 1, 7
+	if schema.MinLength != nil {
+		lo = max(*schema.MinLength, lo)
+	}
+	if schema.MaxLength != nil {
+		hi = *schema.MaxLength
+	}
+	candidates := []string{"example", strings.Repeat("a", lo), strings.Repeat("1", lo)}
+	for _, v := range candidates {
+		n := len(v)
+		if n < lo || (schema.MaxLength != nil && n > hi) {
+			continue
+		}
+		if schema.Pattern != "" {
+			if re, err := regexp.Compile(schema.Pattern); err == nil && !re.MatchString(v) {
+				continue
+			}
+		}
+		return v
+	}
+	return candidates[1]
+}
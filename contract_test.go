@@ -0,0 +1,160 @@
+package apihandler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// contractUser 测试用的用户
+type contractUser struct {
+	ID    int64    `json:"id" example:"1"`
+	Name  string   `json:"name" binding:"required,min=2"`
+	Role  string   `json:"role" binding:"oneof=admin member"`
+	Tags  []string `json:"tags,omitempty"`
+	Email string   `json:"email,omitempty" binding:"omitempty,email"`
+}
+
+// contractGetRequest 测试用的查询请求
+type contractGetRequest struct {
+	ID int64 `path:"id" example:"7"`
+}
+
+// contractCreateRequest 测试用的创建请求
+type contractCreateRequest struct {
+	Name string `json:"name" binding:"required,min=2" example:"tom"`
+	Role string `json:"role" binding:"required,oneof=admin member"`
+}
+
+// contractT 记录测试错误的 ContractT
+type contractT struct {
+	errors []string
+}
+
+func (t *contractT) Helper() {}
+
+func (t *contractT) Errorf(format string, args ...any) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+// newContractRouter 创建契约测试使用的路由，role 为响应中返回的角色
+func newContractRouter(role string) (*gin.Engine, *Registry, *[]string) {
+	r := gin.New()
+	registry := NewRegistry()
+	api := NewGroup(r.Group("/api"), WithRegistry(registry))
+	var requests []string
+
+	GET(api, "/users/:id", func(ctx context.Context, req *contractGetRequest) (*contractUser, error) {
+		requests = append(requests, fmt.Sprintf("get %d", req.ID))
+		return &contractUser{ID: req.ID, Name: "tom", Role: role}, nil
+	})
+	POST(api, "/users", func(ctx context.Context, req *contractCreateRequest) (*contractUser, error) {
+		requests = append(requests, fmt.Sprintf("create %s %s", req.Name, req.Role))
+		return &contractUser{ID: 1, Name: req.Name, Role: req.Role}, nil
+	}, WithSuccessHTTPCode(http.StatusCreated))
+	DELETE(api, "/users/:id", func(ctx context.Context, req *contractGetRequest) (*contractUser, error) {
+		return nil, NewBizError(40401, "用户不存在", http.StatusNotFound)
+	})
+	return r, registry, &requests
+}
+
+// 测试根据文档示例回放请求并验证响应
+func TestVerifyContract(t *testing.T) {
+	r, registry, requests := newContractRouter("admin")
+	spec := registry.OpenAPI(OpenAPIInfo{Title: "API", Version: "1.0.0"})
+
+	if violations := VerifyContract(r, spec, ContractSettings{}); len(violations) > 0 {
+		t.Fatalf("期望响应与文档一致, 实际得到 %v", violations)
+	}
+	if strings.Join(*requests, ";") != "create tom admin;get 7" {
+		t.Errorf("期望使用示例值和 Schema 生成请求, 实际得到 %v", *requests)
+	}
+
+	// 验证失败的错误响应同样符合文档
+	spec.Paths["/api/users"].Post.RequestBody.Content["application/json"].Example = map[string]any{"name": "x", "role": "guest"}
+	if violations := VerifyContract(r, spec, ContractSettings{}); len(violations) > 0 {
+		t.Errorf("期望错误响应与文档一致, 实际得到 %v", violations)
+	}
+}
+
+// 测试发现响应与文档的偏差
+func TestVerifyContractDrift(t *testing.T) {
+	r, registry, _ := newContractRouter("guest")
+	spec := registry.OpenAPI(OpenAPIInfo{Title: "API", Version: "1.0.0"})
+	delete(spec.Components.Schemas["contractUser"].Properties, "name")
+	delete(spec.Paths["/api/users/{id}"].Delete.Responses, "default")
+
+	ct := &contractT{}
+	AssertContract(ct, r, spec, ContractSettings{})
+	got := strings.Join(ct.errors, "\n")
+	for _, want := range []string{
+		`GET /api/users/{id} -> 200: $.data.role: "guest" is not one of ["admin","member"]`,
+		`GET /api/users/{id} -> 200: $.data: property "name" is not declared`,
+		`POST /api/users -> 201: $.data: property "name" is not declared`,
+		`DELETE /api/users/{id} -> 404: status 404 is not declared`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("期望报告 %s, 实际得到:\n%s", want, got)
+		}
+	}
+}
+
+// 测试契约测试的配置
+func TestVerifyContractSettings(t *testing.T) {
+	r, registry, requests := newContractRouter("admin")
+	spec := registry.OpenAPI(OpenAPIInfo{Title: "API", Version: "1.0.0"})
+
+	var tokens []string
+	handler := http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		tokens = append(tokens, req.Header.Get("Authorization"))
+		r.ServeHTTP(w, req)
+	})
+	violations := VerifyContract(handler, spec, ContractSettings{
+		Header: http.Header{"Authorization": {"Bearer token"}},
+		Prepare: func(req *http.Request) {
+			req.URL.Path = strings.Replace(req.URL.Path, "/7", "/8", 1)
+		},
+		Skip: func(op Operation) bool { return op.Method == http.MethodDelete },
+	})
+	if len(violations) > 0 {
+		t.Fatalf("期望响应与文档一致, 实际得到 %v", violations)
+	}
+	if strings.Join(*requests, ";") != "create tom admin;get 8" {
+		t.Errorf("期望 Prepare 修改请求、Skip 跳过操作, 实际得到 %v", *requests)
+	}
+	if strings.Join(tokens, ";") != "Bearer token;Bearer token" {
+		t.Errorf("期望每个请求附加请求头, 实际得到 %v", tokens)
+	}
+}
+
+// contractPageRequest 测试用的分页请求
+type contractPageRequest struct {
+	PageRequest
+}
+
+// 测试列表和分页响应的 data 数组和 meta 符合文档
+func TestVerifyContractList(t *testing.T) {
+	r := gin.New()
+	registry := NewRegistry()
+	api := NewGroup(r.Group("/api"), WithRegistry(registry))
+	users := []contractUser{{ID: 1, Name: "tom", Role: "admin"}, {ID: 2, Name: "amy", Role: "member"}}
+
+	GET(api, "/users", func(ctx context.Context, req *struct{}) (*ListResponse[contractUser], error) {
+		return &ListResponse[contractUser]{Items: users, ListMeta: ListMeta{Total: 2}}, nil
+	})
+	GET(api, "/pages", func(ctx context.Context, req *contractPageRequest) (*PageResult[contractUser], error) {
+		return NewPageResult(req.PageRequest, users, 2), nil
+	})
+	GET(api, "/empty", func(ctx context.Context, req *struct{}) (*ListResponse[contractUser], error) {
+		return &ListResponse[contractUser]{}, nil
+	})
+
+	spec := registry.OpenAPI(OpenAPIInfo{Title: "API", Version: "1.0.0"})
+	if violations := VerifyContract(r, spec, ContractSettings{}); len(violations) > 0 {
+		t.Errorf("期望列表和分页响应与文档一致, 实际得到 %v", violations)
+	}
+}
@@ -0,0 +1,143 @@
+package apihandler
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// NamedField 跨字段规则中的字段名称和值
+type NamedField struct {
+	Name  string
+	Value any
+}
+
+// Field 创建跨字段规则中的字段，name 为错误详情中输出的字段名
+func Field(name string, value any) NamedField {
+	return NamedField{Name: name, Value: value}
+}
+
+// CrossFieldRules 收集跨字段验证的错误，通常在请求类型的 Validate 方法中使用
+//
+// 每条规则失败时生成一个 FieldError，Related 为规则涉及的其他字段，消息按请求的语言环境翻译，
+// 消息键为 ValidationMessageKey(规则名称)，可以通过 RegisterValidationMessage 覆盖：
+//
+//	func (r *SearchRequest) Validate(ctx context.Context) error {
+//		return apihandler.CrossFields(ctx).
+//			DateRange("start_at", r.StartAt, "end_at", r.EndAt).
+//			RequireAny(apihandler.Field("email", r.Email), apihandler.Field("mobile", r.Mobile)).
+//			Err()
+//	}
+type CrossFieldRules struct {
+	translator Translator
+	errs       []error
+}
+
+// CrossFields 创建跨字段规则，消息使用处理器为当前请求使用的翻译器（见 TranslatorFrom）
+func CrossFields(ctx context.Context) *CrossFieldRules {
+	return &CrossFieldRules{translator: TranslatorFrom(ctx)}
+}
+
+// DateRange 检查结束时间不早于开始时间，任一时间为零值时不检查，规则名称为 date_range
+//
+// 错误详情的字段为 endField，Param 和 Related 为 startField。
+func (r *CrossFieldRules) DateRange(startField string, start time.Time, endField string, end time.Time) *CrossFieldRules {
+	if start.IsZero() || end.IsZero() || !end.Before(start) {
+		return r
+	}
+	return r.add(MsgValidationDateRange, FieldError{
+		Field:   endField,
+		Rule:    "date_range",
+		Param:   startField,
+		Related: []string{startField},
+		Value:   end,
+	})
+}
+
+// RequireAny 检查字段中至少有一个不是零值，规则名称为 required_any
+//
+// 错误详情的字段为第一个字段，Related 为其他字段。
+func (r *CrossFieldRules) RequireAny(fields ...NamedField) *CrossFieldRules {
+	if len(fields) == 0 {
+		return r
+	}
+	for _, f := range fields {
+		if fieldValue(f.Value) != nil {
+			return r
+		}
+	}
+	return r.add(MsgValidationRequiredAny, FieldError{
+		Field:   fields[0].Name,
+		Rule:    "required_any",
+		Related: fieldNames(fields[1:]),
+	})
+}
+
+// SumMax 检查数字字段之和不超过 limit，规则名称为 sum_max
+//
+// 错误详情的字段为第一个字段，Param 为 limit，Related 为其他字段，Value 为字段之和。
+// 字段的值必须是整数、浮点数或其指针，nil 视为 0。
+func (r *CrossFieldRules) SumMax(limit float64, fields ...NamedField) *CrossFieldRules {
+	if len(fields) == 0 {
+		return r
+	}
+	sum := 0.0
+	for _, f := range fields {
+		sum += numberValue(f.Value)
+	}
+	if sum <= limit {
+		return r
+	}
+	return r.add(MsgValidationSumMax, FieldError{
+		Field:   fields[0].Name,
+		Rule:    "sum_max",
+		Param:   strconv.FormatFloat(limit, 'f', -1, 64),
+		Related: fieldNames(fields[1:]),
+		Value:   sum,
+	})
+}
+
+// Err 返回收集的字段错误，没有错误时返回 nil
+//
+// 多个错误通过 errors.Join 合并，Validate 方法返回后全部作为错误详情输出。
+func (r *CrossFieldRules) Err() error {
+	return errors.Join(r.errs...)
+}
+
+// add 翻译规则的消息并添加字段错误，key 与 ValidationMessageKey(fe.Rule) 相同
+func (r *CrossFieldRules) add(key MessageKey, fe FieldError) *CrossFieldRules {
+	fe.Message = formatFieldMessage(r.translator.Translate(key), fe)
+	r.errs = append(r.errs, fe)
+	return r
+}
+
+// fieldNames 返回字段的名称
+func fieldNames(fields []NamedField) []string {
+	names := make([]string, len(fields))
+	for i, f := range fields {
+		names[i] = f.Name
+	}
+	return names
+}
+
+// numberValue 将整数、浮点数或其指针转换为 float64，其他值返回 0
+func numberValue(value any) float64 {
+	v := reflect.ValueOf(value)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return 0
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	}
+	return 0
+}
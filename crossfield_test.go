@@ -0,0 +1,101 @@
+package apihandler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试跨字段规则
+func TestCrossFields(t *testing.T) {
+	start := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	end := start.Add(-time.Hour)
+	email, quota := "", 3
+
+	err := CrossFields(context.Background()).
+		DateRange("start_at", start, "end_at", end).
+		DateRange("start_at", start, "end_at", time.Time{}).
+		RequireAny(Field("email", email), Field("mobile", (*string)(nil))).
+		RequireAny(Field("email", "a@example.com"), Field("mobile", nil)).
+		SumMax(10, Field("cpu", 4), Field("memory", 6.5), Field("quota", &quota)).
+		SumMax(20, Field("cpu", 4), Field("memory", 6.5)).
+		Err()
+
+	errs := FieldErrors(joinedDetails(err))
+	if len(errs) != 3 {
+		t.Fatalf("期望 3 个字段错误, 实际得到 %+v", errs)
+	}
+	if e := errs[0]; e.Field != "end_at" || e.Rule != "date_range" || e.Param != "start_at" || !slices.Equal(e.Related, []string{"start_at"}) || e.Message != "end_at 不能早于 start_at" {
+		t.Errorf("期望日期范围错误引用两个字段, 实际得到 %+v", e)
+	}
+	if e := errs[1]; e.Field != "email" || e.Rule != "required_any" || !slices.Equal(e.Related, []string{"mobile"}) || e.Message != "email 和 mobile 至少需要填写一项" {
+		t.Errorf("期望至少填写一项的错误引用两个字段, 实际得到 %+v", e)
+	}
+	if e := errs[2]; e.Field != "cpu" || e.Param != "10" || e.Value != 13.5 || !slices.Equal(e.Related, []string{"memory", "quota"}) || e.Message != "cpu 与 memory, quota 之和不能超过 10" {
+		t.Errorf("期望数字之和的错误包含上限和实际值, 实际得到 %+v", e)
+	}
+
+	if err := CrossFields(context.Background()).DateRange("start_at", start, "end_at", start).Err(); err != nil {
+		t.Errorf("期望没有错误时返回 nil, 实际得到 %v", err)
+	}
+}
+
+// joinedDetails 返回 errors.Join 合并的错误
+func joinedDetails(err error) []any {
+	var details []any
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, e := range joined.Unwrap() {
+			details = append(details, e)
+		}
+	}
+	return details
+}
+
+// crossFieldRequest 测试用的使用跨字段规则的请求
+type crossFieldRequest struct {
+	StartAt time.Time `json:"start_at"`
+	EndAt   time.Time `json:"end_at"`
+	Email   string    `json:"email"`
+	Mobile  string    `json:"mobile"`
+}
+
+// Validate 实现 ContextValidator 接口
+func (r *crossFieldRequest) Validate(ctx context.Context) error {
+	return CrossFields(ctx).
+		DateRange("start_at", r.StartAt, "end_at", r.EndAt).
+		RequireAny(Field("email", r.Email), Field("mobile", r.Mobile)).
+		Err()
+}
+
+// 测试在 Validate 方法中使用跨字段规则
+func TestCrossFieldsInValidate(t *testing.T) {
+	r := gin.New()
+	r.POST("/search", Handler(func(ctx context.Context, req *crossFieldRequest) (*struct{}, error) {
+		return &struct{}{}, nil
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/search", strings.NewReader(`{"start_at":"2024-01-02T00:00:00Z","end_at":"2024-01-01T00:00:00Z"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", "en")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("期望状态码 %d, 实际得到 %d", http.StatusBadRequest, w.Code)
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		`"field":"end_at","rule":"date_range","param":"start_at","related":["start_at"],"message":"end_at must not be earlier than start_at"`,
+		`"message":"At least one of email and mobile is required"`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("期望响应包含 %s, 实际得到 %s", want, body)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package apihandler
+
+import (
+	"errors"
+	"fmt"
+)
+
+// WithDebug 开启调试模式，非业务错误的错误响应中包含 stack 和 cause_chain 字段，
+// 便于本地开发和测试环境排查问题，生产环境不应开启
+func WithDebug() Option {
+	return func(c *HandlerConfig) {
+		c.Debug = true
+	}
+}
+
+// panicError 处理函数 panic 转换得到的错误，保留 panic 的值和调用栈
+type panicError struct {
+	value any
+	stack []byte
+}
+
+// Error 实现 error 接口
+func (e *panicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.value)
+}
+
+// debugDetails 返回非业务错误的调用栈和错误链，业务错误返回空
+//
+// panic 使用 recover 时的调用栈；普通错误使用错误链中 %+v 格式化的结果（如 github.com/pkg/errors 记录的调用栈），
+// 与 Error() 相同时视为没有调用栈。
+func debugDetails(err BizError) (stack string, chain []string) {
+	var p *panicError
+	if errors.As(err, &p) {
+		return string(p.stack), []string{p.Error()}
+	}
+
+	var cause error
+	var masked *maskedError
+	var internal *internalError
+	switch {
+	case errors.As(err, &masked):
+		cause = masked.cause
+	case errors.As(err, &internal):
+		cause = internal.cause
+	default:
+		return "", nil
+	}
+
+	return errorStack(cause), causeChain(cause)
+}
+
+// errorStack 返回错误链中第一个 %+v 格式化结果与 Error() 不同的错误的格式化结果
+func errorStack(err error) string {
+	for err != nil {
+		if s := fmt.Sprintf("%+v", err); s != err.Error() {
+			return s
+		}
+		err = errors.Unwrap(err)
+	}
+	return ""
+}
+
+// causeChain 按 Unwrap 的顺序返回错误链中每个错误的消息
+func causeChain(err error) []string {
+	var chain []string
+	var walk func(err error)
+	walk = func(err error) {
+		if err == nil {
+			return
+		}
+		chain = append(chain, err.Error())
+		switch e := err.(type) {
+		case interface{ Unwrap() error }:
+			walk(e.Unwrap())
+		case interface{ Unwrap() []error }:
+			for _, inner := range e.Unwrap() {
+				walk(inner)
+			}
+		}
+	}
+	walk(err)
+	return chain
+}
@@ -0,0 +1,90 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// stackError 模拟 %+v 时输出调用栈的错误
+type stackError struct {
+	msg string
+}
+
+func (e *stackError) Error() string { return e.msg }
+
+func (e *stackError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		fmt.Fprintf(s, "%s\nmain.handleCreateUser\n\t/app/main.go:42", e.msg)
+		return
+	}
+	fmt.Fprint(s, e.msg)
+}
+
+// 测试调试模式下的调用栈和错误链
+func TestDebugMode(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		switch req.ID {
+		case 1:
+			return nil, fmt.Errorf("create user: %w", fmt.Errorf("insert: %w", errors.New("connection refused")))
+		case 2:
+			return nil, fmt.Errorf("create user: %w", &stackError{msg: "connection refused"})
+		case 3:
+			panic("boom")
+		default:
+			return nil, ErrNotFound(40400, "资源不存在")
+		}
+	}
+	r.GET("/debug/:id", Handler(handleFunc, WithDebug()))
+	r.GET("/test/:id", Handler(handleFunc))
+
+	get := func(path string) ErrorResponse {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		var resp ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("解析响应失败: %v", err)
+		}
+		return resp
+	}
+
+	resp := get("/debug/1")
+	want := []string{"create user: insert: connection refused", "insert: connection refused", "connection refused"}
+	if strings.Join(resp.CauseChain, "|") != strings.Join(want, "|") {
+		t.Errorf("期望错误链 %v, 实际得到 %v", want, resp.CauseChain)
+	}
+	if resp.Stack != "" {
+		t.Errorf("期望没有调用栈的错误不输出 stack, 实际得到 %q", resp.Stack)
+	}
+
+	resp = get("/debug/2")
+	if !strings.Contains(resp.Stack, "main.handleCreateUser") {
+		t.Errorf("期望 stack 包含 %%+v 输出的调用栈, 实际得到 %q", resp.Stack)
+	}
+
+	resp = get("/debug/3")
+	if !strings.Contains(resp.Stack, "debug_test.go") || len(resp.CauseChain) != 1 || resp.CauseChain[0] != "panic: boom" {
+		t.Errorf("期望 panic 的调用栈和错误链, 实际得到 %+v", resp)
+	}
+	if resp.Message != "服务器内部错误" {
+		t.Errorf("期望 panic 的消息不变, 实际得到 '%s'", resp.Message)
+	}
+
+	resp = get("/debug/4")
+	if resp.Stack != "" || resp.CauseChain != nil {
+		t.Errorf("期望业务错误不输出调试信息, 实际得到 %+v", resp)
+	}
+
+	resp = get("/test/1")
+	if resp.Stack != "" || resp.CauseChain != nil {
+		t.Errorf("期望未开启调试模式时不输出调试信息, 实际得到 %+v", resp)
+	}
+}
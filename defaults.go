@@ -0,0 +1,37 @@
+package apihandler
+
+import "sync"
+
+// defaultConfigMu 保护 DefaultConfig 的替换和读取
+var defaultConfigMu sync.RWMutex
+
+// SetDefaults 在当前默认配置的基础上应用选项，并替换 DefaultConfig，可并发调用
+//
+// 新的默认配置只影响之后创建的处理器，已创建的处理器保留创建时的配置。
+// 直接修改 DefaultConfig 的字段不是并发安全的，应在启动阶段、注册路由前完成。
+func SetDefaults(opts ...Option) {
+	defaultConfigMu.Lock()
+	defer defaultConfigMu.Unlock()
+
+	config := DefaultConfig.Clone()
+	for _, opt := range opts {
+		opt(config)
+	}
+	DefaultConfig = config
+}
+
+// Defaults 返回当前默认配置的副本
+func Defaults() *HandlerConfig {
+	defaultConfigMu.RLock()
+	defer defaultConfigMu.RUnlock()
+	return DefaultConfig.Clone()
+}
+
+// NewConfig 基于当前默认配置的副本应用选项，可用于 HandlerWithConfig
+func NewConfig(opts ...Option) *HandlerConfig {
+	config := Defaults()
+	for _, opt := range opts {
+		opt(config)
+	}
+	return config
+}
@@ -0,0 +1,95 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// restoreDefaults 测试结束后恢复默认配置
+func restoreDefaults(t *testing.T) {
+	saved := Defaults()
+	t.Cleanup(func() {
+		defaultConfigMu.Lock()
+		DefaultConfig = saved
+		defaultConfigMu.Unlock()
+	})
+}
+
+// 测试修改默认配置
+func TestSetDefaults(t *testing.T) {
+	restoreDefaults(t)
+
+	r := gin.New()
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return &testResponse{ID: req.ID}, nil
+	}
+	r.GET("/before/:id", Handler(handleFunc))
+
+	SetDefaults(WithSuccessCode(1))
+	r.GET("/after/:id", Handler(handleFunc))
+
+	get := func(path string) any {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+		var resp map[string]any
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("解析响应失败: %v", err)
+		}
+		return resp["code"]
+	}
+
+	if code := get("/before/1"); code != float64(0) {
+		t.Errorf("期望已创建的处理器保留原配置, 实际 code 为 %v", code)
+	}
+	if code := get("/after/1"); code != float64(1) {
+		t.Errorf("期望新的处理器使用新的默认配置, 实际 code 为 %v", code)
+	}
+	if code := Defaults().SuccessCode; code != 1 {
+		t.Errorf("期望默认配置的 SuccessCode 为 1, 实际得到 %v", code)
+	}
+}
+
+// 测试并发修改和读取默认配置
+func TestSetDefaultsConcurrent(t *testing.T) {
+	restoreDefaults(t)
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return nil, nil
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			SetDefaults(WithSuccessCode(i), WithErrorMapper(func(err error) (BizError, bool) { return nil, false }))
+		}(i)
+		go func() {
+			defer wg.Done()
+			Handler(handleFunc)
+			NewConfig(WithBindErrorCode(40000))
+		}()
+	}
+	wg.Wait()
+
+	if n := len(Defaults().ErrorMappers); n != 20 {
+		t.Errorf("期望累积 20 个错误映射函数, 实际得到 %d", n)
+	}
+}
+
+// 测试复制配置
+func TestHandlerConfigClone(t *testing.T) {
+	config := NewConfig(WithFormats(FormatJSON, FormatXML))
+	cp := config.Clone()
+	cp.Formats[0] = FormatMsgPack
+	cp.SuccessCode = 1
+
+	if config.Formats[0] != FormatJSON || config.SuccessCode != 0 {
+		t.Errorf("期望修改副本不影响原配置, 实际得到 %+v", config.Formats)
+	}
+}
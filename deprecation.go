@@ -0,0 +1,66 @@
+package apihandler
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecation 路由的废弃信息
+type Deprecation struct {
+	Date        time.Time // 废弃时间，可以是将来的时间；零值表示创建处理器时已废弃
+	Replacement string    // 替代的路由或迁移文档地址，为空时不输出 Link 响应头
+}
+
+// WithDeprecated 将路由标记为已废弃
+//
+// 所有响应（包括错误响应）都会带上 Deprecation 响应头（RFC 9745，如 Deprecation: @1735689600），
+// replacement 不为空时带上 Link: <replacement>; rel="successor-version"。
+// 注册表和 OpenAPI 文档中的路由同样标记为已废弃。
+//
+//	apihandler.GET(api, "/v1/user/:id", handleGetUserV1,
+//		apihandler.WithDeprecated(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), "/api/v2/user/:id"))
+func WithDeprecated(date time.Time, replacement string) Option {
+	return func(c *HandlerConfig) {
+		c.Deprecation = &Deprecation{Date: date, Replacement: replacement}
+	}
+}
+
+// deprecationHeaders 生成废弃路由的响应头，未废弃时返回 nil
+func deprecationHeaders(deprecation *Deprecation) http.Header {
+	if deprecation == nil {
+		return nil
+	}
+	date := deprecation.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	header := make(http.Header)
+	header.Set("Deprecation", "@"+strconv.FormatInt(date.Unix(), 10))
+	if deprecation.Replacement != "" {
+		header.Set("Link", "<"+deprecation.Replacement+`>; rel="successor-version"`)
+	}
+	return header
+}
+
+// setDeprecationHeaders 输出废弃路由的响应头，Link 追加到已有的值之后
+func setDeprecationHeaders(c *gin.Context, header http.Header) {
+	c.Writer.Header().Set("Deprecation", header.Get("Deprecation"))
+	if link := header.Get("Link"); link != "" {
+		c.Writer.Header().Add("Link", link)
+	}
+}
+
+// deprecationNote 生成文档中的废弃说明
+func deprecationNote(deprecation *Deprecation) string {
+	note := ""
+	if !deprecation.Date.IsZero() {
+		note = "自 " + deprecation.Date.Format(time.DateOnly) + " 起废弃。"
+	}
+	if deprecation.Replacement != "" {
+		note += "请改用 " + deprecation.Replacement + "。"
+	}
+	return note
+}
@@ -0,0 +1,70 @@
+package apihandler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试废弃路由输出 Deprecation 和 Link 响应头
+func TestWithDeprecated(t *testing.T) {
+	date := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	r := gin.New()
+	registry := NewRegistry()
+	api := NewGroup(r.Group("/api"), WithRegistry(registry))
+	GET(api, "/v1/user/:id", func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		if req.ID == 0 {
+			return nil, NewBizError(404, "not found", http.StatusNotFound)
+		}
+		return &testResponse{}, nil
+	}, WithDeprecated(date, "/api/v2/user/:id"), WithMeta(Meta{Summary: "获取用户"}))
+
+	for _, path := range []string{"/api/v1/user/1", "/api/v1/user/0"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if got := w.Header().Get("Deprecation"); got != "@1735689600" {
+			t.Errorf("期望 %s 的 Deprecation 响应头为 @1735689600, 实际得到 %q", path, got)
+		}
+		if got := w.Header().Get("Link"); got != `</api/v2/user/:id>; rel="successor-version"` {
+			t.Errorf("期望 %s 的 Link 响应头指向替代路由, 实际得到 %q", path, got)
+		}
+	}
+
+	route, _ := registry.Lookup(http.MethodGet, "/api/v1/user/:id")
+	if route.Deprecation == nil || !route.Deprecation.Date.Equal(date) {
+		t.Fatalf("期望注册表记录废弃信息, 实际得到 %+v", route.Deprecation)
+	}
+
+	op := registry.OpenAPI(OpenAPIInfo{Title: "User API", Version: "1"}).Paths["/api/v1/user/{id}"].Get
+	if !op.Deprecated || op.Description != "自 2025-01-01 起废弃。请改用 /api/v2/user/:id。" {
+		t.Errorf("期望 OpenAPI 文档标记为已废弃, 实际得到 %v %q", op.Deprecated, op.Description)
+	}
+	if code := registry.TypeScript(); !strings.Contains(code, "/** 获取用户 @deprecated 自 2025-01-01 起废弃。请改用 /api/v2/user/:id。 */") {
+		t.Errorf("期望 TypeScript 方法带有 @deprecated 注释, 实际得到:\n%s", code)
+	}
+}
+
+// 测试未设置废弃时间和替代路由
+func TestWithDeprecatedNow(t *testing.T) {
+	r := gin.New()
+	before := time.Now().Unix()
+	r.GET("/old", Handler(func(ctx context.Context, req *struct{}) (*testResponse, error) {
+		return &testResponse{}, nil
+	}, WithDeprecated(time.Time{}, "")))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/old", nil))
+	got := w.Header().Get("Deprecation")
+	if !strings.HasPrefix(got, "@") || got < "@"+strconv.FormatInt(before, 10) {
+		t.Errorf("期望 Deprecation 响应头为创建处理器的时间, 实际得到 %q", got)
+	}
+	if w.Header().Get("Link") != "" {
+		t.Errorf("期望不输出 Link 响应头, 实际得到 %q", w.Header().Get("Link"))
+	}
+}
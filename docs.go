@@ -0,0 +1,126 @@
+package apihandler
+
+import (
+	"html/template"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DocsUI 交互式文档界面
+type DocsUI string
+
+const (
+	DocsSwaggerUI DocsUI = "swagger-ui" // Swagger UI
+	DocsReDoc     DocsUI = "redoc"      // ReDoc
+)
+
+// 文档界面静态资源的默认地址
+const (
+	DefaultSwaggerUIAssetsURL = "https://cdn.jsdelivr.net/npm/swagger-ui-dist@5"
+	DefaultReDocAssetsURL     = "https://cdn.jsdelivr.net/npm/redoc@2/bundles"
+)
+
+// DocsSettings 文档路由的设置
+type DocsSettings struct {
+	Info      OpenAPIInfo // 文档信息
+	Registry  *Registry   // 生成文档的注册表，默认为 DefaultRegistry
+	UI        DocsUI      // 文档界面，默认为 Swagger UI
+	SpecPath  string      // OpenAPI 文档的路由，默认 /openapi.json
+	UIPath    string      // 文档界面的路由，默认 /docs
+	AssetsURL string      // 文档界面静态资源的地址，内网环境可指向自行部署的 swagger-ui-dist 或 redoc 目录
+	Disabled  bool        // 为 true 时不注册文档路由，如在生产环境中关闭
+}
+
+// ServeDocs 在路由分组中注册 OpenAPI 文档和交互式文档界面
+//
+// 文档在每次请求时根据注册表生成，因此 ServeDocs 可以在注册业务路由之前调用。
+//
+//	apihandler.ServeDocs(&r.RouterGroup, apihandler.DocsSettings{
+//		Info:     apihandler.OpenAPIInfo{Title: "User API", Version: "1.0.0"},
+//		Disabled: gin.Mode() == gin.ReleaseMode,
+//	})
+func ServeDocs(group *gin.RouterGroup, settings DocsSettings) {
+	if settings.Disabled {
+		return
+	}
+	if settings.Registry == nil {
+		settings.Registry = DefaultRegistry
+	}
+	if settings.UI == "" {
+		settings.UI = DocsSwaggerUI
+	}
+	if settings.SpecPath == "" {
+		settings.SpecPath = "/openapi.json"
+	}
+	if settings.UIPath == "" {
+		settings.UIPath = "/docs"
+	}
+	if settings.AssetsURL == "" {
+		settings.AssetsURL = DefaultSwaggerUIAssetsURL
+		if settings.UI == DocsReDoc {
+			settings.AssetsURL = DefaultReDocAssetsURL
+		}
+	}
+
+	page := docsPage{
+		Title:     settings.Info.Title,
+		SpecURL:   joinPaths(group.BasePath(), settings.SpecPath),
+		AssetsURL: strings.TrimSuffix(settings.AssetsURL, "/"),
+	}
+	tmpl := swaggerUITemplate
+	if settings.UI == DocsReDoc {
+		tmpl = redocTemplate
+	}
+
+	group.GET(settings.SpecPath, func(c *gin.Context) {
+		c.JSON(http.StatusOK, settings.Registry.OpenAPI(settings.Info))
+	})
+	group.GET(settings.UIPath, func(c *gin.Context) {
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		c.Status(http.StatusOK)
+		if err := tmpl.Execute(c.Writer, page); err != nil {
+			c.Error(err)
+		}
+	})
+}
+
+// docsPage 文档界面模板的数据
+type docsPage struct {
+	Title     string
+	SpecURL   string
+	AssetsURL string
+}
+
+var swaggerUITemplate = template.Must(template.New("swagger-ui").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+<link rel="stylesheet" href="{{.AssetsURL}}/swagger-ui.css">
+</head>
+<body>
+<div id="swagger-ui"></div>
+<script src="{{.AssetsURL}}/swagger-ui-bundle.js"></script>
+<script>
+window.ui = SwaggerUIBundle({url: {{.SpecURL}}, dom_id: "#swagger-ui"});
+</script>
+</body>
+</html>
+`))
+
+var redocTemplate = template.Must(template.New("redoc").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.Title}}</title>
+</head>
+<body>
+<redoc spec-url="{{.SpecURL}}"></redoc>
+<script src="{{.AssetsURL}}/redoc.standalone.js"></script>
+</body>
+</html>
+`))
@@ -0,0 +1,81 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试注册 OpenAPI 文档和 Swagger UI
+func TestServeDocs(t *testing.T) {
+	r := gin.New()
+	registry := NewRegistry()
+	ServeDocs(r.Group("/internal"), DocsSettings{
+		Info:     OpenAPIInfo{Title: "User <API>", Version: "1.0.0"},
+		Registry: registry,
+	})
+	api := NewGroup(r.Group("/api"), WithRegistry(registry))
+	GET(api, "/ping", func(ctx context.Context, req *struct{}) (*string, error) { return nil, nil })
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/internal/openapi.json", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("期望状态码 %d, 实际得到 %d", http.StatusOK, w.Code)
+	}
+	var doc OpenAPI
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("期望返回 JSON 格式的文档, 实际得到 %v", err)
+	}
+	if doc.Paths["/api/ping"] == nil {
+		t.Errorf("期望文档包含在 ServeDocs 之后注册的路由, 实际得到 %v", doc.Paths)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/internal/docs", nil))
+	body := w.Body.String()
+	if w.Code != http.StatusOK || !strings.HasPrefix(w.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("期望返回 HTML 页面, 实际得到状态码 %d, Content-Type %s", w.Code, w.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(body, DefaultSwaggerUIAssetsURL+"/swagger-ui-bundle.js") || !strings.Contains(body, `"/internal/openapi.json"`) {
+		t.Errorf("期望页面加载 Swagger UI 和文档地址, 实际得到 %s", body)
+	}
+	if !strings.Contains(body, "User &lt;API&gt;") {
+		t.Errorf("期望页面标题被转义, 实际得到 %s", body)
+	}
+}
+
+// 测试使用 ReDoc 和自定义路由
+func TestServeDocsReDoc(t *testing.T) {
+	r := gin.New()
+	ServeDocs(&r.RouterGroup, DocsSettings{
+		Registry:  NewRegistry(),
+		UI:        DocsReDoc,
+		SpecPath:  "/spec.json",
+		UIPath:    "/redoc",
+		AssetsURL: "/static/redoc/",
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/redoc", nil))
+	body := w.Body.String()
+	if !strings.Contains(body, `<redoc spec-url="/spec.json">`) || !strings.Contains(body, `src="/static/redoc/redoc.standalone.js"`) {
+		t.Errorf("期望页面使用 ReDoc 和自定义地址, 实际得到 %s", body)
+	}
+}
+
+// 测试关闭文档路由
+func TestServeDocsDisabled(t *testing.T) {
+	r := gin.New()
+	ServeDocs(&r.RouterGroup, DocsSettings{Disabled: true})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusNotFound, w.Code)
+	}
+}
@@ -0,0 +1,87 @@
+package apihandler
+
+import "reflect"
+
+// NilDataMode 业务返回 nil 时 data 字段的输出方式
+type NilDataMode int
+
+// data 字段的输出方式
+const (
+	NilDataNull        NilDataMode = iota // 输出 "data": null
+	NilDataOmit                           // 不输出 data 字段
+	NilDataEmptyObject                    // 输出 "data": {}
+)
+
+// maxEmptyContainerDepth 替换空容器时的最大递归深度，避免循环引用
+const maxEmptyContainerDepth = 32
+
+// fillEmptyContainers 复制数据并将其中为 nil 的切片和 map 替换为空容器，使其序列化为 [] 和 {}
+//
+// 只处理可导出字段，原数据不会被修改。
+func fillEmptyContainers(data any) any {
+	if data == nil {
+		return nil
+	}
+	return emptyContainers(reflect.ValueOf(data), 0).Interface()
+}
+
+// emptyContainers 递归替换 nil 切片和 map
+func emptyContainers(v reflect.Value, depth int) reflect.Value {
+	if depth > maxEmptyContainerDepth {
+		return v
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		ptr := reflect.New(v.Type().Elem())
+		ptr.Elem().Set(emptyContainers(v.Elem(), depth+1))
+		return ptr
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(emptyContainers(v.Elem(), depth+1))
+		return out
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+		for i := 0; i < out.NumField(); i++ {
+			field := out.Field(i)
+			if field.CanSet() {
+				field.Set(emptyContainers(field, depth+1))
+			}
+		}
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return reflect.MakeSlice(v.Type(), 0, 0)
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(emptyContainers(v.Index(i), depth+1))
+		}
+		return out
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(emptyContainers(v.Index(i), depth+1))
+		}
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return reflect.MakeMap(v.Type())
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), emptyContainers(iter.Value(), depth+1))
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,80 @@
+package apihandler
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试业务返回 nil 时 data 字段的输出方式
+func TestNilDataMode(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return nil, nil
+	}
+
+	r.GET("/null", Handler(handleFunc))
+	r.GET("/omit", Handler(handleFunc, WithNilData(NilDataOmit)))
+	r.GET("/object", Handler(handleFunc, WithNilData(NilDataEmptyObject)))
+
+	cases := []struct {
+		path string
+		body string
+	}{
+		{"/null", `{"code":0,"data":null}`},
+		{"/omit", `{"code":0}`},
+		{"/object", `{"code":0,"data":{}}`},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", tc.path, nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Body.String() != tc.body {
+			t.Errorf("%s: 期望响应为 %s, 实际得到 %s", tc.path, tc.body, w.Body.String())
+		}
+	}
+}
+
+// 测试 nil 切片和 map 输出为空容器
+func TestEmptyContainers(t *testing.T) {
+	type item struct {
+		Tags []string `json:"tags"`
+	}
+
+	type containerResponse struct {
+		Items  []item            `json:"items"`
+		Labels map[string]string `json:"labels"`
+		Child  *item             `json:"child"`
+		Extra  any               `json:"extra"`
+		secret []string
+	}
+
+	resp := &containerResponse{Items: []item{{}}, Child: &item{}}
+
+	r := gin.New()
+
+	r.GET("/test", Handler(func(ctx context.Context, req *testRequest) (*containerResponse, error) {
+		return resp, nil
+	}, WithEmptyContainers()))
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	expected := `{"code":0,"data":{"items":[{"tags":[]}],"labels":{},"child":{"tags":[]},"extra":null}}`
+	if w.Body.String() != expected {
+		t.Errorf("期望响应为 %s, 实际得到 %s", expected, w.Body.String())
+	}
+
+	// 原数据不会被修改
+	if resp.Labels != nil || resp.Items[0].Tags != nil || resp.Child.Tags != nil {
+		t.Errorf("期望原数据不被修改, 实际得到 %+v", resp)
+	}
+}
@@ -0,0 +1,148 @@
+package apihandler
+
+import (
+	"encoding/xml"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Envelope 响应封装接口，用于整体替换成功和错误响应的外层结构
+//
+// 例如平台统一的响应结构为 {status, result, error} 时，实现该接口并通过
+// WithEnvelope 或 HandlerConfig.Envelope 配置即可。
+type Envelope interface {
+	// Success 构造成功响应体，code 为配置的成功业务代码，data 为业务处理函数的返回值
+	Success(c *gin.Context, code any, data any) any
+	// Error 构造错误响应体，非业务错误会先被转换为 500 的 BizError
+	Error(c *gin.Context, err BizError) any
+}
+
+// successEnvelope 默认成功响应体
+type successEnvelope struct {
+	XMLName        xml.Name `json:"-" xml:"response"`
+	Code           any      `json:"code" xml:"code"`
+	Data           any      `json:"data" xml:"data,omitempty"`
+	Meta           any      `json:"meta,omitempty" xml:"meta,omitempty"`
+	Links          Links    `json:"links,omitempty" xml:"links,omitempty"`
+	RequestID      string   `json:"-" xml:"-" codec:"request_id,omitempty"` // 请求 ID，字段名由 RequestIDField 指定
+	RequestIDField string   `json:"-" xml:"-" codec:"-"`
+}
+
+// successNoDataEnvelope 不输出 data 字段的成功响应体
+type successNoDataEnvelope struct {
+	XMLName        xml.Name `json:"-" xml:"response"`
+	Code           any      `json:"code" xml:"code"`
+	Meta           any      `json:"meta,omitempty" xml:"meta,omitempty"`
+	Links          Links    `json:"links,omitempty" xml:"links,omitempty"`
+	RequestID      string   `json:"-" xml:"-" codec:"request_id,omitempty"` // 请求 ID，字段名由 RequestIDField 指定
+	RequestIDField string   `json:"-" xml:"-" codec:"-"`
+}
+
+// MarshalJSON 实现 json.Marshaler 接口，请求 ID 输出在最后
+func (e successEnvelope) MarshalJSON() ([]byte, error) {
+	return e.marshalJSON(StdJSONCodec{})
+}
+
+// marshalJSON 实现 codecMarshaler 接口
+func (e successEnvelope) marshalJSON(codec JSONCodec) ([]byte, error) {
+	type envelope successEnvelope
+	data, err := codec.Marshal(envelope(e))
+	if err != nil {
+		return nil, err
+	}
+	return appendRequestID(data, e.RequestIDField, e.RequestID), nil
+}
+
+// MarshalXML 实现 xml.Marshaler 接口，请求 ID 输出在最后
+func (e successEnvelope) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	type envelope successEnvelope
+	return encodeXMLWithRequestID(enc, envelope(e), e.RequestIDField, e.RequestID)
+}
+
+// MarshalJSON 实现 json.Marshaler 接口，请求 ID 输出在最后
+func (e successNoDataEnvelope) MarshalJSON() ([]byte, error) {
+	return e.marshalJSON(StdJSONCodec{})
+}
+
+// marshalJSON 实现 codecMarshaler 接口
+func (e successNoDataEnvelope) marshalJSON(codec JSONCodec) ([]byte, error) {
+	type envelope successNoDataEnvelope
+	data, err := codec.Marshal(envelope(e))
+	if err != nil {
+		return nil, err
+	}
+	return appendRequestID(data, e.RequestIDField, e.RequestID), nil
+}
+
+// MarshalXML 实现 xml.Marshaler 接口，请求 ID 输出在最后
+func (e successNoDataEnvelope) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	type envelope successNoDataEnvelope
+	return encodeXMLWithRequestID(enc, envelope(e), e.RequestIDField, e.RequestID)
+}
+
+// DefaultEnvelope 默认响应封装，成功时为 {code, data, meta, links}，失败时为 {code, message, errors}
+type DefaultEnvelope struct {
+	NilData        NilDataMode   // 业务返回 nil 时 data 字段的输出方式
+	Flatten        bool          // JSON 响应中将 data 的字段合并到与 code 同级的顶层对象
+	RequestIDField string        // 错误响应中请求 ID 的字段名，为空时使用 request_id，"-" 表示不输出
+	RequestIDFunc  RequestIDFunc // 获取请求 ID 的函数，为空时使用 X-Request-Id 或 traceparent 请求头
+	Debug          bool          // 非业务错误的错误响应中包含调用栈和错误链
+	SuccessID      bool          // 成功响应中也输出请求 context 中保存的请求 ID
+}
+
+// Success 实现 Envelope 接口
+func (e DefaultEnvelope) Success(c *gin.Context, code any, data any) any {
+	links := responseLinks(c, data)
+	data, meta := splitMeta(data)
+	var id string
+	if e.SuccessID {
+		id = RequestIDFromContext(c.Request.Context())
+	}
+	if isNil(data) {
+		switch e.NilData {
+		case NilDataOmit:
+			return successNoDataEnvelope{Code: code, Meta: meta, Links: links, RequestID: id, RequestIDField: e.RequestIDField}
+		case NilDataEmptyObject:
+			data = struct{}{}
+		}
+	}
+	env := successEnvelope{
+		Code:           code,
+		Data:           data,
+		Meta:           meta,
+		Links:          links,
+		RequestID:      id,
+		RequestIDField: e.RequestIDField,
+	}
+	if e.Flatten {
+		return flatSuccessEnvelope{env}
+	}
+	return env
+}
+
+// Error 实现 Envelope 接口
+func (e DefaultEnvelope) Error(c *gin.Context, err BizError) any {
+	resp := ErrorResponse{
+		Code:           err.Code(),
+		Message:        err.Error(),
+		Errors:         err.Errors(),
+		RequestIDField: e.RequestIDField,
+	}
+	if requestIDField(e.RequestIDField) != "-" {
+		resp.RequestID = requestID(c, e.RequestIDFunc)
+	}
+	if e.Debug {
+		resp.Stack, resp.CauseChain = debugDetails(err)
+	}
+	return resp
+}
+
+// rawEnvelope 不封装成功响应，直接返回业务数据，错误响应沿用原有封装
+type rawEnvelope struct {
+	Envelope
+}
+
+// Success 实现 Envelope 接口
+func (rawEnvelope) Success(c *gin.Context, code any, data any) any {
+	return data
+}
@@ -0,0 +1,165 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 自定义响应封装：{status, result, error}
+type platformEnvelope struct{}
+
+func (platformEnvelope) Success(c *gin.Context, code any, data any) any {
+	return gin.H{"status": "ok", "result": data}
+}
+
+func (platformEnvelope) Error(c *gin.Context, err BizError) any {
+	return gin.H{"status": "fail", "error": gin.H{"code": err.Code(), "reason": err.Error()}}
+}
+
+// 测试自定义响应封装 - 成功响应
+func TestEnvelopeSuccess(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return &testResponse{ID: req.ID}, nil
+	}
+
+	r.GET("/test/:id", Handler(handleFunc, WithEnvelope(platformEnvelope{})))
+
+	req := httptest.NewRequest("GET", "/test/123", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusOK, w.Code)
+	}
+
+	var resp struct {
+		Status string       `json:"status"`
+		Result testResponse `json:"result"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+
+	if resp.Status != "ok" {
+		t.Errorf("期望 status 为 'ok', 实际得到 '%s'", resp.Status)
+	}
+
+	if resp.Result.ID != 123 {
+		t.Errorf("期望 result.id 为 123, 实际得到 %d", resp.Result.ID)
+	}
+}
+
+// 测试自定义响应封装 - 错误响应（包括非业务错误）
+func TestEnvelopeError(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		if req.ID == 1 {
+			return nil, ErrNotFound(40400, "资源不存在")
+		}
+		return nil, context.Canceled
+	}
+
+	r.GET("/test/:id", Handler(handleFunc, WithEnvelope(platformEnvelope{})))
+
+	cases := []struct {
+		path     string
+		httpCode int
+		reason   string
+	}{
+		{"/test/1", http.StatusNotFound, "资源不存在"},
+		{"/test/2", http.StatusInternalServerError, context.Canceled.Error()},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", tc.path, nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.httpCode {
+			t.Errorf("%s: 期望状态码 %d, 实际得到 %d", tc.path, tc.httpCode, w.Code)
+		}
+
+		var resp struct {
+			Status string `json:"status"`
+			Error  struct {
+				Reason string `json:"reason"`
+			} `json:"error"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("解析响应失败: %v", err)
+		}
+
+		if resp.Status != "fail" {
+			t.Errorf("%s: 期望 status 为 'fail', 实际得到 '%s'", tc.path, resp.Status)
+		}
+
+		if resp.Error.Reason != tc.reason {
+			t.Errorf("%s: 期望 reason 为 '%s', 实际得到 '%s'", tc.path, tc.reason, resp.Error.Reason)
+		}
+	}
+}
+
+// 测试 RawHandler 直接返回业务数据
+func TestRawHandler(t *testing.T) {
+	type item struct {
+		ID int64 `json:"id"`
+	}
+
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*[]item, error) {
+		if req.ID == 0 {
+			return nil, ErrBadRequest(40000, "ID不能为0")
+		}
+		return &[]item{{ID: req.ID}, {ID: req.ID + 1}}, nil
+	}
+
+	r.GET("/items/:id", RawHandler(handleFunc, WithSuccessHTTPCode(http.StatusAccepted)))
+
+	req := httptest.NewRequest("GET", "/items/7", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusAccepted, w.Code)
+	}
+
+	var items []item
+	if err := json.Unmarshal(w.Body.Bytes(), &items); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+
+	if len(items) != 2 || items[0].ID != 7 || items[1].ID != 8 {
+		t.Errorf("期望返回 [7, 8], 实际得到 %v", items)
+	}
+
+	// 错误仍然使用 ErrorResponse 结构
+	req = httptest.NewRequest("GET", "/items/0", nil)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusBadRequest, w.Code)
+	}
+
+	var errResp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &errResp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+
+	if errResp.Message != "ID不能为0" {
+		t.Errorf("期望 message 为 'ID不能为0', 实际得到 '%s'", errResp.Message)
+	}
+}
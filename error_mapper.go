@@ -0,0 +1,39 @@
+package apihandler
+
+import "sync"
+
+// ErrorMapper 错误映射函数，将领域错误（如 sql.ErrNoRows、context.DeadlineExceeded）转换为业务错误，
+// 无法处理时返回 false
+type ErrorMapper func(err error) (BizError, bool)
+
+// errorMappers 全局注册的错误映射函数
+var errorMappers struct {
+	sync.RWMutex
+	list []ErrorMapper
+}
+
+// RegisterErrorMapper 注册全局错误映射函数，按注册顺序匹配，在处理器配置的 ErrorMappers 之后执行
+func RegisterErrorMapper(mapper ErrorMapper) {
+	errorMappers.Lock()
+	defer errorMappers.Unlock()
+	errorMappers.list = append(errorMappers.list, mapper)
+}
+
+// mapError 依次使用处理器配置和全局注册的错误映射函数转换错误
+func mapError(config *HandlerConfig, err error) (BizError, bool) {
+	for _, mapper := range config.ErrorMappers {
+		if bizErr, ok := mapper(err); ok && bizErr != nil {
+			return bizErr, true
+		}
+	}
+
+	errorMappers.RLock()
+	mappers := errorMappers.list
+	errorMappers.RUnlock()
+	for _, mapper := range mappers {
+		if bizErr, ok := mapper(err); ok && bizErr != nil {
+			return bizErr, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,116 @@
+package apihandler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errQuotaExceeded 仅用于测试全局错误映射的领域错误
+var errQuotaExceeded = errors.New("quota exceeded")
+
+func init() {
+	RegisterErrorMapper(func(err error) (BizError, bool) {
+		if errors.Is(err, errQuotaExceeded) {
+			return NewBizError(42900, "配额不足", http.StatusTooManyRequests), true
+		}
+		return nil, false
+	})
+}
+
+// 测试全局和处理器级别的错误映射
+func TestErrorMapper(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		switch req.ID {
+		case 1:
+			return nil, fmt.Errorf("find user: %w", sql.ErrNoRows)
+		case 2:
+			return nil, errQuotaExceeded
+		case 3:
+			return nil, ErrBadRequest(40000, "参数错误")
+		}
+		return nil, errors.New("unknown")
+	}
+
+	notFoundMapper := func(err error) (BizError, bool) {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNotFound(40400, "资源不存在"), true
+		}
+		return nil, false
+	}
+
+	r.GET("/test/:id", Handler(handleFunc, WithErrorMapper(notFoundMapper)))
+	r.GET("/global/:id", Handler(handleFunc))
+
+	cases := []struct {
+		path     string
+		httpCode int
+		code     float64
+		message  string
+	}{
+		{"/test/1", http.StatusNotFound, 40400, "资源不存在"},
+		{"/test/2", http.StatusTooManyRequests, 42900, "配额不足"},
+		{"/test/3", http.StatusBadRequest, 40000, "参数错误"},
+		{"/test/4", http.StatusInternalServerError, 500, "unknown"},
+		// 未配置处理器级别的映射函数
+		{"/global/1", http.StatusInternalServerError, 500, "find user: " + sql.ErrNoRows.Error()},
+		{"/global/2", http.StatusTooManyRequests, 42900, "配额不足"},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", tc.path, nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.httpCode {
+			t.Errorf("%s: 期望状态码 %d, 实际得到 %d", tc.path, tc.httpCode, w.Code)
+		}
+
+		var resp ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("解析响应失败: %v", err)
+		}
+
+		if resp.Code != tc.code || resp.Message != tc.message {
+			t.Errorf("%s: 期望 {code:%v, message:%s}, 实际得到 {code:%v, message:%s}", tc.path, tc.code, tc.message, resp.Code, resp.Message)
+		}
+	}
+}
+
+// 测试 WithErrorMapper 不修改共享的配置
+func TestWithErrorMapperCopy(t *testing.T) {
+	mapper := func(err error) (BizError, bool) { return nil, false }
+	base := &HandlerConfig{ErrorMappers: make([]ErrorMapper, 1, 4)}
+
+	a, b := *base, *base
+	WithErrorMapper(mapper)(&a)
+	WithErrorMapper(mapper, mapper)(&b)
+
+	if len(base.ErrorMappers) != 1 || len(a.ErrorMappers) != 2 || len(b.ErrorMappers) != 3 {
+		t.Errorf("期望映射函数数量为 1、2、3, 实际得到 %d、%d、%d", len(base.ErrorMappers), len(a.ErrorMappers), len(b.ErrorMappers))
+	}
+}
+
+// 测试映射后的业务错误保留原始错误
+func TestErrorMapperCause(t *testing.T) {
+	config := &HandlerConfig{}
+	bizErr := toBizError(config, fmt.Errorf("charge: %w", errQuotaExceeded))
+
+	if bizErr.HTTPCode() != http.StatusTooManyRequests || bizErr.Error() != "配额不足" {
+		t.Errorf("期望 429 '配额不足', 实际得到 %d '%s'", bizErr.HTTPCode(), bizErr.Error())
+	}
+
+	if !errors.Is(bizErr, errQuotaExceeded) {
+		t.Errorf("期望可以通过 errors.Is 获取原始错误")
+	}
+}
@@ -0,0 +1,157 @@
+package apihandler
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorMetric 一次错误响应的统计维度
+type ErrorMetric struct {
+	Method   string // 请求方法
+	Route    string // 路由模板，如 /users/:id
+	Code     any    // 业务错误码
+	HTTPCode int    // HTTP 状态码
+}
+
+// ErrorRecorder 错误响应计数接口，每个错误响应调用一次，可对接 Prometheus、StatsD 等监控系统
+type ErrorRecorder interface {
+	RecordError(c *gin.Context, metric ErrorMetric)
+}
+
+// ErrorRecorderFunc 函数形式的 ErrorRecorder
+type ErrorRecorderFunc func(c *gin.Context, metric ErrorMetric)
+
+// RecordError 实现 ErrorRecorder 接口
+func (f ErrorRecorderFunc) RecordError(c *gin.Context, metric ErrorMetric) {
+	f(c, metric)
+}
+
+// WithErrorMetrics 设置错误响应计数器
+func WithErrorMetrics(recorder ErrorRecorder) Option {
+	return func(c *HandlerConfig) {
+		c.ErrorRecorder = recorder
+	}
+}
+
+// recordError 记录一次错误响应
+func recordError(c *gin.Context, config *HandlerConfig, bizErr BizError) {
+	if config.ErrorRecorder == nil {
+		return
+	}
+	config.ErrorRecorder.RecordError(c, ErrorMetric{
+		Method:   c.Request.Method,
+		Route:    c.FullPath(),
+		Code:     bizErr.Code(),
+		HTTPCode: bizErr.HTTPCode(),
+	})
+}
+
+// ErrorCount 错误响应计数
+type ErrorCount struct {
+	Method   string
+	Route    string
+	Code     string // 业务错误码的字符串形式
+	HTTPCode int
+	Count    int64
+}
+
+// errorCountKey 错误计数的键
+type errorCountKey struct {
+	method   string
+	route    string
+	code     string
+	httpCode int
+}
+
+// ErrorStats 内置的内存错误计数器，可并发使用
+type ErrorStats struct {
+	mu     sync.Mutex
+	counts map[errorCountKey]int64
+}
+
+// NewErrorStats 创建内存错误计数器
+func NewErrorStats() *ErrorStats {
+	return &ErrorStats{counts: make(map[errorCountKey]int64)}
+}
+
+// RecordError 实现 ErrorRecorder 接口
+func (s *ErrorStats) RecordError(c *gin.Context, metric ErrorMetric) {
+	key := errorCountKey{
+		method:   metric.Method,
+		route:    metric.Route,
+		code:     fmt.Sprint(metric.Code),
+		httpCode: metric.HTTPCode,
+	}
+	s.mu.Lock()
+	s.counts[key]++
+	s.mu.Unlock()
+}
+
+// Total 返回错误响应总数
+func (s *ErrorStats) Total() int64 {
+	return s.sum(func(errorCountKey) bool { return true })
+}
+
+// ByRoute 返回路由的错误响应数
+func (s *ErrorStats) ByRoute(method, route string) int64 {
+	return s.sum(func(k errorCountKey) bool { return k.method == method && k.route == route })
+}
+
+// ByCode 返回业务错误码的错误响应数
+func (s *ErrorStats) ByCode(code any) int64 {
+	str := fmt.Sprint(code)
+	return s.sum(func(k errorCountKey) bool { return k.code == str })
+}
+
+// ByStatus 返回 HTTP 状态码的错误响应数
+func (s *ErrorStats) ByStatus(httpCode int) int64 {
+	return s.sum(func(k errorCountKey) bool { return k.httpCode == httpCode })
+}
+
+// Snapshot 返回所有维度组合的计数，按路由、方法、HTTP 状态码和业务错误码排序
+func (s *ErrorStats) Snapshot() []ErrorCount {
+	s.mu.Lock()
+	counts := make([]ErrorCount, 0, len(s.counts))
+	for k, n := range s.counts {
+		counts = append(counts, ErrorCount{Method: k.method, Route: k.route, Code: k.code, HTTPCode: k.httpCode, Count: n})
+	}
+	s.mu.Unlock()
+
+	sort.Slice(counts, func(i, j int) bool {
+		a, b := counts[i], counts[j]
+		if a.Route != b.Route {
+			return a.Route < b.Route
+		}
+		if a.Method != b.Method {
+			return a.Method < b.Method
+		}
+		if a.HTTPCode != b.HTTPCode {
+			return a.HTTPCode < b.HTTPCode
+		}
+		return a.Code < b.Code
+	})
+	return counts
+}
+
+// Reset 清空计数
+func (s *ErrorStats) Reset() {
+	s.mu.Lock()
+	s.counts = make(map[errorCountKey]int64)
+	s.mu.Unlock()
+}
+
+// sum 汇总满足条件的计数
+func (s *ErrorStats) sum(match func(errorCountKey) bool) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var total int64
+	for k, n := range s.counts {
+		if match(k) {
+			total += n
+		}
+	}
+	return total
+}
@@ -0,0 +1,84 @@
+package apihandler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试错误响应计数
+func TestErrorMetrics(t *testing.T) {
+	r := gin.New()
+	stats := NewErrorStats()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		if req.ID == 1 {
+			return &testResponse{ID: req.ID}, nil
+		}
+		return nil, ErrNotFound(40400, "资源不存在")
+	}
+	r.GET("/test/:id", Handler(handleFunc, WithErrorMetrics(stats)))
+	r.POST("/test/:id", Handler(handleFunc, WithErrorMetrics(stats)))
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest("GET", "/test/1", nil),
+		httptest.NewRequest("GET", "/test/2", nil),
+		httptest.NewRequest("GET", "/test/3", nil),
+		httptest.NewRequest("GET", "/test/abc", nil),
+		httptest.NewRequest("POST", "/test/2", nil),
+	} {
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	if got := stats.Total(); got != 4 {
+		t.Errorf("期望错误总数为 4, 实际得到 %d", got)
+	}
+	if got := stats.ByRoute("GET", "/test/:id"); got != 3 {
+		t.Errorf("期望 GET /test/:id 的错误数为 3, 实际得到 %d", got)
+	}
+	if got := stats.ByCode(40400); got != 3 {
+		t.Errorf("期望错误码 40400 的错误数为 3, 实际得到 %d", got)
+	}
+	if got := stats.ByStatus(http.StatusBadRequest); got != 1 {
+		t.Errorf("期望状态码 400 的错误数为 1, 实际得到 %d", got)
+	}
+
+	snapshot := stats.Snapshot()
+	if len(snapshot) != 3 {
+		t.Fatalf("期望 3 个维度组合, 实际得到 %+v", snapshot)
+	}
+	if first := snapshot[0]; first.Method != "GET" || first.HTTPCode != http.StatusBadRequest || first.Count != 1 {
+		t.Errorf("期望第一个维度组合为 GET 400, 实际得到 %+v", first)
+	}
+
+	stats.Reset()
+	if got := stats.Total(); got != 0 {
+		t.Errorf("期望重置后错误总数为 0, 实际得到 %d", got)
+	}
+}
+
+// 测试函数形式的错误计数器
+func TestErrorRecorderFunc(t *testing.T) {
+	r := gin.New()
+	var metrics []ErrorMetric
+	recorder := ErrorRecorderFunc(func(c *gin.Context, metric ErrorMetric) {
+		metrics = append(metrics, metric)
+	})
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		panic("boom")
+	}
+	r.GET("/test/:id", Handler(handleFunc, WithErrorMetrics(recorder)))
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/test/1", nil))
+
+	if len(metrics) != 1 {
+		t.Fatalf("期望记录 1 次错误, 实际得到 %d", len(metrics))
+	}
+	want := ErrorMetric{Method: "GET", Route: "/test/:id", Code: http.StatusInternalServerError, HTTPCode: http.StatusInternalServerError}
+	if metrics[0] != want {
+		t.Errorf("期望 %+v, 实际得到 %+v", want, metrics[0])
+	}
+}
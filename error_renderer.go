@@ -0,0 +1,35 @@
+package apihandler
+
+import "github.com/gin-gonic/gin"
+
+// ErrorRenderer 错误响应体构造函数，优先于 ProblemDetails 和 Envelope.Error，用于完全控制错误响应体的结构
+//
+// 返回值与成功响应一样按协商的格式编码输出，HTTP 状态码和响应头仍由 err 决定。
+type ErrorRenderer func(c *gin.Context, err BizError, info ErrorInfo) any
+
+// ErrorInfo 构造错误响应体时可用的诊断信息
+type ErrorInfo struct {
+	RequestID  string   // 请求 ID，来自 RequestIDFunc 或 X-Request-Id、traceparent 请求头
+	Reference  string   // 隐藏内部错误时生成的错误编号
+	Stack      string   // 调用栈，仅在调试模式下提供
+	CauseChain []string // 错误链，仅在调试模式下提供
+}
+
+// WithErrorRenderer 设置错误响应体构造函数
+func WithErrorRenderer(renderer ErrorRenderer) Option {
+	return func(c *HandlerConfig) {
+		c.ErrorRenderer = renderer
+	}
+}
+
+// renderErrorBody 使用 ErrorRenderer 构造错误响应体
+func renderErrorBody(c *gin.Context, config *HandlerConfig, bizErr BizError) any {
+	info := ErrorInfo{
+		RequestID: requestID(c, config.RequestIDFunc),
+		Reference: ErrorReference(bizErr),
+	}
+	if config.Debug {
+		info.Stack, info.CauseChain = debugDetails(bizErr)
+	}
+	return config.ErrorRenderer(c, bizErr, info)
+}
@@ -0,0 +1,76 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试自定义错误响应体
+func TestErrorRenderer(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		if req.ID == 1 {
+			return nil, ErrNotFound(40400, "资源不存在")
+		}
+		return nil, errors.New("connection refused")
+	}
+	renderer := func(c *gin.Context, err BizError, info ErrorInfo) any {
+		return gin.H{
+			"error":     gin.H{"code": err.Code(), "reason": err.Error()},
+			"trace_id":  info.RequestID,
+			"reference": info.Reference,
+			"chain":     info.CauseChain,
+		}
+	}
+	r.GET("/test/:id", Handler(handleFunc, WithErrorRenderer(renderer), WithProblemDetails()))
+	r.GET("/masked/:id", Handler(handleFunc, WithErrorRenderer(renderer), WithMaskedInternalErrors(), WithDebug()))
+
+	req := httptest.NewRequest("GET", "/test/1", nil)
+	req.Header.Set("X-Request-Id", "req-123")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusNotFound, w.Code)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != jsonContentType {
+		t.Errorf("期望 Content-Type 为 %s, 实际得到 %s", jsonContentType, ct)
+	}
+
+	var body struct {
+		Error struct {
+			Code   int    `json:"code"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+		TraceID   string   `json:"trace_id"`
+		Reference string   `json:"reference"`
+		Chain     []string `json:"chain"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	if body.Error.Code != 40400 || body.Error.Reason != "资源不存在" || body.TraceID != "req-123" {
+		t.Errorf("期望自定义的错误响应体, 实际得到 %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/masked/2", nil))
+
+	body.Chain = nil
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	if body.Reference == "" {
+		t.Errorf("期望提供错误编号, 实际得到 %s", w.Body.String())
+	}
+	if len(body.Chain) != 1 || body.Chain[0] != "connection refused" {
+		t.Errorf("期望调试模式下提供错误链, 实际得到 %v", body.Chain)
+	}
+}
@@ -0,0 +1,48 @@
+package apihandler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// etagFor 根据响应内容生成弱 ETag
+func etagFor(data []byte) string {
+	sum := sha256.Sum256(data)
+	return `W/"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// notModified 为 GET/HEAD 请求设置 ETag，If-None-Match 匹配时响应 304 并返回 true
+func notModified(c *gin.Context, data []byte) bool {
+	if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+		return false
+	}
+
+	etag := etagFor(data)
+	c.Header("ETag", etag)
+	if !etagMatch(c.GetHeader("If-None-Match"), etag) {
+		return false
+	}
+
+	c.Status(http.StatusNotModified)
+	c.Writer.WriteHeaderNow()
+	return true
+}
+
+// etagMatch 按弱比较判断 If-None-Match 是否匹配 ETag
+func etagMatch(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
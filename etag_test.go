@@ -0,0 +1,77 @@
+package apihandler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试 ETag 生成和 If-None-Match 返回 304
+func TestETag(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		if req.ID == 0 {
+			return nil, ErrNotFound(40400, "资源不存在")
+		}
+		return &testResponse{ID: req.ID}, nil
+	}
+
+	r.GET("/test/:id", Handler(handleFunc, WithETag()))
+	r.PUT("/test/:id", Handler(handleFunc, WithETag()))
+
+	req := httptest.NewRequest("GET", "/test/1", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	etag := w.Header().Get("ETag")
+	if etag == "" {
+		t.Fatal("期望响应包含 ETag")
+	}
+
+	// 内容未变化时返回 304 且不输出响应体
+	req = httptest.NewRequest("GET", "/test/1", nil)
+	req.Header.Set("If-None-Match", `"other", `+etag)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotModified {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusNotModified, w.Code)
+	}
+
+	if w.Body.Len() != 0 {
+		t.Errorf("期望 304 响应体为空, 实际得到 '%s'", w.Body.String())
+	}
+
+	// 内容变化时 ETag 不同，返回 200
+	req = httptest.NewRequest("GET", "/test/2", nil)
+	req.Header.Set("If-None-Match", etag)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusOK, w.Code)
+	}
+
+	if w.Header().Get("ETag") == etag {
+		t.Errorf("期望不同内容生成不同的 ETag")
+	}
+
+	// 错误响应和非 GET 请求不生成 ETag
+	for _, tc := range []struct{ method, path string }{{"GET", "/test/0"}, {"PUT", "/test/1"}} {
+		req = httptest.NewRequest(tc.method, tc.path, nil)
+		w = httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Header().Get("ETag") != "" {
+			t.Errorf("%s %s: 期望不生成 ETag", tc.method, tc.path)
+		}
+	}
+}
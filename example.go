@@ -0,0 +1,192 @@
+package apihandler
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ExampleTag 示例值的 tag 名称
+//
+//	type User struct {
+//		Name string   `json:"name" example:"zhangsan"`
+//		Age  int      `json:"age" example:"18"`
+//		Tags []string `json:"tags" example:"admin,vip"`
+//	}
+const ExampleTag = "example"
+
+// ExampleFor 根据 example tag 创建类型 T 的示例值，用于文档和模拟服务
+//
+// 嵌套的结构体递归填充，结构体切片填充一个元素；基本类型的切片以逗号分隔多个元素；
+// 没有 example tag 或无法解析的字段保持零值。
+func ExampleFor[T any]() *T {
+	v := new(T)
+	fillExample(reflect.ValueOf(v).Elem(), make(map[reflect.Type]bool))
+	return v
+}
+
+// exampleOf 返回类型的示例值，没有任何示例时返回 false
+func exampleOf(t reflect.Type) (any, bool) {
+	if t == nil {
+		return nil, false
+	}
+	v := reflect.New(t).Elem()
+	if !fillExample(v, make(map[reflect.Type]bool)) {
+		return nil, false
+	}
+	return v.Interface(), true
+}
+
+// fillExample 填充结构体的示例值，返回是否设置了任何字段，visiting 中的类型不再展开以支持递归类型
+func fillExample(v reflect.Value, visiting map[reflect.Type]bool) bool {
+	switch v.Kind() {
+	case reflect.Pointer:
+		elem := reflect.New(v.Type().Elem())
+		if fillExample(elem.Elem(), visiting) {
+			v.Set(elem)
+			return true
+		}
+	case reflect.Struct:
+		if v.Type() == timeType || visiting[v.Type()] {
+			return false
+		}
+		visiting[v.Type()] = true
+		defer delete(visiting, v.Type())
+
+		filled := false
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+			if tag, ok := field.Tag.Lookup(ExampleTag); ok {
+				if example, ok := parseExample(field.Type, tag); ok {
+					v.Field(i).Set(example)
+					filled = true
+				}
+				continue
+			}
+			if fillExample(v.Field(i), visiting) {
+				filled = true
+			}
+		}
+		return filled
+	case reflect.Slice:
+		elem := reflect.New(v.Type().Elem()).Elem()
+		if fillExample(elem, visiting) {
+			v.Set(reflect.Append(reflect.MakeSlice(v.Type(), 0, 1), elem))
+			return true
+		}
+	}
+	return false
+}
+
+// fieldExample 返回字段 example tag 的示例值
+func fieldExample(field reflect.StructField) (any, bool) {
+	tag, ok := field.Tag.Lookup(ExampleTag)
+	if !ok {
+		return nil, false
+	}
+	v, ok := parseExample(field.Type, tag)
+	if !ok {
+		return nil, false
+	}
+	return v.Interface(), true
+}
+
+// parseExample 按类型解析 example tag，支持基本类型、time.Time、time.Duration 及其指针和切片
+func parseExample(t reflect.Type, tag string) (reflect.Value, bool) {
+	v := reflect.New(t).Elem()
+	switch {
+	case t.Kind() == reflect.Pointer:
+		elem, ok := parseExample(t.Elem(), tag)
+		if !ok {
+			return v, false
+		}
+		ptr := reflect.New(t.Elem())
+		ptr.Elem().Set(elem)
+		return ptr, true
+	case t == timeType:
+		parsed, err := time.Parse(time.RFC3339, tag)
+		if err != nil {
+			return v, false
+		}
+		v.Set(reflect.ValueOf(parsed))
+	case t == durationType:
+		parsed, err := time.ParseDuration(tag)
+		if err != nil {
+			return v, false
+		}
+		v.SetInt(int64(parsed))
+	case t.Kind() == reflect.Slice && t.Elem().Kind() != reflect.Uint8:
+		for _, item := range strings.Split(tag, ",") {
+			elem, ok := parseExample(t.Elem(), strings.TrimSpace(item))
+			if !ok {
+				return v, false
+			}
+			v = reflect.Append(v, elem)
+		}
+	default:
+		if !setScalar(v, tag) {
+			return v, false
+		}
+	}
+	return v, true
+}
+
+// setScalar 将字符串解析为基本类型的值
+func setScalar(v reflect.Value, s string) bool {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Slice:
+		v.SetBytes([]byte(s))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return false
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return false
+		}
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return false
+		}
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return false
+		}
+		v.SetFloat(n)
+	default:
+		return false
+	}
+	return true
+}
+
+// bodyExample 根据 example tag 生成请求体的示例，没有任何示例时返回 nil
+func bodyExample(t reflect.Type, method string) any {
+	example := make(map[string]any)
+	for _, f := range requestLayout(t, method) {
+		if f.In != "json" && f.In != "form" {
+			continue
+		}
+		if v, ok := fieldExample(f.Field); ok {
+			example[f.Name] = v
+		} else if v, ok := exampleOf(f.Field.Type); ok {
+			example[f.Name] = v
+		}
+	}
+	if len(example) == 0 {
+		return nil
+	}
+	return example
+}
@@ -0,0 +1,82 @@
+package apihandler
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// exampleProfile 测试用的资料
+type exampleProfile struct {
+	Bio  string          `json:"bio" example:"hello"`
+	Next *exampleProfile `json:"next,omitempty"`
+}
+
+// exampleUser 测试用的用户
+type exampleUser struct {
+	ID        int64            `path:"id" example:"42"`
+	Name      string           `json:"name" example:"zhangsan"`
+	Age       *int             `json:"age" example:"18"`
+	Active    bool             `json:"active" example:"true"`
+	Tags      []string         `json:"tags" example:"admin, vip"`
+	CreatedAt time.Time        `json:"created_at" example:"2024-01-02T03:04:05Z"`
+	Profile   exampleProfile   `json:"profile"`
+	Friends   []exampleProfile `json:"friends"`
+	Invalid   int              `json:"invalid" example:"abc"`
+	Plain     string           `json:"plain"`
+}
+
+// 测试根据 example tag 创建示例值
+func TestExampleFor(t *testing.T) {
+	user := ExampleFor[exampleUser]()
+	if user.ID != 42 || user.Name != "zhangsan" || user.Age == nil || *user.Age != 18 || !user.Active {
+		t.Errorf("期望基本类型的字段填充示例值, 实际得到 %+v", user)
+	}
+	if !reflect.DeepEqual(user.Tags, []string{"admin", "vip"}) {
+		t.Errorf("期望切片以逗号分隔多个元素, 实际得到 %v", user.Tags)
+	}
+	if !user.CreatedAt.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) {
+		t.Errorf("期望时间按 RFC3339 解析, 实际得到 %v", user.CreatedAt)
+	}
+	if user.Profile.Bio != "hello" || user.Profile.Next != nil {
+		t.Errorf("期望嵌套结构体递归填充且不展开递归类型, 实际得到 %+v", user.Profile)
+	}
+	if len(user.Friends) != 1 || user.Friends[0].Bio != "hello" {
+		t.Errorf("期望结构体切片填充一个元素, 实际得到 %+v", user.Friends)
+	}
+	if user.Invalid != 0 || user.Plain != "" {
+		t.Errorf("期望无法解析和没有示例的字段保持零值, 实际得到 %d %q", user.Invalid, user.Plain)
+	}
+}
+
+// 测试 OpenAPI 文档包含示例
+func TestOpenAPIExamples(t *testing.T) {
+	doc := GenerateOpenAPI(OpenAPIInfo{Title: "User API", Version: "1"}, []RouteInfo{{
+		Method:      http.MethodPut,
+		Path:        "/users/:id",
+		Request:     reflect.TypeFor[exampleUser](),
+		Response:    reflect.TypeFor[exampleProfile](),
+		SuccessCode: 0,
+	}})
+	op := doc.Paths["/users/{id}"].Put
+
+	if op.Parameters[0].Example != int64(42) {
+		t.Errorf("期望路径参数包含示例, 实际得到 %v", op.Parameters[0].Example)
+	}
+	body := op.RequestBody.Content["application/json"].Example.(map[string]any)
+	if body["name"] != "zhangsan" || body["id"] != nil || body["ID"] != nil {
+		t.Errorf("期望请求体示例只包含请求体字段, 实际得到 %v", body)
+	}
+	if profile, ok := body["profile"].(exampleProfile); !ok || profile.Bio != "hello" {
+		t.Errorf("期望请求体示例包含嵌套结构体, 实际得到 %v", body["profile"])
+	}
+
+	success := op.Responses["200"].Content["application/json"].Example.(map[string]any)
+	if success["code"] != 0 || success["data"].(exampleProfile).Bio != "hello" {
+		t.Errorf("期望成功响应示例包含响应封装, 实际得到 %v", success)
+	}
+	if bio := doc.Components.Schemas["exampleProfile"].Properties["bio"]; !reflect.DeepEqual(bio.Examples, []any{"hello"}) {
+		t.Errorf("期望 Schema 属性包含示例, 实际得到 %v", bio.Examples)
+	}
+}
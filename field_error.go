@@ -0,0 +1,88 @@
+package apihandler
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// FieldError 字段级别的错误详情，参数验证失败时作为 Errors() 的元素输出
+//
+// JSON 编码时始终包含 field 和 message，与之前的 {"field", "message"} 结构兼容。
+type FieldError struct {
+	Field   string   `json:"field" xml:"field"`                         // 字段名
+	Rule    string   `json:"rule,omitempty" xml:"rule,omitempty"`       // 验证规则，如 required、min
+	Param   string   `json:"param,omitempty" xml:"param,omitempty"`     // 验证规则的参数，如 min=18 中的 18
+	Related []string `json:"related,omitempty" xml:"related,omitempty"` // 跨字段规则涉及的其他字段
+	Message string   `json:"message" xml:"message"`                     // 翻译后的错误消息
+	Value   any      `json:"value,omitempty" xml:"value,omitempty"`     // 字段的实际值
+}
+
+// Error 实现 error 接口
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// FieldErrors 返回错误详情中的字段错误
+//
+// 服务端可以直接传入 BizError 的 Errors()，客户端可以传入解码后的 ErrorResponse.Errors（元素为 map[string]any）。
+func FieldErrors(details []any) []FieldError {
+	var fieldErrors []FieldError
+	for _, detail := range details {
+		switch d := detail.(type) {
+		case FieldError:
+			fieldErrors = append(fieldErrors, d)
+		case *FieldError:
+			fieldErrors = append(fieldErrors, *d)
+		case map[string]string:
+			fieldErrors = append(fieldErrors, FieldError{Field: d["field"], Rule: d["rule"], Param: d["param"], Message: d["message"]})
+		case map[string]any:
+			fieldErrors = append(fieldErrors, FieldError{
+				Field:   stringValue(d["field"]),
+				Rule:    stringValue(d["rule"]),
+				Param:   stringValue(d["param"]),
+				Related: stringSlice(d["related"]),
+				Message: stringValue(d["message"]),
+				Value:   d["value"],
+			})
+		}
+	}
+	return fieldErrors
+}
+
+// FieldErrors 返回错误响应中的字段错误
+func (e ErrorResponse) FieldErrors() []FieldError {
+	return FieldErrors(e.Errors)
+}
+
+// fieldValue 返回字段错误中输出的实际值，零值（如未填写的必填字段）不输出
+func fieldValue(v any) any {
+	if v == nil || reflect.ValueOf(v).IsZero() {
+		return nil
+	}
+	return v
+}
+
+// stringSlice 将 JSON 解码得到的数组转换为字符串切片
+func stringSlice(v any) []string {
+	items, ok := v.([]any)
+	if !ok {
+		return nil
+	}
+	values := make([]string, len(items))
+	for i, item := range items {
+		values[i] = stringValue(item)
+	}
+	return values
+}
+
+// stringValue 将 JSON 解码得到的值转换为字符串
+func stringValue(v any) string {
+	switch s := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return s
+	default:
+		return fmt.Sprint(s)
+	}
+}
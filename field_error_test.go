@@ -0,0 +1,79 @@
+package apihandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试参数验证失败时输出 FieldError
+func TestFieldErrors(t *testing.T) {
+	type createRequest struct {
+		Name string `json:"name" binding:"required"`
+		Age  int    `json:"age" binding:"min=18"`
+	}
+
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *createRequest) (*testResponse, error) {
+		return &testResponse{}, nil
+	}
+
+	r.POST("/users", Handler(handleFunc))
+
+	req := httptest.NewRequest("POST", "/users", bytes.NewReader([]byte(`{"age":10}`)))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusBadRequest, w.Code)
+	}
+
+	expected := `{"code":400,"message":"参数绑定失败","errors":[` +
+		`{"field":"name","rule":"required","message":"字段验证失败: required"},` +
+		`{"field":"age","rule":"min","param":"18","message":"字段验证失败: min=18","value":10}]}`
+	if w.Body.String() != expected {
+		t.Errorf("期望响应为 %s, 实际得到 %s", expected, w.Body.String())
+	}
+
+	var resp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+
+	fieldErrors := resp.FieldErrors()
+	if len(fieldErrors) != 2 {
+		t.Fatalf("期望 2 个字段错误, 实际得到 %d", len(fieldErrors))
+	}
+
+	if fe := fieldErrors[1]; fe.Field != "age" || fe.Rule != "min" || fe.Param != "18" || fe.Value != float64(10) {
+		t.Errorf("期望 {age min 18 10}, 实际得到 %+v", fe)
+	}
+}
+
+// 测试 FieldErrors 兼容不同类型的错误详情
+func TestFieldErrorsFromDetails(t *testing.T) {
+	details := []any{
+		FieldError{Field: "name", Message: "名称不能为空"},
+		&FieldError{Field: "age", Rule: "min", Param: "18"},
+		map[string]string{"field": "email", "message": "邮箱格式不正确"},
+		"其他详情",
+	}
+
+	fieldErrors := FieldErrors(NewBizErrorWithDetails(40000, "参数错误", http.StatusBadRequest, details).Errors())
+
+	if len(fieldErrors) != 3 {
+		t.Fatalf("期望 3 个字段错误, 实际得到 %d", len(fieldErrors))
+	}
+
+	if fieldErrors[0].Field != "name" || fieldErrors[1].Param != "18" || fieldErrors[2].Message != "邮箱格式不正确" {
+		t.Errorf("期望按顺序转换字段错误, 实际得到 %+v", fieldErrors)
+	}
+}
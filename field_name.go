@@ -0,0 +1,126 @@
+package apihandler
+
+import (
+	"reflect"
+	"strings"
+)
+
+// fieldNamer 将验证错误的结构体命名空间转换为客户端发送的字段名
+type fieldNamer struct {
+	t    reflect.Type // 请求类型
+	form bool         // 请求参数来自表单或查询参数时优先使用 form tag，否则优先使用 json tag
+}
+
+// newFieldNamer 创建请求的字段名转换
+func newFieldNamer(req any, form bool) fieldNamer {
+	return fieldNamer{t: reflect.TypeOf(req), form: form}
+}
+
+// segments 返回命名空间（如 CreateRequest.Items[0].SKU）中每一级字段的名称，如 [items[0] sku]
+//
+// 第一级为请求类型的名称（匿名结构体没有该级），不包含在结果中；匿名嵌入且没有 json tag 的结构体在 JSON 中展开，
+// 同样不包含。无法解析的字段使用 Go 字段名。
+func (n fieldNamer) segments(namespace string) []string {
+	if namespace == "" {
+		return nil
+	}
+	parts := splitNamespace(namespace)
+	t := n.t
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Name() != "" {
+		parts = parts[1:]
+	}
+	if t == nil {
+		return parts
+	}
+
+	var names []string
+	for _, part := range parts {
+		name, index, _ := strings.Cut(part, "[")
+		if index != "" {
+			index = "[" + index
+		}
+
+		for t != nil && t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+		var field reflect.StructField
+		ok := t != nil && t.Kind() == reflect.Struct
+		if ok {
+			field, ok = t.FieldByName(name)
+		}
+		if !ok {
+			names = append(names, part)
+			t = nil
+			continue
+		}
+
+		t = field.Type
+		for i := strings.Count(index, "["); i > 0; i-- {
+			for t.Kind() == reflect.Pointer {
+				t = t.Elem()
+			}
+			if k := t.Kind(); k == reflect.Slice || k == reflect.Array || k == reflect.Map {
+				t = t.Elem()
+			}
+		}
+		if field.Anonymous && field.Tag.Get("json") == "" && index == "" {
+			continue
+		}
+		names = append(names, n.fieldName(field)+index)
+	}
+	return names
+}
+
+// path 返回字段在请求中的完整路径，如 items[2].price，命名空间为空时返回 fallback
+func (n fieldNamer) path(namespace, fallback string) string {
+	segments := n.segments(namespace)
+	if len(segments) == 0 {
+		return fallback
+	}
+	return strings.Join(segments, ".")
+}
+
+// fieldName 返回字段在请求中的名称，依次使用 path tag、json 或 form tag（按请求类型决定优先级）和 Go 字段名
+func (n fieldNamer) fieldName(field reflect.StructField) string {
+	if name := tagName(field, PathTag); name != "" {
+		return name
+	}
+	jsonName := ""
+	if name, _, ok := jsonFieldName(field); ok && field.Tag.Get("json") != "" {
+		jsonName = name
+	}
+	primary, secondary := jsonName, tagName(field, "form")
+	if n.form {
+		primary, secondary = secondary, primary
+	}
+	switch {
+	case primary != "":
+		return primary
+	case secondary != "":
+		return secondary
+	}
+	return field.Name
+}
+
+// splitNamespace 按 . 拆分命名空间，方括号中的 . 不拆分，如 map 的键
+func splitNamespace(namespace string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range namespace {
+		switch r {
+		case '[':
+			depth++
+		case ']':
+			depth--
+		case '.':
+			if depth == 0 {
+				parts = append(parts, namespace[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, namespace[start:])
+}
@@ -0,0 +1,154 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+type fieldNameItem struct {
+	SKU      string `json:"sku" form:"sku_id"`
+	Quantity int
+}
+
+type fieldNameBase struct {
+	TenantID string `json:"tenant_id"`
+}
+
+type fieldNameRequest struct {
+	fieldNameBase
+	ID      string                   `path:"id"`
+	Name    string                   `json:"name" form:"user_name"`
+	Items   []fieldNameItem          `json:"items"`
+	Labels  map[string]fieldNameItem `json:"labels"`
+	Address *struct {
+		City string `form:"city"`
+	} `json:"address"`
+}
+
+// 测试验证错误的命名空间转换为客户端发送的字段名
+func TestFieldNamerSegments(t *testing.T) {
+	tests := []struct {
+		namespace string
+		form      bool
+		expected  []string
+	}{
+		{"fieldNameRequest.Name", false, []string{"name"}},
+		{"fieldNameRequest.Name", true, []string{"user_name"}},
+		{"fieldNameRequest.ID", false, []string{"id"}},
+		{"fieldNameRequest.fieldNameBase.TenantID", false, []string{"tenant_id"}},
+		{"fieldNameRequest.Items[1].SKU", false, []string{"items[1]", "sku"}},
+		{"fieldNameRequest.Items[1].SKU", true, []string{"items[1]", "sku_id"}},
+		{"fieldNameRequest.Items[0].Quantity", false, []string{"items[0]", "Quantity"}},
+		{"fieldNameRequest.Labels[a.b].SKU", false, []string{"labels[a.b]", "sku"}},
+		{"fieldNameRequest.Address.City", false, []string{"address", "city"}},
+		{"fieldNameRequest.Missing.Name", false, []string{"Missing", "Name"}},
+	}
+
+	for _, tt := range tests {
+		names := newFieldNamer(&fieldNameRequest{}, tt.form)
+		if got := names.segments(tt.namespace); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("%s (form=%v): 期望 %v, 实际得到 %v", tt.namespace, tt.form, tt.expected, got)
+		}
+	}
+
+	names := newFieldNamer(&fieldNameRequest{}, false)
+	if got := names.path("fieldNameRequest.Items[2].SKU", "SKU"); got != "items[2].sku" {
+		t.Errorf("期望完整路径 items[2].sku, 实际得到 %s", got)
+	}
+	if got := newFieldNamer(nil, false).path("Request.Name", "Name"); got != "Name" {
+		t.Errorf("期望无法解析时使用 Go 字段名, 实际得到 %s", got)
+	}
+	anonymous := newFieldNamer(&struct {
+		Items []fieldNameItem `json:"items"`
+	}{}, false)
+	if got := anonymous.path("Items[0].SKU", "SKU"); got != "items[0].sku" {
+		t.Errorf("期望匿名请求类型的完整路径 items[0].sku, 实际得到 %s", got)
+	}
+	if got := names.path("", "Name"); got != "Name" {
+		t.Errorf("期望命名空间为空时使用 fallback, 实际得到 %s", got)
+	}
+}
+
+// 测试 JSON 和查询参数验证失败时的字段名
+func TestValidationFieldNames(t *testing.T) {
+	type searchRequest struct {
+		Keyword string `json:"keyword" form:"q" binding:"required"`
+		Page    int    `json:"page" form:"page" binding:"min=1"`
+	}
+
+	r := gin.New()
+	handleFunc := func(ctx context.Context, req *searchRequest) (*testResponse, error) {
+		return &testResponse{}, nil
+	}
+	r.GET("/search", Handler(handleFunc))
+	r.POST("/search", Handler(handleFunc))
+
+	send := func(req *http.Request) []string {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("期望状态码 %d, 实际得到 %d", http.StatusBadRequest, w.Code)
+		}
+		var resp ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("解析响应失败: %v", err)
+		}
+		var fields []string
+		for _, fe := range resp.FieldErrors() {
+			fields = append(fields, fe.Field)
+		}
+		return fields
+	}
+
+	if got := send(httptest.NewRequest("GET", "/search?page=0", nil)); strings.Join(got, ",") != "q,page" {
+		t.Errorf("期望查询参数使用 form tag 的名称, 实际得到 %v", got)
+	}
+
+	req := httptest.NewRequest("POST", "/search", strings.NewReader(`{"page":0}`))
+	req.Header.Set("Content-Type", "application/json")
+	if got := send(req); strings.Join(got, ",") != "keyword,page" {
+		t.Errorf("期望 JSON 请求使用 json tag 的名称, 实际得到 %v", got)
+	}
+}
+
+// 测试嵌套结构体和切片元素验证失败时输出完整路径
+func TestValidationFieldPaths(t *testing.T) {
+	type orderItem struct {
+		Price int `json:"price" binding:"gt=0"`
+	}
+	type createOrderRequest struct {
+		Items   []orderItem `json:"items" binding:"dive"`
+		Address struct {
+			City string `json:"city" binding:"required"`
+		} `json:"address"`
+	}
+
+	r := gin.New()
+	r.POST("/orders", Handler(func(ctx context.Context, req *createOrderRequest) (*testResponse, error) {
+		return &testResponse{}, nil
+	}))
+
+	req := httptest.NewRequest("POST", "/orders", strings.NewReader(`{"items":[{"price":1},{"price":2},{"price":0}]}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var resp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	var fields []string
+	for _, fe := range resp.FieldErrors() {
+		fields = append(fields, fe.Field)
+	}
+	if strings.Join(fields, ",") != "items[2].price,address.city" {
+		t.Errorf("期望 items[2].price,address.city, 实际得到 %v", fields)
+	}
+}
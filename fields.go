@@ -0,0 +1,122 @@
+package apihandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fieldTree 字段选择树，子树为空表示保留整个字段
+type fieldTree map[string]fieldTree
+
+// parseFields 解析字段列表，如 "id,user.id,user.name"
+func parseFields(fields string) fieldTree {
+	tree := fieldTree{}
+	for _, field := range strings.Split(fields, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		node := tree
+		parts := strings.Split(field, ".")
+		for i, part := range parts {
+			child, exists := node[part]
+			if exists && len(child) == 0 {
+				// 已选择整个字段
+				break
+			}
+			if i == len(parts)-1 {
+				node[part] = fieldTree{}
+				break
+			}
+			if !exists {
+				child = fieldTree{}
+				node[part] = child
+			}
+			node = child
+		}
+	}
+	return tree
+}
+
+// prune 按字段选择树裁剪 JSON 解码后的数据，数组按元素逐个裁剪
+func (t fieldTree) prune(v any) any {
+	switch value := v.(type) {
+	case map[string]any:
+		pruned := make(map[string]any, len(t))
+		for key, subtree := range t {
+			child, ok := value[key]
+			if !ok {
+				continue
+			}
+			if len(subtree) == 0 {
+				pruned[key] = child
+			} else {
+				pruned[key] = subtree.prune(child)
+			}
+		}
+		return pruned
+	case []any:
+		for i, item := range value {
+			value[i] = t.prune(item)
+		}
+		return value
+	default:
+		return v
+	}
+}
+
+// splitResponse 保留元数据和链接的裁剪结果
+type splitResponse struct {
+	data  any
+	meta  any
+	links LinkBuilder
+}
+
+// Data 实现 DataProvider 接口
+func (r *splitResponse) Data() any {
+	return r.data
+}
+
+// Meta 实现 MetaProvider 接口
+func (r *splitResponse) Meta() any {
+	return r.meta
+}
+
+// Links 实现 LinkBuilder 接口
+func (r *splitResponse) Links(c *gin.Context) Links {
+	if r.links == nil {
+		return nil
+	}
+	return r.links.Links(c)
+}
+
+// sparseResponse 只保留客户端请求的字段，字段名为 JSON 字段名，meta 和 links 不受影响
+func sparseResponse(resp any, fields string) any {
+	tree := parseFields(fields)
+	if len(tree) == 0 || isNil(resp) {
+		return resp
+	}
+
+	data, meta := splitMeta(resp)
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return resp
+	}
+	var decoded any
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&decoded); err != nil {
+		return resp
+	}
+
+	pruned := tree.prune(decoded)
+	_, isDataProvider := resp.(DataProvider)
+	links, _ := resp.(LinkBuilder)
+	if meta != nil || isDataProvider || links != nil {
+		return &splitResponse{data: pruned, meta: meta, links: links}
+	}
+	return pruned
+}
@@ -0,0 +1,59 @@
+package apihandler
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试通过 fields 参数裁剪响应字段
+func TestSparseFields(t *testing.T) {
+	type profile struct {
+		ID    int64  `json:"id"`
+		Name  string `json:"name"`
+		Email string `json:"email"`
+	}
+
+	type order struct {
+		ID     int64   `json:"id"`
+		Amount int64   `json:"amount"`
+		User   profile `json:"user"`
+	}
+
+	r := gin.New()
+
+	r.GET("/order", Handler(func(ctx context.Context, req *testRequest) (*order, error) {
+		return &order{ID: 1, Amount: 100, User: profile{ID: 2, Name: "tom", Email: "tom@example.com"}}, nil
+	}, WithSparseFields("fields")))
+
+	r.GET("/orders", Handler(func(ctx context.Context, req *testRequest) (*ListResponse[order], error) {
+		return &ListResponse[order]{
+			Items:    []order{{ID: 1, Amount: 100}, {ID: 2, Amount: 200}},
+			ListMeta: ListMeta{Total: 2},
+		}, nil
+	}, WithSparseFields("fields")))
+
+	cases := []struct {
+		path string
+		body string
+	}{
+		{"/order?fields=id,user.name", `{"code":0,"data":{"id":1,"user":{"name":"tom"}}}`},
+		{"/order?fields=user,user.name", `{"code":0,"data":{"user":{"email":"tom@example.com","id":2,"name":"tom"}}}`},
+		{"/order?fields=missing", `{"code":0,"data":{}}`},
+		{"/order", `{"code":0,"data":{"id":1,"amount":100,"user":{"id":2,"name":"tom","email":"tom@example.com"}}}`},
+		{"/orders?fields=amount", `{"code":0,"data":[{"amount":100},{"amount":200}],"meta":{"total":2}}`},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", tc.path, nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Body.String() != tc.body {
+			t.Errorf("%s: 期望响应为 %s, 实际得到 %s", tc.path, tc.body, w.Body.String())
+		}
+	}
+}
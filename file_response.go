@@ -0,0 +1,132 @@
+package apihandler
+
+import (
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Responder 自行写入响应的返回值接口
+//
+// 业务处理函数的返回值实现该接口时跳过响应封装，由 Respond 直接写入响应。
+// Respond 在写入任何内容之前返回的错误会按普通错误处理。
+type Responder interface {
+	Respond(c *gin.Context, httpCode int) error
+}
+
+// FileResponse 文件下载响应，以附件形式流式输出文件内容
+type FileResponse struct {
+	Name        string    // 下载时的文件名
+	ContentType string    // 内容类型，为空时根据文件名推断
+	Reader      io.Reader // 文件内容，实现 io.Closer 时输出完成后会被关闭
+	Size        int64     // 内容长度，小于等于 0 表示未知，仅对 Reader 生效
+	Path        string    // 本地文件路径，Reader 为空时使用
+}
+
+// Respond 实现 Responder 接口
+func (f *FileResponse) Respond(c *gin.Context, httpCode int) error {
+	reader, size, name := f.Reader, f.Size, f.Name
+	if reader == nil {
+		file, err := os.Open(f.Path)
+		if err != nil {
+			return err
+		}
+		info, err := file.Stat()
+		if err != nil {
+			file.Close()
+			return err
+		}
+		reader, size = file, info.Size()
+		if name == "" {
+			name = filepath.Base(f.Path)
+		}
+	}
+	if closer, ok := reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+	if size <= 0 {
+		size = -1
+	}
+
+	contentType := f.ContentType
+	if contentType == "" {
+		contentType = mime.TypeByExtension(filepath.Ext(name))
+	}
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	c.DataFromReader(httpCode, size, contentType, reader, map[string]string{
+		"Content-Disposition": contentDisposition("attachment", name),
+	})
+	return nil
+}
+
+// contentDisposition 生成 Content-Disposition 头，非 ASCII 文件名按 RFC 2231 编码
+func contentDisposition(disposition, name string) string {
+	if name == "" {
+		return disposition
+	}
+	if v := mime.FormatMediaType(disposition, map[string]string{"filename": name}); v != "" {
+		return v
+	}
+	return disposition
+}
+
+// streamChunkSize 流式输出时每次写入的最大字节数
+const streamChunkSize = 32 * 1024
+
+// StreamResponse 流式响应，边读取边写入并刷新，避免大文件导出时在内存中缓存完整内容
+type StreamResponse struct {
+	ContentType string    // 内容类型，为空时使用 application/octet-stream
+	Reader      io.Reader // 响应内容，实现 io.Closer 时输出完成后会被关闭
+	Size        int64     // 内容长度，小于等于 0 表示未知（使用分块传输）
+}
+
+// Respond 实现 Responder 接口
+func (s *StreamResponse) Respond(c *gin.Context, httpCode int) error {
+	if closer, ok := s.Reader.(io.Closer); ok {
+		defer closer.Close()
+	}
+
+	contentType := s.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	c.Header("Content-Type", contentType)
+	if s.Size > 0 {
+		c.Header("Content-Length", strconv.FormatInt(s.Size, 10))
+	}
+	c.Status(httpCode)
+
+	buf := make([]byte, streamChunkSize)
+	for {
+		n, err := s.Reader.Read(buf)
+		if n > 0 {
+			if _, werr := c.Writer.Write(buf[:n]); werr != nil {
+				// 客户端断开连接
+				return nil
+			}
+			c.Writer.Flush()
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// 尚未写入任何内容时按普通错误处理，否则只能中断输出
+			if !c.Writer.Written() {
+				c.Writer.Header().Del("Content-Type")
+				c.Writer.Header().Del("Content-Length")
+				return err
+			}
+			c.Error(err)
+			break
+		}
+	}
+	c.Writer.WriteHeaderNow()
+	return nil
+}
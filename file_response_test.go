@@ -0,0 +1,175 @@
+package apihandler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试以附件形式输出 Reader 内容
+func TestFileResponseReader(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*FileResponse, error) {
+		return &FileResponse{
+			Name:   "用户列表.csv",
+			Reader: strings.NewReader("id,name\n1,张三\n"),
+		}, nil
+	}
+
+	r.GET("/export", Handler(handleFunc))
+
+	req := httptest.NewRequest("GET", "/export", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusOK, w.Code)
+	}
+
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/csv") {
+		t.Errorf("期望 Content-Type 为 text/csv, 实际得到 '%s'", ct)
+	}
+
+	expected := "attachment; filename*=utf-8''%E7%94%A8%E6%88%B7%E5%88%97%E8%A1%A8.csv"
+	if cd := w.Header().Get("Content-Disposition"); cd != expected {
+		t.Errorf("期望 Content-Disposition 为 '%s', 实际得到 '%s'", expected, cd)
+	}
+
+	if w.Body.String() != "id,name\n1,张三\n" {
+		t.Errorf("响应内容不正确: %q", w.Body.String())
+	}
+}
+
+// 测试输出本地文件，以及文件不存在时返回错误响应
+func TestFileResponsePath(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "report.txt")
+	if err := os.WriteFile(path, []byte("hello"), 0o644); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+
+	type fileRequest struct {
+		Name string `path:"name"`
+	}
+
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *fileRequest) (*FileResponse, error) {
+		return &FileResponse{Path: filepath.Join(dir, req.Name)}, nil
+	}
+
+	r.GET("/files/:name", Handler(handleFunc))
+
+	req := httptest.NewRequest("GET", "/files/report.txt", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusOK, w.Code)
+	}
+
+	if cd := w.Header().Get("Content-Disposition"); cd != "attachment; filename=report.txt" {
+		t.Errorf("期望 Content-Disposition 为 'attachment; filename=report.txt', 实际得到 '%s'", cd)
+	}
+
+	if cl := w.Header().Get("Content-Length"); cl != "5" {
+		t.Errorf("期望 Content-Length 为 5, 实际得到 '%s'", cl)
+	}
+
+	req = httptest.NewRequest("GET", "/files/missing.txt", nil)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusInternalServerError, w.Code)
+	}
+}
+
+// 分块读取的 Reader，用于模拟大文件导出
+type chunkedReader struct {
+	chunks []string
+	closed bool
+}
+
+func (r *chunkedReader) Read(p []byte) (int, error) {
+	if len(r.chunks) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, r.chunks[0])
+	r.chunks = r.chunks[1:]
+	return n, nil
+}
+
+func (r *chunkedReader) Close() error {
+	r.closed = true
+	return nil
+}
+
+// 测试流式输出 Reader 内容
+func TestStreamResponse(t *testing.T) {
+	reader := &chunkedReader{chunks: []string{"line1\n", "line2\n", "line3\n"}}
+
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*StreamResponse, error) {
+		return &StreamResponse{ContentType: "text/plain; charset=utf-8", Reader: reader}, nil
+	}
+
+	r.GET("/stream", Handler(handleFunc))
+
+	req := httptest.NewRequest("GET", "/stream", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusOK, w.Code)
+	}
+
+	if ct := w.Header().Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+		t.Errorf("期望 Content-Type 为 'text/plain; charset=utf-8', 实际得到 '%s'", ct)
+	}
+
+	if w.Body.String() != "line1\nline2\nline3\n" {
+		t.Errorf("响应内容不正确: %q", w.Body.String())
+	}
+
+	if !w.Flushed {
+		t.Errorf("期望输出过程中刷新响应")
+	}
+
+	if !reader.closed {
+		t.Errorf("期望输出完成后关闭 Reader")
+	}
+}
+
+// 测试空内容的流式输出仍然写入状态码
+func TestStreamResponseEmpty(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*StreamResponse, error) {
+		return &StreamResponse{Reader: strings.NewReader("")}, nil
+	}
+
+	r.GET("/stream", Handler(handleFunc, WithSuccessHTTPCode(http.StatusAccepted)))
+
+	req := httptest.NewRequest("GET", "/stream", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusAccepted {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusAccepted, w.Code)
+	}
+}
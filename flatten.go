@@ -0,0 +1,102 @@
+package apihandler
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// flatSuccessEnvelope 扁平化的成功响应体
+//
+// JSON 编码时 data 的字段与 code、meta、links 位于同一层级，data 中与这些字段同名的字段会被忽略；
+// data 不是 JSON 对象（如列表、nil）时仍输出 data 字段。XML 和 MessagePack 响应不受影响。
+type flatSuccessEnvelope struct {
+	successEnvelope
+}
+
+// MarshalJSON 实现 json.Marshaler 接口
+func (e flatSuccessEnvelope) MarshalJSON() ([]byte, error) {
+	return e.marshalJSON(StdJSONCodec{})
+}
+
+// marshalJSON 实现 codecMarshaler 接口
+func (e flatSuccessEnvelope) marshalJSON(codec JSONCodec) ([]byte, error) {
+	raw, err := codec.Marshal(e.Data)
+	if err != nil {
+		return nil, err
+	}
+	fields, ok := objectFields(raw)
+	if !ok {
+		return e.successEnvelope.marshalJSON(codec)
+	}
+
+	var buf bytes.Buffer
+	writeField := func(key string, value json.RawMessage) {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	buf.WriteByte('{')
+	code, err := codec.Marshal(e.Code)
+	if err != nil {
+		return nil, err
+	}
+	writeField("code", code)
+	idField := ""
+	if e.RequestID != "" {
+		idField = requestIDField(e.RequestIDField)
+	}
+	for _, field := range fields {
+		if field.key == "code" || field.key == "meta" || field.key == "links" || field.key == idField {
+			continue
+		}
+		writeField(field.key, field.value)
+	}
+	if e.Meta != nil {
+		meta, err := codec.Marshal(e.Meta)
+		if err != nil {
+			return nil, err
+		}
+		writeField("meta", meta)
+	}
+	if len(e.Links) > 0 {
+		links, err := codec.Marshal(e.Links)
+		if err != nil {
+			return nil, err
+		}
+		writeField("links", links)
+	}
+	buf.WriteByte('}')
+	return appendRequestID(buf.Bytes(), e.RequestIDField, e.RequestID), nil
+}
+
+// objectField JSON 对象中的字段
+type objectField struct {
+	key   string
+	value json.RawMessage
+}
+
+// objectFields 按原有顺序解析 JSON 对象的字段，不是 JSON 对象时 ok 为 false
+func objectFields(raw []byte) (fields []objectField, ok bool) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, false
+	}
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, false
+		}
+		key, _ := token.(string)
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, false
+		}
+		fields = append(fields, objectField{key: key, value: value})
+	}
+	return fields, true
+}
@@ -0,0 +1,114 @@
+package apihandler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 旧接口的用户响应
+type legacyUserResponse struct {
+	UserID int64  `json:"user_id"`
+	Name   string `json:"name"`
+}
+
+// 测试扁平化响应封装
+func TestFlattenedData(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*legacyUserResponse, error) {
+		return &legacyUserResponse{UserID: req.ID, Name: "x"}, nil
+	}
+
+	r.GET("/users/:id", Handler(handleFunc, WithFlattenedData()))
+
+	req := httptest.NewRequest("GET", "/users/1", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusOK, w.Code)
+	}
+
+	expected := `{"code":0,"user_id":1,"name":"x"}`
+	if w.Body.String() != expected {
+		t.Errorf("期望响应为 %s, 实际得到 %s", expected, w.Body.String())
+	}
+}
+
+// 测试扁平化响应封装保留 meta，并忽略 data 中与顶层字段同名的字段
+func TestFlattenedDataReservedFields(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*map[string]any, error) {
+		return &map[string]any{"code": "data-code", "name": "x"}, nil
+	}
+	listFunc := func(ctx context.Context, req *testRequest) (*ListResponse[legacyUserResponse], error) {
+		return &ListResponse[legacyUserResponse]{
+			Items:    []legacyUserResponse{{UserID: 1, Name: "x"}},
+			ListMeta: ListMeta{Total: 1},
+		}, nil
+	}
+
+	r.GET("/map", Handler(handleFunc, WithFlattenedData(), WithSuccessCode(200)))
+	r.GET("/list", Handler(listFunc, WithFlattenedData()))
+
+	cases := []struct {
+		path     string
+		expected string
+	}{
+		{"/map", `{"code":200,"name":"x"}`},
+		// 列表不是 JSON 对象，仍输出 data 字段
+		{"/list", `{"code":0,"data":[{"user_id":1,"name":"x"}],"meta":{"total":1}}`},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", tc.path, nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Body.String() != tc.expected {
+			t.Errorf("%s: 期望响应为 %s, 实际得到 %s", tc.path, tc.expected, w.Body.String())
+		}
+	}
+}
+
+// 测试扁平化响应封装不影响错误响应和 XML 响应
+func TestFlattenedDataErrorAndXML(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*legacyUserResponse, error) {
+		if req.ID == 0 {
+			return nil, ErrNotFound(40400, "用户不存在")
+		}
+		return &legacyUserResponse{UserID: req.ID, Name: "x"}, nil
+	}
+
+	r.GET("/users/:id", Handler(handleFunc, WithFlattenedData(), WithFormats(FormatJSON, FormatXML)))
+
+	req := httptest.NewRequest("GET", "/users/0", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	expected := `{"code":40400,"message":"用户不存在"}`
+	if w.Body.String() != expected {
+		t.Errorf("期望响应为 %s, 实际得到 %s", expected, w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/users/1", nil)
+	req.Header.Set("Accept", "application/xml")
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if !strings.Contains(w.Body.String(), "<data>") {
+		t.Errorf("期望 XML 响应仍包含 data 元素, 实际得到 %s", w.Body.String())
+	}
+}
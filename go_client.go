@@ -0,0 +1,180 @@
+package apihandler
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// apihandlerImportPath 本包的导入路径
+var apihandlerImportPath = reflect.TypeFor[Client]().PkgPath()
+
+// GoClient 根据注册表中的路由生成 Go 客户端包的源码
+func (r *Registry) GoClient(pkgName string) ([]byte, error) {
+	return GenerateGoClient(pkgName, r.Routes())
+}
+
+// GenerateGoClient 根据路由生成 Go 客户端包的源码，每个路由生成一个方法，请求和响应使用处理器的类型
+//
+// 生成的方法通过 Call 发送请求，方法名取自 Meta.Name（首字母大写），未设置时由 HTTP 方法和路径生成，
+// 如 GET /api/user/:id 为 GetApiUserById。请求和响应类型必须是包级别的导出类型，否则返回错误。
+func GenerateGoClient(pkgName string, routes []RouteInfo) ([]byte, error) {
+	imports := &goImports{
+		aliases: map[string]string{apihandlerImportPath: "apihandler"},
+		used:    map[string]bool{"apihandler": true, "context": true},
+	}
+
+	var methods bytes.Buffer
+	methodNames := map[string]bool{"Client": true}
+	for _, route := range routes {
+		reqType, err := imports.typeExpr(structType(route.Request))
+		if err != nil {
+			return nil, fmt.Errorf("apihandler: %s %s request: %w", route.Method, route.Path, err)
+		}
+		respType, err := imports.typeExpr(structType(route.Response))
+		if err != nil {
+			return nil, fmt.Errorf("apihandler: %s %s response: %w", route.Method, route.Path, err)
+		}
+
+		name := clientMethodName(route)
+		name = uniqueIdentifier(methodNames, strings.ToUpper(name[:1])+name[1:])
+		comment := route.Method + " " + route.Path
+		if route.Meta.Summary != "" {
+			comment = route.Meta.Summary + "，" + comment
+		}
+		fmt.Fprintf(&methods, "\n// %s %s\n", name, strings.ReplaceAll(comment, "\n", " "))
+		if route.Deprecation != nil {
+			fmt.Fprintf(&methods, "//\n// Deprecated: 该路由已废弃。%s\n", deprecationNote(route.Deprecation))
+		}
+		fmt.Fprintf(&methods, "func (c *Client) %s(ctx context.Context, req *%s) (*%s, error) {\n", name, reqType, respType)
+		fmt.Fprintf(&methods, "\treturn apihandler.Call[%s, %s](ctx, c.Client, %q, %q, req)\n}\n", reqType, respType, route.Method, route.Path)
+	}
+
+	var b bytes.Buffer
+	b.WriteString("// Code generated by apihandler. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&b, "package %s\n\n", pkgName)
+	b.WriteString("import (\n\t\"context\"\n\n")
+	fmt.Fprintf(&b, "\tapihandler %q\n", apihandlerImportPath)
+	for _, p := range imports.paths() {
+		fmt.Fprintf(&b, "\t%s %q\n", imports.aliases[p], p)
+	}
+	b.WriteString(")\n\n")
+	b.WriteString("// Client 根据路由注册表生成的客户端\ntype Client struct {\n\t*apihandler.Client\n}\n\n")
+	b.WriteString("// New 创建客户端，baseURL 为服务地址\n")
+	b.WriteString("func New(baseURL string, opts ...apihandler.ClientOption) *Client {\n\treturn &Client{Client: apihandler.NewClient(baseURL, opts...)}\n}\n")
+	b.Write(methods.Bytes())
+
+	src, err := format.Source(b.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("apihandler: format client: %w", err)
+	}
+	return src, nil
+}
+
+// structType 去掉类型的指针，为 nil 时返回 struct{}
+func structType(t reflect.Type) reflect.Type {
+	if t == nil {
+		return reflect.TypeFor[struct{}]()
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
+
+// goImports 生成代码引用的包，每个包使用显式的别名
+type goImports struct {
+	aliases map[string]string // 导入路径对应的别名
+	used    map[string]bool   // 已使用的别名
+}
+
+// qualifiedName 匹配泛型类型参数中带包路径的类型名，如 github.com/x/model.User
+var qualifiedName = regexp.MustCompile(`([\w./-]+)\.(\w+)`)
+
+// typeExpr 返回类型在生成代码中的表达式
+func (g *goImports) typeExpr(t reflect.Type) (string, error) {
+	switch t.Kind() {
+	case reflect.Pointer:
+		elem, err := g.typeExpr(t.Elem())
+		return "*" + elem, err
+	case reflect.Slice:
+		if t.Name() == "" {
+			elem, err := g.typeExpr(t.Elem())
+			return "[]" + elem, err
+		}
+	case reflect.Array:
+		if t.Name() == "" {
+			elem, err := g.typeExpr(t.Elem())
+			return "[" + strconv.Itoa(t.Len()) + "]" + elem, err
+		}
+	case reflect.Map:
+		if t.Name() == "" {
+			key, err := g.typeExpr(t.Key())
+			if err != nil {
+				return "", err
+			}
+			elem, err := g.typeExpr(t.Elem())
+			return "map[" + key + "]" + elem, err
+		}
+	case reflect.Struct:
+		if t.Name() == "" {
+			if t.NumField() == 0 {
+				return "struct{}", nil
+			}
+			return "", fmt.Errorf("anonymous struct %s is not supported", t)
+		}
+	case reflect.Interface:
+		if t.Name() == "" && t.NumMethod() == 0 {
+			return "any", nil
+		}
+	}
+
+	if t.PkgPath() == "" {
+		return t.Name(), nil
+	}
+	if !isExportedName(t.Name()) {
+		return "", fmt.Errorf("type %s is not exported", t)
+	}
+	name, args, _ := strings.Cut(t.Name(), "[")
+	expr := g.alias(t.PkgPath()) + "." + name
+	if args != "" {
+		expr += "[" + qualifiedName.ReplaceAllStringFunc(args, func(s string) string {
+			m := qualifiedName.FindStringSubmatch(s)
+			return g.alias(m[1]) + "." + m[2]
+		})
+	}
+	return expr, nil
+}
+
+// alias 返回导入路径的别名，首次引用时添加导入
+func (g *goImports) alias(importPath string) string {
+	if alias, ok := g.aliases[importPath]; ok {
+		return alias
+	}
+	alias := uniqueIdentifier(g.used, identifier(strings.ReplaceAll(path.Base(importPath), "-", "")))
+	g.aliases[importPath] = alias
+	return alias
+}
+
+// paths 返回排序后的导入路径，不包括本包
+func (g *goImports) paths() []string {
+	paths := make([]string, 0, len(g.aliases))
+	for p := range g.aliases {
+		if p != apihandlerImportPath {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+// isExportedName 判断类型名称是否导出
+func isExportedName(name string) bool {
+	return name != "" && name[0] >= 'A' && name[0] <= 'Z'
+}
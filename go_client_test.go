@@ -0,0 +1,56 @@
+package apihandler
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// GoClientUser 测试用的导出响应类型
+type GoClientUser struct {
+	ID int64 `json:"id"`
+}
+
+// GoClientGetRequest 测试用的导出请求类型
+type GoClientGetRequest struct {
+	ID int64 `path:"id"`
+}
+
+// 测试生成 Go 客户端代码
+func TestGenerateGoClient(t *testing.T) {
+	routes := []RouteInfo{
+		{Method: http.MethodGet, Path: "/api/user/:id", Request: reflect.TypeFor[GoClientGetRequest](), Response: reflect.TypeFor[GoClientUser](), Meta: Meta{Name: "getUser", Summary: "获取用户"}},
+		{Method: http.MethodGet, Path: "/api/users", Request: reflect.TypeFor[struct{}](), Response: reflect.TypeFor[PageResult[GoClientUser]]()},
+		{Method: http.MethodGet, Path: "/api/time", Request: reflect.TypeFor[struct{}](), Response: reflect.TypeFor[map[string][]time.Time](), Deprecation: &Deprecation{Replacement: "/api/v2/time"}},
+	}
+	src, err := GenerateGoClient("userclient", routes)
+	if err != nil {
+		t.Fatalf("期望生成客户端代码, 实际得到错误 %v", err)
+	}
+	code := string(src)
+	for _, want := range []string{
+		"// Code generated by apihandler. DO NOT EDIT.",
+		"package userclient",
+		`apihandler "github.com/night1008/gotools/gin-api-handler"`,
+		`time "time"`,
+		"func New(baseURL string, opts ...apihandler.ClientOption) *Client {",
+		"// GetUser 获取用户，GET /api/user/:id\nfunc (c *Client) GetUser(ctx context.Context, req *apihandler.GoClientGetRequest) (*apihandler.GoClientUser, error) {",
+		`return apihandler.Call[apihandler.GoClientGetRequest, apihandler.GoClientUser](ctx, c.Client, "GET", "/api/user/:id", req)`,
+		"func (c *Client) GetApiUsers(ctx context.Context, req *struct{}) (*apihandler.PageResult[apihandler.GoClientUser], error) {",
+		"// GetApiTime GET /api/time\n//\n// Deprecated: 该路由已废弃。请改用 /api/v2/time。\nfunc (c *Client) GetApiTime(ctx context.Context, req *struct{}) (*map[string][]time.Time, error) {",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("期望生成的代码包含 %q, 实际得到:\n%s", want, code)
+		}
+	}
+}
+
+// 测试未导出的类型返回错误
+func TestGenerateGoClientUnexportedType(t *testing.T) {
+	routes := []RouteInfo{{Method: http.MethodGet, Path: "/", Request: reflect.TypeFor[testRequest](), Response: reflect.TypeFor[GoClientUser]()}}
+	if _, err := GenerateGoClient("client", routes); err == nil || !strings.Contains(err.Error(), "not exported") {
+		t.Errorf("期望未导出的类型返回错误, 实际得到 %v", err)
+	}
+}
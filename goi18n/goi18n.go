@@ -0,0 +1,83 @@
+// Package goi18n 基于 go-i18n 的消息包实现 apihandler.Translator，
+// 已维护 go-i18n 消息目录的项目可以直接用于错误消息和验证消息的翻译
+package goi18n
+
+import (
+	"fmt"
+
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	apihandler "github.com/night1008/gotools/gin-api-handler"
+)
+
+// Data 消息模板的数据，作为 Translate 的参数传入
+type Data map[string]any
+
+// pluralCount 复数形式的数量
+type pluralCount struct {
+	count any
+}
+
+// Plural 返回复数形式的数量参数，count 可以是整数、浮点数或数字字符串，模板中通过 {{.Count}} 引用
+func Plural(count any) any {
+	return pluralCount{count: count}
+}
+
+// Translator 基于 go-i18n 消息包的翻译器，消息键即 go-i18n 的消息 ID
+//
+// Translate 的参数中 Data 作为模板数据，Plural 决定复数形式，其余参数按 fmt.Sprintf 格式化翻译后的消息。
+// 消息包中没有的消息键使用 apihandler 的内置消息，因此只需在消息包中定义需要覆盖的消息。
+// 通过 apihandler.WithTranslator 设置时，每个请求使用 LocaleFunc 确定的语言环境。
+type Translator struct {
+	bundle    *i18n.Bundle
+	langs     []string
+	localizer *i18n.Localizer
+	fallback  apihandler.Translator
+}
+
+// New 创建翻译器，langs 为按优先级排列的语言，可以是语言标签或 Accept-Language 请求头的值
+func New(bundle *i18n.Bundle, langs ...string) *Translator {
+	locale := ""
+	if len(langs) > 0 {
+		locale = langs[0]
+	}
+	return &Translator{
+		bundle:    bundle,
+		langs:     langs,
+		localizer: i18n.NewLocalizer(bundle, langs...),
+		fallback:  apihandler.NewSimpleTranslator(locale),
+	}
+}
+
+// ForLocale 实现 apihandler.LocaleTranslator 接口，返回优先使用 locale 的翻译器
+func (t *Translator) ForLocale(locale string) apihandler.Translator {
+	return New(t.bundle, append([]string{locale}, t.langs...)...)
+}
+
+// Translate 实现 apihandler.Translator 接口
+func (t *Translator) Translate(key apihandler.MessageKey, args ...any) string {
+	config := &i18n.LocalizeConfig{MessageID: string(key)}
+	var values []any
+	for _, arg := range args {
+		switch arg := arg.(type) {
+		case Data:
+			config.TemplateData = map[string]any(arg)
+		case pluralCount:
+			config.PluralCount = arg.count
+		default:
+			values = append(values, arg)
+		}
+	}
+	if config.PluralCount != nil && config.TemplateData == nil {
+		config.TemplateData = map[string]any{"Count": config.PluralCount}
+	}
+
+	// 请求的语言中没有该消息时 Localize 返回默认语言的消息和 MessageNotFoundErr
+	message, err := t.localizer.Localize(config)
+	if message == "" && err != nil {
+		return t.fallback.Translate(key, values...)
+	}
+	if len(values) > 0 {
+		return fmt.Sprintf(message, values...)
+	}
+	return message
+}
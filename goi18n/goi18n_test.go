@@ -0,0 +1,107 @@
+package goi18n
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/nicksnyder/go-i18n/v2/i18n"
+	apihandler "github.com/night1008/gotools/gin-api-handler"
+	"golang.org/x/text/language"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// newTestBundle 创建测试用的中英文消息包
+func newTestBundle(t *testing.T) *i18n.Bundle {
+	t.Helper()
+	bundle := i18n.NewBundle(language.English)
+	bundle.RegisterUnmarshalFunc("json", json.Unmarshal)
+	files := map[string]string{
+		"active.en.json": `{
+			"bind_error": "Invalid request",
+			"cart_items": {"one": "{{.Count}} item in cart", "other": "{{.Count}} items in cart"},
+			"greeting": "Hello, {{.Name}}",
+			"insufficient_scope": "Scope %s is required",
+			"validation.required": "{field} can't be blank"
+		}`,
+		"active.zh.json": `{
+			"bind_error": "请求参数错误",
+			"cart_items": {"other": "购物车中有 {{.Count}} 件商品"}
+		}`,
+	}
+	for name, content := range files {
+		if _, err := bundle.ParseMessageFileBytes([]byte(content), name); err != nil {
+			t.Fatalf("解析消息文件 %s 失败: %v", name, err)
+		}
+	}
+	return bundle
+}
+
+// 测试模板数据、复数形式和格式化参数
+func TestTranslate(t *testing.T) {
+	en := New(newTestBundle(t), "en")
+	zh := New(newTestBundle(t), "zh-CN")
+
+	cases := []struct {
+		translator *Translator
+		key        apihandler.MessageKey
+		args       []any
+		expected   string
+	}{
+		{en, "greeting", []any{Data{"Name": "Tom"}}, "Hello, Tom"},
+		{en, "cart_items", []any{Plural(1)}, "1 item in cart"},
+		{en, "cart_items", []any{Plural(3)}, "3 items in cart"},
+		{zh, "cart_items", []any{Plural(3)}, "购物车中有 3 件商品"},
+		{en, apihandler.MsgInsufficientScope, []any{"orders:write"}, "Scope orders:write is required"},
+		{zh, "greeting", []any{Data{"Name": "Tom"}}, "Hello, Tom"},                                                // 使用消息包的默认语言
+		{zh, apihandler.MsgNotFound, nil, apihandler.NewSimpleTranslator("zh").Translate(apihandler.MsgNotFound)}, // 使用内置消息
+		{en, apihandler.MsgTooManyRequests, nil, "Too many requests, please try again later"},
+	}
+
+	for _, tc := range cases {
+		if got := tc.translator.Translate(tc.key, tc.args...); got != tc.expected {
+			t.Errorf("%s: 期望 %q, 实际得到 %q", tc.key, tc.expected, got)
+		}
+	}
+}
+
+// 测试作为处理器的翻译器时按请求的语言环境翻译
+func TestWithTranslator(t *testing.T) {
+	type createRequest struct {
+		Name string `json:"name" binding:"required"`
+	}
+
+	r := gin.New()
+	r.POST("/users", apihandler.Handler(func(ctx context.Context, req *createRequest) (*struct{}, error) {
+		return &struct{}{}, nil
+	}, apihandler.WithTranslator(New(newTestBundle(t)))))
+
+	send := func(locale string) apihandler.ErrorResponse {
+		req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Language", locale)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var resp apihandler.ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("解析响应失败: %v", err)
+		}
+		return resp
+	}
+
+	resp := send("en")
+	if fe := resp.FieldErrors(); resp.Message != "Invalid request" || len(fe) != 1 || fe[0].Message != "name can't be blank" {
+		t.Errorf("期望英文消息, 实际得到 %s %+v", resp.Message, fe)
+	}
+	resp = send("zh")
+	if fe := resp.FieldErrors(); resp.Message != "请求参数错误" || len(fe) != 1 || fe[0].Message != "name can't be blank" {
+		t.Errorf("期望中文消息, 未翻译的消息使用默认语言, 实际得到 %s %+v", resp.Message, fe)
+	}
+}
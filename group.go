@@ -0,0 +1,77 @@
+package apihandler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Group 携带公共选项的路由分组
+//
+// 通过 GET、POST 等函数注册的处理器会继承分组的选项（翻译器、业务代码、回调、日志等），
+// 路由自身的选项在分组选项之后应用。Group 内嵌 *gin.RouterGroup，仍可使用 Use、GET 等方法注册普通的 gin 处理器。
+//
+//	api := apihandler.NewGroup(r.Group("/api"), apihandler.WithSuccessCode(1), apihandler.WithTranslator(t))
+//	apihandler.GET(api, "/user/:id", handleGetUser)
+//	apihandler.POST(api, "/user", handleCreateUser, apihandler.WithSuccessHTTPCode(http.StatusCreated))
+type Group struct {
+	*gin.RouterGroup
+	opts []Option
+}
+
+// NewGroup 创建携带公共选项的路由分组，如 NewGroup(r.Group("/api"), opts...) 或 NewGroup(&r.RouterGroup, opts...)
+func NewGroup(group *gin.RouterGroup, opts ...Option) *Group {
+	return &Group{RouterGroup: group, opts: opts}
+}
+
+// Group 创建子分组，继承当前分组的选项并追加 opts
+func (g *Group) Group(relativePath string, opts ...Option) *Group {
+	return &Group{
+		RouterGroup: g.RouterGroup.Group(relativePath),
+		opts:        g.options(opts),
+	}
+}
+
+// Options 返回分组的选项
+func (g *Group) Options() []Option {
+	return g.options(nil)
+}
+
+// options 返回分组选项和 opts 合并后的选项，不修改分组的选项
+func (g *Group) options(opts []Option) []Option {
+	merged := make([]Option, 0, len(g.opts)+len(opts))
+	merged = append(merged, g.opts...)
+	return append(merged, opts...)
+}
+
+// Handle 在分组中注册处理器，并将路由记录到配置的注册表
+func Handle[T any, R any](g *Group, httpMethod, relativePath string, handleFunc HandleFunc[T, R], opts ...Option) gin.IRoutes {
+	config := NewConfig(g.options(opts)...)
+	registerRoute[T, R](config, httpMethod, joinPaths(g.BasePath(), relativePath))
+	return g.RouterGroup.Handle(httpMethod, relativePath, HandlerWithConfig(handleFunc, config))
+}
+
+// GET 在分组中注册 GET 处理器
+func GET[T any, R any](g *Group, relativePath string, handleFunc HandleFunc[T, R], opts ...Option) gin.IRoutes {
+	return Handle(g, http.MethodGet, relativePath, handleFunc, opts...)
+}
+
+// POST 在分组中注册 POST 处理器
+func POST[T any, R any](g *Group, relativePath string, handleFunc HandleFunc[T, R], opts ...Option) gin.IRoutes {
+	return Handle(g, http.MethodPost, relativePath, handleFunc, opts...)
+}
+
+// PUT 在分组中注册 PUT 处理器
+func PUT[T any, R any](g *Group, relativePath string, handleFunc HandleFunc[T, R], opts ...Option) gin.IRoutes {
+	return Handle(g, http.MethodPut, relativePath, handleFunc, opts...)
+}
+
+// PATCH 在分组中注册 PATCH 处理器
+func PATCH[T any, R any](g *Group, relativePath string, handleFunc HandleFunc[T, R], opts ...Option) gin.IRoutes {
+	return Handle(g, http.MethodPatch, relativePath, handleFunc, opts...)
+}
+
+// DELETE 在分组中注册 DELETE 处理器
+func DELETE[T any, R any](g *Group, relativePath string, handleFunc HandleFunc[T, R], opts ...Option) gin.IRoutes {
+	return Handle(g, http.MethodDelete, relativePath, handleFunc, opts...)
+}
@@ -0,0 +1,65 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试路由分组继承选项
+func TestGroup(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return &testResponse{ID: req.ID}, nil
+	}
+	api := NewGroup(r.Group("/api"), WithSuccessCode(1), WithBindErrorCode(40000))
+	GET(api, "/test/:id", handleFunc)
+	POST(api, "/test/:id", handleFunc, WithSuccessCode(2), WithSuccessHTTPCode(http.StatusCreated))
+
+	admin := api.Group("/admin", WithSuccessCode(3))
+	DELETE(admin, "/test/:id", handleFunc)
+	admin.GET("/ping", func(c *gin.Context) { c.String(http.StatusOK, "pong") })
+
+	tests := []struct {
+		method       string
+		path         string
+		expectedHTTP int
+		expectedCode float64
+	}{
+		{"GET", "/api/test/1", http.StatusOK, 1},
+		{"POST", "/api/test/1", http.StatusCreated, 2},
+		{"DELETE", "/api/admin/test/1", http.StatusOK, 3},
+		{"GET", "/api/test/abc", http.StatusBadRequest, 40000},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+
+		if w.Code != tt.expectedHTTP {
+			t.Errorf("%s %s 期望状态码 %d, 实际得到 %d", tt.method, tt.path, tt.expectedHTTP, w.Code)
+		}
+		var resp map[string]any
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("解析响应失败: %v", err)
+		}
+		if resp["code"] != tt.expectedCode {
+			t.Errorf("%s %s 期望 code 为 %v, 实际得到 %v", tt.method, tt.path, tt.expectedCode, resp["code"])
+		}
+	}
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/api/admin/ping", nil))
+	if w.Body.String() != "pong" {
+		t.Errorf("期望普通 gin 处理器正常工作, 实际得到 %s", w.Body.String())
+	}
+
+	if n := len(api.Options()); n != 2 {
+		t.Errorf("期望子分组不影响父分组的选项, 实际父分组有 %d 个选项", n)
+	}
+}
@@ -0,0 +1,145 @@
+// Package grpcerr 在 apihandler.BizError 与 gRPC status 之间转换，
+// 使 gRPC 与 HTTP 共用业务逻辑时保持一致的错误语义
+package grpcerr
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	apihandler "github.com/night1008/gotools/gin-api-handler"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorInfoDomain 业务错误码在 errdetails.ErrorInfo 中使用的 Domain
+const ErrorInfoDomain = "apihandler"
+
+// httpStatusByCode gRPC 状态码对应的 HTTP 状态码
+var httpStatusByCode = map[codes.Code]int{
+	codes.OK:                 http.StatusOK,
+	codes.Canceled:           499,
+	codes.Unknown:            http.StatusInternalServerError,
+	codes.InvalidArgument:    http.StatusBadRequest,
+	codes.DeadlineExceeded:   http.StatusGatewayTimeout,
+	codes.NotFound:           http.StatusNotFound,
+	codes.AlreadyExists:      http.StatusConflict,
+	codes.PermissionDenied:   http.StatusForbidden,
+	codes.ResourceExhausted:  http.StatusTooManyRequests,
+	codes.FailedPrecondition: http.StatusBadRequest,
+	codes.Aborted:            http.StatusConflict,
+	codes.OutOfRange:         http.StatusBadRequest,
+	codes.Unimplemented:      http.StatusNotImplemented,
+	codes.Internal:           http.StatusInternalServerError,
+	codes.Unavailable:        http.StatusServiceUnavailable,
+	codes.DataLoss:           http.StatusInternalServerError,
+	codes.Unauthenticated:    http.StatusUnauthorized,
+}
+
+// codeByHTTPStatus HTTP 状态码对应的 gRPC 状态码
+var codeByHTTPStatus = map[int]codes.Code{
+	http.StatusBadRequest:                   codes.InvalidArgument,
+	http.StatusUnauthorized:                 codes.Unauthenticated,
+	http.StatusForbidden:                    codes.PermissionDenied,
+	http.StatusNotFound:                     codes.NotFound,
+	http.StatusRequestTimeout:               codes.DeadlineExceeded,
+	http.StatusConflict:                     codes.AlreadyExists,
+	http.StatusPreconditionFailed:           codes.FailedPrecondition,
+	http.StatusRequestedRangeNotSatisfiable: codes.OutOfRange,
+	http.StatusTooManyRequests:              codes.ResourceExhausted,
+	499:                                     codes.Canceled,
+	http.StatusNotImplemented:               codes.Unimplemented,
+	http.StatusServiceUnavailable:           codes.Unavailable,
+	http.StatusGatewayTimeout:               codes.DeadlineExceeded,
+}
+
+// HTTPStatus 返回 gRPC 状态码对应的 HTTP 状态码，未知状态码返回 500
+func HTTPStatus(code codes.Code) int {
+	if httpCode, ok := httpStatusByCode[code]; ok {
+		return httpCode
+	}
+	return http.StatusInternalServerError
+}
+
+// Code 返回 HTTP 状态码对应的 gRPC 状态码
+//
+// 2xx 返回 codes.OK，未单独映射的 4xx 返回 codes.FailedPrecondition，其余返回 codes.Internal。
+func Code(httpCode int) codes.Code {
+	if code, ok := codeByHTTPStatus[httpCode]; ok {
+		return code
+	}
+	switch {
+	case httpCode >= 200 && httpCode < 300:
+		return codes.OK
+	case httpCode >= 400 && httpCode < 500:
+		return codes.FailedPrecondition
+	}
+	return codes.Internal
+}
+
+// ToStatus 将业务错误转换为 gRPC status
+//
+// 业务错误码以 errdetails.ErrorInfo（Domain 为 ErrorInfoDomain）的 Reason 携带，便于调用方还原。
+func ToStatus(err apihandler.BizError) *status.Status {
+	return toStatus(err, err.Error())
+}
+
+// toStatus 使用指定的消息将业务错误转换为 gRPC status
+func toStatus(err apihandler.BizError, message string) *status.Status {
+	st := status.New(Code(err.HTTPCode()), message)
+	if err.Code() == nil {
+		return st
+	}
+	withDetails, detailErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: fmt.Sprint(err.Code()),
+		Domain: ErrorInfoDomain,
+	})
+	if detailErr != nil {
+		return st
+	}
+	return withDetails
+}
+
+// Error 将错误转换为 gRPC 错误，错误链中包含业务错误时按 ToStatus 转换并保留外层消息，其他错误原样返回
+func Error(err error) error {
+	var bizErr apihandler.BizError
+	if errors.As(err, &bizErr) {
+		return toStatus(bizErr, err.Error()).Err()
+	}
+	return err
+}
+
+// FromStatus 将 gRPC status 转换为业务错误
+//
+// 业务错误码优先使用 ToStatus 写入的 ErrorInfo.Reason，整数形式的 Reason（如 "40400"）还原为 int，
+// 其他 Reason 为字符串；没有 ErrorInfo 时使用对应的 HTTP 状态码。
+func FromStatus(st *status.Status) apihandler.BizError {
+	httpCode := HTTPStatus(st.Code())
+	var code any = httpCode
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok && info.GetDomain() == ErrorInfoDomain {
+			code = reasonCode(info.GetReason())
+			break
+		}
+	}
+	return apihandler.NewBizError(code, st.Message(), httpCode)
+}
+
+// reasonCode 将 ErrorInfo.Reason 还原为业务错误码，只有 fmt.Sprint 整数得到的形式转换为 int（"0042" 仍为字符串）
+func reasonCode(reason string) any {
+	if n, err := strconv.Atoi(reason); err == nil && strconv.Itoa(n) == reason {
+		return n
+	}
+	return reason
+}
+
+// ErrorMapper 将 gRPC status 错误转换为业务错误，可通过 apihandler.RegisterErrorMapper 注册
+func ErrorMapper(err error) (apihandler.BizError, bool) {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() == codes.OK {
+		return nil, false
+	}
+	return FromStatus(st), true
+}
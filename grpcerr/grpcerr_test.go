@@ -0,0 +1,118 @@
+package grpcerr
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	apihandler "github.com/night1008/gotools/gin-api-handler"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// 测试 gRPC 状态码与 HTTP 状态码的相互转换
+func TestCodeMapping(t *testing.T) {
+	cases := []struct {
+		code     codes.Code
+		httpCode int
+	}{
+		{codes.InvalidArgument, http.StatusBadRequest},
+		{codes.Unauthenticated, http.StatusUnauthorized},
+		{codes.PermissionDenied, http.StatusForbidden},
+		{codes.NotFound, http.StatusNotFound},
+		{codes.AlreadyExists, http.StatusConflict},
+		{codes.ResourceExhausted, http.StatusTooManyRequests},
+		{codes.Canceled, 499},
+		{codes.Unimplemented, http.StatusNotImplemented},
+		{codes.Unavailable, http.StatusServiceUnavailable},
+		{codes.DeadlineExceeded, http.StatusGatewayTimeout},
+	}
+
+	for _, tc := range cases {
+		if got := HTTPStatus(tc.code); got != tc.httpCode {
+			t.Errorf("%s: 期望 HTTP 状态码 %d, 实际得到 %d", tc.code, tc.httpCode, got)
+		}
+		if got := Code(tc.httpCode); got != tc.code {
+			t.Errorf("%d: 期望 gRPC 状态码 %s, 实际得到 %s", tc.httpCode, tc.code, got)
+		}
+	}
+
+	if got := Code(http.StatusTeapot); got != codes.FailedPrecondition {
+		t.Errorf("期望未映射的 4xx 转换为 FailedPrecondition, 实际得到 %s", got)
+	}
+
+	if got := Code(http.StatusBadGateway); got != codes.Internal {
+		t.Errorf("期望未映射的 5xx 转换为 Internal, 实际得到 %s", got)
+	}
+}
+
+// 测试业务错误经过 gRPC status 往返后保持一致
+func TestStatusRoundTrip(t *testing.T) {
+	bizErr := apihandler.ErrNotFound(40400, "用户不存在")
+
+	grpcErr := Error(fmt.Errorf("get user: %w", bizErr))
+	st, ok := status.FromError(grpcErr)
+	if !ok {
+		t.Fatalf("期望得到 gRPC status 错误, 实际得到 %v", grpcErr)
+	}
+
+	if st.Code() != codes.NotFound || st.Message() != "get user: 用户不存在" {
+		t.Errorf("期望 NotFound 和 'get user: 用户不存在', 实际得到 %s, '%s'", st.Code(), st.Message())
+	}
+
+	back := FromStatus(st)
+	if code, ok := back.Code().(int); !ok || code != 40400 || back.HTTPCode() != http.StatusNotFound {
+		t.Errorf("期望还原 int 类型的业务错误码 40400 和状态码 404, 实际得到 %#v, %d", back.Code(), back.HTTPCode())
+	}
+
+	// 字符串错误码和不能无损转换为整数的 Reason 保持字符串
+	for _, code := range []string{"USER_NOT_FOUND", "0042", "-"} {
+		back := FromStatus(ToStatus(apihandler.ErrNotFound(code, "用户不存在")))
+		if back.Code() != code {
+			t.Errorf("期望还原字符串业务错误码 %q, 实际得到 %#v", code, back.Code())
+		}
+	}
+
+	plain := errors.New("plain")
+	if Error(plain) != plain {
+		t.Errorf("期望非业务错误原样返回")
+	}
+}
+
+// 测试通过 ErrorMapper 输出下游 gRPC 服务的错误
+func TestErrorMapper(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *struct{}) (*struct{}, error) {
+		return nil, status.Error(codes.PermissionDenied, "无权访问")
+	}
+
+	r.GET("/test", apihandler.Handler(handleFunc, apihandler.WithErrorMapper(ErrorMapper)))
+
+	req := httptest.NewRequest("GET", "/test", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusForbidden, w.Code)
+	}
+
+	var resp apihandler.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+
+	if resp.Code != float64(http.StatusForbidden) || resp.Message != "无权访问" {
+		t.Errorf("期望 {code:403, message:无权访问}, 实际得到 %+v", resp)
+	}
+}
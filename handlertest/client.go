@@ -0,0 +1,124 @@
+// Package handlertest 提供调用 apihandler 处理器的类型化测试客户端，通过 httptest 直接驱动 gin 引擎，
+// 测试不再需要手动构造请求体和解析响应封装
+package handlertest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	apihandler "github.com/night1008/gotools/gin-api-handler"
+)
+
+// baseURL 测试请求的地址，与 httptest.NewRequest 的默认主机相同
+const baseURL = "http://example.com"
+
+// Client 调用处理器的测试客户端，可以在并行的测试中共用
+type Client struct {
+	handler http.Handler
+	header  http.Header
+}
+
+// Option 客户端选项
+type Option func(*Client)
+
+// WithHeader 设置每个请求都携带的请求头，如 Authorization 和 Accept-Language
+func WithHeader(key, value string) Option {
+	return func(c *Client) {
+		c.header.Set(key, value)
+	}
+}
+
+// New 创建调用 handler（通常为注册了路由的 *gin.Engine）的测试客户端
+func New(handler http.Handler, opts ...Option) *Client {
+	c := &Client{handler: handler, header: make(http.Header)}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Call 调用路由并解析默认响应封装，path 为注册时的完整路由路径（如 /api/users/:id）或实际的请求路径
+//
+// 请求字段与 apihandler.Call 一样按 tag 放入路径、查询参数、请求头和请求体。2xx 响应返回成功响应，
+// 204 或空响应体时两者都为 nil；其他响应返回错误响应，不是默认错误格式时错误码为 HTTP 状态码、消息为响应体。
+// 响应中的整数业务代码解析为 int，可以直接与定义错误时使用的错误码比较。error 只在请求无法发送或响应无法解析时返回：
+//
+//	c := handlertest.New(r, handlertest.WithHeader("Accept-Language", "en"))
+//	resp, errResp, err := handlertest.Call[GetUserRequest, User](c, http.MethodGet, "/users/:id", &GetUserRequest{ID: 1})
+func Call[T any, R any](c *Client, method, path string, req *T) (*apihandler.SuccessResponse[R], *apihandler.ErrorResponse, error) {
+	rec, err := Record(c, method, path, req)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	body := rec.Body.Bytes()
+	if rec.Code < 200 || rec.Code > 299 {
+		return nil, decodeError(rec.Code, body), nil
+	}
+	if rec.Code == http.StatusNoContent || len(bytes.TrimSpace(body)) == 0 {
+		return nil, nil, nil
+	}
+	var resp apihandler.SuccessResponse[R]
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, nil, fmt.Errorf("handlertest: decode response: %w", err)
+	}
+	resp.Code = normalizeCode(resp.Code)
+	return &resp, nil, nil
+}
+
+// Record 调用路由并返回记录的原始响应，用于检查状态码、响应头和非 JSON 响应
+func Record[T any](c *Client, method, path string, req *T) (*httptest.ResponseRecorder, error) {
+	var rec *httptest.ResponseRecorder
+	transport := roundTripFunc(func(r *http.Request) (*http.Response, error) {
+		rec = httptest.NewRecorder()
+		c.handler.ServeHTTP(rec, r)
+		return rec.Result(), nil
+	})
+	opts := []apihandler.ClientOption{apihandler.WithHTTPClient(&http.Client{Transport: transport})}
+	for key := range c.header {
+		opts = append(opts, apihandler.WithClientHeader(key, c.header.Get(key)))
+	}
+
+	// 请求由 apihandler.Client 按 tag 编码；响应由调用方从 rec 中解析，这里忽略其解析结果
+	_, err := apihandler.Call[T, json.RawMessage](context.Background(), apihandler.NewClient(baseURL, opts...), method, path, req)
+	if rec == nil {
+		return nil, err
+	}
+	return rec, nil
+}
+
+// roundTripFunc 将函数转换为 http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip 实现 http.RoundTripper 接口
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
+
+// decodeError 解析错误响应，不是默认错误格式时以 HTTP 状态码作为错误码
+func decodeError(httpCode int, body []byte) *apihandler.ErrorResponse {
+	var resp apihandler.ErrorResponse
+	if err := json.Unmarshal(body, &resp); err != nil || resp.Code == nil {
+		message := strings.TrimSpace(string(body))
+		if message == "" {
+			message = http.StatusText(httpCode)
+		}
+		return &apihandler.ErrorResponse{Code: httpCode, Message: message}
+	}
+	resp.Code = normalizeCode(resp.Code)
+	return &resp
+}
+
+// normalizeCode 将 JSON 中的整数业务代码转换为 int，其他数字仍为 float64，字符串为 string
+func normalizeCode(code any) any {
+	if f, ok := code.(float64); ok && f == math.Trunc(f) && math.Abs(f) <= 1<<53 {
+		return int(f)
+	}
+	return code
+}
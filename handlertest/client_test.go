@@ -0,0 +1,129 @@
+package handlertest
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	apihandler "github.com/night1008/gotools/gin-api-handler"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// getUserRequest 获取用户的请求
+type getUserRequest struct {
+	ID     int64  `path:"id" binding:"required"`
+	Fields string `form:"fields"`
+}
+
+// createUserRequest 创建用户的请求
+type createUserRequest struct {
+	Name string `json:"name" binding:"required"`
+	Age  int    `json:"age" binding:"gte=0"`
+}
+
+// user 用户
+type user struct {
+	ID     int64  `json:"id"`
+	Name   string `json:"name"`
+	Fields string `json:"fields"`
+}
+
+// errUserExists 用户已存在
+var errUserExists = apihandler.NewBizError(40901, "user exists", http.StatusConflict)
+
+func newRouter() *gin.Engine {
+	r := gin.New()
+	r.GET("/users/:id", apihandler.Handler(func(ctx context.Context, req *getUserRequest) (*user, error) {
+		return &user{ID: req.ID, Fields: req.Fields}, nil
+	}))
+	r.POST("/users", apihandler.Handler(func(ctx context.Context, req *createUserRequest) (*user, error) {
+		if req.Name == "exists" {
+			return nil, errUserExists
+		}
+		return &user{ID: 1, Name: req.Name}, nil
+	}, apihandler.WithSuccessHTTPCode(http.StatusCreated)))
+	r.DELETE("/users/:id", apihandler.Handler(func(ctx context.Context, req *getUserRequest) (*struct{}, error) {
+		return nil, nil
+	}, apihandler.WithNoContentOnNil()))
+	return r
+}
+
+// 测试调用路由并解析成功响应
+func TestCall(t *testing.T) {
+	c := New(newRouter())
+
+	resp, errResp, err := Call[getUserRequest, user](c, http.MethodGet, "/users/:id", &getUserRequest{ID: 7, Fields: "name"})
+	if err != nil || errResp != nil {
+		t.Fatalf("期望调用成功, 实际得到 %v %+v", err, errResp)
+	}
+	if resp.Code != 0 {
+		t.Errorf("期望 code 为 0, 实际得到 %#v", resp.Code)
+	}
+	expected := user{ID: 7, Fields: "name"}
+	if *resp.Data != expected {
+		t.Errorf("期望 data 为 %+v, 实际得到 %+v", expected, *resp.Data)
+	}
+
+	created, errResp, err := Call[createUserRequest, user](c, http.MethodPost, "/users", &createUserRequest{Name: "alice"})
+	if err != nil || errResp != nil || created.Data.Name != "alice" {
+		t.Errorf("期望创建成功, 实际得到 %+v %+v %v", created, errResp, err)
+	}
+
+	deleted, errResp, err := Call[getUserRequest, struct{}](c, http.MethodDelete, "/users/7", nil)
+	if deleted != nil || errResp != nil || err != nil {
+		t.Errorf("期望 204 响应时都为 nil, 实际得到 %+v %+v %v", deleted, errResp, err)
+	}
+}
+
+// 测试调用路由并解析错误响应
+func TestCallError(t *testing.T) {
+	c := New(newRouter(), WithHeader("Accept-Language", "en"))
+
+	testCases := []struct {
+		name     string
+		method   string
+		path     string
+		req      *createUserRequest
+		code     any
+		message  string
+		detailed bool
+	}{
+		{"业务错误", http.MethodPost, "/users", &createUserRequest{Name: "exists"}, 40901, "user exists", false},
+		{"参数验证失败", http.MethodPost, "/users", &createUserRequest{Age: -1}, http.StatusBadRequest, "Parameter binding failed", true},
+		{"不是默认错误格式", http.MethodGet, "/missing", nil, http.StatusNotFound, "404 page not found", false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp, errResp, err := Call[createUserRequest, user](c, tc.method, tc.path, tc.req)
+			if err != nil || resp != nil {
+				t.Fatalf("期望返回错误响应, 实际得到 %+v %v", resp, err)
+			}
+			if errResp.Code != tc.code || errResp.Message != tc.message {
+				t.Errorf("期望错误为 %v %q, 实际得到 %#v %q", tc.code, tc.message, errResp.Code, errResp.Message)
+			}
+			if tc.detailed != (len(errResp.Errors) > 0) {
+				t.Errorf("期望%s错误详情, 实际得到 %v", map[bool]string{true: "有", false: "没有"}[tc.detailed], errResp.Errors)
+			}
+		})
+	}
+}
+
+// 测试返回记录的原始响应
+func TestRecord(t *testing.T) {
+	c := New(newRouter())
+
+	rec, err := Record(c, http.MethodPost, "/users", &createUserRequest{Name: "bob"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Code != http.StatusCreated {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusCreated, rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Errorf("期望 JSON 响应, 实际得到 %q", ct)
+	}
+}
@@ -0,0 +1,67 @@
+package apihandler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// HeaderedError 携带响应头的错误
+//
+// 错误链中的错误实现该接口时，Headers 的结果会随错误响应一起写入，
+// 如 401 响应的 WWW-Authenticate、429 和 503 响应的 Retry-After。
+type HeaderedError interface {
+	error
+	Headers() http.Header
+}
+
+// headeredBizError 携带响应头的业务错误
+type headeredBizError struct {
+	BizError
+	header http.Header
+}
+
+// Headers 实现 HeaderedError 接口
+func (e *headeredBizError) Headers() http.Header {
+	return e.header
+}
+
+// Unwrap 返回原始业务错误
+func (e *headeredBizError) Unwrap() error {
+	return e.BizError
+}
+
+// NewHeaderedError 为业务错误附加响应头
+func NewHeaderedError(err BizError, header http.Header) BizError {
+	return &headeredBizError{BizError: err, header: header}
+}
+
+// ErrUnauthorizedWithChallenge 未授权，challenge 作为 WWW-Authenticate 响应头，如 `Bearer realm="api"`
+func ErrUnauthorizedWithChallenge(code any, msg string, challenge string) BizError {
+	return NewHeaderedError(ErrUnauthorized(code, msg), http.Header{"Www-Authenticate": {challenge}})
+}
+
+// ErrTooManyRequests 请求过于频繁，retryAfter 大于 0 时输出 Retry-After 响应头（向上取整到秒）
+func ErrTooManyRequests(code any, msg string, retryAfter time.Duration) BizError {
+	err := NewBizError(code, msg, http.StatusTooManyRequests)
+	if retryAfter <= 0 {
+		return err
+	}
+	return NewHeaderedError(err, http.Header{"Retry-After": {retryAfterSeconds(retryAfter)}})
+}
+
+// retryAfterSeconds 将时长转换为 Retry-After 的秒数
+func retryAfterSeconds(d time.Duration) string {
+	seconds := int64((d + time.Second - 1) / time.Second)
+	return strconv.FormatInt(seconds, 10)
+}
+
+// errorHeaders 返回错误链中 HeaderedError 的响应头
+func errorHeaders(err error) http.Header {
+	var he HeaderedError
+	if errors.As(err, &he) {
+		return he.Headers()
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package apihandler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试错误响应输出 HeaderedError 的响应头
+func TestHeaderedError(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		switch req.ID {
+		case 1:
+			return nil, ErrUnauthorizedWithChallenge(40100, "请先登录", `Bearer realm="api"`)
+		case 2:
+			return nil, fmt.Errorf("charge: %w", ErrTooManyRequests(42900, "请求过于频繁", 1500*time.Millisecond))
+		case 3:
+			return nil, NewHeaderedError(ErrNotFound(40400, "资源不存在"), http.Header{"X-Reason": {"deleted"}})
+		}
+		return nil, ErrTooManyRequests(42900, "请求过于频繁", 0)
+	}
+
+	r.GET("/test/:id", Handler(handleFunc))
+
+	cases := []struct {
+		path     string
+		httpCode int
+		header   string
+		value    string
+		body     string
+	}{
+		{"/test/1", http.StatusUnauthorized, "WWW-Authenticate", `Bearer realm="api"`, `{"code":40100,"message":"请先登录"}`},
+		{"/test/2", http.StatusTooManyRequests, "Retry-After", "2", `{"code":42900,"message":"charge: 请求过于频繁"}`},
+		{"/test/3", http.StatusNotFound, "X-Reason", "deleted", `{"code":40400,"message":"资源不存在"}`},
+		{"/test/4", http.StatusTooManyRequests, "Retry-After", "", `{"code":42900,"message":"请求过于频繁"}`},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", tc.path, nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.httpCode {
+			t.Errorf("%s: 期望状态码 %d, 实际得到 %d", tc.path, tc.httpCode, w.Code)
+		}
+
+		if got := w.Header().Get(tc.header); got != tc.value {
+			t.Errorf("%s: 期望 %s 为 '%s', 实际得到 '%s'", tc.path, tc.header, tc.value, got)
+		}
+
+		if w.Body.String() != tc.body {
+			t.Errorf("%s: 期望响应为 %s, 实际得到 %s", tc.path, tc.body, w.Body.String())
+		}
+	}
+}
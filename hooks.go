@@ -0,0 +1,72 @@
+package apihandler
+
+import "github.com/gin-gonic/gin"
+
+// BindHook 参数绑定前后的钩子函数，req 为请求对象的指针（如 *T），返回错误时中止处理并输出错误响应
+type BindHook func(c *gin.Context, req any) error
+
+// RespondHook 输出响应前后的钩子函数
+//
+// resp 为业务处理函数的返回值（如 *R），err 为参数绑定或业务处理函数返回的原始错误，成功时为 nil。
+type RespondHook func(c *gin.Context, req any, resp any, err error)
+
+// WithBeforeBind 添加参数绑定前的钩子函数，可用于为请求对象设置默认值
+func WithBeforeBind(hooks ...BindHook) Option {
+	return func(c *HandlerConfig) {
+		c.BeforeBind = append(c.BeforeBind[:len(c.BeforeBind):len(c.BeforeBind)], hooks...)
+	}
+}
+
+// WithAfterBind 添加参数绑定成功后的钩子函数，可用于补充请求对象（如从认证信息中填充用户 ID）
+func WithAfterBind(hooks ...BindHook) Option {
+	return func(c *HandlerConfig) {
+		c.AfterBind = append(c.AfterBind[:len(c.AfterBind):len(c.AfterBind)], hooks...)
+	}
+}
+
+// WithBeforeRespond 添加输出响应前的钩子函数，可用于设置响应头
+func WithBeforeRespond(hooks ...RespondHook) Option {
+	return func(c *HandlerConfig) {
+		c.BeforeRespond = append(c.BeforeRespond[:len(c.BeforeRespond):len(c.BeforeRespond)], hooks...)
+	}
+}
+
+// WithAfterRespond 添加输出响应后的钩子函数，可用于统计耗时
+func WithAfterRespond(hooks ...RespondHook) Option {
+	return func(c *HandlerConfig) {
+		c.AfterRespond = append(c.AfterRespond[:len(c.AfterRespond):len(c.AfterRespond)], hooks...)
+	}
+}
+
+// bindWithHooks 依次调用 BeforeBind、绑定请求参数和 AfterBind
+func bindWithHooks(c *gin.Context, config *HandlerConfig, translator Translator, req any) error {
+	for _, hook := range config.BeforeBind {
+		if err := hook(c, req); err != nil {
+			return err
+		}
+	}
+	if err := bindRequest(c, config, translator, req); err != nil {
+		return err
+	}
+	for _, hook := range config.AfterBind {
+		if err := hook(c, req); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// respond 输出成功或错误响应，前后分别调用 BeforeRespond 和 AfterRespond
+func respond(c *gin.Context, config *HandlerConfig, req any, resp any, err error) {
+	for _, hook := range config.BeforeRespond {
+		hook(c, req, resp, err)
+	}
+	if err != nil {
+		handleError(c, config, req, err)
+	} else {
+		handleSuccess(c, config, req, resp)
+	}
+	for _, hook := range config.AfterRespond {
+		hook(c, req, resp, err)
+	}
+}
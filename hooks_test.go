@@ -0,0 +1,106 @@
+package apihandler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试生命周期钩子的调用顺序
+func TestLifecycleHooks(t *testing.T) {
+	r := gin.New()
+
+	var calls []string
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		calls = append(calls, "handle")
+		if req.ID == 2 {
+			return nil, ErrNotFound(40400, "资源不存在")
+		}
+		return &testResponse{ID: req.ID, Name: req.Name}, nil
+	}
+
+	r.GET("/test/:id", Handler(handleFunc,
+		WithBeforeBind(func(c *gin.Context, req any) error {
+			calls = append(calls, "before_bind")
+			req.(*testRequest).Name = "默认名称"
+			return nil
+		}),
+		WithAfterBind(func(c *gin.Context, req any) error {
+			calls = append(calls, "after_bind")
+			if req.(*testRequest).ID == 3 {
+				return ErrForbidden(40300, "禁止访问")
+			}
+			return nil
+		}),
+		WithBeforeRespond(func(c *gin.Context, req, resp any, err error) {
+			calls = append(calls, "before_respond")
+			c.Header("X-Stamp", "1")
+		}),
+		WithAfterRespond(func(c *gin.Context, req, resp any, err error) {
+			status := "ok"
+			if err != nil {
+				status = err.Error()
+			}
+			calls = append(calls, "after_respond:"+status)
+		}),
+	))
+
+	tests := []struct {
+		path         string
+		expectedHTTP int
+		expected     string
+	}{
+		{"/test/1", http.StatusOK, "before_bind,after_bind,handle,before_respond,after_respond:ok"},
+		{"/test/2", http.StatusNotFound, "before_bind,after_bind,handle,before_respond,after_respond:资源不存在"},
+		{"/test/3", http.StatusForbidden, "before_bind,after_bind,before_respond,after_respond:禁止访问"},
+	}
+
+	for _, tt := range tests {
+		calls = nil
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", tt.path, nil))
+
+		if w.Code != tt.expectedHTTP {
+			t.Errorf("%s 期望状态码 %d, 实际得到 %d", tt.path, tt.expectedHTTP, w.Code)
+		}
+		if got := strings.Join(calls, ","); got != tt.expected {
+			t.Errorf("%s 期望调用顺序 %s, 实际得到 %s", tt.path, tt.expected, got)
+		}
+		if w.Header().Get("X-Stamp") != "1" {
+			t.Errorf("%s 期望 BeforeRespond 设置的响应头", tt.path)
+		}
+	}
+
+	calls = nil
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/test/1", nil))
+	if !strings.Contains(w.Body.String(), "默认名称") {
+		t.Errorf("期望 BeforeBind 设置的默认值, 实际响应 %s", w.Body.String())
+	}
+}
+
+// 测试 BeforeBind 返回错误时中止处理
+func TestBeforeBindError(t *testing.T) {
+	r := gin.New()
+
+	called := false
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		called = true
+		return nil, nil
+	}
+	r.GET("/test/:id", Handler(handleFunc, WithBeforeBind(func(c *gin.Context, req any) error {
+		return errors.New("not ready")
+	})))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/test/1", nil))
+
+	if w.Code != http.StatusInternalServerError || called {
+		t.Errorf("期望中止处理并返回 500, 实际状态码 %d, 业务函数被调用: %v", w.Code, called)
+	}
+}
@@ -1,9 +1,16 @@
 package apihandler
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"maps"
 	"net/http"
+	"slices"
 	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
 )
 
 // MessageKey 消息键类型
@@ -11,13 +18,33 @@ type MessageKey string
 
 // 预定义的消息键
 const (
-	MsgBindError                MessageKey = "bind_error"
-	MsgBindErrorDetail          MessageKey = "bind_error_detail"
-	MsgPathBindError            MessageKey = "path_bind_error"
-	MsgFieldValidationFailed    MessageKey = "field_validation_failed"
+	MsgBindError                      MessageKey = "bind_error"
+	MsgBindErrorDetail                MessageKey = "bind_error_detail"
+	MsgPathBindError                  MessageKey = "path_bind_error"
+	MsgFieldValidationFailed          MessageKey = "field_validation_failed"
 	MsgFieldValidationFailedWithParam MessageKey = "field_validation_failed_with_param"
-	MsgFieldParseFailed         MessageKey = "field_parse_failed"
-	MsgFieldTypeNotSupported    MessageKey = "field_type_not_supported"
+	MsgFieldParseFailed               MessageKey = "field_parse_failed"
+	MsgFieldTypeNotSupported          MessageKey = "field_type_not_supported"
+	MsgInternalError                  MessageKey = "internal_error"
+	MsgInternalErrorWithReference     MessageKey = "internal_error_with_reference"
+	MsgNotFound                       MessageKey = "not_found"
+	MsgRequestCanceled                MessageKey = "request_canceled"
+	MsgRequestTimeout                 MessageKey = "request_timeout"
+	MsgUnexpectedEOF                  MessageKey = "unexpected_eof"
+	MsgTooManyRequests                MessageKey = "too_many_requests"
+	MsgIdempotencyInFlight            MessageKey = "idempotency_in_flight"
+	MsgIdempotencyKeyReused           MessageKey = "idempotency_key_reused"
+	MsgUnauthorized                   MessageKey = "unauthorized"
+	MsgForbidden                      MessageKey = "forbidden"
+	MsgInsufficientScope              MessageKey = "insufficient_scope"
+	MsgServiceUnavailable             MessageKey = "service_unavailable"
+	MsgUnsupportedVersion             MessageKey = "unsupported_version"
+	MsgUploadTooLarge                 MessageKey = "upload_too_large"
+	MsgValidationDateRange            MessageKey = "validation.date_range"
+	MsgValidationRequiredAny          MessageKey = "validation.required_any"
+	MsgValidationSumMax               MessageKey = "validation.sum_max"
+	MsgValidationUnique               MessageKey = "validation.unique"
+	MsgValidationExists               MessageKey = "validation.exists"
 )
 
 // Translator 翻译器接口
@@ -26,46 +53,96 @@ type Translator interface {
 	Translate(key MessageKey, args ...interface{}) string
 }
 
+// LocaleTranslator 按语言环境提供翻译器，WithTranslator 设置的翻译器实现该接口时，每个请求使用 ForLocale 返回的翻译器
+type LocaleTranslator interface {
+	Translator
+	// ForLocale 返回语言环境的翻译器，locale 为 LocaleFunc 确定的语言环境
+	ForLocale(locale string) Translator
+}
+
 // LocaleFunc 从请求中获取语言环境的函数
 type LocaleFunc func(r *http.Request) string
 
 // defaultMessages 默认消息（中文）
 var defaultMessages = map[MessageKey]string{
-	MsgBindError:                "参数绑定失败",
-	MsgBindErrorDetail:          "参数绑定失败: %v",
-	MsgPathBindError:            "路径参数绑定失败: %v",
-	MsgFieldValidationFailed:    "字段验证失败: %s",
+	MsgBindError:                      "参数绑定失败",
+	MsgBindErrorDetail:                "参数绑定失败: %v",
+	MsgPathBindError:                  "路径参数绑定失败: %v",
+	MsgFieldValidationFailed:          "字段验证失败: %s",
 	MsgFieldValidationFailedWithParam: "字段验证失败: %s=%s",
-	MsgFieldParseFailed:         "字段 %s 解析失败: %v",
-	MsgFieldTypeNotSupported:    "字段 %s 的类型 %s 不支持路径绑定",
+	MsgFieldParseFailed:               "字段 %s 解析失败: %v",
+	MsgFieldTypeNotSupported:          "字段 %s 的类型 %s 不支持路径绑定",
+	MsgInternalError:                  "服务器内部错误",
+	MsgInternalErrorWithReference:     "服务器内部错误，错误编号: %s",
+	MsgNotFound:                       "资源不存在",
+	MsgRequestCanceled:                "请求已取消",
+	MsgRequestTimeout:                 "请求超时",
+	MsgUnexpectedEOF:                  "请求体不完整",
+	MsgTooManyRequests:                "请求过于频繁，请稍后重试",
+	MsgIdempotencyInFlight:            "相同幂等键的请求正在处理中",
+	MsgIdempotencyKeyReused:           "幂等键已用于内容不同的请求",
+	MsgUnauthorized:                   "未登录或登录已过期",
+	MsgForbidden:                      "没有访问权限",
+	MsgInsufficientScope:              "缺少访问权限: %s",
+	MsgServiceUnavailable:             "服务暂时不可用，请稍后重试",
+	MsgUnsupportedVersion:             "不支持的 API 版本: %s",
+	MsgUploadTooLarge:                 "上传的文件 %s 超过 %d 字节",
+	MsgValidationDateRange:            "{field} 不能早于 {param}",
+	MsgValidationRequiredAny:          "{field} 和 {related} 至少需要填写一项",
+	MsgValidationSumMax:               "{field} 与 {related} 之和不能超过 {param}",
+	MsgValidationUnique:               "{field} 已存在",
+	MsgValidationExists:               "{field} 不存在",
 }
 
 // englishMessages 英文消息
 var englishMessages = map[MessageKey]string{
-	MsgBindError:                "Parameter binding failed",
-	MsgBindErrorDetail:          "Parameter binding failed: %v",
-	MsgPathBindError:            "Path parameter binding failed: %v",
-	MsgFieldValidationFailed:    "Field validation failed: %s",
+	MsgBindError:                      "Parameter binding failed",
+	MsgBindErrorDetail:                "Parameter binding failed: %v",
+	MsgPathBindError:                  "Path parameter binding failed: %v",
+	MsgFieldValidationFailed:          "Field validation failed: %s",
 	MsgFieldValidationFailedWithParam: "Field validation failed: %s=%s",
-	MsgFieldParseFailed:         "Field %s parsing failed: %v",
-	MsgFieldTypeNotSupported:    "Field %s type %s does not support path binding",
+	MsgFieldParseFailed:               "Field %s parsing failed: %v",
+	MsgFieldTypeNotSupported:          "Field %s type %s does not support path binding",
+	MsgInternalError:                  "Internal server error",
+	MsgInternalErrorWithReference:     "Internal server error, reference: %s",
+	MsgNotFound:                       "Resource not found",
+	MsgRequestCanceled:                "Request canceled",
+	MsgRequestTimeout:                 "Request timed out",
+	MsgUnexpectedEOF:                  "Unexpected end of request body",
+	MsgTooManyRequests:                "Too many requests, please try again later",
+	MsgIdempotencyInFlight:            "A request with the same idempotency key is in progress",
+	MsgIdempotencyKeyReused:           "The idempotency key was already used for a different request",
+	MsgUnauthorized:                   "Authentication required",
+	MsgForbidden:                      "Permission denied",
+	MsgInsufficientScope:              "Missing required scope: %s",
+	MsgServiceUnavailable:             "Service temporarily unavailable, please try again later",
+	MsgUnsupportedVersion:             "Unsupported API version: %s",
+	MsgUploadTooLarge:                 "Uploaded file %s exceeds %d bytes",
+	MsgValidationDateRange:            "{field} must not be earlier than {param}",
+	MsgValidationRequiredAny:          "At least one of {field} and {related} is required",
+	MsgValidationSumMax:               "The sum of {field} and {related} must not exceed {param}",
+	MsgValidationUnique:               "{field} already exists",
+	MsgValidationExists:               "{field} does not exist",
 }
 
 // SimpleTranslator 简单翻译器实现
 type SimpleTranslator struct {
 	locale   string
-	messages map[MessageKey]string
+	messages []map[MessageKey]string // 语言环境及其回退链中已注册的语言环境的消息
 }
 
 // NewSimpleTranslator 创建简单翻译器
+//
+// 找不到消息时依次使用回退链（见 SetLocaleFallbacks）中语言环境的消息，最后使用中文消息。
 func NewSimpleTranslator(locale string) Translator {
-	var messages map[MessageKey]string
-	switch locale {
-	case "en", "en-US", "en_US":
-		messages = englishMessages
-	default:
-		// 默认使用中文
-		messages = defaultMessages
+	chain := localeChain(locale)
+	localesMu.RLock()
+	defer localesMu.RUnlock()
+	var messages []map[MessageKey]string
+	for _, candidate := range chain {
+		if m := registeredLocales[candidate]; m != nil {
+			messages = append(messages, m)
+		}
 	}
 	return &SimpleTranslator{
 		locale:   locale,
@@ -73,47 +150,284 @@ func NewSimpleTranslator(locale string) Translator {
 	}
 }
 
+// localesMu 保护 registeredLocales 及其中的消息和 localeFallbacks，加载消息可以与请求的翻译并发进行
+var localesMu sync.RWMutex
+
+// registeredLocales 各语言环境的消息，键为规范化的语言环境
+var registeredLocales = map[string]map[MessageKey]string{
+	"zh": defaultMessages,
+	"en": englishMessages,
+}
+
+// localeAliases 与内置语言环境使用相同消息的语言标签
+var localeAliases = map[string]string{
+	"zh-cn": "zh",
+	"en-us": "en",
+}
+
+// localeFallbacks 各语言环境的回退链，键和值均为规范化的语言环境
+var localeFallbacks = map[string][]string{}
+
+// SetLocaleFallbacks 设置语言环境的回退链，部分翻译的语言环境依次使用回退链中语言环境的消息，应在注册路由之前调用
+//
+// 回退链同时用于翻译器查找消息和 NegotiateLocale 协商语言环境。没有设置回退链的语言环境回退到其语言代码
+// （如 zh-TW 回退到 zh），回退链的最后总是中文消息。不传 fallbacks 时恢复默认的回退：
+//
+//	apihandler.SetLocaleFallbacks("zh-HK", "zh-TW", "zh")
+//	apihandler.SetLocaleFallbacks("pt-BR", "pt", "en")
+func SetLocaleFallbacks(locale string, fallbacks ...string) error {
+	key := normalizeLocale(locale)
+	if key == "" {
+		return errors.New("apihandler: empty locale")
+	}
+	localesMu.Lock()
+	defer localesMu.Unlock()
+	if len(fallbacks) == 0 {
+		delete(localeFallbacks, key)
+		return nil
+	}
+	chain := make([]string, 0, len(fallbacks))
+	for _, fallback := range fallbacks {
+		normalized := normalizeLocale(fallback)
+		if normalized == "" {
+			return fmt.Errorf("apihandler: empty fallback locale for %s", locale)
+		}
+		chain = append(chain, normalized)
+	}
+	localeFallbacks[key] = chain
+	return nil
+}
+
+// localeChain 返回语言环境及其回退链中的语言环境（均已规范化），如 zh-tw 为 [zh-tw zh]
+func localeChain(locale string) []string {
+	key := normalizeLocale(locale)
+	if key == "" {
+		return nil
+	}
+	chain := []string{key}
+	localesMu.RLock()
+	fallbacks, ok := localeFallbacks[key]
+	localesMu.RUnlock()
+	if !ok {
+		if language, _, found := strings.Cut(key, "-"); found {
+			fallbacks = []string{normalizeLocale(language)}
+		}
+	}
+	for _, fallback := range fallbacks {
+		if !slices.Contains(chain, fallback) {
+			chain = append(chain, fallback)
+		}
+	}
+	return chain
+}
+
+// RegisterLocale 添加语言环境的消息，语言环境已存在时（包括内置的 zh 和 en）合并消息，应在注册路由之前调用
+//
+// 除内置的消息键外也可以定义应用自己的消息键（如 NewLocalizedBizError 使用的消息键）。
+// 语言环境中没有的消息使用中文消息：
+//
+//	apihandler.RegisterLocale("ja", map[apihandler.MessageKey]string{
+//		apihandler.MsgBindError: "パラメータのバインドに失敗しました",
+//		apihandler.MsgNotFound:  "リソースが見つかりません",
+//	})
+func RegisterLocale(locale string, messages map[MessageKey]string) error {
+	localesMu.Lock()
+	defer localesMu.Unlock()
+	return registerLocale(locale, messages)
+}
+
+// registerLocale 添加语言环境的消息，调用方需持有 localesMu 的写锁
+func registerLocale(locale string, messages map[MessageKey]string) error {
+	if normalizeLocale(locale) == "" {
+		return errors.New("apihandler: empty locale")
+	}
+	target := localeMessages(locale)
+	if target == nil {
+		target = make(map[MessageKey]string, len(messages))
+		registeredLocales[normalizeLocale(locale)] = target
+	}
+	maps.Copy(target, messages)
+	return nil
+}
+
+// OverrideMessage 修改已注册的语言环境中的一条消息，用于调整内置消息的措辞
+func OverrideMessage(locale string, key MessageKey, text string) error {
+	localesMu.Lock()
+	defer localesMu.Unlock()
+	messages := localeMessages(locale)
+	if messages == nil {
+		return fmt.Errorf("apihandler: unsupported locale %q", locale)
+	}
+	if !knownMessageKey(key) {
+		return fmt.Errorf("apihandler: unknown message key %q", key)
+	}
+	messages[key] = text
+	return nil
+}
+
+// localeMessages 返回语言环境的消息，未注册的语言环境返回 nil，调用方需持有 localesMu
+func localeMessages(locale string) map[MessageKey]string {
+	return registeredLocales[normalizeLocale(locale)]
+}
+
+// knownMessageKey 判断是否为已注册的语言环境中的消息键或验证消息键，调用方需持有 localesMu
+func knownMessageKey(key MessageKey) bool {
+	for _, messages := range registeredLocales {
+		if _, ok := messages[key]; ok {
+			return true
+		}
+	}
+	return strings.HasPrefix(string(key), "validation.")
+}
+
+// Locales 返回已注册的语言环境，如 [en ja zh zh-TW]
+func Locales() []string {
+	localesMu.RLock()
+	defer localesMu.RUnlock()
+	names := make([]string, 0, len(registeredLocales))
+	for name := range registeredLocales {
+		names = append(names, canonicalLocale(name))
+	}
+	slices.Sort(names)
+	return names
+}
+
+// NegotiateLocale 按 Accept-Language 头的权重从 supported 中选择语言环境，无法匹配时返回 false
+//
+// 依次尝试每个语言的完整标签、回退链（见 SetLocaleFallbacks）中的语言环境和语言代码（fr-CA 匹配 fr），
+// 以及相同语言代码的其他地区（en 匹配 en-GB）；q=0 的语言和通配符 * 不参与匹配。返回 supported 中的取值。
+func NegotiateLocale(acceptLanguage string, supported ...string) (string, bool) {
+	for _, accepted := range parseAccept(acceptLanguage) {
+		if accepted.value == "*" {
+			continue
+		}
+		tag := normalizeLocale(accepted.value)
+		language, _, _ := strings.Cut(tag, "-")
+		for _, candidate := range append(localeChain(tag), language) {
+			for _, locale := range supported {
+				if normalizeLocale(locale) == candidate {
+					return locale, true
+				}
+			}
+		}
+		for _, locale := range supported {
+			if base, _, _ := strings.Cut(normalizeLocale(locale), "-"); base == language {
+				return locale, true
+			}
+		}
+	}
+	return "", false
+}
+
+// normalizeLocale 规范化语言环境，如 zh_TW 为 zh-tw，zh-CN 为 zh
+func normalizeLocale(locale string) string {
+	locale = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+	if alias, ok := localeAliases[locale]; ok {
+		return alias
+	}
+	return locale
+}
+
+// canonicalLocale 按语言标签的惯例书写规范化的语言环境，如 zh-tw 为 zh-TW，zh-hant 为 zh-Hant
+func canonicalLocale(locale string) string {
+	subtags := strings.Split(locale, "-")
+	for i, subtag := range subtags[1:] {
+		switch len(subtag) {
+		case 2:
+			subtags[i+1] = strings.ToUpper(subtag)
+		case 4:
+			subtags[i+1] = strings.ToUpper(subtag[:1]) + subtag[1:]
+		}
+	}
+	return strings.Join(subtags, "-")
+}
+
 // Translate 实现翻译
 func (t *SimpleTranslator) Translate(key MessageKey, args ...interface{}) string {
-	format, ok := t.messages[key]
+	format, ok := "", false
+	localesMu.RLock()
+	for _, messages := range t.messages {
+		if format, ok = messages[key]; ok {
+			break
+		}
+	}
 	if !ok {
 		// 如果找不到翻译，使用默认消息
 		format = defaultMessages[key]
 	}
-	
+	localesMu.RUnlock()
+
 	if len(args) > 0 {
 		return fmt.Sprintf(format, args...)
 	}
 	return format
 }
 
+// localeContextKey 请求 context 中保存语言环境的键
+type localeContextKey struct{}
+
+// LocaleFromContext 返回处理器为当前请求确定的语言环境，不在处理器中调用时返回空
+func LocaleFromContext(ctx context.Context) string {
+	locale, _ := ctx.Value(localeContextKey{}).(string)
+	return locale
+}
+
+// contextLocale 返回上游中间件以 key 保存在 gin.Context 或请求 context 中的语言环境，没有时返回空
+func contextLocale(c *gin.Context, key any) string {
+	if key == nil {
+		return ""
+	}
+	if name, ok := key.(string); ok {
+		if value, ok := c.Get(name); ok {
+			if locale, ok := value.(string); ok && locale != "" {
+				return locale
+			}
+		}
+	}
+	locale, _ := c.Request.Context().Value(key).(string)
+	return locale
+}
+
+// translatorContextKey 请求 context 中保存翻译器的键
+type translatorContextKey struct{}
+
+// TranslatorFrom 返回处理器为当前请求使用的翻译器，业务代码可以用它按相同的语言环境翻译自己的消息
+//
+// 不在处理器中调用时返回 ctx 中语言环境（见 LocaleFromContext）的简单翻译器。
+func TranslatorFrom(ctx context.Context) Translator {
+	if translator, ok := ctx.Value(translatorContextKey{}).(Translator); ok {
+		return translator
+	}
+	return NewSimpleTranslator(LocaleFromContext(ctx))
+}
+
+// setRequestLocale 将当前请求的语言环境和翻译器保存到请求 context 中，返回翻译器
+func setRequestLocale(c *gin.Context, config *HandlerConfig) Translator {
+	ctx := context.WithValue(c.Request.Context(), localeContextKey{}, requestLocale(c, config))
+	c.Request = c.Request.WithContext(ctx)
+	translator := requestTranslator(c, config)
+	c.Request = c.Request.WithContext(context.WithValue(ctx, translatorContextKey{}, translator))
+	return translator
+}
+
 // DefaultTranslator 默认翻译器（中文）
 var DefaultTranslator = NewSimpleTranslator("zh")
 
 // DefaultLocaleFunc 默认语言环境函数（从 Accept-Language 头获取）
+//
+// 按权重将请求头（如 "fr-CA,en;q=0.9,zh-CN;q=0.8"）与已注册的语言环境（见 Locales）匹配，
+// 只注册了 en 和 zh 时选择 en。都无法匹配时返回权重最高的语言代码，供自定义翻译器和验证消息使用，
+// 没有请求头时使用中文。
 var DefaultLocaleFunc = func(r *http.Request) string {
-	locale := r.Header.Get("Accept-Language")
-	if locale == "" {
-		return "zh"
-	}
-	
-	// 解析 Accept-Language 头，格式如: "en-US,en;q=0.9,zh-CN;q=0.8"
-	// 取第一个语言代码（逗号或分号之前）
-	if idx := strings.IndexAny(locale, ",;"); idx > 0 {
-		locale = locale[:idx]
-	}
-	
-	// 只取语言代码部分（连字符之前），如 "en-US" -> "en"
-	if idx := strings.Index(locale, "-"); idx > 0 {
-		locale = locale[:idx]
-	}
-	
-	// 去除空格
-	locale = strings.TrimSpace(locale)
-	
-	if locale == "" {
-		return "zh"
-	}
-	
-	return locale
+	header := r.Header.Get("Accept-Language")
+	if locale, ok := NegotiateLocale(header, Locales()...); ok {
+		return locale
+	}
+	for _, accepted := range parseAccept(header) {
+		if accepted.value != "*" {
+			language, _, _ := strings.Cut(accepted.value, "-")
+			return language
+		}
+	}
+	return "zh"
 }
@@ -4,9 +4,11 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -141,6 +143,51 @@ func TestI18nCustomTranslator(t *testing.T) {
 	}
 }
 
+// prefixTranslator 测试用的按语言环境提供翻译器的翻译器
+type prefixTranslator struct {
+	locale string
+}
+
+func (t prefixTranslator) Translate(key MessageKey, args ...interface{}) string {
+	return t.locale + ":" + string(key)
+}
+
+func (t prefixTranslator) ForLocale(locale string) Translator {
+	return prefixTranslator{locale: locale}
+}
+
+// 测试实现 LocaleTranslator 的翻译器按请求的语言环境翻译
+func TestI18nLocaleTranslator(t *testing.T) {
+	r := gin.New()
+
+	type testReq struct {
+		Name string `json:"name" binding:"required"`
+	}
+
+	handleFunc := func(ctx context.Context, req *testReq) (*struct{}, error) {
+		return &struct{}{}, nil
+	}
+
+	r.POST("/test", Handler(handleFunc, WithTranslator(prefixTranslator{locale: "zh"})))
+
+	for _, locale := range []string{"en", "fr"} {
+		req := httptest.NewRequest("POST", "/test", bytes.NewBufferString(`{}`))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Language", locale)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		var resp ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("解析响应失败: %v", err)
+		}
+		if expected := locale + ":bind_error"; resp.Message != expected {
+			t.Errorf("期望消息为 '%s', 实际得到 '%s'", expected, resp.Message)
+		}
+	}
+}
+
 // 测试验证错误详情的国际化
 func TestI18nValidationErrorDetails(t *testing.T) {
 	r := gin.New()
@@ -358,3 +405,386 @@ func TestI18nComplexAcceptLanguage(t *testing.T) {
 		})
 	}
 }
+
+// 测试注册新的语言环境和修改消息
+func TestRegisterLocale(t *testing.T) {
+	original := englishMessages[MsgNotFound]
+	t.Cleanup(func() {
+		delete(registeredLocales, "ja")
+		englishMessages[MsgNotFound] = original
+	})
+
+	err := RegisterLocale("ja", map[MessageKey]string{
+		MsgBindError: "パラメータのバインドに失敗しました",
+	})
+	if err != nil {
+		t.Fatalf("注册语言环境失败: %v", err)
+	}
+	if err := OverrideMessage("ja_JP", MsgBindError, "x"); err == nil {
+		t.Errorf("期望未注册的语言环境返回错误")
+	}
+	if err := OverrideMessage("en", MsgNotFound, "Nothing here"); err != nil {
+		t.Fatalf("修改消息失败: %v", err)
+	}
+	if err := OverrideMessage("en", "no_such_key", "x"); err == nil {
+		t.Errorf("期望未知的消息键返回错误")
+	}
+	if err := RegisterLocale("", map[MessageKey]string{MsgNotFound: "x"}); err == nil {
+		t.Errorf("期望空的语言环境返回错误")
+	}
+	if err := RegisterLocale("ja", map[MessageKey]string{"app.greeting": "こんにちは"}); err != nil {
+		t.Fatalf("注册应用消息键失败: %v", err)
+	}
+	if err := OverrideMessage("ja", "app.greeting", "やあ"); err != nil {
+		t.Errorf("期望已定义的应用消息键可以修改, 实际得到 %v", err)
+	}
+
+	cases := []struct {
+		locale   string
+		key      MessageKey
+		expected string
+	}{
+		{"ja", MsgBindError, "パラメータのバインドに失敗しました"},
+		{"JA", MsgBindError, "パラメータのバインドに失敗しました"},
+		{"ja", MsgNotFound, defaultMessages[MsgNotFound]},
+		{"en", MsgNotFound, "Nothing here"},
+		{"en_US", MsgNotFound, "Nothing here"},
+	}
+	for _, tc := range cases {
+		if got := NewSimpleTranslator(tc.locale).Translate(tc.key); got != tc.expected {
+			t.Errorf("%s %s: 期望 %q, 实际得到 %q", tc.locale, tc.key, tc.expected, got)
+		}
+	}
+}
+
+// 测试翻译的同时注册语言环境和修改消息，需要使用 -race 运行
+func TestRegisterLocaleConcurrent(t *testing.T) {
+	original := englishMessages[MsgNotFound]
+	t.Cleanup(func() {
+		delete(registeredLocales, "ja")
+		englishMessages[MsgNotFound] = original
+	})
+
+	var wg sync.WaitGroup
+	for _, locale := range []string{"ja", "en", "zh"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				if NewSimpleTranslator(locale).Translate(MsgNotFound) == "" || len(Locales()) == 0 {
+					t.Errorf("%s: 期望翻译出消息", locale)
+					return
+				}
+			}
+		}()
+	}
+	for i := range 100 {
+		if err := RegisterLocale("ja", map[MessageKey]string{MsgNotFound: fmt.Sprintf("見つかりません %d", i)}); err != nil {
+			t.Fatalf("注册语言环境失败: %v", err)
+		}
+		if err := OverrideMessage("en", MsgNotFound, fmt.Sprintf("Nothing here %d", i)); err != nil {
+			t.Fatalf("修改消息失败: %v", err)
+		}
+	}
+	wg.Wait()
+
+	if got := DefaultTranslator.Translate(MsgNotFound); got != defaultMessages[MsgNotFound] {
+		t.Errorf("期望中文消息不变, 实际得到 %q", got)
+	}
+	if got := NewSimpleTranslator("en").Translate(MsgNotFound); got != "Nothing here 99" {
+		t.Errorf("期望使用最后修改的消息, 实际得到 %q", got)
+	}
+}
+
+// 测试按权重匹配 Accept-Language 头与支持的语言环境
+func TestNegotiateLocale(t *testing.T) {
+	testCases := []struct {
+		acceptLanguage string
+		supported      []string
+		expected       string
+		ok             bool
+	}{
+		{"fr-CA,en;q=0.9", []string{"en", "zh"}, "en", true},
+		{"fr-CA,en;q=0.9", []string{"en", "fr", "zh"}, "fr", true},
+		{"en;q=0.5, zh;q=0.8", []string{"en", "zh"}, "zh", true},
+		{"de;q=0, en;q=0.1", []string{"de", "en"}, "en", true},
+		{"zh-tw, zh;q=0.5", []string{"en", "zh", "zh-TW"}, "zh-TW", true},
+		{"zh-HK", []string{"en", "zh-TW"}, "zh-TW", true},
+		{"zh-CN", []string{"en", "zh"}, "zh", true},
+		{"*", []string{"en"}, "", false},
+		{"ja", []string{"en", "zh"}, "", false},
+		{"", []string{"en", "zh"}, "", false},
+	}
+
+	for _, tc := range testCases {
+		locale, ok := NegotiateLocale(tc.acceptLanguage, tc.supported...)
+		if locale != tc.expected || ok != tc.ok {
+			t.Errorf("%q: 期望 (%q, %v), 实际得到 (%q, %v)", tc.acceptLanguage, tc.expected, tc.ok, locale, ok)
+		}
+	}
+}
+
+// 测试语言环境的回退链
+func TestLocaleFallbacks(t *testing.T) {
+	const keyFarewell MessageKey = "app.farewell"
+	if err := RegisterLocale("zh-TW", map[MessageKey]string{MsgBindError: "參數綁定失敗"}); err != nil {
+		t.Fatalf("注册语言环境失败: %v", err)
+	}
+	if err := RegisterLocale("pt-BR", map[MessageKey]string{MsgBindError: "Falha ao vincular parâmetros"}); err != nil {
+		t.Fatalf("注册语言环境失败: %v", err)
+	}
+	if err := RegisterLocale("en", map[MessageKey]string{keyFarewell: "Goodbye"}); err != nil {
+		t.Fatalf("注册消息失败: %v", err)
+	}
+	t.Cleanup(func() {
+		delete(registeredLocales, "zh-tw")
+		delete(registeredLocales, "pt-br")
+		delete(englishMessages, keyFarewell)
+		clear(localeFallbacks)
+	})
+
+	translate := func(locale string, key MessageKey) string {
+		return NewSimpleTranslator(locale).Translate(key)
+	}
+
+	// 没有设置回退链时回退到语言代码，最后使用中文
+	if got := translate("zh-TW", MsgNotFound); got != defaultMessages[MsgNotFound] {
+		t.Errorf("期望 zh-TW 缺少的消息使用 zh, 实际得到 '%s'", got)
+	}
+	if got := translate("en-GB", MsgNotFound); got != englishMessages[MsgNotFound] {
+		t.Errorf("期望 en-GB 使用 en 的消息, 实际得到 '%s'", got)
+	}
+	if got := translate("pt-BR", MsgNotFound); got != defaultMessages[MsgNotFound] {
+		t.Errorf("期望没有回退链时使用中文, 实际得到 '%s'", got)
+	}
+
+	if err := SetLocaleFallbacks("zh_TW", "zh", "en"); err != nil {
+		t.Fatalf("设置回退链失败: %v", err)
+	}
+	if err := SetLocaleFallbacks("pt-BR", "en"); err != nil {
+		t.Fatalf("设置回退链失败: %v", err)
+	}
+	testCases := []struct {
+		locale   string
+		key      MessageKey
+		expected string
+	}{
+		{"zh-TW", MsgBindError, "參數綁定失敗"},
+		{"zh-TW", MsgNotFound, defaultMessages[MsgNotFound]},
+		{"zh-TW", keyFarewell, "Goodbye"},
+		{"pt-BR", MsgBindError, "Falha ao vincular parâmetros"},
+		{"pt-BR", MsgNotFound, englishMessages[MsgNotFound]},
+		{"pt_br", MsgNotFound, englishMessages[MsgNotFound]},
+	}
+	for _, tc := range testCases {
+		if got := translate(tc.locale, tc.key); got != tc.expected {
+			t.Errorf("%s %s: 期望 '%s', 实际得到 '%s'", tc.locale, tc.key, tc.expected, got)
+		}
+	}
+
+	// 协商语言环境时依次尝试回退链
+	if err := SetLocaleFallbacks("zh-HK", "zh-TW", "zh"); err != nil {
+		t.Fatalf("设置回退链失败: %v", err)
+	}
+	if locale, _ := NegotiateLocale("zh-HK", "zh", "zh-TW"); locale != "zh-TW" {
+		t.Errorf("期望 zh-HK 协商为 zh-TW, 实际得到 '%s'", locale)
+	}
+	if locale, ok := NegotiateLocale("pt-BR", "en", "zh"); locale != "en" || !ok {
+		t.Errorf("期望 pt-BR 协商为 en, 实际得到 (%q, %v)", locale, ok)
+	}
+
+	// 不传回退链时恢复默认的回退
+	if err := SetLocaleFallbacks("pt-BR"); err != nil {
+		t.Fatalf("清除回退链失败: %v", err)
+	}
+	if got := translate("pt-BR", MsgNotFound); got != defaultMessages[MsgNotFound] {
+		t.Errorf("期望清除回退链后使用中文, 实际得到 '%s'", got)
+	}
+	if _, ok := NegotiateLocale("pt-BR", "en", "zh"); ok {
+		t.Errorf("期望清除回退链后 pt-BR 无法协商")
+	}
+
+	if err := SetLocaleFallbacks("", "en"); err == nil {
+		t.Errorf("期望空的语言环境返回错误")
+	}
+	if err := SetLocaleFallbacks("pt", " "); err == nil {
+		t.Errorf("期望空的回退语言环境返回错误")
+	}
+}
+
+// 测试翻译和协商语言环境的同时设置回退链，需要使用 -race 运行
+func TestLocaleFallbacksConcurrent(t *testing.T) {
+	t.Cleanup(func() { clear(localeFallbacks) })
+	if err := SetLocaleFallbacks("pt-BR", "en"); err != nil {
+		t.Fatalf("设置回退链失败: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for range 3 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 100 {
+				NewSimpleTranslator("pt-BR").Translate(MsgNotFound)
+				if _, ok := NegotiateLocale("pt-BR", "en", "zh"); !ok {
+					t.Error("期望从回退链或同语言环境协商出语言环境")
+					return
+				}
+			}
+		}()
+	}
+	for i := range 100 {
+		fallbacks := []string{"en"}
+		if i%2 == 1 {
+			fallbacks = []string{"zh"}
+		}
+		if err := SetLocaleFallbacks("pt-BR", fallbacks...); err != nil {
+			t.Fatalf("设置回退链失败: %v", err)
+		}
+	}
+	wg.Wait()
+
+	if got := NewSimpleTranslator("pt-BR").Translate(MsgNotFound); got != defaultMessages[MsgNotFound] {
+		t.Errorf("期望使用最后设置的回退链, 实际得到 %q", got)
+	}
+}
+
+// 测试从上游中间件保存的键读取语言环境
+func TestWithLocaleKey(t *testing.T) {
+	type userLocaleKey struct{}
+	type testResp struct {
+		Locale string `json:"locale"`
+	}
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		if locale := c.GetHeader("X-User-Locale"); locale != "" {
+			c.Set("locale", locale)
+		}
+		if locale := c.GetHeader("X-Profile-Locale"); locale != "" {
+			c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), userLocaleKey{}, locale))
+		}
+	})
+	handleFunc := func(ctx context.Context, req *struct{}) (*testResp, error) {
+		return &testResp{Locale: LocaleFromContext(ctx)}, nil
+	}
+	r.GET("/gin", Handler(handleFunc, WithLocaleKey("locale")))
+	r.GET("/context", Handler(handleFunc, WithLocaleKey(userLocaleKey{})))
+	r.GET("/error", Handler(func(ctx context.Context, req *struct{}) (*struct{}, error) {
+		return nil, NewLocalizedBizError(404, MsgNotFound, http.StatusNotFound)
+	}, WithLocaleKey("locale")))
+
+	testCases := []struct {
+		name     string
+		path     string
+		headers  map[string]string
+		expected string
+	}{
+		{"gin.Context 的键", "/gin", map[string]string{"X-User-Locale": "en", "Accept-Language": "zh"}, "en"},
+		{"请求 context 的值", "/context", map[string]string{"X-Profile-Locale": "en", "Accept-Language": "zh"}, "en"},
+		{"没有保存时使用 Accept-Language", "/gin", map[string]string{"Accept-Language": "en"}, "en"},
+		{"没有保存时使用默认语言", "/context", map[string]string{"X-User-Locale": "en"}, "zh"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tc.path, nil)
+			for name, value := range tc.headers {
+				req.Header.Set(name, value)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			var resp SuccessResponse[testResp]
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("解析响应失败: %v", err)
+			}
+			if resp.Data == nil || resp.Data.Locale != tc.expected {
+				t.Errorf("期望语言环境为 '%s', 实际得到 %+v", tc.expected, resp.Data)
+			}
+		})
+	}
+
+	req := httptest.NewRequest("GET", "/error", nil)
+	req.Header.Set("X-User-Locale", "en")
+	req.Header.Set("Accept-Language", "zh")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	var resp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	if resp.Message != englishMessages[MsgNotFound] {
+		t.Errorf("期望错误消息使用用户设置的语言, 实际得到 '%s'", resp.Message)
+	}
+}
+
+// 测试默认语言环境函数与已注册的语言环境匹配
+func TestDefaultLocaleFunc(t *testing.T) {
+	if err := RegisterLocale("zh_TW", map[MessageKey]string{MsgBindError: "參數綁定失敗"}); err != nil {
+		t.Fatalf("注册语言环境失败: %v", err)
+	}
+	t.Cleanup(func() { delete(registeredLocales, "zh-tw") })
+
+	if locales := strings.Join(Locales(), ","); locales != "en,zh,zh-TW" {
+		t.Errorf("期望已注册的语言环境为 en,zh,zh-TW, 实际得到 %s", locales)
+	}
+
+	testCases := []struct {
+		acceptLanguage string
+		expected       string
+	}{
+		{"fr-CA,en;q=0.9", "en"},
+		{"zh-TW,zh;q=0.9", "zh-TW"},
+		{"en-US,en;q=0.9", "en"},
+		{"fr-CA,de;q=0.9", "fr"},
+		{"", "zh"},
+	}
+
+	for _, tc := range testCases {
+		req := httptest.NewRequest("GET", "/", nil)
+		req.Header.Set("Accept-Language", tc.acceptLanguage)
+		if locale := DefaultLocaleFunc(req); locale != tc.expected {
+			t.Errorf("%q: 期望 %q, 实际得到 %q", tc.acceptLanguage, tc.expected, locale)
+		}
+	}
+}
+
+// 测试业务处理函数通过 context 获取当前请求的翻译器
+func TestTranslatorFrom(t *testing.T) {
+	type testResp struct {
+		Message string `json:"message"`
+	}
+
+	r := gin.New()
+	handleFunc := func(ctx context.Context, req *struct{}) (*testResp, error) {
+		return &testResp{Message: TranslatorFrom(ctx).Translate(MsgNotFound)}, nil
+	}
+	r.GET("/default", Handler(handleFunc))
+	r.GET("/custom", Handler(handleFunc, WithTranslator(prefixTranslator{locale: "zh"})))
+
+	testCases := []struct {
+		path     string
+		expected string
+	}{
+		{"/default", englishMessages[MsgNotFound]},
+		{"/custom", "en:not_found"},
+	}
+	for _, tc := range testCases {
+		req := httptest.NewRequest("GET", tc.path, nil)
+		req.Header.Set("Accept-Language", "en")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		var resp SuccessResponse[testResp]
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("解析响应失败: %v", err)
+		}
+		if resp.Data == nil || resp.Data.Message != tc.expected {
+			t.Errorf("%s: 期望消息为 '%s', 实际得到 %+v", tc.path, tc.expected, resp.Data)
+		}
+	}
+
+	if got := TranslatorFrom(context.Background()).Translate(MsgNotFound); got != defaultMessages[MsgNotFound] {
+		t.Errorf("期望不在处理器中时使用中文, 实际得到 '%s'", got)
+	}
+}
@@ -0,0 +1,229 @@
+package apihandler
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IdempotencyKeyHeader 携带幂等键的请求头
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader 重放已保存响应时输出的响应头
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+// IdempotencyStore 幂等键存储接口，多实例部署时应使用 Redis 等共享存储实现
+type IdempotencyStore interface {
+	// Acquire 开始处理幂等键：首次出现时占用该键并返回 acquired 为 true；
+	// 已保存响应时返回该响应；其他请求正在处理时 record 为空且 acquired 为 false
+	Acquire(ctx context.Context, key string, ttl time.Duration) (record []byte, acquired bool, err error)
+	// Complete 保存处理结果，之后相同幂等键的请求将重放该结果
+	Complete(ctx context.Context, key string, record []byte, ttl time.Duration) error
+	// Release 处理失败（如 5xx 错误）时释放幂等键，允许客户端重试
+	Release(ctx context.Context, key string) error
+}
+
+// idempotencyKeyContextKey 在 gin.Context 中保存当前请求占用的幂等键
+const idempotencyKeyContextKey = "apihandler.idempotencyKey"
+
+// idempotencyBodyContextKey 在 gin.Context 中保存计算请求体摘要的 *hashingBody
+const idempotencyBodyContextKey = "apihandler.idempotencyBody"
+
+// WithIdempotency 开启幂等键支持，ttl 为保存响应的有效期
+//
+// 请求带有 Idempotency-Key 请求头时，首个请求的响应会被保存，相同调用方（按 Authorization 和 Cookie 头区分）
+// 以相同幂等键发送的重复请求直接重放该响应；请求体与首个请求不同时返回 422 错误响应，
+// 首个请求仍在处理中时返回 409 错误响应。5xx 错误响应不会被保存。
+func WithIdempotency(store IdempotencyStore, ttl time.Duration) Option {
+	return func(c *HandlerConfig) {
+		c.IdempotencyStore = store
+		c.IdempotencyTTL = ttl
+	}
+}
+
+// beginIdempotentRequest 检查幂等键，已重放保存的响应时返回 true，其他请求正在处理时返回 409 业务错误
+func beginIdempotentRequest(c *gin.Context, config *HandlerConfig, translator Translator) (bool, error) {
+	header := c.GetHeader(IdempotencyKeyHeader)
+	if header == "" {
+		return false, nil
+	}
+	key := c.Request.Method + " " + c.Request.URL.Path + " " + header
+	if digest := credentialDigest(c); digest != "" {
+		key += " " + digest
+	}
+
+	record, acquired, err := config.IdempotencyStore.Acquire(c.Request.Context(), key, config.IdempotencyTTL)
+	if err != nil {
+		return false, err
+	}
+	if acquired {
+		// 请求体在处理器读取时计算摘要，不需要预先读取整个请求体
+		body := newHashingBody(c.Request.Body)
+		c.Request.Body = body
+		c.Set(idempotencyKeyContextKey, key)
+		c.Set(idempotencyBodyContextKey, body)
+		return false, nil
+	}
+
+	var saved idempotentRecord
+	if len(record) == 0 || json.Unmarshal(record, &saved) != nil {
+		return false, ErrConflict(http.StatusConflict, translator.Translate(MsgIdempotencyInFlight))
+	}
+	if saved.BodyHash != "" {
+		if sum, err := newHashingBody(c.Request.Body).sum(); err != nil || sum != saved.BodyHash {
+			return false, NewBizError(http.StatusUnprocessableEntity, translator.Translate(MsgIdempotencyKeyReused), http.StatusUnprocessableEntity)
+		}
+	}
+	c.Header(IdempotentReplayedHeader, "true")
+	if saved.ContentType == "" && len(saved.Body) == 0 {
+		// WithNoContentOnNil 的 204 响应
+		c.Status(saved.Status)
+		c.Writer.WriteHeaderNow()
+		return true, nil
+	}
+	c.Data(saved.Status, saved.ContentType, saved.Body)
+	return true, nil
+}
+
+// idempotentRecord 幂等键保存的响应和首个请求的请求体摘要
+type idempotentRecord struct {
+	cachedResponse
+	BodyHash string `json:"body_hash,omitempty"`
+}
+
+// hashingBody 读取请求体的同时计算摘要
+type hashingBody struct {
+	io.ReadCloser
+	hash hash.Hash
+}
+
+// newHashingBody 创建计算 body 摘要的请求体，body 为 nil 时为空请求体
+func newHashingBody(body io.ReadCloser) *hashingBody {
+	if body == nil {
+		body = http.NoBody
+	}
+	return &hashingBody{ReadCloser: body, hash: sha256.New()}
+}
+
+// Read 实现 io.Reader 接口
+func (b *hashingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.hash.Write(p[:n])
+	return n, err
+}
+
+// sum 读取剩余的请求体并返回整个请求体的摘要
+func (b *hashingBody) sum() (string, error) {
+	if _, err := io.Copy(b.hash, b.ReadCloser); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b.hash.Sum(nil)), nil
+}
+
+// storeIdempotentResponse 保存编码后的响应，5xx 响应不保存
+func storeIdempotentResponse(c *gin.Context, config *HandlerConfig, status int, contentType string, data []byte) {
+	key := c.GetString(idempotencyKeyContextKey)
+	if key == "" || status >= http.StatusInternalServerError {
+		return
+	}
+	c.Set(idempotencyKeyContextKey, "")
+
+	saved := idempotentRecord{cachedResponse: cachedResponse{Status: status, ContentType: contentType, Body: data}}
+	body, _ := c.Get(idempotencyBodyContextKey)
+	if body, ok := body.(*hashingBody); ok {
+		sum, err := body.sum()
+		if err != nil {
+			// 请求体无法读完时不能确定请求的内容，不保存响应
+			c.Error(err)
+			releaseIdempotencyKey(c, config, key)
+			return
+		}
+		saved.BodyHash = sum
+	}
+	value, err := json.Marshal(saved)
+	if err == nil {
+		err = config.IdempotencyStore.Complete(c.Request.Context(), key, value, config.IdempotencyTTL)
+	}
+	if err != nil {
+		c.Error(err)
+		releaseIdempotencyKey(c, config, key)
+	}
+}
+
+// endIdempotentRequest 请求结束时释放未保存响应的幂等键
+func endIdempotentRequest(c *gin.Context, config *HandlerConfig) {
+	if key := c.GetString(idempotencyKeyContextKey); key != "" {
+		releaseIdempotencyKey(c, config, key)
+	}
+}
+
+// releaseIdempotencyKey 释放幂等键
+func releaseIdempotencyKey(c *gin.Context, config *HandlerConfig, key string) {
+	// 客户端断开连接时请求 context 已取消，释放操作不应随之失败
+	ctx := context.WithoutCancel(c.Request.Context())
+	if err := config.IdempotencyStore.Release(ctx, key); err != nil {
+		c.Error(err)
+	}
+}
+
+// MemoryIdempotencyStore 基于内存的幂等键存储，只适用于单实例部署
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryIdempotencyEntry
+}
+
+// memoryIdempotencyEntry 内存幂等键记录，record 为空表示正在处理
+type memoryIdempotencyEntry struct {
+	record    []byte
+	expiresAt time.Time
+}
+
+// NewMemoryIdempotencyStore 创建内存幂等键存储
+func NewMemoryIdempotencyStore() *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{entries: make(map[string]memoryIdempotencyEntry)}
+}
+
+// Acquire 实现 IdempotencyStore 接口
+func (s *MemoryIdempotencyStore) Acquire(ctx context.Context, key string, ttl time.Duration) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if entry, ok := s.entries[key]; ok && (entry.expiresAt.IsZero() || now.Before(entry.expiresAt)) {
+		return entry.record, false, nil
+	}
+	s.entries[key] = memoryIdempotencyEntry{expiresAt: idempotencyExpiry(now, ttl)}
+	return nil, true, nil
+}
+
+// Complete 实现 IdempotencyStore 接口
+func (s *MemoryIdempotencyStore) Complete(ctx context.Context, key string, record []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = memoryIdempotencyEntry{record: record, expiresAt: idempotencyExpiry(time.Now(), ttl)}
+	return nil
+}
+
+// Release 实现 IdempotencyStore 接口
+func (s *MemoryIdempotencyStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
+
+// idempotencyExpiry 计算幂等键记录的过期时间，ttl 小于等于 0 表示不过期
+func idempotencyExpiry(now time.Time, ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return now.Add(ttl)
+}
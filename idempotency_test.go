@@ -0,0 +1,176 @@
+package apihandler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试重放相同幂等键的响应
+func TestIdempotency(t *testing.T) {
+	r := gin.New()
+
+	var calls atomic.Int32
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		n := calls.Add(1)
+		if req.ID == 2 {
+			return nil, ErrInternalServer(50000, "服务异常")
+		}
+		return &testResponse{ID: req.ID, Name: strings.Repeat("x", int(n))}, nil
+	}
+	r.POST("/test/:id", Handler(handleFunc, WithIdempotency(NewMemoryIdempotencyStore(), time.Minute)))
+
+	send := func(path, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", path, nil)
+		if key != "" {
+			req.Header.Set(IdempotencyKeyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	first := send("/test/1", "key-1")
+	second := send("/test/1", "key-1")
+	if calls.Load() != 1 {
+		t.Errorf("期望业务函数只调用 1 次, 实际调用 %d 次", calls.Load())
+	}
+	if second.Body.String() != first.Body.String() || second.Code != first.Code {
+		t.Errorf("期望重放首个响应 %s, 实际得到 %s", first.Body.String(), second.Body.String())
+	}
+	if second.Header().Get(IdempotentReplayedHeader) != "true" {
+		t.Errorf("期望重放的响应带有 %s 响应头", IdempotentReplayedHeader)
+	}
+
+	send("/test/1", "key-2")
+	send("/test/1", "")
+	if calls.Load() != 3 {
+		t.Errorf("期望不同幂等键和不带幂等键的请求正常处理, 实际调用 %d 次", calls.Load())
+	}
+
+	send("/test/2", "key-3")
+	if w := send("/test/2", "key-3"); w.Code != http.StatusInternalServerError || calls.Load() != 5 {
+		t.Errorf("期望 5xx 响应不被保存, 实际状态码 %d, 调用 %d 次", w.Code, calls.Load())
+	}
+}
+
+// 测试处理中的重复请求返回 409
+func TestIdempotencyInFlight(t *testing.T) {
+	r := gin.New()
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		close(started)
+		<-release
+		return &testResponse{ID: req.ID}, nil
+	}
+	r.POST("/test/:id", Handler(handleFunc, WithIdempotency(NewMemoryIdempotencyStore(), time.Minute)))
+
+	newRequest := func() *http.Request {
+		req := httptest.NewRequest("POST", "/test/1", nil)
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		return req
+	}
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, newRequest())
+		done <- w
+	}()
+	<-started
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newRequest())
+	if w.Code != http.StatusConflict {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusConflict, w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "相同幂等键的请求正在处理中") {
+		t.Errorf("期望翻译后的错误消息, 实际得到 %s", w.Body.String())
+	}
+
+	close(release)
+	if first := <-done; first.Code != http.StatusOK {
+		t.Errorf("期望首个请求成功, 实际状态码 %d", first.Code)
+	}
+}
+
+// 测试幂等键按调用方区分，请求体不同时返回 422
+func TestIdempotencyScopeAndBody(t *testing.T) {
+	r := gin.New()
+
+	type createRequest struct {
+		Name string `json:"name"`
+	}
+	var calls atomic.Int32
+	handleFunc := func(ctx context.Context, req *createRequest) (*testResponse, error) {
+		calls.Add(1)
+		return &testResponse{Name: req.Name}, nil
+	}
+	r.POST("/orders", Handler(handleFunc, WithIdempotency(NewMemoryIdempotencyStore(), time.Minute)))
+
+	send := func(token, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/orders", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", token)
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	first := send("Bearer a", `{"name":"alice"}`)
+	if w := send("Bearer a", `{"name":"alice"}`); w.Header().Get(IdempotentReplayedHeader) != "true" || w.Body.String() != first.Body.String() {
+		t.Errorf("期望同一调用方的重复请求重放响应, 实际得到 %s", w.Body.String())
+	}
+	if w := send("Bearer b", `{"name":"bob"}`); w.Header().Get(IdempotentReplayedHeader) != "" || !strings.Contains(w.Body.String(), "bob") {
+		t.Errorf("期望其他调用方使用相同幂等键时不重放他人的响应, 实际得到 %s", w.Body.String())
+	}
+	if calls.Load() != 2 {
+		t.Errorf("期望业务函数调用 2 次, 实际调用 %d 次", calls.Load())
+	}
+
+	w := send("Bearer a", `{"name":"mallory"}`)
+	if w.Code != http.StatusUnprocessableEntity || !strings.Contains(w.Body.String(), "幂等键已用于内容不同的请求") {
+		t.Errorf("期望请求体不同时返回 422, 实际得到 %d %s", w.Code, w.Body.String())
+	}
+	if calls.Load() != 2 {
+		t.Errorf("期望请求体不同时不调用业务函数, 实际调用 %d 次", calls.Load())
+	}
+}
+
+// 测试 WithNoContentOnNil 的 204 响应同样被保存和重放
+func TestIdempotencyNoContent(t *testing.T) {
+	r := gin.New()
+
+	var calls atomic.Int32
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		calls.Add(1)
+		return nil, nil
+	}
+	r.DELETE("/test/:id", Handler(handleFunc, WithNoContentOnNil(), WithIdempotency(NewMemoryIdempotencyStore(), time.Minute)))
+
+	for i := range 2 {
+		req := httptest.NewRequest("DELETE", "/test/1", nil)
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusNoContent || w.Body.Len() != 0 {
+			t.Errorf("第 %d 次请求: 期望 204 空响应, 实际得到 %d %s", i+1, w.Code, w.Body.String())
+		}
+		if replayed := w.Header().Get(IdempotentReplayedHeader) == "true"; replayed != (i == 1) {
+			t.Errorf("第 %d 次请求: 期望 Idempotent-Replayed 为 %v", i+1, i == 1)
+		}
+	}
+	if calls.Load() != 1 {
+		t.Errorf("期望业务函数只调用 1 次, 实际调用 %d 次", calls.Load())
+	}
+}
@@ -0,0 +1,52 @@
+package apihandler
+
+import "fmt"
+
+// Interceptor 包装业务处理函数的拦截器，可在调用 next 前后访问类型化的请求和响应，
+// 用于事务、缓存、鉴权等业务层面的中间件
+//
+//	func withTx[T any, R any](db *sql.DB) Interceptor[T, R] {
+//		return func(next HandleFunc[T, R]) HandleFunc[T, R] {
+//			return func(ctx context.Context, req *T) (*R, error) {
+//				tx, err := db.BeginTx(ctx, nil)
+//				...
+//			}
+//		}
+//	}
+type Interceptor[T any, R any] func(next HandleFunc[T, R]) HandleFunc[T, R]
+
+// WithInterceptors 添加拦截器，先添加的拦截器在外层
+//
+// 拦截器的请求和响应类型必须与处理器一致，否则创建处理器时 panic。
+func WithInterceptors[T any, R any](interceptors ...Interceptor[T, R]) Option {
+	return func(c *HandlerConfig) {
+		items := make([]any, len(interceptors))
+		for i, interceptor := range interceptors {
+			items[i] = interceptor
+		}
+		c.Interceptors = append(c.Interceptors[:len(c.Interceptors):len(c.Interceptors)], items...)
+	}
+}
+
+// Chain 将多个拦截器组合为一个，先传入的拦截器在外层
+func Chain[T any, R any](interceptors ...Interceptor[T, R]) Interceptor[T, R] {
+	return func(next HandleFunc[T, R]) HandleFunc[T, R] {
+		for i := len(interceptors) - 1; i >= 0; i-- {
+			next = interceptors[i](next)
+		}
+		return next
+	}
+}
+
+// intercept 使用配置的拦截器包装业务处理函数
+func intercept[T any, R any](handleFunc HandleFunc[T, R], interceptors []any) HandleFunc[T, R] {
+	chain := make([]Interceptor[T, R], len(interceptors))
+	for i, item := range interceptors {
+		interceptor, ok := item.(Interceptor[T, R])
+		if !ok {
+			panic(fmt.Sprintf("apihandler: interceptor %T does not match handler type %T", item, handleFunc))
+		}
+		chain[i] = interceptor
+	}
+	return Chain(chain...)(handleFunc)
+}
@@ -0,0 +1,82 @@
+package apihandler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recordInterceptor 记录调用顺序的拦截器
+func recordInterceptor(name string, calls *[]string) Interceptor[testRequest, testResponse] {
+	return func(next HandleFunc[testRequest, testResponse]) HandleFunc[testRequest, testResponse] {
+		return func(ctx context.Context, req *testRequest) (*testResponse, error) {
+			*calls = append(*calls, name+":before")
+			resp, err := next(ctx, req)
+			*calls = append(*calls, name+":after")
+			return resp, err
+		}
+	}
+}
+
+// 测试拦截器的调用顺序和修改请求、响应
+func TestInterceptors(t *testing.T) {
+	r := gin.New()
+
+	var calls []string
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		calls = append(calls, "handle")
+		return &testResponse{ID: req.ID, Name: req.Name}, nil
+	}
+	authorize := func(next HandleFunc[testRequest, testResponse]) HandleFunc[testRequest, testResponse] {
+		return func(ctx context.Context, req *testRequest) (*testResponse, error) {
+			if req.ID == 2 {
+				return nil, ErrForbidden(40300, "禁止访问")
+			}
+			req.Name = "intercepted"
+			return next(ctx, req)
+		}
+	}
+
+	r.GET("/test/:id", Handler(handleFunc,
+		WithInterceptors(recordInterceptor("outer", &calls)),
+		WithInterceptors(Chain(recordInterceptor("inner", &calls), authorize)),
+	))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/test/1", nil))
+
+	expected := "outer:before,inner:before,handle,inner:after,outer:after"
+	if got := strings.Join(calls, ","); got != expected {
+		t.Errorf("期望调用顺序 %s, 实际得到 %s", expected, got)
+	}
+	if !strings.Contains(w.Body.String(), "intercepted") {
+		t.Errorf("期望拦截器修改请求, 实际响应 %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/test/2", nil))
+	if w.Code != http.StatusForbidden {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusForbidden, w.Code)
+	}
+}
+
+// 测试拦截器类型与处理器不一致时 panic
+func TestInterceptorTypeMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("期望类型不一致时 panic")
+		}
+	}()
+
+	mismatched := func(next HandleFunc[testRequest, string]) HandleFunc[testRequest, string] {
+		return next
+	}
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return nil, nil
+	}
+	Handler(handleFunc, WithInterceptors(mismatched))
+}
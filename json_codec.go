@@ -0,0 +1,88 @@
+package apihandler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+// JSONCodec JSON 编解码接口，用于替换请求解码和响应编码使用的 JSON 库
+//
+// sonic、go-json、jsoniter 等库的 Marshal/Unmarshal 函数签名与该接口一致，可直接包装使用。
+type JSONCodec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// StdJSONCodec 基于标准库 encoding/json 的 JSON 编解码器
+type StdJSONCodec struct{}
+
+// Marshal 实现 JSONCodec 接口
+func (StdJSONCodec) Marshal(v any) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal 实现 JSONCodec 接口
+func (StdJSONCodec) Unmarshal(data []byte, v any) error {
+	return json.Unmarshal(data, v)
+}
+
+// jsonCodec 返回生效的 JSON 编解码器
+func (c *HandlerConfig) jsonCodec() JSONCodec {
+	if c.JSONCodec != nil {
+		return c.JSONCodec
+	}
+	return StdJSONCodec{}
+}
+
+// codecMarshaler 使用指定的 JSONCodec 编码自身的响应体，如默认的成功和错误响应封装
+//
+// 响应封装通过 MarshalJSON 追加请求 ID 等字段，sonic、go-json 等库同样会调用 MarshalJSON，
+// 其中的 encoding/json 会使配置的 JSONCodec 不参与编码，因此编码响应体时优先调用该方法。
+type codecMarshaler interface {
+	marshalJSON(codec JSONCodec) ([]byte, error)
+}
+
+// marshalJSON 使用 codec 编码 v，v 实现了 codecMarshaler 时由其自行编码
+func marshalJSON(codec JSONCodec, v any) ([]byte, error) {
+	if m, ok := v.(codecMarshaler); ok {
+		return m.marshalJSON(codec)
+	}
+	return codec.Marshal(v)
+}
+
+// jsonBinding 使用自定义编解码器的 JSON 请求体绑定
+type jsonBinding struct {
+	codec JSONCodec
+}
+
+// Name 实现 binding.Binding 接口
+func (jsonBinding) Name() string {
+	return "json"
+}
+
+// Bind 实现 binding.Binding 接口
+func (b jsonBinding) Bind(req *http.Request, obj any) error {
+	if req == nil || req.Body == nil {
+		return errors.New("invalid request")
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return err
+	}
+	return b.BindBody(body, obj)
+}
+
+// BindBody 实现 binding.BindingBody 接口
+func (b jsonBinding) BindBody(body []byte, obj any) error {
+	if err := b.codec.Unmarshal(body, obj); err != nil {
+		return err
+	}
+	if binding.Validator == nil {
+		return nil
+	}
+	return binding.Validator.ValidateStruct(obj)
+}
@@ -0,0 +1,179 @@
+package apihandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	gojson "github.com/goccy/go-json"
+)
+
+// 记录调用次数的 JSON 编解码器
+type countingCodec struct {
+	StdJSONCodec
+	marshals   int
+	unmarshals int
+}
+
+func (c *countingCodec) Marshal(v any) ([]byte, error) {
+	c.marshals++
+	return c.StdJSONCodec.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v any) error {
+	c.unmarshals++
+	return c.StdJSONCodec.Unmarshal(data, v)
+}
+
+// 不转义 HTML 字符的 JSON 编解码器，encoding/json 会将 < 编码为 \u003c，
+// 响应体中出现未转义的 < 说明响应由该编解码器编码
+type unescapedCodec struct {
+	StdJSONCodec
+}
+
+func (unescapedCodec) Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n")), nil
+}
+
+// go-json 编解码器
+type goJSONCodec struct{}
+
+func (goJSONCodec) Marshal(v any) ([]byte, error) {
+	return gojson.Marshal(v)
+}
+
+func (goJSONCodec) Unmarshal(data []byte, v any) error {
+	return gojson.Unmarshal(data, v)
+}
+
+// 编解码测试用的请求结构
+type codecRequest struct {
+	Name  string   `json:"name" binding:"required"`
+	Age   int      `json:"age" binding:"min=1"`
+	Tags  []string `json:"tags"`
+	Email string   `json:"email"`
+}
+
+// 测试自定义 JSON 编解码器用于请求解码和响应编码
+func TestJSONCodec(t *testing.T) {
+	codec := &countingCodec{}
+
+	r := gin.New()
+
+	r.POST("/test", Handler(func(ctx context.Context, req *codecRequest) (*codecRequest, error) {
+		return req, nil
+	}, WithJSONCodec(codec)))
+
+	req := httptest.NewRequest("POST", "/test", strings.NewReader(`{"name":"tom","age":20}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusOK, w.Code)
+	}
+
+	if codec.unmarshals != 1 || codec.marshals != 1 {
+		t.Errorf("期望解码和编码各调用 1 次, 实际得到 %d 和 %d", codec.unmarshals, codec.marshals)
+	}
+
+	// 验证规则仍然生效
+	req = httptest.NewRequest("POST", "/test", strings.NewReader(`{"age":0}`))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusBadRequest, w.Code)
+	}
+
+	var resp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+
+	if len(resp.Errors) != 2 {
+		t.Errorf("期望 2 条验证错误, 实际得到 %d", len(resp.Errors))
+	}
+}
+
+// 测试默认的响应封装由自定义 JSON 编解码器编码，而不是 encoding/json
+func TestJSONCodecEncodesEnvelopes(t *testing.T) {
+	type queryRequest struct {
+		Name string `form:"name" json:"name"`
+	}
+	handleFunc := func(ctx context.Context, req *queryRequest) (*queryRequest, error) {
+		if req.Name == "" {
+			return nil, ErrNotFound(40400, "<missing>")
+		}
+		return req, nil
+	}
+
+	r := gin.New()
+	r.GET("/default", Handler(handleFunc, WithJSONCodec(unescapedCodec{}), WithRequestID(nil)))
+	r.GET("/flat", Handler(handleFunc, WithJSONCodec(unescapedCodec{}), WithFlattenedData()))
+	r.GET("/problem", Handler(handleFunc, WithJSONCodec(unescapedCodec{}), WithProblemDetails()))
+
+	cases := []struct {
+		target   string
+		expected string
+	}{
+		{"/default?name=<tom>", `"data":{"name":"<tom>"},"request_id":`},
+		{"/default", `"message":"<missing>"`},
+		{"/flat?name=<tom>", `{"code":0,"name":"<tom>"`},
+		{"/problem", `"detail":"<missing>"`},
+	}
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", tc.target, nil)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		if !strings.Contains(w.Body.String(), tc.expected) {
+			t.Errorf("%s: 期望响应由自定义编解码器编码并包含 %s, 实际得到 %s", tc.target, tc.expected, w.Body.String())
+		}
+	}
+}
+
+// benchmarkJSONCodec 对使用指定编解码器的处理器进行基准测试
+func benchmarkJSONCodec(b *testing.B, opts ...Option) {
+	r := gin.New()
+	r.POST("/test", Handler(func(ctx context.Context, req *codecRequest) (*codecRequest, error) {
+		return req, nil
+	}, opts...))
+
+	body := []byte(`{"name":"tom","age":20,"tags":["a","b","c","d"],"email":"tom@example.com"}`)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		req := httptest.NewRequest("POST", "/test", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			b.Fatalf("期望状态码 %d, 实际得到 %d", http.StatusOK, w.Code)
+		}
+	}
+}
+
+// 基准测试：默认的 encoding/json
+func BenchmarkJSONCodecStd(b *testing.B) {
+	benchmarkJSONCodec(b)
+}
+
+// 基准测试：go-json
+func BenchmarkJSONCodecGoJSON(b *testing.B) {
+	benchmarkJSONCodec(b, WithJSONCodec(goJSONCodec{}))
+}
@@ -0,0 +1,76 @@
+package apihandler
+
+import (
+	"reflect"
+)
+
+// JSONSchemaDialect 生成的 JSON Schema 使用的规范版本
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// jsonSchemaDefsPrefix 独立 JSON Schema 文档中 $defs 引用的前缀
+const jsonSchemaDefsPrefix = "#/$defs/"
+
+// JSONSchemaDocument 独立的 JSON Schema 文档，引用的具名结构体保存在 $defs 中
+type JSONSchemaDocument struct {
+	Dialect string `json:"$schema"`
+	Title   string `json:"title,omitempty"`
+	*Schema
+	Defs map[string]*Schema `json:"$defs,omitempty"`
+}
+
+// JSONSchemaFor 生成类型 T 的 JSON Schema 文档，binding tag 中的验证规则转换为约束
+//
+//	schema := apihandler.JSONSchemaFor[CreateUserRequest]()
+//	data, _ := json.MarshalIndent(schema, "", "  ")
+func JSONSchemaFor[T any]() *JSONSchemaDocument {
+	return GenerateJSONSchema(reflect.TypeFor[T]())
+}
+
+// GenerateJSONSchema 生成类型的 JSON Schema 文档，字段名称与 JSON 编码一致
+func GenerateJSONSchema(t reflect.Type) *JSONSchemaDocument {
+	g := newSchemaGenerator(jsonSchemaDefsPrefix)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var schema *Schema
+	if t.Kind() == reflect.Struct && t != timeType {
+		schema = g.structSchema(t, nil)
+	} else {
+		schema = g.schema(t)
+	}
+	return newJSONSchemaDocument(g, typeName(t), schema)
+}
+
+// RequestSchema 生成路由请求体的 JSON Schema 文档，与 OpenAPI 文档中的请求体一致，没有请求体时返回 nil
+//
+// 路径参数、请求头和查询参数不在请求体中，认证声明字段被忽略。
+func (route RouteInfo) RequestSchema() *JSONSchemaDocument {
+	if route.Request == nil {
+		return nil
+	}
+	g := newSchemaGenerator(jsonSchemaDefsPrefix)
+	body := requestBody(g, route.Request, route.Method)
+	if body == nil {
+		return nil
+	}
+	for _, media := range body.Content {
+		return newJSONSchemaDocument(g, route.Meta.Name, media.Schema)
+	}
+	return nil
+}
+
+// ResponseSchema 生成路由成功响应的 JSON Schema 文档，包括默认的响应封装
+func (route RouteInfo) ResponseSchema() *JSONSchemaDocument {
+	g := newSchemaGenerator(jsonSchemaDefsPrefix)
+	return newJSONSchemaDocument(g, route.Meta.Name, successResponseSchema(g, route.Response))
+}
+
+// newJSONSchemaDocument 创建 JSON Schema 文档，包含生成器中的具名结构体
+func newJSONSchemaDocument(g *schemaGenerator, title string, schema *Schema) *JSONSchemaDocument {
+	doc := &JSONSchemaDocument{Dialect: JSONSchemaDialect, Title: title, Schema: schema}
+	if len(g.schemas) > 0 {
+		doc.Defs = g.schemas
+	}
+	return doc
+}
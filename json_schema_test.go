@@ -0,0 +1,80 @@
+package apihandler
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"slices"
+	"testing"
+)
+
+// jsonSchemaAddress 测试用的地址
+type jsonSchemaAddress struct {
+	City string `json:"city" binding:"required"`
+}
+
+// jsonSchemaRequest 测试用的请求
+type jsonSchemaRequest struct {
+	ID      int64               `path:"id"`
+	Name    string              `json:"name" binding:"required,max=20"`
+	Role    string              `json:"role" binding:"oneof=admin member"`
+	Address *jsonSchemaAddress  `json:"address,omitempty"`
+	Others  []jsonSchemaAddress `json:"others" binding:"max=3,dive"`
+}
+
+// 测试生成类型的 JSON Schema 文档
+func TestJSONSchemaFor(t *testing.T) {
+	doc := JSONSchemaFor[jsonSchemaRequest]()
+	data, err := json.Marshal(doc)
+	if err != nil {
+		t.Fatalf("期望文档可以序列化为 JSON, 实际得到 %v", err)
+	}
+
+	var decoded map[string]any
+	json.Unmarshal(data, &decoded)
+	if decoded["$schema"] != JSONSchemaDialect || decoded["type"] != "object" || decoded["title"] != "jsonSchemaRequest" {
+		t.Errorf("期望文档包含规范版本、类型和标题, 实际得到 %s", data)
+	}
+	if !slices.Equal(doc.Required, []string{"name"}) || *doc.Properties["name"].MaxLength != 20 {
+		t.Errorf("期望 binding 规则转换为约束, 实际得到 %s", data)
+	}
+	if !reflect.DeepEqual(doc.Properties["role"].Enum, []any{"admin", "member"}) {
+		t.Errorf("期望 oneof 转换为枚举, 实际得到 %v", doc.Properties["role"].Enum)
+	}
+	if doc.Properties["address"].Ref != "#/$defs/jsonSchemaAddress" || doc.Defs["jsonSchemaAddress"] == nil {
+		t.Errorf("期望具名结构体保存在 $defs 中, 实际得到 %s", data)
+	}
+	if others := doc.Properties["others"]; *others.MaxItems != 3 || others.Items.Ref == "" {
+		t.Errorf("期望数组的约束作用于数组本身, 实际得到 %+v", others)
+	}
+
+	if doc := JSONSchemaFor[[]int](); doc.Type != "array" || doc.Items.Type != "integer" || doc.Defs != nil {
+		t.Errorf("期望生成数组类型的文档, 实际得到 %+v", doc)
+	}
+}
+
+// 测试生成路由请求体和响应的 JSON Schema 文档
+func TestRouteJSONSchema(t *testing.T) {
+	route := RouteInfo{
+		Method:   http.MethodPut,
+		Path:     "/users/:id",
+		Request:  reflect.TypeFor[jsonSchemaRequest](),
+		Response: reflect.TypeFor[jsonSchemaAddress](),
+		Meta:     Meta{Name: "updateUser"},
+	}
+
+	req := route.RequestSchema()
+	if req == nil || req.Title != "updateUser" || req.Properties["ID"] != nil || req.Properties["id"] != nil || len(req.Properties) != 4 {
+		t.Fatalf("期望请求体不包含路径参数, 实际得到 %+v", req)
+	}
+
+	resp := route.ResponseSchema()
+	if resp.Properties["data"].Ref != "#/$defs/jsonSchemaAddress" || !slices.Equal(resp.Required, []string{"code", "data"}) {
+		t.Errorf("期望响应包含默认的响应封装, 实际得到 %+v", resp)
+	}
+
+	route.Method = http.MethodGet
+	if route.RequestSchema() != nil {
+		t.Error("期望没有请求体的路由返回 nil")
+	}
+}
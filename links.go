@@ -0,0 +1,79 @@
+package apihandler
+
+import (
+	"encoding/xml"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 常用的链接关系名
+const (
+	LinkSelf    = "self"
+	LinkNext    = "next"
+	LinkPrev    = "prev"
+	LinkRelated = "related"
+)
+
+// Link 超媒体链接
+type Link struct {
+	Href   string `json:"href"`
+	Method string `json:"method,omitempty"`
+	Title  string `json:"title,omitempty"`
+}
+
+// Links 以关系名（self、next、related 等）为键的链接集合
+type Links map[string]Link
+
+// LinkBuilder 超媒体链接接口
+//
+// 业务处理函数的返回值实现该接口时，Links 的结果会作为与 data 同级的 links 字段输出，
+// 可通过 c 获取请求路径、查询参数等信息构造链接。
+type LinkBuilder interface {
+	Links(c *gin.Context) Links
+}
+
+// responseLinks 获取业务返回值提供的链接
+func responseLinks(c *gin.Context, resp any) Links {
+	if isNil(resp) {
+		return nil
+	}
+	if b, ok := resp.(LinkBuilder); ok {
+		return b.Links(c)
+	}
+	return nil
+}
+
+// MarshalXML 实现 xml.Marshaler 接口，按关系名顺序输出为 <link rel="..." href="..."/>
+func (l Links) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	rels := make([]string, 0, len(l))
+	for rel := range l {
+		rels = append(rels, rel)
+	}
+	sort.Strings(rels)
+
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, rel := range rels {
+		link := l[rel]
+		attrs := []xml.Attr{
+			{Name: xml.Name{Local: "rel"}, Value: rel},
+			{Name: xml.Name{Local: "href"}, Value: link.Href},
+		}
+		if link.Method != "" {
+			attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "method"}, Value: link.Method})
+		}
+		if link.Title != "" {
+			attrs = append(attrs, xml.Attr{Name: xml.Name{Local: "title"}, Value: link.Title})
+		}
+		linkStart := xml.StartElement{Name: xml.Name{Local: "link"}, Attr: attrs}
+		if err := enc.EncodeToken(linkStart); err != nil {
+			return err
+		}
+		if err := enc.EncodeToken(linkStart.End()); err != nil {
+			return err
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
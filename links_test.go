@@ -0,0 +1,137 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 带超媒体链接的订单响应
+type orderResponse struct {
+	ID     int64  `json:"id"`
+	Status string `json:"status"`
+}
+
+func (o *orderResponse) Links(c *gin.Context) Links {
+	links := Links{
+		LinkSelf:    {Href: fmt.Sprintf("/orders/%d", o.ID)},
+		LinkRelated: {Href: fmt.Sprintf("/orders/%d/items", o.ID)},
+	}
+	if o.Status == "pending" {
+		links["cancel"] = Link{Href: fmt.Sprintf("/orders/%d/cancel", o.ID), Method: http.MethodPost}
+	}
+	return links
+}
+
+// 测试 LinkBuilder 输出 links 字段
+func TestLinkBuilder(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*orderResponse, error) {
+		return &orderResponse{ID: req.ID, Status: "pending"}, nil
+	}
+
+	r.GET("/orders/:id", Handler(handleFunc))
+
+	req := httptest.NewRequest("GET", "/orders/5", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusOK, w.Code)
+	}
+
+	var resp struct {
+		Data  orderResponse `json:"data"`
+		Links Links         `json:"links"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+
+	if resp.Data.ID != 5 {
+		t.Errorf("期望 data.id 为 5, 实际得到 %d", resp.Data.ID)
+	}
+
+	if resp.Links[LinkSelf].Href != "/orders/5" {
+		t.Errorf("期望 self 链接为 '/orders/5', 实际得到 '%s'", resp.Links[LinkSelf].Href)
+	}
+
+	if resp.Links[LinkRelated].Href != "/orders/5/items" {
+		t.Errorf("期望 related 链接为 '/orders/5/items', 实际得到 '%s'", resp.Links[LinkRelated].Href)
+	}
+
+	if cancel := resp.Links["cancel"]; cancel.Href != "/orders/5/cancel" || cancel.Method != http.MethodPost {
+		t.Errorf("期望 cancel 链接为 POST /orders/5/cancel, 实际得到 %+v", cancel)
+	}
+}
+
+// 测试未实现 LinkBuilder 时不输出 links 字段
+func TestLinkBuilderAbsent(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return &testResponse{ID: req.ID}, nil
+	}
+
+	r.GET("/test/:id", Handler(handleFunc))
+
+	req := httptest.NewRequest("GET", "/test/1", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if strings.Contains(w.Body.String(), `"links"`) {
+		t.Errorf("期望响应不包含 links 字段, 实际得到 %s", w.Body.String())
+	}
+}
+
+// 测试按需返回字段时保留 links
+func TestLinkBuilderWithSparseFields(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*orderResponse, error) {
+		return &orderResponse{ID: req.ID, Status: "paid"}, nil
+	}
+
+	r.GET("/orders/:id", Handler(handleFunc, WithSparseFields("fields")))
+
+	req := httptest.NewRequest("GET", "/orders/5?fields=id", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	expected := `{"code":0,"data":{"id":5},"links":{"related":{"href":"/orders/5/items"},"self":{"href":"/orders/5"}}}`
+	if w.Body.String() != expected {
+		t.Errorf("期望响应为 %s, 实际得到 %s", expected, w.Body.String())
+	}
+}
+
+// 测试 XML 响应中的 links 输出
+func TestLinkBuilderXML(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*orderResponse, error) {
+		return &orderResponse{ID: req.ID, Status: "pending"}, nil
+	}
+
+	r.GET("/orders/:id", Handler(handleFunc, WithResponseFormat(FormatXML)))
+
+	req := httptest.NewRequest("GET", "/orders/5", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	expected := `<links><link rel="cancel" href="/orders/5/cancel" method="POST"></link>` +
+		`<link rel="related" href="/orders/5/items"></link><link rel="self" href="/orders/5"></link></links>`
+	if !strings.Contains(w.Body.String(), expected) {
+		t.Errorf("期望响应包含 %s, 实际得到 %s", expected, w.Body.String())
+	}
+}
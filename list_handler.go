@@ -0,0 +1,140 @@
+package apihandler
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 默认的分页参数
+const (
+	PageParam      = "page"     // 页码的 query 参数名
+	PerPageParam   = "per_page" // 每页数量的 query 参数名
+	DefaultPerPage = 30         // 默认每页数量
+)
+
+// ListFunc 列表业务处理函数类型，返回当前页的数据和总数
+type ListFunc[T any, R any] func(ctx context.Context, req *T) (items []R, total int64, err error)
+
+// ListHandler 创建列表处理器
+//
+// 成功时 data 为当前页的数据，并输出 X-Total-Count 和 RFC 5988 Link（first、prev、next、last）响应头。
+// 请求实现 Paginator 接口（如嵌入 PageRequest）时使用其分页参数，否则从 page 和 per_page 这两个 query 参数中读取。
+func ListHandler[T any, R any](listFunc ListFunc[T, R], opts ...Option) gin.HandlerFunc {
+	return Handler(func(ctx context.Context, req *T) (*listPage[R], error) {
+		items, total, err := listFunc(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		page := &listPage[R]{items: items, total: total}
+		if p, ok := any(req).(Paginator); ok {
+			page.page, page.perPage = p.Pagination()
+		}
+		return page, nil
+	}, opts...)
+}
+
+// contextHeaderSetter 需要根据请求上下文生成响应头的响应
+type contextHeaderSetter interface {
+	setContextHeaders(c *gin.Context)
+}
+
+// listPage 列表处理器的响应
+type listPage[R any] struct {
+	items   []R
+	total   int64
+	page    int // 为 0 时从 query 参数中读取
+	perPage int
+}
+
+// Data 实现 DataProvider 接口
+func (p *listPage[R]) Data() any {
+	if p.items == nil {
+		return []R{}
+	}
+	return p.items
+}
+
+// mock 实现 mocker 接口，生成 1 到 3 条模拟数据
+func (p *listPage[R]) mock() {
+	p.items = make([]R, mockInt(1, 3))
+	for i := range p.items {
+		p.items[i] = *MockFor[R]()
+	}
+	p.total = int64(len(p.items))
+}
+
+// setContextHeaders 输出 X-Total-Count 和 Link 响应头
+func (p *listPage[R]) setContextHeaders(c *gin.Context) {
+	c.Header("X-Total-Count", strconv.FormatInt(p.total, 10))
+
+	page, perPage := p.page, p.perPage
+	if page == 0 {
+		page, perPage = pageParams(c)
+	}
+	if link := paginationLinks(c, page, perPage, p.total); link != "" {
+		c.Header("Link", link)
+	}
+}
+
+// pageParams 从 query 参数中读取页码和每页数量
+func pageParams(c *gin.Context) (page, perPage int) {
+	page, _ = strconv.Atoi(c.Query(PageParam))
+	if page < 1 {
+		page = 1
+	}
+	perPage, _ = strconv.Atoi(c.Query(PerPageParam))
+	if perPage < 1 {
+		perPage = DefaultPerPage
+	}
+	return page, perPage
+}
+
+// paginationLinks 生成 RFC 5988 Link 响应头
+func paginationLinks(c *gin.Context, page, perPage int, total int64) string {
+	lastPage := int((total + int64(perPage) - 1) / int64(perPage))
+	if lastPage < 1 {
+		lastPage = 1
+	}
+
+	var links []string
+	add := func(rel string, target int) {
+		links = append(links, fmt.Sprintf(`<%s>; rel="%s"`, pageURL(c, target, perPage), rel))
+	}
+	if page > 1 {
+		add("first", 1)
+		add("prev", min(page-1, lastPage))
+	}
+	if page < lastPage {
+		add("next", page+1)
+		add("last", lastPage)
+	}
+	return strings.Join(links, ", ")
+}
+
+// pageURL 生成指定页码的绝对地址，保留其他 query 参数
+func pageURL(c *gin.Context, page, perPage int) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+
+	query := c.Request.URL.Query()
+	query.Set(PageParam, strconv.Itoa(page))
+	query.Set(PerPageParam, strconv.Itoa(perPage))
+
+	u := url.URL{
+		Scheme:   scheme,
+		Host:     c.Request.Host,
+		Path:     c.Request.URL.Path,
+		RawQuery: query.Encode(),
+	}
+	return u.String()
+}
@@ -0,0 +1,88 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 列表请求
+type listUsersRequest struct {
+	Page    int    `form:"page"`
+	PerPage int    `form:"per_page"`
+	Status  string `form:"status"`
+}
+
+// 测试列表处理器输出 X-Total-Count 和 Link 响应头
+func TestListHandler(t *testing.T) {
+	r := gin.New()
+
+	listFunc := func(ctx context.Context, req *listUsersRequest) ([]testResponse, int64, error) {
+		if req.Status == "invalid" {
+			return nil, 0, ErrBadRequest(40000, "状态不合法")
+		}
+		return []testResponse{{ID: 1}, {ID: 2}}, 95, nil
+	}
+
+	r.GET("/users", ListHandler(listFunc))
+
+	req := httptest.NewRequest("GET", "/users?status=active&page=2&per_page=10", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusOK, w.Code)
+	}
+
+	if total := w.Header().Get("X-Total-Count"); total != "95" {
+		t.Errorf("期望 X-Total-Count 为 95, 实际得到 '%s'", total)
+	}
+
+	expected := `<http://example.com/users?page=1&per_page=10&status=active>; rel="first", ` +
+		`<http://example.com/users?page=1&per_page=10&status=active>; rel="prev", ` +
+		`<http://example.com/users?page=3&per_page=10&status=active>; rel="next", ` +
+		`<http://example.com/users?page=10&per_page=10&status=active>; rel="last"`
+	if link := w.Header().Get("Link"); link != expected {
+		t.Errorf("期望 Link 为 %s, 实际得到 %s", expected, link)
+	}
+
+	var resp SuccessResponse[[]testResponse]
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+
+	if len(*resp.Data) != 2 {
+		t.Errorf("期望 data 长度为 2, 实际得到 %d", len(*resp.Data))
+	}
+
+	// 最后一页没有 next 和 last
+	req = httptest.NewRequest("GET", "/users?page=10&per_page=10", nil)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	expected = `<http://example.com/users?page=1&per_page=10>; rel="first", ` +
+		`<http://example.com/users?page=9&per_page=10>; rel="prev"`
+	if link := w.Header().Get("Link"); link != expected {
+		t.Errorf("期望 Link 为 %s, 实际得到 %s", expected, link)
+	}
+
+	// 错误时不输出分页响应头
+	req = httptest.NewRequest("GET", "/users?status=invalid", nil)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusBadRequest, w.Code)
+	}
+
+	if w.Header().Get("X-Total-Count") != "" {
+		t.Errorf("错误响应不应输出 X-Total-Count")
+	}
+}
@@ -0,0 +1,75 @@
+package apihandler
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// localizedBizError 消息由消息键定义的业务错误，输出错误响应时按请求的翻译器翻译
+type localizedBizError struct {
+	code     any
+	key      MessageKey
+	httpCode int
+	args     []any
+}
+
+// NewLocalizedBizError 创建消息由消息键定义的业务错误，args 为消息的参数
+//
+// 消息在输出错误响应时由处理器为当前请求使用的翻译器翻译，同一个错误值按调用方的语言环境输出中文或英文，
+// 适合定义为包级变量复用。在处理器之外调用 Error 时返回中文消息。
+//
+//	var ErrOrderClosed = handler.NewLocalizedBizError(40901, "order_closed", http.StatusConflict)
+func NewLocalizedBizError(code any, key MessageKey, httpCode int, args ...any) BizError {
+	return &localizedBizError{code: code, key: key, httpCode: httpCode, args: args}
+}
+
+// Error 实现 error 接口，返回默认翻译器翻译的消息
+func (e *localizedBizError) Error() string {
+	return e.Translate(DefaultTranslator)
+}
+
+// Code 返回业务错误码
+func (e *localizedBizError) Code() any {
+	return e.code
+}
+
+// HTTPCode 返回 HTTP 状态码
+func (e *localizedBizError) HTTPCode() int {
+	return e.httpCode
+}
+
+// Errors 返回详细错误列表
+func (e *localizedBizError) Errors() []any {
+	return nil
+}
+
+// Translate 使用翻译器翻译错误消息
+func (e *localizedBizError) Translate(translator Translator) string {
+	return translator.Translate(e.key, e.args...)
+}
+
+// translatedBizError 按请求的翻译器翻译后的业务错误
+type translatedBizError struct {
+	BizError
+	message string
+}
+
+// Error 实现 error 接口，返回翻译后的消息
+func (e *translatedBizError) Error() string {
+	return e.message
+}
+
+// Unwrap 返回原始业务错误
+func (e *translatedBizError) Unwrap() error {
+	return e.BizError
+}
+
+// localizeError 错误链中包含 NewLocalizedBizError 创建的错误时，按当前请求的翻译器翻译其消息
+func localizeError(c *gin.Context, config *HandlerConfig, bizErr BizError) BizError {
+	var localized *localizedBizError
+	if !errors.As(bizErr, &localized) {
+		return bizErr
+	}
+	return &translatedBizError{BizError: bizErr, message: localized.Translate(requestTranslator(c, config))}
+}
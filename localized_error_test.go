@@ -0,0 +1,103 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试按请求的语言环境翻译业务错误的消息
+func TestLocalizedBizError(t *testing.T) {
+	const keyOrderClosed MessageKey = "order_closed"
+	const keyStockShort MessageKey = "stock_short"
+	if err := RegisterLocale("zh", map[MessageKey]string{
+		keyOrderClosed: "订单已关闭",
+		keyStockShort:  "库存不足，剩余 %d 件",
+	}); err != nil {
+		t.Fatalf("注册中文消息失败: %v", err)
+	}
+	if err := RegisterLocale("en", map[MessageKey]string{
+		keyOrderClosed: "order is closed",
+		keyStockShort:  "only %d left in stock",
+	}); err != nil {
+		t.Fatalf("注册英文消息失败: %v", err)
+	}
+	t.Cleanup(func() {
+		for _, key := range []MessageKey{keyOrderClosed, keyStockShort} {
+			delete(defaultMessages, key)
+			delete(englishMessages, key)
+		}
+	})
+
+	errOrderClosed := NewLocalizedBizError(40901, keyOrderClosed, http.StatusConflict)
+	errStockShort := NewLocalizedBizError(40902, keyStockShort, http.StatusConflict, 3)
+
+	r := gin.New()
+	r.GET("/closed", Handler(func(ctx context.Context, req *struct{}) (*struct{}, error) {
+		return nil, errOrderClosed
+	}))
+	r.GET("/stock", Handler(func(ctx context.Context, req *struct{}) (*struct{}, error) {
+		return nil, errStockShort
+	}))
+	r.GET("/wrapped", Handler(func(ctx context.Context, req *struct{}) (*struct{}, error) {
+		return nil, fmt.Errorf("close order: %w", errOrderClosed)
+	}))
+	r.GET("/headered", Handler(func(ctx context.Context, req *struct{}) (*struct{}, error) {
+		return nil, NewHeaderedError(errOrderClosed, http.Header{"Retry-After": {"30"}})
+	}))
+
+	testCases := []struct {
+		name     string
+		path     string
+		language string
+		expected string
+		code     float64
+	}{
+		{"中文", "/closed", "zh-CN", "订单已关闭", 40901},
+		{"英文", "/closed", "en-US,en;q=0.9", "order is closed", 40901},
+		{"中文参数", "/stock", "zh", "库存不足，剩余 3 件", 40902},
+		{"英文参数", "/stock", "en", "only 3 left in stock", 40902},
+		{"包装的错误", "/wrapped", "en", "order is closed", 40901},
+		{"带响应头的错误", "/headered", "en", "order is closed", 40901},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("GET", tc.path, nil)
+			req.Header.Set("Accept-Language", tc.language)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusConflict {
+				t.Errorf("期望状态码为 %d, 实际得到 %d", http.StatusConflict, w.Code)
+			}
+			var resp ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("解析响应失败: %v", err)
+			}
+			if resp.Message != tc.expected {
+				t.Errorf("期望消息为 '%s', 实际得到 '%s'", tc.expected, resp.Message)
+			}
+			if resp.Code != tc.code {
+				t.Errorf("期望错误码为 %v, 实际得到 %v", tc.code, resp.Code)
+			}
+			if tc.path == "/headered" && w.Header().Get("Retry-After") != "30" {
+				t.Errorf("期望保留响应头 Retry-After, 实际得到 '%s'", w.Header().Get("Retry-After"))
+			}
+		})
+	}
+
+	if got := errOrderClosed.Error(); got != "订单已关闭" {
+		t.Errorf("期望在处理器之外返回中文消息, 实际得到 '%s'", got)
+	}
+	var bizErr BizError
+	if !errors.As(fmt.Errorf("wrap: %w", errStockShort), &bizErr) || bizErr.Code() != 40902 {
+		t.Errorf("期望可以通过 errors.As 获取业务错误, 实际得到 %v", bizErr)
+	}
+}
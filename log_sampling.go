@@ -0,0 +1,38 @@
+package apihandler
+
+import (
+	"math/rand/v2"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LogSampler 判断是否记录请求日志的函数，req 为绑定后的请求对象
+type LogSampler func(req any) bool
+
+// logSkippedContextKey 标记请求未被日志采样的 gin.Context 键
+const logSkippedContextKey = "apihandler.logSkipped"
+
+// WithLogSampling 按比例采样请求日志和访问日志，rate 为 0 到 1 之间的采样率
+//
+// 未被采样的请求在出错时仍会记录，高 QPS 接口可以只记录一部分成功请求。
+func WithLogSampling(rate float64) Option {
+	return WithLogSampler(func(req any) bool {
+		return rand.Float64() < rate
+	})
+}
+
+// WithLogSampler 设置请求日志和访问日志的采样函数，未被采样的请求在出错时仍会记录
+func WithLogSampler(sampler LogSampler) Option {
+	return func(c *HandlerConfig) {
+		c.LogSampler = sampler
+	}
+}
+
+// sampleLog 判断是否记录请求日志，不记录时在 gin.Context 中标记，供访问日志使用
+func sampleLog(c *gin.Context, config *HandlerConfig, req any) bool {
+	if config.LogSampler == nil || config.LogSampler(req) {
+		return true
+	}
+	c.Set(logSkippedContextKey, true)
+	return false
+}
@@ -0,0 +1,69 @@
+package apihandler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试日志采样只记录部分成功请求，出错的请求始终记录
+func TestLogSampler(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		if req.Name == "error" {
+			return nil, ErrBadRequest(40000, "参数错误")
+		}
+		return &testResponse{}, nil
+	}
+	var requestLogs, accessLogs []string
+	sampler := func(req any) bool {
+		return req.(*testRequest).Name == "sampled"
+	}
+	r.GET("/users", Handler(handleFunc,
+		WithLogSampler(sampler),
+		WithRequestLogger(func(r *http.Request, req any) {
+			requestLogs = append(requestLogs, req.(*testRequest).Name)
+		}),
+		WithAccessLog(func(c *gin.Context, info RequestInfo) {
+			accessLogs = append(accessLogs, c.Query("name"))
+		}),
+	))
+
+	for _, name := range []string{"sampled", "skipped", "error"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/users?name="+name, nil))
+	}
+
+	for _, logs := range [][]string{requestLogs, accessLogs} {
+		if len(logs) != 2 || logs[0] != "sampled" || logs[1] != "error" {
+			t.Errorf("期望记录被采样和出错的请求, 实际得到 %v", logs)
+		}
+	}
+}
+
+// 测试采样率为 0 和 1 的日志采样
+func TestLogSamplingRate(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return &testResponse{}, nil
+	}
+	counts := map[string]int{}
+	for path, rate := range map[string]float64{"/none": 0, "/all": 1} {
+		r.GET(path, Handler(handleFunc, WithLogSampling(rate), WithRequestLogger(func(r *http.Request, req any) {
+			counts[r.URL.Path]++
+		})))
+	}
+
+	for i := 0; i < 10; i++ {
+		for _, path := range []string{"/none", "/all"} {
+			r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+		}
+	}
+	if counts["/none"] != 0 || counts["/all"] != 10 {
+		t.Errorf("期望采样率 0 不记录、采样率 1 全部记录, 实际得到 %v", counts)
+	}
+}
@@ -0,0 +1,53 @@
+package apihandler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maskedError 隐藏了原始消息的内部服务器错误
+type maskedError struct {
+	*internalError
+	message     string
+	referenceID string
+}
+
+// Error 实现 error 接口，返回通用的错误消息
+func (e *maskedError) Error() string {
+	return e.message
+}
+
+// ErrorReference 返回错误响应中的错误编号，错误未被隐藏时返回空
+//
+// 可在 OnError 中与原始错误一起记录日志，便于根据用户反馈的编号排查问题。
+func ErrorReference(err error) string {
+	var masked *maskedError
+	if errors.As(err, &masked) {
+		return masked.referenceID
+	}
+	return ""
+}
+
+// maskInternalError 将内部服务器错误的消息替换为带错误编号的通用消息，业务错误原样返回
+func maskInternalError(c *gin.Context, config *HandlerConfig, bizErr BizError) BizError {
+	internal, ok := bizErr.(*internalError)
+	if !ok {
+		return bizErr
+	}
+	referenceID := newReferenceID()
+	return &maskedError{
+		internalError: internal,
+		message:       requestTranslator(c, config).Translate(MsgInternalErrorWithReference, referenceID),
+		referenceID:   referenceID,
+	}
+}
+
+// newReferenceID 生成随机的错误编号
+func newReferenceID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,75 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试隐藏非业务错误的原始消息
+func TestMaskedInternalErrors(t *testing.T) {
+	r := gin.New()
+
+	errDB := errors.New("pq: password authentication failed for user \"app\"")
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		if req.ID == 1 {
+			return nil, ErrNotFound(40400, "资源不存在")
+		}
+		return nil, errDB
+	}
+
+	var logged error
+	var reference string
+	onError := func(c *gin.Context, req any, err error) {
+		logged, reference = errors.Unwrap(err), ErrorReference(err)
+	}
+
+	r.GET("/test/:id", Handler(handleFunc, WithMaskedInternalErrors(), WithOnError(onError)))
+
+	req := httptest.NewRequest("GET", "/test/2", nil)
+	req.Header.Set("Accept-Language", "en")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusInternalServerError, w.Code)
+	}
+
+	var resp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+
+	if strings.Contains(resp.Message, "pq:") {
+		t.Errorf("期望不泄露原始错误消息, 实际得到 '%s'", resp.Message)
+	}
+
+	if len(reference) != 16 || resp.Message != "Internal server error, reference: "+reference {
+		t.Errorf("期望消息包含错误编号 '%s', 实际得到 '%s'", reference, resp.Message)
+	}
+
+	if logged != errDB {
+		t.Errorf("期望 OnError 可以获取原始错误, 实际得到 %v", logged)
+	}
+
+	// 业务错误不受影响
+	req = httptest.NewRequest("GET", "/test/1", nil)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+
+	if resp.Message != "资源不存在" || reference != "" {
+		t.Errorf("期望业务错误消息不变且没有错误编号, 实际得到 '%s', '%s'", resp.Message, reference)
+	}
+}
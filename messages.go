@@ -0,0 +1,194 @@
+package apihandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"maps"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// MessageFormat 消息文件的格式
+type MessageFormat string
+
+// 支持的消息文件格式
+const (
+	MessageFormatJSON MessageFormat = "json"
+	MessageFormatYAML MessageFormat = "yaml"
+	MessageFormatTOML MessageFormat = "toml"
+)
+
+// LoadMessages 从消息文件添加或覆盖语言环境的消息，应在启动时、注册路由之前调用
+//
+// 文件的键为消息键，嵌套的对象按 . 连接，如 YAML 中 validation 下的 required 为 validation.required。
+// 中文（zh）消息文件可以定义应用自己的消息键；其他语言环境的键必须是已注册的语言环境中的消息键或 validation. 开头的
+// 验证消息键，存在未知的键或非字符串的值时返回错误且不修改任何消息：
+//
+//	f, _ := os.Open("locales/fr.yaml")
+//	defer f.Close()
+//	if err := apihandler.LoadMessages("fr", f, apihandler.MessageFormatYAML); err != nil {
+//		log.Fatal(err)
+//	}
+func LoadMessages(locale string, r io.Reader, format MessageFormat) error {
+	messages, err := parseMessages(locale, r, format)
+	if err != nil {
+		return err
+	}
+	localesMu.Lock()
+	defer localesMu.Unlock()
+	if err := checkMessageKeys(locale, messages, nil); err != nil {
+		return err
+	}
+	return registerLocale(locale, messages)
+}
+
+// LoadMessagesFS 从 fsys 中匹配 glob 的消息文件添加或覆盖语言环境的消息，适合加载 go:embed 嵌入的消息文件
+//
+// 文件名（不含扩展名）为语言环境，扩展名决定格式（.json、.yaml、.yml 或 .toml）。中文消息文件中定义的
+// 应用消息键可以在其他语言环境的文件中使用。任一文件有错误时返回错误且不修改任何消息：
+//
+//	//go:embed locales/*.yaml
+//	var localeFiles embed.FS
+//
+//	func init() {
+//		if err := apihandler.LoadMessagesFS(localeFiles, "locales/*.yaml"); err != nil {
+//			panic(err)
+//		}
+//	}
+func LoadMessagesFS(fsys fs.FS, glob string) error {
+	names, err := fs.Glob(fsys, glob)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		return fmt.Errorf("apihandler: no message files match %q", glob)
+	}
+
+	type messageFile struct {
+		locale   string
+		messages map[MessageKey]string
+	}
+	files := make([]messageFile, 0, len(names))
+	defined := make(map[MessageKey]string)
+	for _, name := range names {
+		ext := path.Ext(name)
+		format, ok := messageFormats[strings.ToLower(ext)]
+		if !ok {
+			return fmt.Errorf("apihandler: unsupported message file %s", name)
+		}
+		locale := strings.TrimSuffix(path.Base(name), ext)
+		if normalizeLocale(locale) == "" {
+			return fmt.Errorf("apihandler: message file %s has no locale", name)
+		}
+		data, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			return err
+		}
+		messages, err := parseMessages(locale, bytes.NewReader(data), format)
+		if err != nil {
+			return fmt.Errorf("%w (%s)", err, name)
+		}
+		if normalizeLocale(locale) == "zh" {
+			maps.Copy(defined, messages)
+		}
+		files = append(files, messageFile{locale: locale, messages: messages})
+	}
+
+	// 检查和添加消息在同一个写锁中进行，其他 goroutine 不会看到只加载了部分文件的消息
+	localesMu.Lock()
+	defer localesMu.Unlock()
+	for _, file := range files {
+		if err := checkMessageKeys(file.locale, file.messages, defined); err != nil {
+			return err
+		}
+	}
+	for _, file := range files {
+		if err := registerLocale(file.locale, file.messages); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// messageFormats 消息文件扩展名对应的格式
+var messageFormats = map[string]MessageFormat{
+	".json": MessageFormatJSON,
+	".yaml": MessageFormatYAML,
+	".yml":  MessageFormatYAML,
+	".toml": MessageFormatTOML,
+}
+
+// parseMessages 解析消息文件，返回展开后的消息
+func parseMessages(locale string, r io.Reader, format MessageFormat) (map[MessageKey]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]any
+	switch format {
+	case MessageFormatJSON:
+		err = json.Unmarshal(data, &raw)
+	case MessageFormatYAML:
+		err = yaml.Unmarshal(data, &raw)
+	case MessageFormatTOML:
+		err = toml.Unmarshal(data, &raw)
+	default:
+		return nil, fmt.Errorf("apihandler: unsupported message format %q", format)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("apihandler: parse %s messages for %s: %w", format, locale, err)
+	}
+
+	messages := make(map[MessageKey]string)
+	if err := flattenMessages("", raw, messages); err != nil {
+		return nil, fmt.Errorf("apihandler: %s messages for %s: %w", format, locale, err)
+	}
+	return messages, nil
+}
+
+// checkMessageKeys 检查消息键是否已定义，defined 为同时加载的中文消息；中文消息可以定义新的消息键。调用方需持有 localesMu
+func checkMessageKeys(locale string, messages, defined map[MessageKey]string) error {
+	if normalizeLocale(locale) == "zh" {
+		return nil
+	}
+	var unknown []string
+	for key := range messages {
+		if _, ok := defined[key]; !ok && !knownMessageKey(key) {
+			unknown = append(unknown, string(key))
+		}
+	}
+	if len(unknown) > 0 {
+		slices.Sort(unknown)
+		return fmt.Errorf("apihandler: unknown message keys for %s: %s", locale, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// flattenMessages 将嵌套的消息展开为以 . 连接的消息键
+func flattenMessages(prefix string, raw map[string]any, messages map[MessageKey]string) error {
+	for name, value := range raw {
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+		switch value := value.(type) {
+		case string:
+			messages[MessageKey(key)] = value
+		case map[string]any:
+			if err := flattenMessages(key, value, messages); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("message %q must be a string, got %T", key, value)
+		}
+	}
+	return nil
+}
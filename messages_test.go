@@ -0,0 +1,236 @@
+package apihandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试从 YAML、JSON 和 TOML 消息文件加载消息
+func TestLoadMessages(t *testing.T) {
+	t.Cleanup(func() {
+		delete(registeredLocales, "fr")
+		delete(registeredLocales, "de-ch")
+		englishMessages[MsgBindError] = "Parameter binding failed"
+	})
+
+	yamlMessages := `
+bind_error: Échec de la liaison des paramètres
+validation:
+  required: "{field} est obligatoire"
+`
+	if err := LoadMessages("fr", strings.NewReader(yamlMessages), MessageFormatYAML); err != nil {
+		t.Fatalf("加载 YAML 消息失败: %v", err)
+	}
+	if err := LoadMessages("en", strings.NewReader(`{"bind_error": "Invalid parameters"}`), MessageFormatJSON); err != nil {
+		t.Fatalf("加载 JSON 消息失败: %v", err)
+	}
+	tomlMessages := `
+not_found = "Nicht gefunden"
+validation.min = "{field} ist zu klein"
+`
+	if err := LoadMessages("de_CH", strings.NewReader(tomlMessages), MessageFormatTOML); err != nil {
+		t.Fatalf("加载 TOML 消息失败: %v", err)
+	}
+
+	cases := []struct {
+		locale   string
+		key      MessageKey
+		expected string
+	}{
+		{"fr", MsgBindError, "Échec de la liaison des paramètres"},
+		{"fr", ValidationMessageKey("required"), "{field} est obligatoire"},
+		{"fr", MsgNotFound, defaultMessages[MsgNotFound]},
+		{"en", MsgBindError, "Invalid parameters"},
+		{"de-CH", MsgNotFound, "Nicht gefunden"},
+		{"de-CH", ValidationMessageKey("min"), "{field} ist zu klein"},
+	}
+	for _, tc := range cases {
+		if got := NewSimpleTranslator(tc.locale).Translate(tc.key); got != tc.expected {
+			t.Errorf("%s %s: 期望 %q, 实际得到 %q", tc.locale, tc.key, tc.expected, got)
+		}
+	}
+
+	r := gin.New()
+	r.POST("/users", Handler(func(ctx context.Context, req *struct {
+		Name string `json:"name" binding:"required"`
+	}) (*struct{}, error) {
+		return &struct{}{}, nil
+	}))
+	req := httptest.NewRequest("POST", "/users", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", "fr-FR,fr;q=0.9")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var resp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	if fe := resp.FieldErrors(); resp.Message != "Échec de la liaison des paramètres" || len(fe) != 1 || fe[0].Message != "name est obligatoire" {
+		t.Errorf("期望使用加载的法语消息, 实际得到 %s %+v", resp.Message, fe)
+	}
+}
+
+// 测试处理请求的同时加载消息，需要使用 -race 运行
+func TestLoadMessagesConcurrent(t *testing.T) {
+	original := englishMessages[MsgBindError]
+	t.Cleanup(func() {
+		delete(registeredLocales, "fr")
+		englishMessages[MsgBindError] = original
+	})
+
+	r := gin.New()
+	r.POST("/users", Handler(func(ctx context.Context, req *struct {
+		Name string `json:"name" binding:"required"`
+	}) (*struct{}, error) {
+		return &struct{}{}, nil
+	}))
+	fsys := fstest.MapFS{
+		"locales/en.json": {Data: []byte(`{"bind_error": "Invalid parameters"}`)},
+		"locales/fr.yaml": {Data: []byte("bind_error: Paramètres invalides\n")},
+	}
+
+	var wg sync.WaitGroup
+	for _, language := range []string{"fr", "en", "zh"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				req := httptest.NewRequest("POST", "/users", bytes.NewBufferString(`{}`))
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Accept-Language", language)
+				w := httptest.NewRecorder()
+				r.ServeHTTP(w, req)
+				if w.Code != http.StatusBadRequest {
+					t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusBadRequest, w.Code)
+					return
+				}
+			}
+		}()
+	}
+	for i := range 50 {
+		var err error
+		if i%2 == 0 {
+			err = LoadMessages("en", strings.NewReader(`{"bind_error": "Bad parameters"}`), MessageFormatJSON)
+		} else {
+			err = LoadMessagesFS(fsys, "locales/*")
+		}
+		if err != nil {
+			t.Fatalf("加载消息失败: %v", err)
+		}
+	}
+	wg.Wait()
+
+	if got := NewSimpleTranslator("fr").Translate(MsgBindError); got != "Paramètres invalides" {
+		t.Errorf("期望使用加载的法语消息, 实际得到 %q", got)
+	}
+}
+
+// 测试消息文件中的未知键和错误的值
+func TestLoadMessagesInvalid(t *testing.T) {
+	t.Cleanup(func() { delete(registeredLocales, "it") })
+
+	err := LoadMessages("it", strings.NewReader(`{"bind_error": "Errore", "bind_eror": "x", "nested": {"key": "y"}}`), MessageFormatJSON)
+	if err == nil || !strings.Contains(err.Error(), "unknown message keys for it: bind_eror, nested.key") {
+		t.Errorf("期望未知键的错误, 实际得到 %v", err)
+	}
+	if localeMessages("it") != nil {
+		t.Errorf("期望存在未知键时不添加任何消息")
+	}
+
+	if err := LoadMessages("it", strings.NewReader("bind_error: 1\n"), MessageFormatYAML); err == nil || !strings.Contains(err.Error(), `message "bind_error" must be a string`) {
+		t.Errorf("期望非字符串值的错误, 实际得到 %v", err)
+	}
+	if err := LoadMessages("it", strings.NewReader("{"), MessageFormatJSON); err == nil {
+		t.Errorf("期望解析失败的错误")
+	}
+	if err := LoadMessages("it", strings.NewReader(""), "ini"); err == nil || !strings.Contains(err.Error(), "unsupported message format") {
+		t.Errorf("期望不支持的格式的错误, 实际得到 %v", err)
+	}
+}
+
+// 测试从文件系统加载匹配的消息文件
+func TestLoadMessagesFS(t *testing.T) {
+	const keyOrderClosed MessageKey = "order.closed"
+	t.Cleanup(func() {
+		delete(registeredLocales, "fr")
+		delete(registeredLocales, "pt-br")
+		delete(defaultMessages, keyOrderClosed)
+		englishMessages[MsgNotFound] = "Resource not found"
+	})
+
+	fsys := fstest.MapFS{
+		"locales/zh.yaml":    {Data: []byte("order:\n  closed: 订单已关闭\n")},
+		"locales/en.json":    {Data: []byte(`{"not_found": "Nothing here", "order": {"closed": "Order is closed"}}`)},
+		"locales/fr.yml":     {Data: []byte("order.closed: La commande est fermée\n")},
+		"locales/pt_BR.toml": {Data: []byte(`bind_error = "Falha ao vincular parâmetros"`)},
+		"locales/README.md":  {Data: []byte("# locales")},
+	}
+	if err := LoadMessagesFS(fsys, "locales/*.*ml"); err != nil {
+		t.Fatalf("加载 YAML 和 TOML 消息文件失败: %v", err)
+	}
+	if err := LoadMessagesFS(fsys, "locales/*.json"); err != nil {
+		t.Fatalf("加载 JSON 消息文件失败: %v", err)
+	}
+
+	cases := []struct {
+		locale   string
+		key      MessageKey
+		expected string
+	}{
+		{"zh", keyOrderClosed, "订单已关闭"},
+		{"en", keyOrderClosed, "Order is closed"},
+		{"en", MsgNotFound, "Nothing here"},
+		{"fr", keyOrderClosed, "La commande est fermée"},
+		{"pt-BR", MsgBindError, "Falha ao vincular parâmetros"},
+	}
+	for _, tc := range cases {
+		if got := NewSimpleTranslator(tc.locale).Translate(tc.key); got != tc.expected {
+			t.Errorf("%s %s: 期望 %q, 实际得到 %q", tc.locale, tc.key, tc.expected, got)
+		}
+	}
+}
+
+// 测试消息文件系统中的错误不修改任何消息
+func TestLoadMessagesFSInvalid(t *testing.T) {
+	t.Cleanup(func() { delete(registeredLocales, "it") })
+
+	fsys := fstest.MapFS{
+		"locales/it.yaml": {Data: []byte("bind_error: Errore\n")},
+		"locales/es.yaml": {Data: []byte("bind_eror: Error\n")},
+		"locales/de.ini":  {Data: []byte("bind_error = Fehler\n")},
+		"nameless/.yaml":  {Data: []byte("bind_error: x\n")},
+	}
+	testCases := []struct {
+		glob     string
+		expected string
+	}{
+		{"locales/*.yaml", "unknown message keys for es: bind_eror"},
+		{"locales/*.ini", "unsupported message file locales/de.ini"},
+		{"nameless/*.yaml", "message file nameless/.yaml has no locale"},
+		{"locales/*.json", `no message files match "locales/*.json"`},
+		{"locales/[", "syntax error in pattern"},
+	}
+	for _, tc := range testCases {
+		err := LoadMessagesFS(fsys, tc.glob)
+		if err == nil || !strings.Contains(err.Error(), tc.expected) {
+			t.Errorf("%s: 期望包含 %q 的错误, 实际得到 %v", tc.glob, tc.expected, err)
+		}
+	}
+	if localeMessages("it") != nil {
+		t.Errorf("期望任一文件有错误时不添加任何消息")
+	}
+
+	if err := LoadMessagesFS(fstest.MapFS{"it.yaml": {Data: []byte("bind_error: 1\n")}}, "*.yaml"); err == nil || !strings.Contains(err.Error(), "(it.yaml)") {
+		t.Errorf("期望错误包含文件名, 实际得到 %v", err)
+	}
+}
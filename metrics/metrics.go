@@ -0,0 +1,114 @@
+// Package metrics 将 apihandler 处理器的请求数、耗时、处理中请求数和错误数导出为 Prometheus 指标，
+// 指标带有路由、请求方法、HTTP 状态码和业务代码标签
+package metrics
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	apihandler "github.com/night1008/gotools/gin-api-handler"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DefaultNamespace 默认的指标命名空间
+const DefaultNamespace = "apihandler"
+
+// Config 指标配置
+type Config struct {
+	Namespace   string            // 指标命名空间，为空时使用 apihandler
+	Subsystem   string            // 指标子系统
+	Buckets     []float64         // 耗时直方图的桶（秒），为空时使用 prometheus.DefBuckets
+	ConstLabels prometheus.Labels // 所有指标共有的标签，如服务名
+}
+
+// Collector 实现 apihandler.RequestObserver，将请求记录为 Prometheus 指标
+//
+// 导出的指标：
+//   - <namespace>_requests_total：请求数，标签 route、method、status、code
+//   - <namespace>_request_duration_seconds：请求耗时直方图，标签 route、method、status
+//   - <namespace>_requests_in_flight：处理中的请求数，标签 route、method
+//   - <namespace>_errors_total：错误响应数，标签 route、method、status、code
+type Collector struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	inFlight *prometheus.GaugeVec
+	errors   *prometheus.CounterVec
+}
+
+// New 创建指标收集器并注册到 reg
+func New(reg prometheus.Registerer, config Config) (*Collector, error) {
+	if config.Namespace == "" {
+		config.Namespace = DefaultNamespace
+	}
+	if config.Buckets == nil {
+		config.Buckets = prometheus.DefBuckets
+	}
+	opts := func(name, help string) prometheus.Opts {
+		return prometheus.Opts{
+			Namespace:   config.Namespace,
+			Subsystem:   config.Subsystem,
+			Name:        name,
+			Help:        help,
+			ConstLabels: config.ConstLabels,
+		}
+	}
+
+	c := &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts(opts("requests_total", "Total number of handled requests.")),
+			[]string{"route", "method", "status", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace:   config.Namespace,
+			Subsystem:   config.Subsystem,
+			Name:        "request_duration_seconds",
+			Help:        "Request handling duration in seconds.",
+			ConstLabels: config.ConstLabels,
+			Buckets:     config.Buckets,
+		}, []string{"route", "method", "status"}),
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts(opts("requests_in_flight", "Number of requests currently being handled.")),
+			[]string{"route", "method"}),
+		errors: prometheus.NewCounterVec(prometheus.CounterOpts(opts("errors_total", "Total number of error responses.")),
+			[]string{"route", "method", "status", "code"}),
+	}
+	for _, collector := range []prometheus.Collector{c.requests, c.duration, c.inFlight, c.errors} {
+		if err := reg.Register(collector); err != nil {
+			return nil, fmt.Errorf("register metrics: %w", err)
+		}
+	}
+	return c, nil
+}
+
+// MustNew 创建指标收集器并注册到 reg，注册失败时 panic
+func MustNew(reg prometheus.Registerer, config Config) *Collector {
+	c, err := New(reg, config)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Option 返回添加该收集器的处理器选项，可用于 apihandler.SetDefaults 或路由分组
+func (c *Collector) Option() apihandler.Option {
+	return apihandler.WithObserver(c)
+}
+
+// ObserveStart 实现 apihandler.RequestObserver 接口
+func (c *Collector) ObserveStart(ctx *gin.Context) {
+	c.inFlight.WithLabelValues(ctx.FullPath(), ctx.Request.Method).Inc()
+}
+
+// ObserveEnd 实现 apihandler.RequestObserver 接口
+func (c *Collector) ObserveEnd(ctx *gin.Context, info apihandler.RequestInfo) {
+	status := strconv.Itoa(info.HTTPCode)
+	code := ""
+	if info.Code != nil {
+		code = fmt.Sprint(info.Code)
+	}
+
+	c.inFlight.WithLabelValues(info.Route, info.Method).Dec()
+	c.requests.WithLabelValues(info.Route, info.Method, status, code).Inc()
+	c.duration.WithLabelValues(info.Route, info.Method, status).Observe(info.Latency.Seconds())
+	if info.Err != nil {
+		c.errors.WithLabelValues(info.Route, info.Method, status, code).Inc()
+	}
+}
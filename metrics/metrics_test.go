@@ -0,0 +1,82 @@
+package metrics
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	apihandler "github.com/night1008/gotools/gin-api-handler"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// userRequest 测试请求
+type userRequest struct {
+	ID int64 `path:"id"`
+}
+
+// userResponse 测试响应
+type userResponse struct {
+	ID int64 `json:"id"`
+}
+
+// 测试请求数、耗时、处理中请求数和错误数指标
+func TestCollector(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	collector := MustNew(reg, Config{ConstLabels: prometheus.Labels{"service": "user"}})
+
+	r := gin.New()
+	handleFunc := func(ctx context.Context, req *userRequest) (*userResponse, error) {
+		if req.ID == 0 {
+			return nil, apihandler.ErrNotFound(40400, "用户不存在")
+		}
+		return &userResponse{ID: req.ID}, nil
+	}
+	r.GET("/users/:id", apihandler.Handler(handleFunc, collector.Option()))
+
+	for _, path := range []string{"/users/1", "/users/2", "/users/0"} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest("GET", path, nil))
+	}
+
+	expected := `
+# HELP apihandler_requests_total Total number of handled requests.
+# TYPE apihandler_requests_total counter
+apihandler_requests_total{code="0",method="GET",route="/users/:id",service="user",status="200"} 2
+apihandler_requests_total{code="40400",method="GET",route="/users/:id",service="user",status="404"} 1
+# HELP apihandler_errors_total Total number of error responses.
+# TYPE apihandler_errors_total counter
+apihandler_errors_total{code="40400",method="GET",route="/users/:id",service="user",status="404"} 1
+# HELP apihandler_requests_in_flight Number of requests currently being handled.
+# TYPE apihandler_requests_in_flight gauge
+apihandler_requests_in_flight{method="GET",route="/users/:id",service="user"} 0
+`
+	if err := testutil.GatherAndCompare(reg, strings.NewReader(expected),
+		"apihandler_requests_total", "apihandler_errors_total", "apihandler_requests_in_flight"); err != nil {
+		t.Errorf("指标与期望不一致: %v", err)
+	}
+
+	if n := testutil.CollectAndCount(collector.duration); n != 2 {
+		t.Errorf("期望耗时直方图有 2 组标签, 实际得到 %d", n)
+	}
+}
+
+// 测试重复注册时返回错误
+func TestNewDuplicateRegister(t *testing.T) {
+	reg := prometheus.NewRegistry()
+	if _, err := New(reg, Config{}); err != nil {
+		t.Fatalf("期望第一次注册成功, 实际得到 %v", err)
+	}
+	if _, err := New(reg, Config{}); err == nil {
+		t.Error("期望重复注册时返回错误")
+	}
+	if _, err := New(reg, Config{Namespace: "api", Subsystem: "v2"}); err != nil {
+		t.Errorf("期望不同命名空间的指标注册成功, 实际得到 %v", err)
+	}
+}
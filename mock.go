@@ -0,0 +1,301 @@
+package apihandler
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WithMock 启用模拟模式，处理器不调用业务函数，而是返回根据响应类型生成的模拟数据
+//
+// 参数绑定、验证和拦截器照常执行，前端可以在业务逻辑实现之前对接接口。模拟数据见 MockFor。
+//
+//	if os.Getenv("API_MOCK") == "1" {
+//		apihandler.SetDefaults(apihandler.WithMock())
+//	}
+func WithMock() Option {
+	return func(c *HandlerConfig) {
+		c.Mock = true
+	}
+}
+
+// MockFor 生成类型 T 的模拟数据
+//
+// 有 example tag 的字段使用示例值；其他字段按 binding tag 生成符合约束的随机值：
+// oneof 从枚举中选取，min、max、len、gt、gte、lt、lte 限制字符串长度、数字范围和切片长度，
+// email、uuid、url、ipv4 等生成对应格式的字符串，dive 之后的规则作用于切片元素。
+func MockFor[T any]() *T {
+	v := new(T)
+	mockValue(reflect.ValueOf(v).Elem(), mockRules{}, make(map[reflect.Type]bool))
+	return v
+}
+
+// mockHandleFunc 返回生成模拟数据的业务函数
+func mockHandleFunc[T any, R any]() HandleFunc[T, R] {
+	return func(ctx context.Context, req *T) (*R, error) {
+		resp := MockFor[R]()
+		if m, ok := any(resp).(mocker); ok {
+			m.mock()
+		}
+		return resp, nil
+	}
+}
+
+// mocker 字段未导出、需要自行生成模拟数据的响应，如列表处理器的响应
+type mocker interface {
+	mock()
+}
+
+// mockRules 生成模拟数据时使用的验证规则
+type mockRules struct {
+	enum     []string
+	min, max *float64 // 数字的取值范围，或字符串、切片的长度范围
+	length   *int     // 字符串或切片的长度
+	format   string   // 字符串格式，如 email、uuid
+	elem     string   // dive 之后作用于元素的规则
+}
+
+// parseMockRules 解析 binding tag
+func parseMockRules(tag string) mockRules {
+	var rules mockRules
+	parts := strings.Split(tag, ",")
+	for i, rule := range parts {
+		name, param, _ := strings.Cut(rule, "=")
+		n, err := strconv.ParseFloat(param, 64)
+		switch name {
+		case "dive":
+			rules.elem = strings.Join(parts[i+1:], ",")
+			return rules
+		case "oneof":
+			rules.enum = strings.Fields(param)
+		case "len":
+			if err == nil {
+				length := int(n)
+				rules.length = &length
+			}
+		case "min", "gte":
+			if err == nil {
+				rules.min = &n
+			}
+		case "gt":
+			if err == nil {
+				n = math.Nextafter(n, math.Inf(1))
+				rules.min = &n
+			}
+		case "max", "lte":
+			if err == nil {
+				rules.max = &n
+			}
+		case "lt":
+			if err == nil {
+				n = math.Nextafter(n, math.Inf(-1))
+				rules.max = &n
+			}
+		case "email", "url", "uri", "uuid", "uuid4", "ipv4", "ipv6", "ip", "hostname", "datetime", "alpha", "alphanum", "numeric":
+			rules.format = name
+		}
+	}
+	return rules
+}
+
+// mockValue 为值生成模拟数据，visiting 中的结构体类型不再展开以支持递归类型
+func mockValue(v reflect.Value, rules mockRules, visiting map[reflect.Type]bool) {
+	t := v.Type()
+	switch t {
+	case timeType:
+		v.Set(reflect.ValueOf(time.Now().Add(-time.Duration(rand.IntN(30*24)) * time.Hour).Truncate(time.Second)))
+		return
+	case durationType:
+		v.SetInt(int64(time.Duration(mockInt(1, 3600)) * time.Second))
+		return
+	case rawMessageType:
+		return // 任意 JSON，无法推断结构
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		if t.Elem().Kind() == reflect.Struct && visiting[t.Elem()] {
+			return
+		}
+		elem := reflect.New(t.Elem())
+		mockValue(elem.Elem(), rules, visiting)
+		v.Set(elem)
+	case reflect.Struct:
+		if visiting[t] {
+			return
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if !field.IsExported() || field.Tag.Get("json") == "-" {
+				continue
+			}
+			if tag, ok := field.Tag.Lookup(ExampleTag); ok {
+				if example, ok := parseExample(field.Type, tag); ok {
+					v.Field(i).Set(example)
+					continue
+				}
+			}
+			mockValue(v.Field(i), parseMockRules(field.Tag.Get("binding")), visiting)
+		}
+	case reflect.Slice, reflect.Array:
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			v.SetBytes([]byte(mockString(mockRules{}, 8)))
+			return
+		}
+		if t.Elem().Kind() == reflect.Struct && visiting[t.Elem()] {
+			return
+		}
+		n := v.Len()
+		if t.Kind() == reflect.Slice {
+			n = mockLength(rules, 1, 3)
+			v.Set(reflect.MakeSlice(t, n, n))
+		}
+		elemRules := parseMockRules(rules.elem)
+		for i := 0; i < n; i++ {
+			mockValue(v.Index(i), elemRules, visiting)
+		}
+	case reflect.Map:
+		m := reflect.MakeMapWithSize(t, 2)
+		for i := 0; i < 2; i++ {
+			key := reflect.New(t.Key()).Elem()
+			mockValue(key, mockRules{}, visiting)
+			elem := reflect.New(t.Elem()).Elem()
+			mockValue(elem, mockRules{}, visiting)
+			m.SetMapIndex(key, elem)
+		}
+		v.Set(m)
+	case reflect.String:
+		if len(rules.enum) > 0 {
+			v.SetString(rules.enum[rand.IntN(len(rules.enum))])
+			return
+		}
+		v.SetString(mockString(rules, mockLength(rules, 6, 12)))
+	case reflect.Bool:
+		v.SetBool(rand.IntN(2) == 1)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		lo, hi := mockRange(rules, 1, 1000, float64(minInt(t)), float64(maxInt(t)))
+		if !setMockEnum(v, rules.enum) {
+			v.SetInt(mockInt(int64(math.Ceil(lo)), int64(math.Floor(hi))))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		lo, hi := mockRange(rules, 1, 255, 0, float64(maxUint(t)))
+		if !setMockEnum(v, rules.enum) {
+			v.SetUint(uint64(mockInt(int64(math.Ceil(lo)), int64(math.Floor(hi)))))
+		}
+	case reflect.Float32, reflect.Float64:
+		lo, hi := mockRange(rules, 0, 100, -math.MaxFloat32, math.MaxFloat32)
+		if !setMockEnum(v, rules.enum) {
+			f := lo + rand.Float64()*(hi-lo)
+			if rounded := math.Round(f*100) / 100; rounded >= lo && rounded <= hi {
+				f = rounded // 保留两位小数，超出取值范围时不舍入
+			}
+			v.SetFloat(f)
+		}
+	}
+}
+
+// setMockEnum 从枚举中随机选取数字，没有可用的枚举值时返回 false
+func setMockEnum(v reflect.Value, enum []string) bool {
+	if len(enum) == 0 {
+		return false
+	}
+	return setScalar(v, enum[rand.IntN(len(enum))])
+}
+
+// mockRange 返回数字的取值范围，未设置的一侧根据另一侧和默认范围推算
+func mockRange(rules mockRules, defaultMin, defaultMax, lower, upper float64) (float64, float64) {
+	lo, hi := defaultMin, defaultMax
+	switch {
+	case rules.min != nil && rules.max != nil:
+		lo, hi = *rules.min, *rules.max
+	case rules.min != nil:
+		lo, hi = *rules.min, *rules.min+defaultMax-defaultMin
+	case rules.max != nil:
+		lo, hi = math.Min(defaultMin, *rules.max), *rules.max
+	}
+	return math.Max(lo, lower), math.Min(hi, upper)
+}
+
+// mockLength 返回字符串或切片的长度
+func mockLength(rules mockRules, defaultMin, defaultMax int) int {
+	if rules.length != nil {
+		return *rules.length
+	}
+	lo, hi := mockRange(rules, float64(defaultMin), float64(defaultMax), 0, math.MaxInt32)
+	return int(mockInt(int64(math.Ceil(lo)), int64(math.Floor(hi))))
+}
+
+// mockInt 返回 [lo, hi] 内的随机整数
+func mockInt(lo, hi int64) int64 {
+	if hi <= lo {
+		return lo
+	}
+	return lo + rand.Int64N(hi-lo+1)
+}
+
+// mockString 按格式生成字符串，n 为没有格式时的长度
+func mockString(rules mockRules, n int) string {
+	switch rules.format {
+	case "email":
+		return "user" + strconv.Itoa(rand.IntN(10000)) + "@example.com"
+	case "url", "uri":
+		return "https://example.com/" + mockLetters(6)
+	case "uuid", "uuid4":
+		b := make([]byte, 16)
+		for i := range b {
+			b[i] = byte(rand.IntN(256))
+		}
+		b[6] = b[6]&0x0f | 0x40
+		b[8] = b[8]&0x3f | 0x80
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+	case "ipv4", "ip":
+		return "192.0.2." + strconv.Itoa(1+rand.IntN(254))
+	case "ipv6":
+		return "2001:db8::" + strconv.FormatInt(int64(1+rand.IntN(0xfffe)), 16)
+	case "hostname":
+		return mockLetters(6) + ".example.com"
+	case "datetime":
+		return time.Now().Format(time.RFC3339)
+	case "numeric":
+		digits := make([]byte, max(n, 1))
+		for i := range digits {
+			digits[i] = byte('0' + rand.IntN(10))
+		}
+		return string(digits)
+	}
+	return mockLetters(n)
+}
+
+// mockLetters 生成小写字母组成的字符串
+func mockLetters(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte('a' + rand.IntN(26))
+	}
+	return string(b)
+}
+
+// minInt 返回有符号整数类型的最小值
+func minInt(t reflect.Type) int64 {
+	return -1 << (t.Bits() - 1)
+}
+
+// maxInt 返回有符号整数类型的最大值
+func maxInt(t reflect.Type) int64 {
+	return 1<<(t.Bits()-1) - 1
+}
+
+// maxUint 返回无符号整数类型的最大值，超过 int64 时按 int64 计算
+func maxUint(t reflect.Type) uint64 {
+	if t.Bits() >= 64 {
+		return math.MaxInt64
+	}
+	return 1<<t.Bits() - 1
+}
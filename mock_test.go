@@ -0,0 +1,135 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// mockNode 测试用的递归类型
+type mockNode struct {
+	Name     string      `json:"name" binding:"len=4"`
+	Children []*mockNode `json:"children"`
+}
+
+// mockUser 测试用的用户
+type mockUser struct {
+	ID        int64            `json:"id" example:"42"`
+	Name      string           `json:"name" binding:"min=2,max=5"`
+	Email     string           `json:"email" binding:"email"`
+	UUID      string           `json:"uuid" binding:"uuid"`
+	Role      string           `json:"role" binding:"oneof=admin member"`
+	Level     int              `json:"level" binding:"oneof=1 2 3"`
+	Age       int              `json:"age" binding:"gte=18,lt=20"`
+	Score     float64          `json:"score" binding:"gt=0,lte=1"`
+	Tags      []string         `json:"tags" binding:"min=2,max=2,dive,oneof=a b"`
+	CreatedAt time.Time        `json:"created_at"`
+	Extra     map[string]int   `json:"extra"`
+	Node      mockNode         `json:"node"`
+	Ignored   string           `json:"-"`
+	Settings  *json.RawMessage `json:"settings,omitempty"`
+	internal  string
+}
+
+// 测试生成符合约束的模拟数据
+func TestMockFor(t *testing.T) {
+	uuidPattern := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	for i := 0; i < 50; i++ {
+		user := MockFor[mockUser]()
+		if user.ID != 42 {
+			t.Fatalf("期望使用示例值, 实际得到 %d", user.ID)
+		}
+		if len(user.Name) < 2 || len(user.Name) > 5 {
+			t.Fatalf("期望字符串长度在 2 到 5 之间, 实际得到 %q", user.Name)
+		}
+		if _, err := mail.ParseAddress(user.Email); err != nil {
+			t.Fatalf("期望生成邮箱地址, 实际得到 %q", user.Email)
+		}
+		if !uuidPattern.MatchString(user.UUID) {
+			t.Fatalf("期望生成 UUID, 实际得到 %q", user.UUID)
+		}
+		if !slices.Contains([]string{"admin", "member"}, user.Role) || user.Level < 1 || user.Level > 3 {
+			t.Fatalf("期望从枚举中选取, 实际得到 %q %d", user.Role, user.Level)
+		}
+		if user.Age < 18 || user.Age >= 20 || user.Score <= 0 || user.Score > 1 {
+			t.Fatalf("期望数字在取值范围内, 实际得到 %d %v", user.Age, user.Score)
+		}
+		if len(user.Tags) != 2 || !slices.Contains([]string{"a", "b"}, user.Tags[0]) {
+			t.Fatalf("期望切片长度和元素符合约束, 实际得到 %v", user.Tags)
+		}
+		if user.CreatedAt.IsZero() || len(user.Extra) == 0 || len(user.Node.Name) != 4 {
+			t.Fatalf("期望时间、映射和嵌套结构体有值, 实际得到 %+v", user)
+		}
+		if len(user.Node.Children) == 0 || user.Node.Children[0] != nil {
+			t.Fatalf("期望递归类型不再展开, 实际得到 %+v", user.Node.Children)
+		}
+		if user.Ignored != "" || user.internal != "" {
+			t.Fatal("期望忽略 json:\"-\" 和未导出的字段")
+		}
+	}
+}
+
+// 测试模拟模式返回模拟数据且不调用业务函数
+func TestWithMock(t *testing.T) {
+	called := false
+	r := gin.New()
+	r.POST("/users", Handler(func(ctx context.Context, req *struct {
+		Name string `json:"name" binding:"required"`
+	}) (*mockUser, error) {
+		called = true
+		return nil, nil
+	}, WithMock()))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"tom"}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusOK || called {
+		t.Fatalf("期望返回模拟数据且不调用业务函数, 实际得到状态码 %d, 调用 %v", w.Code, called)
+	}
+	var resp struct {
+		Data mockUser `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || resp.Data.ID != 42 {
+		t.Errorf("期望响应包含模拟数据, 实际得到 %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("期望模拟模式仍然验证请求, 实际得到状态码 %d", w.Code)
+	}
+}
+
+// 测试列表处理器的模拟模式
+func TestWithMockList(t *testing.T) {
+	r := gin.New()
+	r.GET("/users", ListHandler(func(ctx context.Context, req *struct{}) ([]mockUser, int64, error) {
+		t.Fatal("期望模拟模式不调用业务函数")
+		return nil, 0, nil
+	}, WithMock()))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users", nil))
+	var resp struct {
+		Data []mockUser `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil || len(resp.Data) == 0 {
+		t.Fatalf("期望返回模拟的列表数据, 实际得到 %s", w.Body.String())
+	}
+	if total := w.Header().Get("X-Total-Count"); total != strconv.Itoa(len(resp.Data)) {
+		t.Errorf("期望 X-Total-Count 为 %d, 实际得到 %q", len(resp.Data), total)
+	}
+}
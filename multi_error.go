@@ -0,0 +1,146 @@
+package apihandler
+
+import (
+	"net/http"
+	"sync"
+)
+
+// ItemError MultiError 中单个错误的详情
+type ItemError struct {
+	Index   *int   `json:"index,omitempty" xml:"index,omitempty"` // 批量处理中出错的元素下标
+	Code    any    `json:"code" xml:"code"`
+	Message string `json:"message" xml:"message"`
+	Errors  []any  `json:"errors,omitempty" xml:"-"`
+}
+
+// MultiError 汇总多个业务错误的业务错误，可并发使用
+//
+// 适用于批量接口：逐个处理元素时收集失败的元素，最后一次性返回，每个错误作为一条详情输出在 errors 中：
+//
+//	multi := NewMultiError(40000, "部分用户创建失败", 0)
+//	for i, user := range req.Users {
+//		if err := create(ctx, user); err != nil {
+//			multi.AddAt(i, err)
+//		}
+//	}
+//	return resp, multi.ErrorOrNil()
+type MultiError struct {
+	code     any
+	message  string
+	httpCode int
+
+	mu    sync.Mutex
+	items []multiErrorItem
+}
+
+// multiErrorItem 收集的业务错误
+type multiErrorItem struct {
+	index *int
+	err   BizError
+}
+
+// NewMultiError 创建汇总多个业务错误的业务错误，code 和 message 为汇总的错误码和消息
+//
+// httpCode 为 0 时根据收集的错误确定：所有错误的 HTTP 状态码相同时使用该状态码，
+// 存在 5xx 错误时为 500，否则为 400。
+func NewMultiError(code any, message string, httpCode int) *MultiError {
+	return &MultiError{code: code, message: message, httpCode: httpCode}
+}
+
+// Add 添加一个业务错误，err 为 nil 时忽略
+func (e *MultiError) Add(err BizError) {
+	e.add(nil, err)
+}
+
+// AddAt 添加批量处理中第 index 个元素的业务错误，err 为 nil 时忽略
+func (e *MultiError) AddAt(index int, err BizError) {
+	e.add(&index, err)
+}
+
+// add 添加业务错误
+func (e *MultiError) add(index *int, err BizError) {
+	if err == nil {
+		return
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.items = append(e.items, multiErrorItem{index: index, err: err})
+}
+
+// Len 返回收集的业务错误数量
+func (e *MultiError) Len() int {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return len(e.items)
+}
+
+// ErrorOrNil 没有收集到业务错误时返回 nil，否则返回 MultiError 本身
+func (e *MultiError) ErrorOrNil() error {
+	if e.Len() == 0 {
+		return nil
+	}
+	return e
+}
+
+// Error 实现 error 接口
+func (e *MultiError) Error() string {
+	return e.message
+}
+
+// Code 返回汇总的业务错误码
+func (e *MultiError) Code() any {
+	return e.code
+}
+
+// HTTPCode 返回 HTTP 状态码
+func (e *MultiError) HTTPCode() int {
+	if e.httpCode != 0 {
+		return e.httpCode
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	httpCode := 0
+	for _, item := range e.items {
+		code := item.err.HTTPCode()
+		switch {
+		case code >= http.StatusInternalServerError:
+			return http.StatusInternalServerError
+		case httpCode == 0:
+			httpCode = code
+		case httpCode != code:
+			httpCode = http.StatusBadRequest
+		}
+	}
+	if httpCode == 0 {
+		return http.StatusBadRequest
+	}
+	return httpCode
+}
+
+// Errors 返回每个业务错误的详情
+func (e *MultiError) Errors() []any {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	details := make([]any, len(e.items))
+	for i, item := range e.items {
+		details[i] = ItemError{
+			Index:   item.index,
+			Code:    item.err.Code(),
+			Message: item.err.Error(),
+			Errors:  item.err.Errors(),
+		}
+	}
+	return details
+}
+
+// Unwrap 返回收集的业务错误，支持 errors.Is/errors.As
+func (e *MultiError) Unwrap() []error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	errs := make([]error, len(e.items))
+	for i, item := range e.items {
+		errs[i] = item.err
+	}
+	return errs
+}
@@ -0,0 +1,106 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试批量错误响应
+func TestMultiErrorResponse(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		multi := NewMultiError(40000, "部分用户创建失败", 0)
+		multi.AddAt(1, ErrConflict(40900, "邮箱已被注册"))
+		multi.Add(nil)
+		multi.AddAt(3, ErrConflict(40901, "用户名已被注册"))
+		return nil, multi.ErrorOrNil()
+	}
+	r.GET("/test/:id", Handler(handleFunc))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/test/1", nil))
+
+	if w.Code != http.StatusConflict {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusConflict, w.Code)
+	}
+
+	var resp struct {
+		Code   int         `json:"code"`
+		Errors []ItemError `json:"errors"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	if resp.Code != 40000 || len(resp.Errors) != 2 {
+		t.Fatalf("期望汇总错误码 40000 和 2 条详情, 实际得到 %s", w.Body.String())
+	}
+	if item := resp.Errors[1]; item.Index == nil || *item.Index != 3 || item.Message != "用户名已被注册" {
+		t.Errorf("期望第 3 个元素的错误, 实际得到 %+v", item)
+	}
+}
+
+// 测试批量错误的 HTTP 状态码
+func TestMultiErrorHTTPCode(t *testing.T) {
+	tests := []struct {
+		name     string
+		httpCode int
+		errs     []BizError
+		expected int
+	}{
+		{"指定状态码", http.StatusUnprocessableEntity, []BizError{ErrNotFound(40400, "a")}, http.StatusUnprocessableEntity},
+		{"状态码相同", 0, []BizError{ErrNotFound(40400, "a"), ErrNotFound(40401, "b")}, http.StatusNotFound},
+		{"状态码不同", 0, []BizError{ErrNotFound(40400, "a"), ErrConflict(40900, "b")}, http.StatusBadRequest},
+		{"包含 5xx", 0, []BizError{ErrNotFound(40400, "a"), ErrInternalServer(50000, "b")}, http.StatusInternalServerError},
+		{"没有错误", 0, nil, http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			multi := NewMultiError(40000, "批量处理失败", tt.httpCode)
+			for _, err := range tt.errs {
+				multi.Add(err)
+			}
+			if got := multi.HTTPCode(); got != tt.expected {
+				t.Errorf("期望状态码 %d, 实际得到 %d", tt.expected, got)
+			}
+		})
+	}
+}
+
+// 测试并发收集错误和错误链
+func TestMultiErrorConcurrent(t *testing.T) {
+	multi := NewMultiError(40000, "批量处理失败", 0)
+	if multi.ErrorOrNil() != nil {
+		t.Errorf("期望没有错误时 ErrorOrNil 返回 nil")
+	}
+
+	target := ErrNotFound(40400, "资源不存在")
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if i == 5 {
+				multi.AddAt(i, target)
+				return
+			}
+			multi.AddAt(i, ErrBadRequest(40000, "参数错误"))
+		}(i)
+	}
+	wg.Wait()
+
+	if multi.Len() != 10 {
+		t.Errorf("期望收集 10 个错误, 实际得到 %d", multi.Len())
+	}
+	if !errors.Is(multi, target) {
+		t.Errorf("期望 errors.Is 能找到收集的业务错误")
+	}
+}
@@ -0,0 +1,70 @@
+package apihandler
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestObserver 请求观察者接口，用于对接监控指标、访问日志等，不影响响应
+type RequestObserver interface {
+	// ObserveStart 在处理器开始处理请求时调用
+	ObserveStart(c *gin.Context)
+	// ObserveEnd 在响应输出后调用
+	ObserveEnd(c *gin.Context, info RequestInfo)
+}
+
+// RequestInfo 请求处理结果
+type RequestInfo struct {
+	Method   string        // 请求方法
+	Path     string        // 请求路径
+	Route    string        // 路由模板，如 /users/:id
+	HTTPCode int           // HTTP 状态码
+	Code     any           // 业务代码，成功响应为成功代码，缓存命中或 Responder 自行输出的响应为 nil
+	Err      BizError      // 错误响应的业务错误，成功时为 nil
+	Latency  time.Duration // 处理耗时
+	Size     int           // 响应体字节数
+	Request  any           // 绑定后的请求对象，绑定前结束的请求（如限流、缓存命中）为 nil
+}
+
+// responseCodeContextKey 保存响应业务代码的 gin.Context 键
+const responseCodeContextKey = "apihandler.responseCode"
+
+// requestContextKey 保存绑定后的请求对象的 gin.Context 键
+const requestContextKey = "apihandler.request"
+
+// responseErrorContextKey 保存错误响应业务错误的 gin.Context 键
+const responseErrorContextKey = "apihandler.responseError"
+
+// WithObserver 添加请求观察者，可以添加多个，按添加顺序调用
+func WithObserver(observers ...RequestObserver) Option {
+	return func(c *HandlerConfig) {
+		c.Observers = append(c.Observers[:len(c.Observers):len(c.Observers)], observers...)
+	}
+}
+
+// observeStart 通知观察者请求开始，返回请求结束时调用的函数
+func observeStart(c *gin.Context, config *HandlerConfig) func() {
+	start := time.Now()
+	for _, observer := range config.Observers {
+		observer.ObserveStart(c)
+	}
+	return func() {
+		info := RequestInfo{
+			Method:   c.Request.Method,
+			Path:     c.Request.URL.Path,
+			Route:    c.FullPath(),
+			HTTPCode: c.Writer.Status(),
+			Latency:  time.Since(start),
+			Size:     max(c.Writer.Size(), 0),
+		}
+		info.Code, _ = c.Get(responseCodeContextKey)
+		info.Request, _ = c.Get(requestContextKey)
+		if err, ok := c.Get(responseErrorContextKey); ok {
+			info.Err, _ = err.(BizError)
+		}
+		for _, observer := range config.Observers {
+			observer.ObserveEnd(c, info)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package apihandler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recordingObserver 测试用的请求观察者
+type recordingObserver struct {
+	started int
+	infos   []RequestInfo
+}
+
+func (o *recordingObserver) ObserveStart(c *gin.Context) {
+	o.started++
+}
+
+func (o *recordingObserver) ObserveEnd(c *gin.Context, info RequestInfo) {
+	o.infos = append(o.infos, info)
+}
+
+// 测试请求观察者收到请求处理结果
+func TestObserver(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		if req.ID == 0 {
+			return nil, ErrNotFound(40400, "用户不存在")
+		}
+		return &testResponse{ID: req.ID}, nil
+	}
+	observer := &recordingObserver{}
+	r.GET("/users/:id", Handler(handleFunc, WithSuccessCode(1), WithObserver(observer)))
+
+	for _, path := range []string{"/users/1", "/users/0"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", path, nil))
+	}
+
+	if observer.started != 2 || len(observer.infos) != 2 {
+		t.Fatalf("期望观察者收到 2 个请求, 实际开始 %d 次, 结束 %d 次", observer.started, len(observer.infos))
+	}
+
+	success := observer.infos[0]
+	if success.Method != "GET" || success.Path != "/users/1" || success.Route != "/users/:id" {
+		t.Errorf("期望记录请求方法、路径和路由模板, 实际得到 %+v", success)
+	}
+	if success.HTTPCode != http.StatusOK || success.Code != 1 || success.Err != nil {
+		t.Errorf("期望成功响应的状态码 200 和成功代码 1, 实际得到 %+v", success)
+	}
+	if success.Size == 0 || success.Latency <= 0 {
+		t.Errorf("期望记录响应大小和耗时, 实际得到 %+v", success)
+	}
+
+	failure := observer.infos[1]
+	if failure.HTTPCode != http.StatusNotFound || failure.Code != 40400 || failure.Err == nil {
+		t.Errorf("期望错误响应的状态码 404 和业务错误码 40400, 实际得到 %+v", failure)
+	}
+}
@@ -0,0 +1,440 @@
+package apihandler
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// OpenAPIVersion 生成的 OpenAPI 文档版本
+const OpenAPIVersion = "3.1.0"
+
+// openAPISchemaPrefix OpenAPI 文档中 Schema 引用的前缀
+const openAPISchemaPrefix = "#/components/schemas/"
+
+// OpenAPI OpenAPI 3.1 文档
+type OpenAPI struct {
+	OpenAPI    string                      `json:"openapi"`
+	Info       OpenAPIInfo                 `json:"info"`
+	Servers    []OpenAPIServer             `json:"servers,omitempty"`
+	Paths      map[string]*OpenAPIPathItem `json:"paths"`
+	Components OpenAPIComponents           `json:"components"`
+}
+
+// OpenAPIInfo 文档信息
+type OpenAPIInfo struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// OpenAPIServer 服务地址
+type OpenAPIServer struct {
+	URL         string `json:"url"`
+	Description string `json:"description,omitempty"`
+}
+
+// OpenAPIPathItem 路径下各 HTTP 方法的操作
+type OpenAPIPathItem struct {
+	Get     *OpenAPIOperation `json:"get,omitempty"`
+	Put     *OpenAPIOperation `json:"put,omitempty"`
+	Post    *OpenAPIOperation `json:"post,omitempty"`
+	Delete  *OpenAPIOperation `json:"delete,omitempty"`
+	Options *OpenAPIOperation `json:"options,omitempty"`
+	Head    *OpenAPIOperation `json:"head,omitempty"`
+	Patch   *OpenAPIOperation `json:"patch,omitempty"`
+}
+
+// OpenAPIOperation 操作，对应一个处理器
+type OpenAPIOperation struct {
+	OperationID string                      `json:"operationId,omitempty"`
+	Summary     string                      `json:"summary,omitempty"`
+	Description string                      `json:"description,omitempty"`
+	Tags        []string                    `json:"tags,omitempty"`
+	Deprecated  bool                        `json:"deprecated,omitempty"`
+	APIVersion  int                         `json:"x-api-version,omitempty"`
+	Parameters  []*OpenAPIParameter         `json:"parameters,omitempty"`
+	RequestBody *OpenAPIRequestBody         `json:"requestBody,omitempty"`
+	Responses   map[string]*OpenAPIResponse `json:"responses"`
+}
+
+// OpenAPIParameter 路径、查询或请求头参数
+type OpenAPIParameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+	Example     any     `json:"example,omitempty"`
+}
+
+// OpenAPIRequestBody 请求体
+type OpenAPIRequestBody struct {
+	Required bool                         `json:"required,omitempty"`
+	Content  map[string]*OpenAPIMediaType `json:"content"`
+}
+
+// OpenAPIResponse 响应
+type OpenAPIResponse struct {
+	Description string                       `json:"description"`
+	Content     map[string]*OpenAPIMediaType `json:"content,omitempty"`
+}
+
+// OpenAPIMediaType 请求体或响应的内容
+type OpenAPIMediaType struct {
+	Schema  *Schema `json:"schema"`
+	Example any     `json:"example,omitempty"`
+}
+
+// OpenAPIComponents 可复用的组件
+type OpenAPIComponents struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// OpenAPI 根据注册表中的路由生成 OpenAPI 文档
+func (r *Registry) OpenAPI(info OpenAPIInfo) *OpenAPI {
+	return GenerateOpenAPI(info, r.Routes())
+}
+
+// GenerateOpenAPI 根据路由生成 OpenAPI 3.1 文档
+//
+// 路径参数来自 path 和 uri tag，请求头参数来自 header tag；GET、DELETE 等没有请求体的方法中，
+// form tag 为查询参数，其他方法的 json 字段为 JSON 请求体，没有 json 字段时 form 字段为表单请求体。
+// binding tag 中的 required、min、max、oneof 等规则转换为 Schema 约束，claim tag 的字段不出现在文档中。
+// 成功响应和错误响应使用默认的响应封装格式。
+func GenerateOpenAPI(info OpenAPIInfo, routes []RouteInfo) *OpenAPI {
+	g := newSchemaGenerator(openAPISchemaPrefix)
+	doc := &OpenAPI{
+		OpenAPI: OpenAPIVersion,
+		Info:    info,
+		Paths:   make(map[string]*OpenAPIPathItem),
+	}
+	errorResponse := errorResponseSchema(g)
+
+	for _, route := range routes {
+		path := openAPIPath(route.Path)
+		item := doc.Paths[path]
+		if item == nil {
+			item = &OpenAPIPathItem{}
+			doc.Paths[path] = item
+		}
+		item.set(route.Method, newOperation(g, route, errorResponse))
+	}
+
+	doc.Components.Schemas = g.schemas
+	return doc
+}
+
+// set 设置 HTTP 方法对应的操作
+func (p *OpenAPIPathItem) set(method string, op *OpenAPIOperation) {
+	switch method {
+	case http.MethodGet:
+		p.Get = op
+	case http.MethodPut:
+		p.Put = op
+	case http.MethodPost:
+		p.Post = op
+	case http.MethodDelete:
+		p.Delete = op
+	case http.MethodOptions:
+		p.Options = op
+	case http.MethodHead:
+		p.Head = op
+	case http.MethodPatch:
+		p.Patch = op
+	}
+}
+
+// openAPIPath 将 gin 的路由路径转换为 OpenAPI 路径，如 /users/:id 为 /users/{id}
+func openAPIPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
+
+// newOperation 生成路由的操作，声明的业务错误按 HTTP 状态码合并为错误响应
+func newOperation(g *schemaGenerator, route RouteInfo, errorResponse *Schema) *OpenAPIOperation {
+	op := &OpenAPIOperation{
+		OperationID: route.Meta.Name,
+		Summary:     route.Meta.Summary,
+		Description: route.Meta.Description,
+		Tags:        route.Meta.Tags,
+		Responses:   make(map[string]*OpenAPIResponse),
+		APIVersion:  route.APIVersion,
+	}
+	if route.Deprecation != nil {
+		op.Deprecated = true
+		if note := deprecationNote(route.Deprecation); note != "" {
+			op.Description = strings.TrimSpace(op.Description + "\n\n" + note)
+		}
+	}
+
+	hasInput := false
+	if route.Request != nil {
+		op.Parameters = requestParameters(g, route.Request, route.Method)
+		op.RequestBody = requestBody(g, route.Request, route.Method)
+		hasInput = len(op.Parameters) > 0 || op.RequestBody != nil
+	}
+
+	status := route.SuccessHTTPCode
+	if status == 0 {
+		status = http.StatusOK
+	}
+	if op.RequestBody != nil {
+		example := bodyExample(route.Request, route.Method)
+		for _, media := range op.RequestBody.Content {
+			media.Example = example
+		}
+	}
+
+	success := jsonContent(successResponseSchema(g, route.Response))
+	if data, ok := exampleOf(route.Response); ok {
+		example := map[string]any{"code": route.SuccessCode, "data": data}
+		if _, ok := reflect.New(route.Response).Interface().(DataProvider); ok {
+			// 与处理器的输出一致，示例中的 data 和 meta 由响应的方法返回
+			resp := reflect.New(route.Response)
+			resp.Elem().Set(reflect.ValueOf(data))
+			example["data"], example["meta"] = splitMeta(resp.Interface())
+		}
+		success["application/json"].Example = example
+	}
+	op.Responses[strconv.Itoa(status)] = &OpenAPIResponse{
+		Description: http.StatusText(status),
+		Content:     success,
+	}
+	if hasInput {
+		op.Responses[strconv.Itoa(http.StatusBadRequest)] = &OpenAPIResponse{
+			Description: "参数绑定或验证失败",
+			Content:     jsonContent(errorResponse),
+		}
+	}
+	for _, e := range route.Errors {
+		if e.HTTPCode == status {
+			continue
+		}
+		key := strconv.Itoa(e.HTTPCode)
+		line := fmt.Sprintf("%v: %s", e.Code, e.Message)
+		if resp, ok := op.Responses[key]; ok {
+			resp.Description += "\n" + line
+			continue
+		}
+		op.Responses[key] = &OpenAPIResponse{Description: line, Content: jsonContent(errorResponse)}
+	}
+	op.Responses["default"] = &OpenAPIResponse{
+		Description: "错误响应",
+		Content:     jsonContent(errorResponse),
+	}
+	return op
+}
+
+// jsonContent 返回 application/json 的内容
+func jsonContent(schema *Schema) map[string]*OpenAPIMediaType {
+	return map[string]*OpenAPIMediaType{"application/json": {Schema: schema}}
+}
+
+// hasRequestBody 判断 HTTP 方法是否有请求体
+func hasRequestBody(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodDelete, http.MethodOptions:
+		return false
+	}
+	return true
+}
+
+// requestFields 返回请求结构的所有字段，匿名嵌入的结构体字段展开
+func requestFields(t reflect.Type) []reflect.StructField {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []reflect.StructField
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && fieldType.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			fields = append(fields, requestFields(fieldType)...)
+			continue
+		}
+		if field.IsExported() && field.Tag.Get(ClaimTag) == "" {
+			fields = append(fields, field)
+		}
+	}
+	return fields
+}
+
+// tagName 返回 tag 中的名称，忽略 tag 的选项
+func tagName(field reflect.StructField, key string) string {
+	name, _, _ := strings.Cut(field.Tag.Get(key), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// requestParameters 生成请求结构的路径、请求头和查询参数
+func requestParameters(g *schemaGenerator, t reflect.Type, method string) []*OpenAPIParameter {
+	var params []*OpenAPIParameter
+	for _, field := range requestFields(t) {
+		param := &OpenAPIParameter{}
+		switch {
+		case tagName(field, PathTag) != "":
+			param.Name, param.In = tagName(field, PathTag), "path"
+		case tagName(field, "uri") != "":
+			param.Name, param.In = tagName(field, "uri"), "path"
+		case tagName(field, "header") != "":
+			param.Name, param.In = tagName(field, "header"), "header"
+		case tagName(field, "form") != "" && !hasRequestBody(method):
+			param.Name, param.In = tagName(field, "form"), "query"
+		default:
+			continue
+		}
+		param.Schema = g.schema(field.Type)
+		param.Required = applyBindingRules(param.Schema, field) || param.In == "path"
+		param.Example, _ = fieldExample(field)
+		param.Description = field.Tag.Get(DocTag)
+		params = append(params, param)
+	}
+	return params
+}
+
+// requestBody 生成请求体，没有请求体字段时返回 nil
+func requestBody(g *schemaGenerator, t reflect.Type, method string) *OpenAPIRequestBody {
+	if !hasRequestBody(method) {
+		return nil
+	}
+
+	var jsonFields, formFields bool
+	for _, field := range requestFields(t) {
+		if isParameterField(field) {
+			continue
+		}
+		if field.Tag.Get("json") != "" {
+			jsonFields = true
+		} else if tagName(field, "form") != "" {
+			formFields = true
+		}
+	}
+
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case jsonFields:
+		schema := g.structSchema(t, func(field reflect.StructField) bool {
+			if isParameterField(field) || field.Tag.Get(ClaimTag) != "" {
+				return false
+			}
+			return field.Tag.Get("json") != "" || tagName(field, "form") == ""
+		})
+		return &OpenAPIRequestBody{Required: true, Content: jsonContent(schema)}
+	case formFields:
+		schema := g.structSchema(t, func(field reflect.StructField) bool {
+			return !isParameterField(field) && tagName(field, "form") != ""
+		})
+		renameFormProperties(schema, t)
+		return &OpenAPIRequestBody{
+			Required: true,
+			Content:  map[string]*OpenAPIMediaType{"application/x-www-form-urlencoded": {Schema: schema}},
+		}
+	}
+	return nil
+}
+
+// isParameterField 判断字段是否为路径或请求头参数
+func isParameterField(field reflect.StructField) bool {
+	return tagName(field, PathTag) != "" || tagName(field, "uri") != "" || tagName(field, "header") != ""
+}
+
+// renameFormProperties 将表单请求体 Schema 的属性名从 JSON 名称改为 form tag 的名称
+func renameFormProperties(schema *Schema, t reflect.Type) {
+	renamed := make(map[string]*Schema, len(schema.Properties))
+	names := make(map[string]string)
+	for _, field := range requestFields(t) {
+		jsonName, _, ok := jsonFieldName(field)
+		if !ok {
+			jsonName = field.Name
+		}
+		if form := tagName(field, "form"); form != "" {
+			names[jsonName] = form
+		}
+	}
+	for name, property := range schema.Properties {
+		if form, ok := names[name]; ok {
+			name = form
+		}
+		renamed[name] = property
+	}
+	for i, name := range schema.Required {
+		if form, ok := names[name]; ok {
+			schema.Required[i] = form
+		}
+	}
+	schema.Properties = renamed
+}
+
+// successResponseSchema 生成默认响应封装的成功响应 Schema
+func successResponseSchema(g *schemaGenerator, response reflect.Type) *Schema {
+	schema := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"code":       {Description: "业务代码"},
+			"data":       {},
+			"meta":       {Description: "列表元数据，如分页信息"},
+			"links":      {Type: "object", AdditionalProperties: g.schema(reflect.TypeFor[Link]())},
+			"request_id": {Type: "string"},
+		},
+		Required: []string{"code", "data"},
+	}
+	data, meta := responseParts(response)
+	if data != nil {
+		schema.Properties["data"] = g.schema(data)
+	}
+	if meta != nil {
+		schema.Properties["meta"] = g.schema(meta)
+		schema.Required = append(schema.Required, "meta")
+	}
+	return schema
+}
+
+// responseParts 返回响应类型输出为 data 和 meta 的类型，实现了 DataProvider 或 MetaProvider 时按零值的返回值确定
+func responseParts(response reflect.Type) (data, meta reflect.Type) {
+	if response == nil {
+		return nil, nil
+	}
+	data = response
+	resp := reflect.New(response).Interface()
+	if p, ok := resp.(DataProvider); ok {
+		data = reflect.TypeOf(p.Data())
+	}
+	if p, ok := resp.(MetaProvider); ok {
+		meta = reflect.TypeOf(p.Meta())
+	}
+	return data, meta
+}
+
+// errorResponseSchema 生成默认响应封装的错误响应 Schema，保存到组件中
+func errorResponseSchema(g *schemaGenerator) *Schema {
+	g.schemas["ErrorResponse"] = &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"code":       {Description: "业务错误码"},
+			"message":    {Type: "string"},
+			"errors":     {Type: "array", Items: g.schema(reflect.TypeFor[FieldError]())},
+			"request_id": {Type: "string"},
+		},
+		Required: []string{"code", "message"},
+	}
+	return &Schema{Ref: openAPISchemaPrefix + "ErrorResponse"}
+}
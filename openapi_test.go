@@ -0,0 +1,215 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// openAPIUser 测试用的用户
+type openAPIUser struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Tags      []string  `json:"tags,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// openAPIListRequest 测试用的列表请求
+type openAPIListRequest struct {
+	Page    int    `form:"page" binding:"omitempty,min=1"`
+	Status  string `form:"status" binding:"omitempty,oneof=active disabled"`
+	TraceID string `header:"X-Trace-Id"`
+}
+
+// openAPIUpdateRequest 测试用的更新请求
+type openAPIUpdateRequest struct {
+	ID       int64  `path:"id"`
+	Operator string `claim:"sub" json:"-"`
+	Name     string `json:"name" binding:"required,min=2,max=32"`
+	Email    string `json:"email,omitempty" binding:"omitempty,email"`
+	Age      int    `json:"age" binding:"gte=0,lte=150"`
+}
+
+// openAPIPage 测试用的泛型分页结果
+type openAPIPage[V any] struct {
+	Items []V `json:"items"`
+	Total int `json:"total"`
+}
+
+// 测试根据注册表生成 OpenAPI 文档
+func TestOpenAPI(t *testing.T) {
+	r := gin.New()
+	registry := NewRegistry()
+	api := NewGroup(r.Group("/api"), WithRegistry(registry))
+
+	list := func(ctx context.Context, req *openAPIListRequest) (*[]openAPIUser, error) { return nil, nil }
+	update := func(ctx context.Context, req *openAPIUpdateRequest) (*openAPIUser, error) { return nil, nil }
+	GET(api, "/users", list, WithMeta(Meta{Name: "listUsers", Tags: []string{"user"}, Summary: "用户列表"}))
+	PUT(api, "/users/:id", update, WithMeta(Meta{Name: "updateUser"}))
+	POST(api, "/users", update, WithSuccessHTTPCode(http.StatusCreated))
+
+	doc := registry.OpenAPI(OpenAPIInfo{Title: "User API", Version: "1.0.0"})
+	if doc.OpenAPI != OpenAPIVersion || doc.Info.Title != "User API" {
+		t.Errorf("期望文档版本和信息, 实际得到 %s %+v", doc.OpenAPI, doc.Info)
+	}
+
+	listOp := doc.Paths["/api/users"].Get
+	if listOp == nil || listOp.OperationID != "listUsers" || listOp.Summary != "用户列表" || !slices.Equal(listOp.Tags, []string{"user"}) {
+		t.Fatalf("期望 GET /api/users 使用处理器元数据, 实际得到 %+v", listOp)
+	}
+	params := map[string]*OpenAPIParameter{}
+	for _, p := range listOp.Parameters {
+		params[p.In+":"+p.Name] = p
+	}
+	if p := params["query:page"]; p == nil || p.Schema.Type != "integer" || p.Schema.Minimum == nil || *p.Schema.Minimum != 1 {
+		t.Errorf("期望 page 查询参数带有最小值约束, 实际得到 %+v", p)
+	}
+	if p := params["query:status"]; p == nil || !reflect.DeepEqual(p.Schema.Enum, []any{"active", "disabled"}) {
+		t.Errorf("期望 status 查询参数带有枚举约束, 实际得到 %+v", p)
+	}
+	if params["header:X-Trace-Id"] == nil {
+		t.Error("期望生成请求头参数")
+	}
+	if items := listOp.Responses["200"].Content["application/json"].Schema.Properties["data"]; items.Type != "array" || items.Items.Ref != "#/components/schemas/openAPIUser" {
+		t.Errorf("期望成功响应的 data 为用户数组, 实际得到 %+v", items)
+	}
+
+	updateOp := doc.Paths["/api/users/{id}"].Put
+	if updateOp == nil || len(updateOp.Parameters) != 1 || updateOp.Parameters[0].In != "path" || !updateOp.Parameters[0].Required {
+		t.Fatalf("期望 PUT /api/users/{id} 有必填的路径参数, 实际得到 %+v", updateOp)
+	}
+	body := updateOp.RequestBody.Content["application/json"].Schema
+	if _, ok := body.Properties["Operator"]; ok || body.Properties["id"] != nil || len(body.Properties) != 3 {
+		t.Errorf("期望请求体只包含 JSON 字段, 实际得到 %v", body.Properties)
+	}
+	if !slices.Equal(body.Required, []string{"name"}) {
+		t.Errorf("期望 name 为必填字段, 实际得到 %v", body.Required)
+	}
+	if name := body.Properties["name"]; *name.MinLength != 2 || *name.MaxLength != 32 {
+		t.Errorf("期望 name 带有长度约束, 实际得到 %+v", name)
+	}
+	if body.Properties["email"].Format != "email" || *body.Properties["age"].Maximum != 150 {
+		t.Errorf("期望 email 格式和 age 取值范围约束, 实际得到 %+v %+v", body.Properties["email"], body.Properties["age"])
+	}
+	for _, status := range []string{"200", "400", "default"} {
+		if updateOp.Responses[status] == nil {
+			t.Errorf("期望生成 %s 响应", status)
+		}
+	}
+
+	if createOp := doc.Paths["/api/users"].Post; createOp == nil || createOp.Responses["201"] == nil {
+		t.Errorf("期望 POST /api/users 的成功响应为 201, 实际得到 %+v", createOp)
+	}
+
+	user := doc.Components.Schemas["openAPIUser"]
+	if user == nil || user.Properties["created_at"].Format != "date-time" || user.Properties["tags"].Items.Type != "string" {
+		t.Errorf("期望生成用户组件, 实际得到 %+v", user)
+	}
+	if doc.Components.Schemas["ErrorResponse"] == nil || doc.Components.Schemas["FieldError"] == nil {
+		t.Error("期望生成错误响应组件")
+	}
+
+	if _, err := json.Marshal(doc); err != nil {
+		t.Errorf("期望文档可以序列化为 JSON, 实际得到 %v", err)
+	}
+}
+
+// 测试表单请求体和泛型类型名称
+func TestOpenAPIFormBodyAndGenericNames(t *testing.T) {
+	type loginRequest struct {
+		Username string `form:"username" binding:"required"`
+		Password string `form:"password" binding:"required"`
+	}
+	routes := []RouteInfo{{
+		Method:   http.MethodPost,
+		Path:     "/login",
+		Request:  reflect.TypeFor[loginRequest](),
+		Response: reflect.TypeFor[openAPIPage[openAPIUser]](),
+	}}
+	doc := GenerateOpenAPI(OpenAPIInfo{Title: "Auth", Version: "1"}, routes)
+
+	body := doc.Paths["/login"].Post.RequestBody.Content["application/x-www-form-urlencoded"]
+	if body == nil || body.Schema.Properties["username"] == nil || !slices.Equal(body.Schema.Required, []string{"username", "password"}) {
+		t.Errorf("期望生成表单请求体, 实际得到 %+v", body)
+	}
+	if doc.Components.Schemas["openAPIPage_openAPIUser"] == nil {
+		t.Errorf("期望泛型类型的组件名称去掉包路径, 实际得到 %v", slices.Collect(maps.Keys(doc.Components.Schemas)))
+	}
+}
+
+// 测试实现 DataProvider 和 MetaProvider 的响应按实际输出的 data 和 meta 生成 Schema
+func TestOpenAPIDataProvider(t *testing.T) {
+	type item struct {
+		ID int64 `json:"id" example:"1"`
+	}
+	routes := []RouteInfo{
+		{Method: http.MethodGet, Path: "/list", Response: reflect.TypeFor[ListResponse[item]]()},
+		{Method: http.MethodGet, Path: "/pages", Response: reflect.TypeFor[PageResult[item]]()},
+	}
+	doc := GenerateOpenAPI(OpenAPIInfo{Title: "List", Version: "1"}, routes)
+
+	for path, meta := range map[string]string{"/list": "ListMeta", "/pages": "PageMeta"} {
+		content := doc.Paths[path].Get.Responses["200"].Content["application/json"]
+		schema := content.Schema
+		if data := schema.Properties["data"]; data.Type != "array" || data.Items.Ref != "#/components/schemas/item" {
+			t.Errorf("期望 %s 的 data 为元素数组, 实际得到 %+v", path, data)
+		}
+		if ref := schema.Properties["meta"].Ref; ref != "#/components/schemas/"+meta {
+			t.Errorf("期望 %s 的 meta 引用 %s, 实际得到 %q", path, meta, ref)
+		}
+		if !slices.Contains(schema.Required, "meta") {
+			t.Errorf("期望 %s 的 meta 为必填字段, 实际得到 %v", path, schema.Required)
+		}
+		example := content.Example.(map[string]any)
+		if data, ok := example["data"].([]item); !ok || len(data) != 1 || data[0].ID != 1 || example["meta"] == nil {
+			t.Errorf("期望 %s 的示例拆分 data 和 meta, 实际得到 %+v", path, example)
+		}
+	}
+}
+
+// 测试 doc tag 和 WithDescription 生成到文档中
+func TestOpenAPIDescriptions(t *testing.T) {
+	type profile struct {
+		Bio string `json:"bio" doc:"个人简介"`
+	}
+	type request struct {
+		ID      int64    `path:"id" doc:"用户 ID"`
+		Name    string   `json:"name" doc:"用户名，2 到 32 个字符"`
+		Profile *profile `json:"profile" doc:"用户资料"`
+	}
+
+	r := gin.New()
+	registry := NewRegistry()
+	api := NewGroup(r.Group("/api"), WithRegistry(registry))
+	update := func(ctx context.Context, req *request) (*profile, error) { return nil, nil }
+	PUT(api, "/users/:id", update, WithMeta(Meta{Summary: "更新用户"}), WithDescription("只有管理员可以修改其他用户"))
+
+	doc := registry.OpenAPI(OpenAPIInfo{Title: "User API", Version: "1.0.0"})
+	op := doc.Paths["/api/users/{id}"].Put
+	if op.Summary != "更新用户" || op.Description != "只有管理员可以修改其他用户" {
+		t.Errorf("期望操作包含简要说明和详细说明, 实际得到 %q %q", op.Summary, op.Description)
+	}
+	if op.Parameters[0].Description != "用户 ID" {
+		t.Errorf("期望参数包含说明, 实际得到 %q", op.Parameters[0].Description)
+	}
+	body := op.RequestBody.Content["application/json"].Schema
+	if body.Properties["name"].Description != "用户名，2 到 32 个字符" || body.Properties["profile"].Description != "用户资料" {
+		t.Errorf("期望请求体属性包含说明, 实际得到 %+v", body.Properties)
+	}
+	if doc.Components.Schemas["profile"].Properties["bio"].Description != "个人简介" {
+		t.Error("期望组件属性包含说明")
+	}
+
+	code := registry.TypeScript()
+	if !strings.Contains(code, "  /** 用户名，2 到 32 个字符 */\n  name?: string;") || !strings.Contains(code, "  /** 个人简介 */\n  bio: string;") {
+		t.Errorf("期望 TypeScript 属性包含说明注释, 实际得到:\n%s", code)
+	}
+}
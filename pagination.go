@@ -0,0 +1,136 @@
+package apihandler
+
+// MaxPerPage 每页数量的上限
+const MaxPerPage = 100
+
+// Paginator 分页请求接口，ListHandler 优先使用其返回的页码和每页数量生成 Link 响应头
+type Paginator interface {
+	Pagination() (page, perPage int)
+}
+
+// PageRequest 页码分页请求，可嵌入到请求结构中使用
+type PageRequest struct {
+	Page    int `form:"page" json:"page" binding:"omitempty,min=1"`
+	PerPage int `form:"per_page" json:"per_page" binding:"omitempty,min=1,max=100"`
+}
+
+// Pagination 实现 Paginator 接口，未传入时页码为 1、每页数量为 DefaultPerPage
+func (p PageRequest) Pagination() (page, perPage int) {
+	page, perPage = p.Page, p.PerPage
+	if page < 1 {
+		page = 1
+	}
+	if perPage < 1 {
+		perPage = DefaultPerPage
+	}
+	if perPage > MaxPerPage {
+		perPage = MaxPerPage
+	}
+	return page, perPage
+}
+
+// Offset 返回数据库查询的偏移量
+func (p PageRequest) Offset() int {
+	page, perPage := p.Pagination()
+	return (page - 1) * perPage
+}
+
+// Limit 返回数据库查询的数量
+func (p PageRequest) Limit() int {
+	_, perPage := p.Pagination()
+	return perPage
+}
+
+// CursorRequest 游标分页请求，可嵌入到请求结构中使用
+type CursorRequest struct {
+	Cursor string `form:"cursor" json:"cursor"`
+	Limit  int    `form:"limit" json:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// PageSize 返回每页数量，未传入时为 DefaultPerPage
+func (r CursorRequest) PageSize() int {
+	if r.Limit < 1 {
+		return DefaultPerPage
+	}
+	if r.Limit > MaxPerPage {
+		return MaxPerPage
+	}
+	return r.Limit
+}
+
+// PageMeta 页码分页元数据
+type PageMeta struct {
+	Total      int64 `json:"total"`
+	Page       int   `json:"page"`
+	PerPage    int   `json:"per_page"`
+	TotalPages int   `json:"total_pages"`
+}
+
+// PageResult 页码分页响应，Items 作为 data 输出，PageMeta 作为 meta 输出
+type PageResult[R any] struct {
+	Items    []R      `json:"items"`
+	PageMeta PageMeta `json:"meta"`
+}
+
+// NewPageResult 创建页码分页响应
+func NewPageResult[R any](req PageRequest, items []R, total int64) *PageResult[R] {
+	page, perPage := req.Pagination()
+	return &PageResult[R]{
+		Items: items,
+		PageMeta: PageMeta{
+			Total:      total,
+			Page:       page,
+			PerPage:    perPage,
+			TotalPages: int((total + int64(perPage) - 1) / int64(perPage)),
+		},
+	}
+}
+
+// Data 实现 DataProvider 接口
+func (r *PageResult[R]) Data() any {
+	if r.Items == nil {
+		return []R{}
+	}
+	return r.Items
+}
+
+// Meta 实现 MetaProvider 接口
+func (r *PageResult[R]) Meta() any {
+	return r.PageMeta
+}
+
+// CursorMeta 游标分页元数据
+type CursorMeta struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// CursorResult 游标分页响应，Items 作为 data 输出，CursorMeta 作为 meta 输出
+type CursorResult[R any] struct {
+	Items      []R        `json:"items"`
+	CursorMeta CursorMeta `json:"meta"`
+}
+
+// NewCursorResult 创建游标分页响应，nextCursor 为空表示没有更多数据
+func NewCursorResult[R any](items []R, nextCursor string) *CursorResult[R] {
+	return &CursorResult[R]{
+		Items: items,
+		CursorMeta: CursorMeta{
+			NextCursor: nextCursor,
+			HasMore:    nextCursor != "",
+		},
+	}
+}
+
+// Data 实现 DataProvider 接口
+func (r *CursorResult[R]) Data() any {
+	if r.Items == nil {
+		return []R{}
+	}
+	return r.Items
+}
+
+// Meta 实现 MetaProvider 接口
+func (r *CursorResult[R]) Meta() any {
+	return r.CursorMeta
+}
@@ -0,0 +1,130 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 嵌入分页请求的列表请求
+type pagedUsersRequest struct {
+	PageRequest
+	Status string `form:"status"`
+}
+
+// 测试页码分页请求和响应
+func TestPageRequestAndResult(t *testing.T) {
+	r := gin.New()
+
+	r.GET("/users", Handler(func(ctx context.Context, req *pagedUsersRequest) (*PageResult[testResponse], error) {
+		items := []testResponse{{ID: int64(req.Offset() + 1)}}
+		return NewPageResult(req.PageRequest, items, 45), nil
+	}))
+
+	req := httptest.NewRequest("GET", "/users?page=3&per_page=20", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	var resp struct {
+		Data []testResponse `json:"data"`
+		Meta PageMeta       `json:"meta"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+
+	if resp.Data[0].ID != 41 {
+		t.Errorf("期望偏移量为 40, 实际得到 %d", resp.Data[0].ID-1)
+	}
+
+	expected := PageMeta{Total: 45, Page: 3, PerPage: 20, TotalPages: 3}
+	if resp.Meta != expected {
+		t.Errorf("期望 meta 为 %+v, 实际得到 %+v", expected, resp.Meta)
+	}
+
+	// 超出范围的分页参数返回验证错误
+	for _, path := range []string{"/users?page=-1", "/users?per_page=101"} {
+		req = httptest.NewRequest("GET", path, nil)
+		w = httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: 期望状态码 %d, 实际得到 %d", path, http.StatusBadRequest, w.Code)
+		}
+	}
+}
+
+// 测试分页请求的默认值
+func TestPageRequestDefaults(t *testing.T) {
+	page, perPage := PageRequest{}.Pagination()
+	if page != 1 || perPage != DefaultPerPage {
+		t.Errorf("期望默认分页为 (1, %d), 实际得到 (%d, %d)", DefaultPerPage, page, perPage)
+	}
+
+	if limit := (CursorRequest{}).PageSize(); limit != DefaultPerPage {
+		t.Errorf("期望默认每页数量为 %d, 实际得到 %d", DefaultPerPage, limit)
+	}
+}
+
+// 测试游标分页响应
+func TestCursorResult(t *testing.T) {
+	type cursorUsersRequest struct {
+		CursorRequest
+	}
+
+	r := gin.New()
+
+	r.GET("/users", Handler(func(ctx context.Context, req *cursorUsersRequest) (*CursorResult[testResponse], error) {
+		if req.Cursor == "last" {
+			return NewCursorResult[testResponse](nil, ""), nil
+		}
+		return NewCursorResult([]testResponse{{ID: int64(req.PageSize())}}, "last"), nil
+	}))
+
+	cases := []struct {
+		path string
+		body string
+	}{
+		{"/users?limit=5", `{"code":0,"data":[{"id":5,"name":"","age":0,"message":""}],"meta":{"next_cursor":"last","has_more":true}}`},
+		{"/users?cursor=last", `{"code":0,"data":[],"meta":{"has_more":false}}`},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", tc.path, nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Body.String() != tc.body {
+			t.Errorf("%s: 期望响应为 %s, 实际得到 %s", tc.path, tc.body, w.Body.String())
+		}
+	}
+}
+
+// 测试列表处理器使用请求中的分页参数
+func TestListHandlerPaginator(t *testing.T) {
+	r := gin.New()
+
+	r.GET("/users", ListHandler(func(ctx context.Context, req *pagedUsersRequest) ([]testResponse, int64, error) {
+		return nil, 250, nil
+	}))
+
+	req := httptest.NewRequest("GET", "/users?page=2", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	expected := `<http://example.com/users?page=1&per_page=30>; rel="first", ` +
+		`<http://example.com/users?page=1&per_page=30>; rel="prev", ` +
+		`<http://example.com/users?page=3&per_page=30>; rel="next", ` +
+		`<http://example.com/users?page=9&per_page=30>; rel="last"`
+	if link := w.Header().Get("Link"); link != expected {
+		t.Errorf("期望 Link 为 %s, 实际得到 %s", expected, link)
+	}
+}
@@ -0,0 +1,43 @@
+package apihandler
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+// PanicAlert 业务处理函数 panic 的告警信息
+type PanicAlert struct {
+	Recovered any    // recover() 的返回值
+	Stack     []byte // 调用栈
+	Request   any    // 绑定后的请求对象
+	Method    string // 请求方法
+	Route     string // 路由模板，如 /users/:id
+	RequestID string // 请求 ID，未启用请求 ID 时为空
+}
+
+// PanicAlertFunc 业务处理函数 panic 时的告警函数
+type PanicAlertFunc func(c *gin.Context, alert PanicAlert)
+
+// WithPanicAlert 设置业务处理函数 panic 时的告警函数，用于对接 PagerDuty、Slack 等告警渠道
+//
+// 告警函数与 OnPanic 相互独立，在 OnPanic 之后调用，同时能拿到绑定后的请求对象。
+// 告警函数在请求处理过程中同步调用，耗时的通知应放到 goroutine 中发送。
+func WithPanicAlert(fn PanicAlertFunc) Option {
+	return func(c *HandlerConfig) {
+		c.PanicAlert = fn
+	}
+}
+
+// alertPanic 调用 panic 告警函数
+func alertPanic(c *gin.Context, config *HandlerConfig, req any, p *panicError) {
+	if config.PanicAlert == nil {
+		return
+	}
+	config.PanicAlert(c, PanicAlert{
+		Recovered: p.value,
+		Stack:     p.stack,
+		Request:   req,
+		Method:    c.Request.Method,
+		Route:     c.FullPath(),
+		RequestID: RequestIDFromContext(c.Request.Context()),
+	})
+}
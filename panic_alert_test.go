@@ -0,0 +1,51 @@
+package apihandler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试业务处理函数 panic 时调用告警函数
+func TestPanicAlert(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		panic("boom")
+	}
+	var calls []string
+	var alert PanicAlert
+	r.GET("/users/:id", Handler(handleFunc,
+		WithRequestID(nil),
+		WithOnPanic(func(c *gin.Context, recovered any, stack []byte) {
+			calls = append(calls, "OnPanic")
+		}),
+		WithPanicAlert(func(c *gin.Context, a PanicAlert) {
+			calls = append(calls, "PanicAlert")
+			alert = a
+		}),
+	))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/users/7?name=test", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusInternalServerError, w.Code)
+	}
+	if strings.Join(calls, ",") != "OnPanic,PanicAlert" {
+		t.Errorf("期望依次调用 OnPanic 和告警函数, 实际得到 %v", calls)
+	}
+	if alert.Recovered != "boom" || !strings.Contains(string(alert.Stack), "panic_alert_test.go") {
+		t.Errorf("期望告警包含 panic 的值和调用栈, 实际得到 %v", alert.Recovered)
+	}
+	if req, ok := alert.Request.(*testRequest); !ok || req.ID != 7 || req.Name != "test" {
+		t.Errorf("期望告警包含绑定后的请求对象, 实际得到 %#v", alert.Request)
+	}
+	if alert.Method != "GET" || alert.Route != "/users/:id" || alert.RequestID == "" || alert.RequestID != w.Header().Get(RequestIDHeader) {
+		t.Errorf("期望告警包含请求方法、路由和请求 ID, 实际得到 %+v", alert)
+	}
+}
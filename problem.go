@@ -0,0 +1,201 @@
+package apihandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Problem Details 响应的 Content-Type
+const (
+	problemJSONContentType = "application/problem+json; charset=utf-8"
+	problemXMLContentType  = "application/problem+xml; charset=utf-8"
+)
+
+// ProblemDetails RFC 7807 Problem Details 错误响应体
+//
+// Extensions 中的成员与标准成员位于同一层级，默认包含业务错误码 code 和错误详情 errors。
+type ProblemDetails struct {
+	Type       string         `json:"type"`
+	Title      string         `json:"title"`
+	Status     int            `json:"status"`
+	Detail     string         `json:"detail,omitempty"`
+	Instance   string         `json:"instance,omitempty"`
+	Extensions map[string]any `json:"-"`
+}
+
+// ProblemTyper 问题类型接口
+//
+// 业务错误实现该接口时，ProblemType 的结果作为 type 成员输出，否则为 "about:blank"。
+type ProblemTyper interface {
+	ProblemType() string
+}
+
+// problemDetailsMembers ProblemDetails 的标准成员，扩展成员不能与其同名
+var problemDetailsMembers = map[string]bool{
+	"type": true, "title": true, "status": true, "detail": true, "instance": true,
+}
+
+// newProblemDetails 将业务错误转换为 Problem Details
+func newProblemDetails(c *gin.Context, config *HandlerConfig, err BizError) *ProblemDetails {
+	status := err.HTTPCode()
+	problem := &ProblemDetails{
+		Type:       "about:blank",
+		Title:      http.StatusText(status),
+		Status:     status,
+		Detail:     err.Error(),
+		Instance:   c.Request.URL.Path,
+		Extensions: map[string]any{"code": err.Code()},
+	}
+	// 业务错误可能被 NewHeaderedError 或错误码区间包装，按错误链查找问题类型
+	var pt ProblemTyper
+	if errors.As(err, &pt) {
+		if problemType := pt.ProblemType(); problemType != "" {
+			problem.Type = problemType
+		}
+	}
+	if details := err.Errors(); len(details) > 0 {
+		problem.Extensions["errors"] = details
+	}
+	if config.Debug {
+		if stack, chain := debugDetails(err); len(chain) > 0 {
+			if stack != "" {
+				problem.Extensions["stack"] = stack
+			}
+			problem.Extensions["cause_chain"] = chain
+		}
+	}
+	if field := requestIDField(config.RequestIDField); field != "-" {
+		if id := requestID(c, config.RequestIDFunc); id != "" {
+			problem.Extensions[field] = id
+		}
+	}
+	return problem
+}
+
+// contentType 实现 bodyContentTyper 接口
+func (p *ProblemDetails) contentType(format Format) string {
+	switch format {
+	case FormatJSON:
+		return problemJSONContentType
+	case FormatXML:
+		return problemXMLContentType
+	}
+	return ""
+}
+
+// MarshalJSON 实现 json.Marshaler 接口，扩展成员按名称顺序输出在标准成员之后
+func (p ProblemDetails) MarshalJSON() ([]byte, error) {
+	return p.marshalJSON(StdJSONCodec{})
+}
+
+// marshalJSON 实现 codecMarshaler 接口
+func (p ProblemDetails) marshalJSON(codec JSONCodec) ([]byte, error) {
+	type problem ProblemDetails
+	data, err := codec.Marshal(problem(p))
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(p.Extensions))
+	for name := range p.Extensions {
+		if !problemDetailsMembers[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	for _, name := range names {
+		value, err := codec.Marshal(p.Extensions[name])
+		if err != nil {
+			return nil, err
+		}
+		key, _ := json.Marshal(name)
+		buf.WriteByte(',')
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler 接口，非标准成员解析到 Extensions
+func (p *ProblemDetails) UnmarshalJSON(data []byte) error {
+	type problem ProblemDetails
+	var standard problem
+	if err := json.Unmarshal(data, &standard); err != nil {
+		return err
+	}
+	var members map[string]any
+	if err := json.Unmarshal(data, &members); err != nil {
+		return err
+	}
+	for name := range problemDetailsMembers {
+		delete(members, name)
+	}
+	*p = ProblemDetails(standard)
+	if len(members) > 0 {
+		p.Extensions = members
+	}
+	return nil
+}
+
+// MarshalXML 实现 xml.Marshaler 接口，按 RFC 7807 附录 A 输出 urn:ietf:rfc:7807 命名空间的 <problem> 元素
+func (p ProblemDetails) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	start = xml.StartElement{
+		Name: xml.Name{Local: "problem"},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "xmlns"}, Value: "urn:ietf:rfc:7807"}},
+	}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+
+	members := []struct {
+		name  string
+		value any
+		omit  bool
+	}{
+		{"type", p.Type, false},
+		{"title", p.Title, false},
+		{"status", p.Status, false},
+		{"detail", p.Detail, p.Detail == ""},
+		{"instance", p.Instance, p.Instance == ""},
+	}
+	for _, m := range members {
+		if m.omit {
+			continue
+		}
+		if err := enc.EncodeElement(m.value, xml.StartElement{Name: xml.Name{Local: m.name}}); err != nil {
+			return err
+		}
+	}
+
+	names := make([]string, 0, len(p.Extensions))
+	for name := range p.Extensions {
+		if !problemDetailsMembers[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		value := p.Extensions[name]
+		if details, ok := value.([]any); ok {
+			if err := encodeXMLDetails(enc, name, details); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := enc.EncodeElement(value, xml.StartElement{Name: xml.Name{Local: name}}); err != nil {
+			return err
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
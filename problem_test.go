@@ -0,0 +1,144 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 带问题类型的业务错误
+type outOfCreditError struct {
+	BizError
+}
+
+func (outOfCreditError) ProblemType() string {
+	return "https://example.com/probs/out-of-credit"
+}
+
+// 测试 Problem Details 错误响应
+func TestProblemDetails(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		switch req.ID {
+		case 1:
+			return nil, outOfCreditError{NewBizError(40300, "余额不足", http.StatusForbidden)}
+		case 3:
+			return nil, NewHeaderedError(outOfCreditError{NewBizError(40300, "余额不足", http.StatusForbidden)}, http.Header{"Retry-After": {"60"}})
+		}
+		return nil, ErrNotFound(40400, "资源不存在")
+	}
+
+	r.GET("/accounts/:id", Handler(handleFunc, WithProblemDetails()))
+
+	cases := []struct {
+		path     string
+		expected string
+	}{
+		{"/accounts/1", `{"type":"https://example.com/probs/out-of-credit","title":"Forbidden","status":403,"detail":"余额不足","instance":"/accounts/1","code":40300}`},
+		{"/accounts/2", `{"type":"about:blank","title":"Not Found","status":404,"detail":"资源不存在","instance":"/accounts/2","code":40400}`},
+		{"/accounts/3", `{"type":"https://example.com/probs/out-of-credit","title":"Forbidden","status":403,"detail":"余额不足","instance":"/accounts/3","code":40300}`},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", tc.path, nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if got := w.Header().Get("Content-Type"); got != "application/problem+json; charset=utf-8" {
+			t.Errorf("%s: 期望 Content-Type 为 application/problem+json, 实际得到 '%s'", tc.path, got)
+		}
+
+		if w.Body.String() != tc.expected {
+			t.Errorf("%s: 期望响应为 %s, 实际得到 %s", tc.path, tc.expected, w.Body.String())
+		}
+	}
+}
+
+// 测试参数验证错误的详情作为 errors 扩展成员输出
+func TestProblemDetailsValidationErrors(t *testing.T) {
+	type createRequest struct {
+		Name string `json:"name" binding:"required"`
+	}
+
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *createRequest) (*testResponse, error) {
+		return &testResponse{}, nil
+	}
+
+	r.POST("/users", Handler(handleFunc, WithProblemDetails()))
+
+	req := httptest.NewRequest("POST", "/users", strings.NewReader(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusBadRequest, w.Code)
+	}
+
+	var problem ProblemDetails
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+
+	if problem.Status != http.StatusBadRequest || problem.Title != "Bad Request" {
+		t.Errorf("期望 status 为 400、title 为 'Bad Request', 实际得到 %d, '%s'", problem.Status, problem.Title)
+	}
+
+	details, ok := problem.Extensions["errors"].([]any)
+	if !ok || len(details) != 1 {
+		t.Fatalf("期望 errors 扩展成员包含 1 条详情, 实际得到 %v", problem.Extensions["errors"])
+	}
+
+	if field := details[0].(map[string]any)["field"]; field != "name" {
+		t.Errorf("期望 field 为 'name', 实际得到 '%v'", field)
+	}
+}
+
+// 测试 XML 格式的 Problem Details 以及成功响应不受影响
+func TestProblemDetailsXML(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		if req.ID == 0 {
+			return nil, ErrNotFound(40400, "资源不存在")
+		}
+		return &testResponse{ID: req.ID}, nil
+	}
+
+	r.GET("/test/:id", Handler(handleFunc, WithProblemDetails(), WithFormats(FormatJSON, FormatXML)))
+
+	req := httptest.NewRequest("GET", "/test/0", nil)
+	req.Header.Set("Accept", "application/xml")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Content-Type"); got != "application/problem+xml; charset=utf-8" {
+		t.Errorf("期望 Content-Type 为 application/problem+xml, 实际得到 '%s'", got)
+	}
+
+	expected := `<problem xmlns="urn:ietf:rfc:7807"><type>about:blank</type><title>Not Found</title><status>404</status>` +
+		`<detail>资源不存在</detail><instance>/test/0</instance><code>40400</code></problem>`
+	if w.Body.String() != expected {
+		t.Errorf("期望响应为 %s, 实际得到 %s", expected, w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/test/1", nil)
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if got := w.Header().Get("Content-Type"); got != jsonContentType {
+		t.Errorf("期望成功响应的 Content-Type 为 %s, 实际得到 '%s'", jsonContentType, got)
+	}
+}
@@ -0,0 +1,128 @@
+package apihandler
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Limiter 限流器接口，可使用内存令牌桶或 Redis 等共享存储实现
+type Limiter interface {
+	// Allow 判断 key 的请求是否允许通过，不允许时返回建议的重试等待时间（未知时为 0）
+	Allow(ctx context.Context, key string) (allowed bool, retryAfter time.Duration, err error)
+}
+
+// RateKeyFunc 生成限流键的函数，如客户端 IP、用户 ID 或 API Key，返回空字符串表示不限流
+type RateKeyFunc func(c *gin.Context) string
+
+// RateKeyByIP 按客户端 IP 限流
+func RateKeyByIP(c *gin.Context) string {
+	return c.ClientIP()
+}
+
+// RateKeyByHeader 按请求头限流，如 X-Api-Key
+func RateKeyByHeader(name string) RateKeyFunc {
+	return func(c *gin.Context) string {
+		return c.GetHeader(name)
+	}
+}
+
+// WithRateLimit 设置限流器，超过限制时返回 429 错误响应和 Retry-After 响应头，keyFunc 为空时按客户端 IP 限流
+func WithRateLimit(limiter Limiter, keyFunc RateKeyFunc) Option {
+	return func(c *HandlerConfig) {
+		c.RateLimiter = limiter
+		c.RateKeyFunc = keyFunc
+	}
+}
+
+// checkRateLimit 检查请求是否超过限流，限流器出错时放行请求并记录错误
+func checkRateLimit(c *gin.Context, config *HandlerConfig, translator Translator) error {
+	keyFunc := config.RateKeyFunc
+	if keyFunc == nil {
+		keyFunc = RateKeyByIP
+	}
+	key := keyFunc(c)
+	if key == "" {
+		return nil
+	}
+
+	allowed, retryAfter, err := config.RateLimiter.Allow(c.Request.Context(), key)
+	if err != nil {
+		c.Error(err)
+		return nil
+	}
+	if allowed {
+		return nil
+	}
+	return ErrTooManyRequests(http.StatusTooManyRequests, translator.Translate(MsgTooManyRequests), retryAfter)
+}
+
+// TokenBucketLimiter 内存令牌桶限流器，每个键独立计数，可并发使用
+//
+// 只适用于单实例部署，多实例部署时应使用基于 Redis 等共享存储的 Limiter 实现。
+type TokenBucketLimiter struct {
+	rate  float64 // 每秒补充的令牌数
+	burst float64 // 令牌桶容量
+
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	now     func() time.Time
+}
+
+// tokenBucket 单个键的令牌桶
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucketLimiter 创建内存令牌桶限流器，rate 为每秒允许的请求数，burst 为允许的突发请求数
+func NewTokenBucketLimiter(rate float64, burst int) *TokenBucketLimiter {
+	return &TokenBucketLimiter{
+		rate:    rate,
+		burst:   float64(burst),
+		buckets: make(map[string]*tokenBucket),
+		now:     time.Now,
+	}
+}
+
+// Allow 实现 Limiter 接口
+func (l *TokenBucketLimiter) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	bucket, ok := l.buckets[key]
+	if !ok {
+		l.sweep(now)
+		bucket = &tokenBucket{tokens: l.burst, last: now}
+		l.buckets[key] = bucket
+	}
+
+	bucket.tokens = math.Min(l.burst, bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate)
+	bucket.last = now
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return true, 0, nil
+	}
+	if l.rate <= 0 {
+		return false, 0, nil
+	}
+	wait := time.Duration((1 - bucket.tokens) / l.rate * float64(time.Second))
+	return false, wait, nil
+}
+
+// sweep 键的数量较多时清理已经补满的令牌桶，补满的令牌桶与新建的令牌桶等价
+func (l *TokenBucketLimiter) sweep(now time.Time) {
+	if len(l.buckets) < 1024 {
+		return
+	}
+	for key, bucket := range l.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*l.rate >= l.burst {
+			delete(l.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试超过限流时返回 429
+func TestRateLimit(t *testing.T) {
+	r := gin.New()
+
+	limiter := NewTokenBucketLimiter(1, 2)
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return &testResponse{ID: req.ID}, nil
+	}
+	r.GET("/test/:id", Handler(handleFunc, WithRateLimit(limiter, RateKeyByHeader("X-Api-Key"))))
+
+	send := func(apiKey string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/test/1", nil)
+		req.Header.Set("X-Api-Key", apiKey)
+		req.Header.Set("Accept-Language", "en")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := 0; i < 2; i++ {
+		if w := send("key-a"); w.Code != http.StatusOK {
+			t.Fatalf("期望第 %d 个请求通过, 实际状态码 %d", i+1, w.Code)
+		}
+	}
+
+	w := send("key-a")
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusTooManyRequests, w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "1" {
+		t.Errorf("期望 Retry-After 为 1, 实际得到 %q", got)
+	}
+	var resp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	if resp.Message != "Too many requests, please try again later" {
+		t.Errorf("期望翻译后的限流消息, 实际得到 '%s'", resp.Message)
+	}
+
+	if w := send("key-b"); w.Code != http.StatusOK {
+		t.Errorf("期望不同的限流键独立计数, 实际状态码 %d", w.Code)
+	}
+	if w := send(""); w.Code != http.StatusOK {
+		t.Errorf("期望空限流键不限流, 实际状态码 %d", w.Code)
+	}
+}
+
+// 测试令牌桶补充令牌
+func TestTokenBucketLimiterRefill(t *testing.T) {
+	limiter := NewTokenBucketLimiter(2, 1)
+	now := time.Unix(0, 0)
+	limiter.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	if ok, _, _ := limiter.Allow(ctx, "k"); !ok {
+		t.Fatal("期望第一个请求通过")
+	}
+	ok, retryAfter, _ := limiter.Allow(ctx, "k")
+	if ok || retryAfter != 500*time.Millisecond {
+		t.Errorf("期望被限流并等待 500ms, 实际得到 %v %v", ok, retryAfter)
+	}
+
+	now = now.Add(500 * time.Millisecond)
+	if ok, _, _ := limiter.Allow(ctx, "k"); !ok {
+		t.Errorf("期望补充令牌后请求通过")
+	}
+}
+
+// failingLimiter 总是返回错误的限流器
+type failingLimiter struct{}
+
+func (failingLimiter) Allow(ctx context.Context, key string) (bool, time.Duration, error) {
+	return false, 0, errors.New("redis: connection refused")
+}
+
+// 测试限流器出错时放行请求
+func TestRateLimitFailOpen(t *testing.T) {
+	r := gin.New()
+
+	var errs int
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return &testResponse{ID: req.ID}, nil
+	}
+	r.GET("/test/:id", Handler(handleFunc, WithRateLimit(failingLimiter{}, nil)), func(c *gin.Context) {
+		errs = len(c.Errors)
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/test/1", nil))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusOK, w.Code)
+	}
+	if errs != 1 {
+		t.Errorf("期望记录 1 个错误, 实际得到 %d", errs)
+	}
+}
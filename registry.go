@@ -0,0 +1,208 @@
+package apihandler
+
+import (
+	"net/http"
+	"path"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// Meta 处理器元数据，用于文档生成、管理界面和测试
+type Meta struct {
+	Name        string   // 处理器名称，如 getUser，应在注册表中唯一
+	Tags        []string // 分组标签
+	Summary     string   // 简要说明
+	Description string   // 详细说明，可以使用 Markdown
+}
+
+// RouteInfo 注册表中的路由信息
+type RouteInfo struct {
+	Method          string       // HTTP 方法
+	Path            string       // 完整路由路径，如 /api/user/:id
+	Request         reflect.Type // 请求类型
+	Response        reflect.Type // 响应类型
+	Meta            Meta         // 处理器元数据
+	SuccessHTTPCode int          // 成功响应的 HTTP 状态码
+	SuccessCode     any          // 成功响应的业务代码
+	Errors          []RouteError // 处理器声明的业务错误
+	Deprecation     *Deprecation // 废弃信息，为 nil 时未废弃
+	APIVersion      int          // API 版本，0 表示未版本化
+}
+
+// RouteError 处理器声明的业务错误
+type RouteError struct {
+	Code     any    // 业务错误码
+	HTTPCode int    // HTTP 状态码
+	Message  string // 错误消息
+}
+
+// Registry 路由注册表，并发安全
+type Registry struct {
+	mu     sync.RWMutex
+	routes []RouteInfo
+}
+
+// DefaultRegistry 默认路由注册表
+var DefaultRegistry = NewRegistry()
+
+// NewRegistry 创建路由注册表
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// WithMeta 设置处理器元数据
+func WithMeta(meta Meta) Option {
+	return func(c *HandlerConfig) {
+		c.Meta = meta
+	}
+}
+
+// WithDescription 设置处理器的详细说明，生成到 OpenAPI 文档中，可以使用 Markdown
+func WithDescription(description string) Option {
+	return func(c *HandlerConfig) {
+		c.Meta.Description = description
+	}
+}
+
+// WithErrors 声明处理器可能返回的业务错误，记录到注册表并生成到 OpenAPI 文档中，多次调用时追加
+//
+//	apihandler.GET(api, "/user/:id", handleGetUser, apihandler.WithErrors(ErrUserNotFound, ErrUserDisabled))
+func WithErrors(errs ...BizError) Option {
+	return func(c *HandlerConfig) {
+		c.DeclaredErrors = append(c.DeclaredErrors[:len(c.DeclaredErrors):len(c.DeclaredErrors)], errs...)
+	}
+}
+
+// WithRegistry 设置记录路由的注册表，为 nil 时不记录
+func WithRegistry(registry *Registry) Option {
+	return func(c *HandlerConfig) {
+		c.Registry = registry
+	}
+}
+
+// Add 记录路由，相同方法、路径和版本的路由会被替换
+func (r *Registry) Add(route RouteInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, existing := range r.routes {
+		if existing.Method == route.Method && existing.Path == route.Path && existing.APIVersion == route.APIVersion {
+			r.routes[i] = route
+			return
+		}
+	}
+	r.routes = append(r.routes, route)
+}
+
+// Routes 按注册顺序返回默认注册表中的所有路由
+func Routes() []RouteInfo {
+	return DefaultRegistry.Routes()
+}
+
+// Routes 按注册顺序返回所有路由
+func (r *Registry) Routes() []RouteInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	routes := make([]RouteInfo, len(r.routes))
+	for i, route := range r.routes {
+		route.Meta.Tags = append([]string(nil), route.Meta.Tags...)
+		route.Errors = append([]RouteError(nil), route.Errors...)
+		routes[i] = route
+	}
+	return routes
+}
+
+// Lookup 按方法和完整路由路径查找路由
+func (r *Registry) Lookup(method, fullPath string) (RouteInfo, bool) {
+	for _, route := range r.Routes() {
+		if route.Method == method && route.Path == fullPath {
+			return route, true
+		}
+	}
+	return RouteInfo{}, false
+}
+
+// Find 按处理器名称查找路由
+func (r *Registry) Find(name string) (RouteInfo, bool) {
+	for _, route := range r.Routes() {
+		if route.Meta.Name == name {
+			return route, true
+		}
+	}
+	return RouteInfo{}, false
+}
+
+// Tagged 返回带有指定标签的路由
+func (r *Registry) Tagged(tag string) []RouteInfo {
+	var routes []RouteInfo
+	for _, route := range r.Routes() {
+		for _, t := range route.Meta.Tags {
+			if t == tag {
+				routes = append(routes, route)
+				break
+			}
+		}
+	}
+	return routes
+}
+
+// Reset 清空注册表
+func (r *Registry) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.routes = nil
+}
+
+// registerRoute 将处理器记录到配置的注册表
+func registerRoute[T any, R any](config *HandlerConfig, method, fullPath string) {
+	if config.Registry == nil {
+		return
+	}
+	var deprecation *Deprecation
+	if config.Deprecation != nil {
+		d := *config.Deprecation
+		deprecation = &d
+	}
+	config.Registry.Add(RouteInfo{
+		Method:          method,
+		Path:            fullPath,
+		Request:         reflect.TypeFor[T](),
+		Response:        reflect.TypeFor[R](),
+		Meta:            config.Meta,
+		SuccessHTTPCode: config.SuccessHTTPCode,
+		SuccessCode:     config.SuccessCode,
+		Errors:          declaredErrors(config),
+		Deprecation:     deprecation,
+		APIVersion:      config.APIVersion,
+	})
+}
+
+// declaredErrors 返回处理器声明的业务错误，HTTP 状态码按错误码区间推断
+func declaredErrors(config *HandlerConfig) []RouteError {
+	var errs []RouteError
+	for _, bizErr := range config.DeclaredErrors {
+		if len(config.CodeRanges) > 0 {
+			bizErr = inferHTTPCode(config, bizErr)
+		}
+		httpCode := bizErr.HTTPCode()
+		if httpCode == 0 {
+			httpCode = http.StatusInternalServerError
+		}
+		errs = append(errs, RouteError{Code: bizErr.Code(), HTTPCode: httpCode, Message: bizErr.Error()})
+	}
+	return errs
+}
+
+// joinPaths 拼接分组路径和相对路径，与 gin 的规则一致，保留相对路径末尾的斜杠
+func joinPaths(basePath, relativePath string) string {
+	if relativePath == "" {
+		return basePath
+	}
+	joined := path.Join(basePath, relativePath)
+	if strings.HasSuffix(relativePath, "/") && !strings.HasSuffix(joined, "/") {
+		return joined + "/"
+	}
+	return joined
+}
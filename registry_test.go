@@ -0,0 +1,115 @@
+package apihandler
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试通过分组注册的处理器记录到注册表
+func TestRegistry(t *testing.T) {
+	r := gin.New()
+	registry := NewRegistry()
+
+	getUser := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return &testResponse{}, nil
+	}
+	api := NewGroup(r.Group("/api"), WithRegistry(registry))
+	GET(api, "/user/:id", getUser, WithMeta(Meta{Name: "getUser", Tags: []string{"user"}, Summary: "获取用户"}))
+	POST(api.Group("/admin"), "/user/", getUser, WithMeta(Meta{Name: "createUser", Tags: []string{"user", "admin"}}))
+
+	routes := registry.Routes()
+	if len(routes) != 2 {
+		t.Fatalf("期望注册表中有 2 个路由, 实际得到 %d", len(routes))
+	}
+
+	route, ok := registry.Lookup(http.MethodGet, "/api/user/:id")
+	if !ok {
+		t.Fatal("期望按方法和路径找到路由")
+	}
+	if route.Request != reflect.TypeFor[testRequest]() || route.Response != reflect.TypeFor[testResponse]() {
+		t.Errorf("期望记录请求和响应类型, 实际得到 %v 和 %v", route.Request, route.Response)
+	}
+	if route.Meta.Name != "getUser" || route.Meta.Summary != "获取用户" {
+		t.Errorf("期望记录处理器元数据, 实际得到 %+v", route.Meta)
+	}
+
+	route, ok = registry.Find("createUser")
+	if !ok || route.Method != http.MethodPost || route.Path != "/api/admin/user/" {
+		t.Errorf("期望按名称找到 POST /api/admin/user/, 实际得到 %+v", route)
+	}
+	if tagged := registry.Tagged("admin"); len(tagged) != 1 || tagged[0].Meta.Name != "createUser" {
+		t.Errorf("期望按标签找到 1 个路由, 实际得到 %+v", tagged)
+	}
+
+	// 返回的路由是副本，修改不影响注册表
+	routes[0].Meta.Tags[0] = "changed"
+	if route, _ := registry.Find("getUser"); route.Meta.Tags[0] != "user" {
+		t.Errorf("期望修改返回的路由不影响注册表, 实际得到 %v", route.Meta.Tags)
+	}
+
+	registry.Reset()
+	if len(registry.Routes()) != 0 {
+		t.Error("期望清空注册表")
+	}
+}
+
+// 测试相同方法和路径的路由被替换，注册表为 nil 时不记录
+func TestRegistryReplaceAndDisable(t *testing.T) {
+	r := gin.New()
+	registry := NewRegistry()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return &testResponse{}, nil
+	}
+	registry.Add(RouteInfo{Method: http.MethodGet, Path: "/ping", Meta: Meta{Name: "old"}})
+	GET(NewGroup(&r.RouterGroup, WithRegistry(registry)), "/ping", handleFunc, WithMeta(Meta{Name: "ping"}))
+	GET(NewGroup(r.Group("/other"), WithRegistry(nil)), "/ping", handleFunc)
+
+	routes := registry.Routes()
+	if len(routes) != 1 || routes[0].Meta.Name != "ping" {
+		t.Errorf("期望替换相同路由且不记录禁用注册表的路由, 实际得到 %+v", routes)
+	}
+}
+
+// 测试声明的业务错误记录到注册表和 OpenAPI 文档
+func TestRegistryErrors(t *testing.T) {
+	r := gin.New()
+	registry := NewRegistry()
+	errUnauthorized := NewBizError(10001, "未登录", http.StatusUnauthorized)
+	errNotFound := NewBizError(20004, "用户不存在", 0)
+	errDisabled := NewBizError(20005, "用户已禁用", 0)
+
+	getUser := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return &testResponse{}, nil
+	}
+	api := NewGroup(r.Group("/api"), WithRegistry(registry), WithErrors(errUnauthorized),
+		WithCodeRanges(CodeRange{Min: 20000, Max: 29999, HTTPCode: http.StatusNotFound}))
+	GET(api, "/user/:id", getUser, WithErrors(errNotFound, errDisabled))
+	GET(api, "/ping", getUser)
+
+	route, _ := registry.Lookup(http.MethodGet, "/api/user/:id")
+	want := []RouteError{
+		{Code: 10001, HTTPCode: http.StatusUnauthorized, Message: "未登录"},
+		{Code: 20004, HTTPCode: http.StatusNotFound, Message: "用户不存在"},
+		{Code: 20005, HTTPCode: http.StatusNotFound, Message: "用户已禁用"},
+	}
+	if !reflect.DeepEqual(route.Errors, want) {
+		t.Errorf("期望记录分组和路由声明的错误, 实际得到 %+v", route.Errors)
+	}
+	if route, _ := registry.Lookup(http.MethodGet, "/api/ping"); len(route.Errors) != 1 {
+		t.Errorf("期望路由的错误声明不影响其他路由, 实际得到 %+v", route.Errors)
+	}
+
+	doc := registry.OpenAPI(OpenAPIInfo{Title: "User API", Version: "1.0.0"})
+	responses := doc.Paths["/api/user/{id}"].Get.Responses
+	if resp := responses["404"]; resp == nil || resp.Description != "20004: 用户不存在\n20005: 用户已禁用" {
+		t.Errorf("期望相同状态码的错误合并为一个响应, 实际得到 %+v", resp)
+	}
+	if responses["401"] == nil {
+		t.Error("期望生成 401 响应")
+	}
+}
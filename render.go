@@ -0,0 +1,292 @@
+package apihandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Format 响应格式
+type Format string
+
+// 支持的响应格式
+const (
+	FormatJSON    Format = "json"
+	FormatXML     Format = "xml"
+	FormatMsgPack Format = "msgpack"
+)
+
+// 各响应格式的 Content-Type
+const (
+	jsonContentType  = "application/json; charset=utf-8"
+	xmlContentType   = "application/xml; charset=utf-8"
+	jsonpContentType = "application/javascript; charset=utf-8"
+)
+
+// render 按响应格式编码并输出响应体
+func render(c *gin.Context, config *HandlerConfig, httpCode int, body any) {
+	format := responseFormat(c, config)
+	contentType, data, err := encodeBody(config, format, body)
+	if err != nil {
+		// 编码错误可能包含响应数据的类型和内容，只记录在 gin.Context 中，响应使用通用的内部错误消息
+		c.Error(err)
+		message := requestTranslator(c, config).Translate(MsgInternalError)
+		data, _ = json.Marshal(ErrorResponse{Code: http.StatusInternalServerError, Message: message})
+		c.Data(http.StatusInternalServerError, jsonContentType, data)
+		return
+	}
+
+	if ct, ok := body.(bodyContentTyper); ok {
+		if v := ct.contentType(format); v != "" {
+			contentType = v
+		}
+	}
+
+	status := httpCode
+	if callback := jsonpCallback(c, config, format); callback != "" {
+		// <script> 标签无法读取 HTTP 状态码且非 2xx 响应不会执行脚本，因此 JSONP 响应总是返回 200，
+		// 调用方通过响应体中的 code 判断结果
+		contentType, data = jsonpContentType, []byte("/**/"+callback+"("+string(data)+");")
+		c.Header("X-Content-Type-Options", "nosniff")
+		status = http.StatusOK
+	}
+
+	if httpCode >= 200 && httpCode < 300 {
+		storeCachedResponse(c, config, status, contentType, data)
+	}
+	if config.IdempotencyStore != nil {
+		storeIdempotentResponse(c, config, status, contentType, data)
+	}
+	writeBody(c, config, httpCode, status, contentType, data)
+}
+
+// writeBody 输出编码后的响应体，成功响应在开启 ETag 时先处理条件请求
+func writeBody(c *gin.Context, config *HandlerConfig, httpCode, status int, contentType string, data []byte) {
+	if config.ETag && httpCode >= 200 && httpCode < 300 && notModified(c, data) {
+		return
+	}
+
+	data = compressBody(c, config, data)
+	c.Data(status, contentType, data)
+}
+
+// bodyContentTyper 需要使用特定 Content-Type 的响应体，如 application/problem+json
+type bodyContentTyper interface {
+	contentType(format Format) string
+}
+
+// encodeBody 将响应体编码为指定格式
+func encodeBody(config *HandlerConfig, format Format, body any) (contentType string, data []byte, err error) {
+	switch format {
+	case FormatXML:
+		data, err = xml.Marshal(body)
+		return xmlContentType, data, err
+	case FormatMsgPack:
+		return encodeMsgPack(body)
+	default:
+		data, err = marshalJSON(config.jsonCodec(), body)
+		if err == nil && config.IndentJSON {
+			var buf bytes.Buffer
+			if err = json.Indent(&buf, data, "", "    "); err == nil {
+				data = buf.Bytes()
+			}
+		}
+		return jsonContentType, data, err
+	}
+}
+
+// jsonpCallbackPattern 合法的 JSONP 回调函数名，避免注入任意脚本
+var jsonpCallbackPattern = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$.]{0,127}$`)
+
+// jsonpCallback 返回请求中合法的 JSONP 回调函数名，未开启 JSONP 或不是 JSON 响应时返回空
+func jsonpCallback(c *gin.Context, config *HandlerConfig, format Format) string {
+	if format != FormatJSON || config.JSONPCallback == "" {
+		return ""
+	}
+	callback := c.Query(config.JSONPCallback)
+	if !jsonpCallbackPattern.MatchString(callback) {
+		return ""
+	}
+	return callback
+}
+
+// formatMIMETypes 各响应格式对应的 MIME 类型
+var formatMIMETypes = map[Format][]string{
+	FormatJSON:    {"application/json"},
+	FormatXML:     {"application/xml", "text/xml"},
+	FormatMsgPack: {"application/msgpack", "application/x-msgpack"},
+}
+
+// defaultFormats 默认的响应格式，XML 和 MessagePack 需要通过 WithFormats 开启
+//
+// 浏览器的 Accept 头通常包含 application/xml，默认协商 XML 会使返回 map 等 XML 无法编码的数据的处理器出错。
+var defaultFormats = []Format{FormatJSON}
+
+// responseFormat 确定响应格式
+//
+// 配置了 ResponseFormat 时直接使用，否则根据 Accept 头在 Formats 中协商，
+// 无法匹配时使用 Formats 中的第一个格式，并输出 Vary: Accept。只有一个格式时不进行协商。
+func responseFormat(c *gin.Context, config *HandlerConfig) Format {
+	if config.ResponseFormat != "" {
+		return config.ResponseFormat
+	}
+
+	offers := config.Formats
+	if len(offers) == 0 {
+		offers = defaultFormats
+	}
+	if len(offers) == 1 {
+		return offers[0]
+	}
+	c.Writer.Header().Add("Vary", "Accept")
+	return negotiateFormat(c.GetHeader("Accept"), offers)
+}
+
+// negotiateFormat 按 Accept 头的权重顺序选择第一个匹配的格式，offers[0] 为默认格式
+func negotiateFormat(accept string, offers []Format) Format {
+	for _, accepted := range parseAccept(accept) {
+		for _, offer := range offers {
+			for _, mimeType := range formatMIMETypes[offer] {
+				if matchMIMEType(accepted.value, mimeType) {
+					return offer
+				}
+			}
+		}
+	}
+	return offers[0]
+}
+
+// matchMIMEType 判断 Accept 中的类型（可包含通配符）是否匹配 MIME 类型
+func matchMIMEType(accepted, mimeType string) bool {
+	if accepted == "*/*" || accepted == "*" || accepted == mimeType {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(accepted, "/*"); ok {
+		return strings.HasPrefix(mimeType, prefix+"/")
+	}
+	return false
+}
+
+// qualityValue 带权重的取值，如 Accept 头中的 "application/xml;q=0.9"
+type qualityValue struct {
+	value   string
+	quality float64
+}
+
+// parseAccept 解析 Accept 类的请求头，按权重从高到低排序，权重相同时保持原有顺序
+func parseAccept(header string) []qualityValue {
+	var values []qualityValue
+	for _, part := range strings.Split(header, ",") {
+		params := strings.Split(part, ";")
+		value := strings.ToLower(strings.TrimSpace(params[0]))
+		if value == "" {
+			continue
+		}
+		quality := 1.0
+		for _, param := range params[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil {
+					quality = q
+				}
+			}
+		}
+		if quality <= 0 {
+			continue
+		}
+		values = append(values, qualityValue{value: value, quality: quality})
+	}
+	sort.SliceStable(values, func(i, j int) bool {
+		return values[i].quality > values[j].quality
+	})
+	return values
+}
+
+// MarshalXML 实现 xml.Marshaler 接口，map 类型的错误详情按键输出为子元素
+func (e ErrorResponse) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	start.Name = xml.Name{Local: "response"}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	if err := enc.EncodeElement(e.Code, xml.StartElement{Name: xml.Name{Local: "code"}}); err != nil {
+		return err
+	}
+	if err := enc.EncodeElement(e.Message, xml.StartElement{Name: xml.Name{Local: "message"}}); err != nil {
+		return err
+	}
+	if len(e.Errors) > 0 {
+		if err := encodeXMLDetails(enc, "errors", e.Errors); err != nil {
+			return err
+		}
+	}
+	if e.Stack != "" {
+		if err := enc.EncodeElement(e.Stack, xml.StartElement{Name: xml.Name{Local: "stack"}}); err != nil {
+			return err
+		}
+	}
+	if len(e.CauseChain) > 0 {
+		if err := enc.EncodeElement(struct {
+			Causes []string `xml:"cause"`
+		}{e.CauseChain}, xml.StartElement{Name: xml.Name{Local: "cause_chain"}}); err != nil {
+			return err
+		}
+	}
+	if err := encodeXMLRequestID(enc, e.RequestIDField, e.RequestID); err != nil {
+		return err
+	}
+	return enc.EncodeToken(start.End())
+}
+
+// encodeXMLDetails 输出错误详情列表，每条详情为一个 <error> 子元素
+func encodeXMLDetails(enc *xml.Encoder, name string, details []any) error {
+	start := xml.StartElement{Name: xml.Name{Local: name}}
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, detail := range details {
+		if err := encodeXMLDetail(enc, detail); err != nil {
+			return err
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
+
+// encodeXMLDetail 输出单条错误详情
+func encodeXMLDetail(enc *xml.Encoder, detail any) error {
+	start := xml.StartElement{Name: xml.Name{Local: "error"}}
+	var fields map[string]any
+	switch d := detail.(type) {
+	case map[string]string:
+		fields = make(map[string]any, len(d))
+		for k, v := range d {
+			fields[k] = v
+		}
+	case map[string]any:
+		fields = d
+	default:
+		return enc.EncodeElement(detail, start)
+	}
+
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	if err := enc.EncodeToken(start); err != nil {
+		return err
+	}
+	for _, k := range keys {
+		if err := enc.EncodeElement(fields[k], xml.StartElement{Name: xml.Name{Local: k}}); err != nil {
+			return err
+		}
+	}
+	return enc.EncodeToken(start.End())
+}
@@ -0,0 +1,17 @@
+//go:build !nomsgpack
+
+package apihandler
+
+import "github.com/ugorji/go/codec"
+
+// msgpackContentType MessagePack 响应的 Content-Type
+const msgpackContentType = "application/msgpack; charset=utf-8"
+
+// msgpackHandle MessagePack 编码配置，只在包初始化时配置，之后可以被多个编码器并发使用
+var msgpackHandle codec.MsgpackHandle
+
+// encodeMsgPack 将响应体编码为 MessagePack
+func encodeMsgPack(body any) (contentType string, data []byte, err error) {
+	err = codec.NewEncoderBytes(&data, &msgpackHandle).Encode(body)
+	return msgpackContentType, data, err
+}
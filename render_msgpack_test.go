@@ -0,0 +1,72 @@
+//go:build !nomsgpack
+
+package apihandler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/ugorji/go/codec"
+)
+
+// 测试 MessagePack 响应
+func TestRenderMsgPack(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		if req.ID == 0 {
+			return nil, ErrNotFound(40400, "资源不存在")
+		}
+		return &testResponse{ID: req.ID, Name: req.Name}, nil
+	}
+
+	r.GET("/test/:id", Handler(handleFunc, WithResponseFormat(FormatMsgPack)))
+	r.GET("/negotiate/:id", Handler(handleFunc, WithFormats(FormatJSON, FormatMsgPack)))
+
+	req := httptest.NewRequest("GET", "/test/1?name=tom", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if ct := w.Header().Get("Content-Type"); ct != "application/msgpack; charset=utf-8" {
+		t.Errorf("期望 Content-Type 为 application/msgpack, 实际得到 '%s'", ct)
+	}
+
+	var resp struct {
+		Code int          `codec:"code"`
+		Data testResponse `codec:"data"`
+	}
+	if err := codec.NewDecoderBytes(w.Body.Bytes(), new(codec.MsgpackHandle)).Decode(&resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+
+	if resp.Data.ID != 1 || resp.Data.Name != "tom" {
+		t.Errorf("期望 data 为 {id:1, name:tom}, 实际得到 %+v", resp.Data)
+	}
+
+	// 通过 Accept 头请求 MessagePack 格式的错误响应
+	req = httptest.NewRequest("GET", "/negotiate/0", nil)
+	req.Header.Set("Accept", "application/x-msgpack")
+	w = httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusNotFound, w.Code)
+	}
+
+	var errResp struct {
+		Code    int    `codec:"code"`
+		Message string `codec:"message"`
+	}
+	if err := codec.NewDecoderBytes(w.Body.Bytes(), new(codec.MsgpackHandle)).Decode(&errResp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+
+	if errResp.Code != 40400 || errResp.Message != "资源不存在" {
+		t.Errorf("期望错误为 {40400, 资源不存在}, 实际得到 %+v", errResp)
+	}
+}
@@ -0,0 +1,11 @@
+//go:build nomsgpack
+
+package apihandler
+
+import "encoding/json"
+
+// encodeMsgPack 使用 nomsgpack 构建时不支持 MessagePack，回退为 JSON
+func encodeMsgPack(body any) (contentType string, data []byte, err error) {
+	data, err = json.Marshal(body)
+	return jsonContentType, data, err
+}
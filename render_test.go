@@ -0,0 +1,277 @@
+package apihandler
+
+import (
+	"bytes"
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// XML 响应测试用的响应结构
+type xmlResponse struct {
+	ID   int64  `json:"id" xml:"id"`
+	Name string `json:"name" xml:"name"`
+}
+
+// 测试通过选项指定 XML 响应
+func TestRenderXMLOption(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*xmlResponse, error) {
+		return &xmlResponse{ID: req.ID, Name: req.Name}, nil
+	}
+
+	r.GET("/test/:id", Handler(handleFunc, WithResponseFormat(FormatXML)))
+
+	req := httptest.NewRequest("GET", "/test/1?name=tom", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/xml") {
+		t.Errorf("期望 Content-Type 为 application/xml, 实际得到 '%s'", ct)
+	}
+
+	expected := "<response><code>0</code><data><id>1</id><name>tom</name></data></response>"
+	if w.Body.String() != expected {
+		t.Errorf("期望响应为 %s, 实际得到 %s", expected, w.Body.String())
+	}
+}
+
+// 测试根据 Accept 头返回 XML
+func TestRenderXMLAccept(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*xmlResponse, error) {
+		return &xmlResponse{ID: req.ID}, nil
+	}
+
+	r.GET("/test/:id", Handler(handleFunc, WithFormats(FormatJSON, FormatXML)))
+
+	cases := []struct {
+		accept      string
+		contentType string
+	}{
+		{"application/xml", "application/xml"},
+		{"text/xml;q=0.8, application/json;q=0.5", "application/xml"},
+		{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", "application/xml"},
+		{"text/html,*/*;q=0.8", "application/json"},
+		{"", "application/json"},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", "/test/1", nil)
+		req.Header.Set("Accept", tc.accept)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, tc.contentType) {
+			t.Errorf("Accept '%s': 期望 Content-Type 为 %s, 实际得到 '%s'", tc.accept, tc.contentType, ct)
+		}
+	}
+}
+
+// 测试默认只返回 JSON，浏览器的 Accept 头不会协商出 XML
+func TestRenderDefaultJSON(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*map[string]any, error) {
+		return &map[string]any{"id": req.ID}, nil
+	}
+	r.GET("/test/:id", Handler(handleFunc))
+
+	req := httptest.NewRequest("GET", "/test/1", nil)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("期望状态码 %d, 实际得到 %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("期望 Content-Type 为 application/json, 实际得到 '%s'", ct)
+	}
+	if expected := `{"code":0,"data":{"id":1}}`; w.Body.String() != expected {
+		t.Errorf("期望响应为 %s, 实际得到 %s", expected, w.Body.String())
+	}
+}
+
+// 测试 XML 格式的错误响应包含验证错误详情
+func TestRenderXMLError(t *testing.T) {
+	type createRequest struct {
+		Name string `json:"name" binding:"required"`
+	}
+
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *createRequest) (*xmlResponse, error) {
+		return &xmlResponse{}, nil
+	}
+
+	r.POST("/create", Handler(handleFunc, WithResponseFormat(FormatXML)))
+
+	req := httptest.NewRequest("POST", "/create", bytes.NewBufferString(`{}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusBadRequest, w.Code)
+	}
+
+	expected := "<response><code>400</code><message>参数绑定失败</message>" +
+		"<errors><error><field>name</field><rule>required</rule><message>字段验证失败: required</message></error></errors></response>"
+	if w.Body.String() != expected {
+		t.Errorf("期望响应为 %s, 实际得到 %s", expected, w.Body.String())
+	}
+}
+
+// 测试内容协商：权重、通配符、默认格式和 Vary 头
+func TestRenderNegotiation(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*xmlResponse, error) {
+		return &xmlResponse{ID: req.ID}, nil
+	}
+
+	r.GET("/default/:id", Handler(handleFunc))
+	r.GET("/all/:id", Handler(handleFunc, WithFormats(FormatJSON, FormatXML, FormatMsgPack)))
+	r.GET("/xml-first/:id", Handler(handleFunc, WithFormats(FormatXML, FormatJSON)))
+	r.GET("/fixed/:id", Handler(handleFunc, WithResponseFormat(FormatJSON)))
+
+	cases := []struct {
+		path        string
+		accept      string
+		contentType string
+	}{
+		{"/default/1", "application/xml", "application/json"},
+		{"/default/1", "application/msgpack", "application/json"},
+		{"/all/1", "application/json;q=0.5, application/msgpack", "application/msgpack"},
+		{"/all/1", "application/*", "application/json"},
+		{"/all/1", "text/*", "application/xml"},
+		{"/all/1", "image/png", "application/json"},
+		{"/xml-first/1", "", "application/xml"},
+		{"/xml-first/1", "*/*", "application/xml"},
+		{"/xml-first/1", "application/msgpack", "application/xml"},
+		{"/xml-first/1", "application/json", "application/json"},
+		{"/fixed/1", "application/xml", "application/json"},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", tc.path, nil)
+		req.Header.Set("Accept", tc.accept)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, tc.contentType) {
+			t.Errorf("%s Accept '%s': 期望 Content-Type 为 %s, 实际得到 '%s'", tc.path, tc.accept, tc.contentType, ct)
+		}
+
+		vary := w.Header().Get("Vary")
+		if strings.HasPrefix(tc.path, "/fixed") || strings.HasPrefix(tc.path, "/default") {
+			if vary != "" {
+				t.Errorf("%s: 固定格式或只有 JSON 时不应输出 Vary, 实际得到 '%s'", tc.path, vary)
+			}
+		} else if vary != "Accept" {
+			t.Errorf("%s: 期望 Vary 为 'Accept', 实际得到 '%s'", tc.path, vary)
+		}
+	}
+}
+
+// 测试 JSONP 响应
+func TestRenderJSONP(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*xmlResponse, error) {
+		if req.ID == 0 {
+			return nil, ErrNotFound(40400, "资源不存在")
+		}
+		return &xmlResponse{ID: req.ID}, nil
+	}
+
+	r.GET("/test/:id", Handler(handleFunc, WithJSONP("cb")))
+
+	cases := []struct {
+		path        string
+		httpCode    int
+		contentType string
+		body        string
+	}{
+		{"/test/1?cb=jQuery.handle_1", http.StatusOK, "application/javascript", `/**/jQuery.handle_1({"code":0,"data":{"id":1,"name":""}});`},
+		{"/test/0?cb=handle", http.StatusOK, "application/javascript", `/**/handle({"code":40400,"message":"资源不存在"});`},
+		{"/test/1", http.StatusOK, "application/json", `{"code":0,"data":{"id":1,"name":""}}`},
+		{"/test/1?cb=alert(1)//", http.StatusOK, "application/json", `{"code":0,"data":{"id":1,"name":""}}`},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", tc.path, nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.httpCode {
+			t.Errorf("%s: 期望状态码 %d, 实际得到 %d", tc.path, tc.httpCode, w.Code)
+		}
+
+		if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, tc.contentType) {
+			t.Errorf("%s: 期望 Content-Type 为 %s, 实际得到 '%s'", tc.path, tc.contentType, ct)
+		}
+
+		if w.Body.String() != tc.body {
+			t.Errorf("%s: 期望响应为 %s, 实际得到 %s", tc.path, tc.body, w.Body.String())
+		}
+	}
+}
+
+// 测试缩进格式的 JSON 输出
+func TestRenderIndentedJSON(t *testing.T) {
+	r := gin.New()
+
+	r.GET("/test/:id", Handler(func(ctx context.Context, req *testRequest) (*xmlResponse, error) {
+		return &xmlResponse{ID: req.ID, Name: "tom"}, nil
+	}, WithIndentedJSON()))
+
+	req := httptest.NewRequest("GET", "/test/1", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	expected := "{\n    \"code\": 0,\n    \"data\": {\n        \"id\": 1,\n        \"name\": \"tom\"\n    }\n}"
+	if w.Body.String() != expected {
+		t.Errorf("期望响应为 %q, 实际得到 %q", expected, w.Body.String())
+	}
+}
+
+// 测试响应编码失败时不输出编码错误的内容
+func TestRenderEncodeError(t *testing.T) {
+	r := gin.New()
+
+	type badResponse struct {
+		Secret string  `json:"secret"`
+		Ratio  float64 `json:"ratio"`
+	}
+	r.GET("/test/:id", Handler(func(ctx context.Context, req *testRequest) (*badResponse, error) {
+		return &badResponse{Secret: "token", Ratio: math.Inf(1)}, nil
+	}))
+
+	req := httptest.NewRequest("GET", "/test/1", nil)
+	req.Header.Set("Accept-Language", "en")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusInternalServerError, w.Code)
+	}
+	expected := `{"code":500,"message":"Internal server error"}`
+	if w.Body.String() != expected {
+		t.Errorf("期望响应为 %s, 实际得到 %s", expected, w.Body.String())
+	}
+}
@@ -0,0 +1,90 @@
+package apihandler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// RequestBinder 请求类型实现该接口时，处理器调用 BindRequest 绑定参数，不再通过反射绑定和验证，通常由 apihandlergen 生成
+//
+// 处理器先解码 JSON 请求体（配置了 JSON 编解码器时使用其解码），再调用 BindRequest 绑定路径参数、查询参数或表单、
+// 请求头并验证字段。返回业务错误时直接输出该错误；返回的 FieldError（或 errors.Join 合并的多个 FieldError）和
+// validator 的验证错误作为参数绑定失败的 400 错误详情，Message 为空时使用 ValidationMessageKey(Rule) 的消息；
+// 返回其他错误时输出包含错误消息的参数绑定失败的 400 错误。JSON 和表单以外的请求体（如 XML）仍通过反射绑定。
+type RequestBinder interface {
+	BindRequest(c *gin.Context) error
+}
+
+// generatedBindable 判断请求能否使用 RequestBinder 绑定，与 gin 按 Content-Type 选择的绑定一致；
+// 没有请求体字段的请求类型不解码请求体，任何 Content-Type 都可以使用 RequestBinder 绑定
+func generatedBindable(c *gin.Context) bool {
+	if c.Request.Method == http.MethodGet {
+		return true
+	}
+	switch c.ContentType() {
+	case binding.MIMEJSON, binding.MIMEPOSTForm, binding.MIMEMultipartPOSTForm, "":
+		return true
+	}
+	return false
+}
+
+// bindGenerated 解码 JSON 请求体后调用 RequestBinder 绑定其他参数并验证
+func bindGenerated(c *gin.Context, config *HandlerConfig, translator Translator, binder RequestBinder) error {
+	if c.ContentType() == binding.MIMEJSON && !skipBody(c, binder) {
+		if err := decodeJSONBody(c.Request, config.JSONCodec, binder); err != nil {
+			return NewBizError(config.BindErrorCode, translator.Translate(MsgBindErrorDetail, err), http.StatusBadRequest)
+		}
+	}
+	err := binder.BindRequest(c)
+	// 填充认证声明，claim 字段按声明的值重新验证
+	var validationErrs validator.ValidationErrors
+	if config.ClaimsExtractor != nil && (err == nil || errors.As(err, &validationErrs)) {
+		claimed, claimErr := bindClaims(c, config, translator, binder)
+		if claimErr != nil {
+			return claimErr
+		}
+		if claimed && binding.Validator != nil {
+			err = binding.Validator.ValidateStruct(binder)
+		}
+	}
+	if err == nil && config.ValidationScenario != "" {
+		err = validateScenario(binder, config.ValidationScenario)
+	}
+	var bizErr BizError
+	if errors.As(err, &bizErr) {
+		return err
+	}
+	if err != nil {
+		return bindError(c, config, translator, binder, err)
+	}
+	return nil
+}
+
+// decodeJSONBody 解码 JSON 请求体但不验证，未配置编解码器时与 gin 的 JSON 绑定使用相同的解码选项
+func decodeJSONBody(r *http.Request, codec JSONCodec, obj any) error {
+	if r == nil || r.Body == nil {
+		return errors.New("invalid request")
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	if codec != nil {
+		return codec.Unmarshal(body, obj)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if binding.EnableDecoderUseNumber {
+		decoder.UseNumber()
+	}
+	if binding.EnableDecoderDisallowUnknownFields {
+		decoder.DisallowUnknownFields()
+	}
+	return decoder.Decode(obj)
+}
@@ -0,0 +1,122 @@
+package apihandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// boundRequest 手写的 RequestBinder，记录是否调用了 BindRequest
+type boundRequest struct {
+	ID    string `path:"id" json:"id" xml:"id"`
+	Name  string `json:"name" xml:"name" binding:"required"`
+	Bound bool   `json:"bound" xml:"-"`
+}
+
+// BindRequest 实现 RequestBinder 接口
+func (r *boundRequest) BindRequest(c *gin.Context) error {
+	r.Bound = true
+	r.ID = c.Param("id")
+	switch r.Name {
+	case "":
+		return FieldError{Field: "name", Rule: "required"}
+	case "multi":
+		return errors.Join(FieldError{Field: "name", Rule: "min", Param: "8", Value: r.Name}, FieldError{Field: "id", Rule: "len", Param: "3"})
+	case "plain":
+		return errors.New("broken")
+	case "biz":
+		return NewBizError(40901, "conflict", http.StatusConflict)
+	}
+	return nil
+}
+
+// 测试请求类型实现 RequestBinder 时不通过反射绑定
+func TestRequestBinder(t *testing.T) {
+	original := englishMessages[ValidationMessageKey("min")]
+	if err := RegisterValidationMessage("min", "en", "{field} must be at least {param} characters"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if original == "" {
+			delete(englishMessages, ValidationMessageKey("min"))
+		} else {
+			englishMessages[ValidationMessageKey("min")] = original
+		}
+	})
+
+	r := gin.New()
+	r.POST("/items/:id", Handler(func(ctx context.Context, req *boundRequest) (*boundRequest, error) {
+		return req, nil
+	}))
+
+	testCases := []struct {
+		name        string
+		contentType string
+		body        string
+		code        int
+		bound       bool
+		message     string
+		details     []FieldError
+	}{
+		{"JSON 请求体", "application/json", `{"name":"ok"}`, http.StatusOK, true, "", nil},
+		{"字段错误", "application/json", `{}`, http.StatusBadRequest, true, englishMessages[MsgBindError], []FieldError{
+			{Field: "name", Rule: "required", Message: englishMessages[ValidationMessageKey("required")]},
+		}},
+		{"合并的字段错误", "application/json", `{"name":"multi"}`, http.StatusBadRequest, true, englishMessages[MsgBindError], []FieldError{
+			{Field: "name", Rule: "min", Param: "8", Message: "name must be at least 8 characters"},
+			{Field: "id", Rule: "len", Param: "3"},
+		}},
+		{"其他错误", "application/json", `{"name":"plain"}`, http.StatusBadRequest, true, "Parameter binding failed: broken", nil},
+		{"业务错误", "application/json", `{"name":"biz"}`, http.StatusConflict, true, "conflict", nil},
+		{"格式错误的 JSON", "application/json", `{"name":`, http.StatusBadRequest, false, "", nil},
+		{"XML 通过反射绑定", "application/xml", `<boundRequest><name>ok</name></boundRequest>`, http.StatusOK, false, "", nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/items/abc", bytes.NewBufferString(tc.body))
+			req.Header.Set("Content-Type", tc.contentType)
+			req.Header.Set("Accept-Language", "en")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tc.code {
+				t.Fatalf("期望状态码为 %d, 实际得到 %d: %s", tc.code, w.Code, w.Body.String())
+			}
+			if tc.code == http.StatusOK {
+				var resp SuccessResponse[boundRequest]
+				if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+					t.Fatalf("解析响应失败: %v", err)
+				}
+				if resp.Data.Bound != tc.bound || resp.Data.ID != "abc" || resp.Data.Name != "ok" {
+					t.Errorf("期望 bound=%v 且绑定 id 和 name, 实际得到 %+v", tc.bound, resp.Data)
+				}
+				return
+			}
+
+			var resp ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("解析响应失败: %v", err)
+			}
+			if tc.message != "" && resp.Message != tc.message {
+				t.Errorf("期望消息为 '%s', 实际得到 '%s'", tc.message, resp.Message)
+			}
+			fieldErrors := resp.FieldErrors()
+			if len(fieldErrors) != len(tc.details) {
+				t.Fatalf("期望 %d 个字段错误, 实际得到 %+v", len(tc.details), fieldErrors)
+			}
+			for i, fe := range fieldErrors {
+				want := tc.details[i]
+				if fe.Field != want.Field || fe.Rule != want.Rule || fe.Param != want.Param || fe.Message == "" || want.Message != "" && fe.Message != want.Message {
+					t.Errorf("期望字段错误为 %+v, 实际得到 %+v", want, fe)
+				}
+			}
+		})
+	}
+}
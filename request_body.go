@@ -0,0 +1,53 @@
+package apihandler
+
+import (
+	"net/http"
+	"reflect"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+// bodylessTypes 请求类型是否没有请求体字段，键为请求类型，在创建处理器时计算
+var bodylessTypes sync.Map
+
+// isBodyless 判断请求类型是否没有请求体字段，结果按类型缓存
+//
+// 路径参数、请求头和认证声明字段以及只有 form tag 的字段不是请求体字段；带有 json 或 xml tag（不为 -）的字段
+// 和没有任何 tag 的字段（JSON 按字段名解码）是请求体字段。
+func isBodyless(t reflect.Type) bool {
+	if bodyless, ok := bodylessTypes.Load(t); ok {
+		return bodyless.(bool)
+	}
+	bodyless := true
+	for _, field := range requestFields(t) {
+		if isParameterField(field) {
+			continue
+		}
+		jsonTag, hasJSON := field.Tag.Lookup("json")
+		xmlTag, hasXML := field.Tag.Lookup("xml")
+		_, hasForm := field.Tag.Lookup("form")
+		if hasJSON && jsonTag != "-" || hasXML && xmlTag != "-" || !hasJSON && !hasXML && !hasForm {
+			bodyless = false
+			break
+		}
+	}
+	bodylessTypes.Store(t, bodyless)
+	return bodyless
+}
+
+// skipBody 判断是否跳过请求体的解码：请求类型没有请求体字段，且请求体不是表单（表单字段来自请求体）
+func skipBody(c *gin.Context, req any) bool {
+	if !isBodyless(reflect.TypeOf(req)) {
+		return false
+	}
+	if c.Request.Method == http.MethodGet {
+		return true
+	}
+	switch c.ContentType() {
+	case binding.MIMEPOSTForm, binding.MIMEMultipartPOSTForm:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// queryOnlyRequest 只有路径参数、查询参数和请求头的请求
+type queryOnlyRequest struct {
+	ID      string `path:"id" binding:"required"`
+	Keyword string `form:"keyword" binding:"required"`
+	Page    int    `form:"page,default=1"`
+	Token   string `header:"X-Token"`
+	Ignored string `json:"-"`
+}
+
+// 测试判断请求类型是否有请求体字段
+func TestIsBodyless(t *testing.T) {
+	type embedded struct {
+		Name string `json:"name"`
+	}
+	testCases := []struct {
+		name     string
+		req      any
+		bodyless bool
+	}{
+		{"只有路径参数和查询参数", queryOnlyRequest{}, true},
+		{"空结构体", struct{}{}, true},
+		{"认证声明字段", struct {
+			UserID string `claim:"sub"`
+		}{}, true},
+		{"JSON 字段", struct {
+			Name string `json:"name"`
+		}{}, false},
+		{"同时有 form 和 json tag", struct {
+			Name string `form:"name" json:"name"`
+		}{}, false},
+		{"XML 字段", struct {
+			Name string `xml:"name"`
+		}{}, false},
+		{"没有 tag 的字段", struct{ Name string }{}, false},
+		{"嵌入结构体中的 JSON 字段", struct {
+			embedded
+			ID string `path:"id"`
+		}{}, false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := isBodyless(reflect.TypeOf(tc.req)); got != tc.bodyless {
+				t.Errorf("期望 %v, 实际得到 %v", tc.bodyless, got)
+			}
+		})
+	}
+}
+
+// 测试没有请求体字段的请求类型跳过请求体的解码
+func TestSkipBodyBinding(t *testing.T) {
+	r := gin.New()
+	handler := Handler(func(ctx context.Context, req *queryOnlyRequest) (*queryOnlyRequest, error) {
+		return req, nil
+	})
+	r.GET("/items/:id", handler)
+	r.DELETE("/items/:id", handler)
+	r.POST("/items/:id", handler)
+
+	testCases := []struct {
+		name        string
+		method      string
+		target      string
+		contentType string
+		body        string
+		code        int
+		keyword     string
+	}{
+		{"GET 带有多余的 JSON 请求体", http.MethodGet, "/items/1?keyword=go", "application/json", `{"keyword":`, http.StatusOK, "go"},
+		{"DELETE 声明 JSON 但没有请求体", http.MethodDelete, "/items/1?keyword=go", "application/json", "", http.StatusOK, "go"},
+		{"DELETE 带有无法解析的 XML 请求体", http.MethodDelete, "/items/1?keyword=go", "application/xml", "<broken", http.StatusOK, "go"},
+		{"POST 表单仍然绑定表单字段", http.MethodPost, "/items/1", "application/x-www-form-urlencoded", "keyword=form", http.StatusOK, "form"},
+		{"请求体中的字段不绑定", http.MethodPost, "/items/1", "application/json", `{"keyword":"go"}`, http.StatusBadRequest, ""},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body))
+			req.Header.Set("Content-Type", tc.contentType)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tc.code {
+				t.Fatalf("期望状态码 %d, 实际得到 %d: %s", tc.code, w.Code, w.Body.String())
+			}
+			if tc.code != http.StatusOK {
+				return
+			}
+			var resp SuccessResponse[queryOnlyRequest]
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Data.ID != "1" || resp.Data.Keyword != tc.keyword || resp.Data.Page != 1 {
+				t.Errorf("期望 ID 1、keyword %s、page 1, 实际得到 %+v", tc.keyword, resp.Data)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package apihandler
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultRequestIDField 错误响应中请求 ID 的默认字段名
+const DefaultRequestIDField = "request_id"
+
+// RequestIDHeader 携带请求 ID 的请求头
+const RequestIDHeader = "X-Request-Id"
+
+// RequestIDFunc 获取请求 ID 的函数，返回空字符串表示没有请求 ID
+type RequestIDFunc func(c *gin.Context) string
+
+// RequestIDGenerator 生成请求 ID 的函数
+type RequestIDGenerator func() string
+
+// WithRequestID 确保每个请求都有请求 ID
+//
+// 请求 ID 按 RequestIDFunc、X-Request-Id 请求头、traceparent 请求头的顺序获取，都没有时使用 generator 生成，
+// generator 为空时生成 32 位十六进制的随机字符串。请求 ID 保存在请求 context 中（可通过 RequestIDFromContext
+// 获取，包括 RequestLogger 收到的 *http.Request），写入 X-Request-Id 响应头，并输出在成功和错误响应中。
+func WithRequestID(generator RequestIDGenerator) Option {
+	return func(c *HandlerConfig) {
+		if generator == nil {
+			generator = newRequestID
+		}
+		c.RequestIDGenerator = generator
+	}
+}
+
+// requestIDContextKey 请求 context 中保存请求 ID 的键
+type requestIDContextKey struct{}
+
+// RequestIDFromContext 返回 WithRequestID 保存在请求 context 中的请求 ID，没有时返回空
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDContextKey{}).(string)
+	return id
+}
+
+// setRequestID 获取或生成请求 ID，保存到请求 context 中并写入响应头
+func setRequestID(c *gin.Context, config *HandlerConfig) {
+	if config.RequestIDGenerator == nil {
+		return
+	}
+	id := requestID(c, config.RequestIDFunc)
+	if id == "" {
+		id = config.RequestIDGenerator()
+	}
+	c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), requestIDContextKey{}, id))
+	c.Header(RequestIDHeader, id)
+}
+
+// newRequestID 生成随机的请求 ID
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// requestID 获取请求 ID，依次使用请求 context 中保存的请求 ID、fn、X-Request-Id 请求头和 traceparent 请求头中的 trace-id
+func requestID(c *gin.Context, fn RequestIDFunc) string {
+	if id := RequestIDFromContext(c.Request.Context()); id != "" {
+		return id
+	}
+	if fn != nil {
+		return fn(c)
+	}
+	if id := c.GetHeader(RequestIDHeader); id != "" {
+		return id
+	}
+	return traceID(c.GetHeader("traceparent"))
+}
+
+// traceID 解析 W3C Trace Context 的 traceparent 请求头（如 00-<trace-id>-<parent-id>-01），返回 trace-id
+func traceID(traceparent string) string {
+	parts := strings.Split(strings.TrimSpace(traceparent), "-")
+	if len(parts) < 4 || len(parts[1]) != 32 || strings.Trim(parts[1], "0") == "" {
+		return ""
+	}
+	for _, r := range parts[1] {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return ""
+		}
+	}
+	return parts[1]
+}
+
+// requestIDField 返回生效的请求 ID 字段名，"-" 表示不输出
+func requestIDField(field string) string {
+	if field == "" {
+		return DefaultRequestIDField
+	}
+	return field
+}
+
+// MarshalJSON 实现 json.Marshaler 接口，请求 ID 按 RequestIDField 指定的字段名输出在最后
+func (e ErrorResponse) MarshalJSON() ([]byte, error) {
+	return e.marshalJSON(StdJSONCodec{})
+}
+
+// marshalJSON 实现 codecMarshaler 接口
+func (e ErrorResponse) marshalJSON(codec JSONCodec) ([]byte, error) {
+	type errorResponse ErrorResponse
+	data, err := codec.Marshal(errorResponse(e))
+	if err != nil {
+		return nil, err
+	}
+	return appendRequestID(data, e.RequestIDField, e.RequestID), nil
+}
+
+// appendRequestID 在编码后的 JSON 对象末尾追加请求 ID 字段
+func appendRequestID(data []byte, field, id string) []byte {
+	field = requestIDField(field)
+	if id == "" || field == "-" || len(data) < 2 || data[len(data)-1] != '}' {
+		return data
+	}
+
+	key, _ := json.Marshal(field)
+	value, _ := json.Marshal(id)
+	var buf bytes.Buffer
+	buf.Write(data[:len(data)-1])
+	if len(data) > 2 {
+		buf.WriteByte(',')
+	}
+	buf.Write(key)
+	buf.WriteByte(':')
+	buf.Write(value)
+	buf.WriteByte('}')
+	return buf.Bytes()
+}
+
+// encodeXMLWithRequestID 输出 v 的 XML 编码，并在根元素末尾追加请求 ID 元素
+func encodeXMLWithRequestID(enc *xml.Encoder, v any, field, id string) error {
+	if id == "" || requestIDField(field) == "-" {
+		return enc.Encode(v)
+	}
+
+	data, err := xml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	depth := 0
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch token.(type) {
+		case xml.StartElement:
+			depth++
+		case xml.EndElement:
+			depth--
+			if depth == 0 {
+				if err := encodeXMLRequestID(enc, field, id); err != nil {
+					return err
+				}
+				return enc.EncodeToken(token)
+			}
+		}
+		if err := enc.EncodeToken(xml.CopyToken(token)); err != nil {
+			return err
+		}
+	}
+}
+
+// encodeXMLRequestID 输出请求 ID 元素
+func encodeXMLRequestID(enc *xml.Encoder, field, id string) error {
+	field = requestIDField(field)
+	if id == "" || field == "-" {
+		return nil
+	}
+	return enc.EncodeElement(id, xml.StartElement{Name: xml.Name{Local: field}})
+}
+
+// UnmarshalJSON 实现 json.Unmarshaler 接口，从默认字段名 request_id 中解析请求 ID
+func (e *ErrorResponse) UnmarshalJSON(data []byte) error {
+	type errorResponse ErrorResponse
+	var resp struct {
+		errorResponse
+		RequestID string `json:"request_id"`
+	}
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return err
+	}
+	*e = ErrorResponse(resp.errorResponse)
+	e.RequestID = resp.RequestID
+	return nil
+}
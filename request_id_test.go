@@ -0,0 +1,212 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func notFoundHandler(ctx context.Context, req *testRequest) (*testResponse, error) {
+	return nil, ErrNotFound(40400, "资源不存在")
+}
+
+// 测试错误响应中的请求 ID
+func TestErrorResponseRequestID(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []Option
+		headers map[string]string
+		want    string
+		field   string
+	}{
+		{
+			name:    "X-Request-Id 请求头",
+			headers: map[string]string{"X-Request-Id": "req-123"},
+			want:    "req-123",
+			field:   "request_id",
+		},
+		{
+			name:    "traceparent 请求头",
+			headers: map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"},
+			want:    "4bf92f3577b34da6a3ce929d0e0e4736",
+			field:   "request_id",
+		},
+		{
+			name:    "非法的 traceparent 请求头",
+			headers: map[string]string{"traceparent": "00-00000000000000000000000000000000-00f067aa0ba902b7-01"},
+			field:   "request_id",
+		},
+		{
+			name:    "自定义字段名",
+			opts:    []Option{WithRequestIDField("trace_id")},
+			headers: map[string]string{"X-Request-Id": "req-123"},
+			want:    "req-123",
+			field:   "trace_id",
+		},
+		{
+			name: "自定义获取函数",
+			opts: []Option{WithRequestIDFunc(func(c *gin.Context) string {
+				return "custom-" + c.Param("id")
+			})},
+			headers: map[string]string{"X-Request-Id": "req-123"},
+			want:    "custom-1",
+			field:   "request_id",
+		},
+		{
+			name:    "不输出请求 ID",
+			opts:    []Option{WithRequestIDField("-")},
+			headers: map[string]string{"X-Request-Id": "req-123"},
+			field:   "-",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			r.GET("/test/:id", Handler(notFoundHandler, tt.opts...))
+
+			req := httptest.NewRequest("GET", "/test/1", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			var body map[string]any
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("解析响应失败: %v", err)
+			}
+			got, ok := body[tt.field]
+			if tt.want == "" {
+				if ok || strings.Contains(w.Body.String(), "req-123") {
+					t.Errorf("期望不输出请求 ID, 实际响应 %s", w.Body.String())
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("期望 %s 为 %q, 实际得到 %v", tt.field, tt.want, got)
+			}
+		})
+	}
+}
+
+// 测试解析带请求 ID 的错误响应
+func TestErrorResponseRequestIDRoundTrip(t *testing.T) {
+	data, err := json.Marshal(ErrorResponse{Code: 40400, Message: "资源不存在", RequestID: "req-123"})
+	if err != nil {
+		t.Fatalf("编码失败: %v", err)
+	}
+	if want := `{"code":40400,"message":"资源不存在","request_id":"req-123"}`; string(data) != want {
+		t.Errorf("期望 %s, 实际得到 %s", want, data)
+	}
+
+	var resp ErrorResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		t.Fatalf("解析失败: %v", err)
+	}
+	if resp.RequestID != "req-123" || resp.Code != float64(40400) {
+		t.Errorf("期望解析出请求 ID, 实际得到 %+v", resp)
+	}
+}
+
+// 测试 XML 和 Problem Details 错误响应中的请求 ID
+func TestErrorResponseRequestIDFormats(t *testing.T) {
+	r := gin.New()
+	r.GET("/xml/:id", Handler(notFoundHandler, WithResponseFormat(FormatXML)))
+	r.GET("/problem/:id", Handler(notFoundHandler, WithProblemDetails()))
+
+	req := httptest.NewRequest("GET", "/xml/1", nil)
+	req.Header.Set("X-Request-Id", "req-123")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if !strings.Contains(w.Body.String(), "<request_id>req-123</request_id>") {
+		t.Errorf("期望 XML 响应包含请求 ID, 实际得到 %s", w.Body.String())
+	}
+
+	req = httptest.NewRequest("GET", "/problem/1", nil)
+	req.Header.Set("X-Request-Id", "req-123")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusNotFound, w.Code)
+	}
+	if !strings.Contains(w.Body.String(), `"request_id":"req-123"`) {
+		t.Errorf("期望 Problem Details 包含请求 ID, 实际得到 %s", w.Body.String())
+	}
+}
+
+// 测试生成和传递请求 ID
+func TestWithRequestID(t *testing.T) {
+	r := gin.New()
+
+	var logged, handled string
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		handled = RequestIDFromContext(ctx)
+		if req.ID == 2 {
+			return nil, ErrNotFound(40400, "资源不存在")
+		}
+		return &testResponse{ID: req.ID}, nil
+	}
+	logger := func(r *http.Request, req any) {
+		logged = RequestIDFromContext(r.Context())
+	}
+	r.GET("/test/:id", Handler(handleFunc, WithRequestID(func() string { return "generated" }), WithRequestLogger(logger)))
+	r.GET("/xml/:id", Handler(handleFunc, WithRequestID(nil), WithRequestIDField("trace_id"), WithResponseFormat(FormatXML)))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/test/1", nil))
+
+	var resp SuccessResponse[testResponse]
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	if resp.RequestID != "generated" || w.Header().Get(RequestIDHeader) != "generated" {
+		t.Errorf("期望成功响应和响应头包含生成的请求 ID, 实际得到 %s", w.Body.String())
+	}
+	if logged != "generated" || handled != "generated" {
+		t.Errorf("期望 RequestLogger 和业务处理函数获取到请求 ID, 实际得到 %q %q", logged, handled)
+	}
+
+	req := httptest.NewRequest("GET", "/test/2", nil)
+	req.Header.Set(RequestIDHeader, "req-123")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if !strings.Contains(w.Body.String(), `"request_id":"req-123"`) || handled != "req-123" {
+		t.Errorf("期望使用请求头中的请求 ID, 实际得到 %s", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/xml/1", nil))
+	id := w.Header().Get(RequestIDHeader)
+	if len(id) != 32 {
+		t.Errorf("期望生成 32 位请求 ID, 实际得到 %q", id)
+	}
+	expected := "<response><code>0</code><data><ID>1</ID><Name></Name><Age>0</Age><Message></Message></data><trace_id>" + id + "</trace_id></response>"
+	if w.Body.String() != expected {
+		t.Errorf("期望 XML 响应 %s, 实际得到 %s", expected, w.Body.String())
+	}
+}
+
+// 测试扁平化响应中的请求 ID
+func TestWithRequestIDFlatten(t *testing.T) {
+	r := gin.New()
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return &testResponse{ID: req.ID}, nil
+	}
+	r.GET("/test/:id", Handler(handleFunc, WithRequestID(func() string { return "generated" }), WithFlattenedData()))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/test/1", nil))
+
+	expected := `{"code":0,"id":1,"name":"","age":0,"message":"","request_id":"generated"}`
+	if w.Body.String() != expected {
+		t.Errorf("期望 %s, 实际得到 %s", expected, w.Body.String())
+	}
+}
@@ -0,0 +1,89 @@
+package apihandler
+
+import (
+	"net/http"
+	"reflect"
+)
+
+// MetaProvider 响应元数据接口
+//
+// 业务处理函数的返回值实现该接口时，Meta 的结果会作为与 data 同级的 meta 字段输出。
+type MetaProvider interface {
+	Meta() any
+}
+
+// DataProvider 响应数据接口
+//
+// 业务处理函数的返回值实现该接口时，Data 的结果替代返回值本身作为 data 字段输出。
+type DataProvider interface {
+	Data() any
+}
+
+// StatusCoder 响应状态码接口
+//
+// 业务处理函数的返回值实现该接口时，使用 HTTPStatus 的结果替代配置的 SuccessHTTPCode。
+type StatusCoder interface {
+	HTTPStatus() int
+}
+
+// HeaderSetter 响应头接口
+//
+// 业务处理函数的返回值实现该接口时，Headers 的结果会写入响应头，如 Location、Link、Cache-Control 等。
+type HeaderSetter interface {
+	Headers() http.Header
+}
+
+// ListMeta 列表元数据
+type ListMeta struct {
+	Total      int64  `json:"total"`
+	Page       int    `json:"page,omitempty"`
+	PerPage    int    `json:"per_page,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ListResponse 列表响应，Items 作为 data 输出，ListMeta 作为 meta 输出
+type ListResponse[R any] struct {
+	Items    []R      `json:"items"`
+	ListMeta ListMeta `json:"meta"`
+}
+
+// Data 实现 DataProvider 接口
+func (l *ListResponse[R]) Data() any {
+	if l.Items == nil {
+		return []R{}
+	}
+	return l.Items
+}
+
+// Meta 实现 MetaProvider 接口
+func (l *ListResponse[R]) Meta() any {
+	return l.ListMeta
+}
+
+// splitMeta 从业务返回值中拆分出 data 和 meta
+func splitMeta(resp any) (data any, meta any) {
+	if isNil(resp) {
+		return resp, nil
+	}
+	data = resp
+	if p, ok := resp.(DataProvider); ok {
+		data = p.Data()
+	}
+	if p, ok := resp.(MetaProvider); ok {
+		meta = p.Meta()
+	}
+	return data, meta
+}
+
+// isNil 判断值是否为 nil（包括值为 nil 的指针、切片、map 等）
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func, reflect.Chan:
+		return rv.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试 ListResponse 输出 meta 字段
+func TestListResponseMeta(t *testing.T) {
+	type user struct {
+		ID int64 `json:"id"`
+	}
+
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*ListResponse[user], error) {
+		return &ListResponse[user]{
+			Items:    []user{{ID: 1}, {ID: 2}},
+			ListMeta: ListMeta{Total: 10, Page: 1, NextCursor: "abc"},
+		}, nil
+	}
+
+	r.GET("/users", Handler(handleFunc))
+
+	req := httptest.NewRequest("GET", "/users", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusOK, w.Code)
+	}
+
+	var resp struct {
+		Data []user   `json:"data"`
+		Meta ListMeta `json:"meta"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+
+	if len(resp.Data) != 2 {
+		t.Errorf("期望 data 长度为 2, 实际得到 %d", len(resp.Data))
+	}
+
+	if resp.Meta.Total != 10 || resp.Meta.NextCursor != "abc" {
+		t.Errorf("期望 meta 为 {total:10, next_cursor:abc}, 实际得到 %+v", resp.Meta)
+	}
+}
+
+// 测试空列表输出为 []
+func TestListResponseEmpty(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*ListResponse[testResponse], error) {
+		return &ListResponse[testResponse]{}, nil
+	}
+
+	r.GET("/empty", Handler(handleFunc))
+
+	req := httptest.NewRequest("GET", "/empty", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	expected := `{"code":0,"data":[],"meta":{"total":0}}`
+	if w.Body.String() != expected {
+		t.Errorf("期望响应为 %s, 实际得到 %s", expected, w.Body.String())
+	}
+}
+
+// 创建或更新的响应，新建时返回 201
+type upsertResponse struct {
+	ID      int64 `json:"id"`
+	Created bool  `json:"created"`
+}
+
+func (r *upsertResponse) HTTPStatus() int {
+	if r.Created {
+		return http.StatusCreated
+	}
+	return http.StatusOK
+}
+
+// 测试响应通过 StatusCoder 控制 HTTP 状态码
+func TestStatusCoder(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*upsertResponse, error) {
+		return &upsertResponse{ID: req.ID, Created: req.ID == 0}, nil
+	}
+
+	r.PUT("/users/:id", Handler(handleFunc, WithSuccessHTTPCode(http.StatusAccepted)))
+
+	cases := []struct {
+		path     string
+		httpCode int
+	}{
+		{"/users/0", http.StatusCreated},
+		{"/users/1", http.StatusOK},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest("PUT", tc.path, nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Code != tc.httpCode {
+			t.Errorf("%s: 期望状态码 %d, 实际得到 %d", tc.path, tc.httpCode, w.Code)
+		}
+	}
+}
+
+// 带响应头的响应
+type createdResponse struct {
+	ID int64 `json:"id"`
+}
+
+func (r *createdResponse) HTTPStatus() int {
+	return http.StatusCreated
+}
+
+func (r *createdResponse) Headers() http.Header {
+	h := http.Header{}
+	h.Set("Location", "/users/"+strconv.FormatInt(r.ID, 10))
+	h.Add("X-Total-Count", "1")
+	return h
+}
+
+// 测试响应通过 HeaderSetter 设置响应头
+func TestHeaderSetter(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*createdResponse, error) {
+		return &createdResponse{ID: req.ID}, nil
+	}
+
+	r.POST("/users/:id", Handler(handleFunc))
+
+	req := httptest.NewRequest("POST", "/users/42", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusCreated, w.Code)
+	}
+
+	if location := w.Header().Get("Location"); location != "/users/42" {
+		t.Errorf("期望 Location 为 '/users/42', 实际得到 '%s'", location)
+	}
+
+	if count := w.Header().Get("X-Total-Count"); count != "1" {
+		t.Errorf("期望 X-Total-Count 为 '1', 实际得到 '%s'", count)
+	}
+}
@@ -0,0 +1,116 @@
+package apihandler
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RetryPolicy 业务处理函数的重试策略
+type RetryPolicy struct {
+	MaxAttempts int                                          // 最大调用次数（包括第一次），默认 3
+	Backoff     func(attempt int) time.Duration              // 第 attempt 次失败后的等待时间，默认 ExponentialBackoff(50ms, 1s)
+	Retryable   func(err error) bool                         // 判断错误是否可以重试，默认 IsRetryable
+	Methods     []string                                     // 允许重试的 HTTP 方法，默认 GET、HEAD、OPTIONS、PUT、DELETE
+	OnRetry     func(c *gin.Context, attempt int, err error) // 每次重试前的回调函数，attempt 为已失败的调用次数
+}
+
+// defaultRetryMethods 默认允许重试的幂等 HTTP 方法
+var defaultRetryMethods = []string{http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete}
+
+// WithRetry 设置业务处理函数的重试策略，可重试的错误按退避时间重试，直到成功或达到最大调用次数
+//
+// 只有幂等的业务处理函数才应启用重试。设置了超时时，每次调用单独计算超时时间；
+// 客户端断开连接时停止重试。
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *HandlerConfig) {
+		if policy.MaxAttempts <= 0 {
+			policy.MaxAttempts = 3
+		}
+		if policy.Backoff == nil {
+			policy.Backoff = ExponentialBackoff(50*time.Millisecond, time.Second)
+		}
+		if policy.Retryable == nil {
+			policy.Retryable = IsRetryable
+		}
+		if policy.Methods == nil {
+			policy.Methods = defaultRetryMethods
+		}
+		policy.Methods = slices.Clone(policy.Methods)
+		c.Retry = &policy
+	}
+}
+
+// ExponentialBackoff 指数退避，第 n 次失败后等待 base*2^(n-1)，不超过 max
+func ExponentialBackoff(base, max time.Duration) func(attempt int) time.Duration {
+	return func(attempt int) time.Duration {
+		d := base
+		for i := 1; i < attempt && d < max; i++ {
+			d *= 2
+		}
+		return min(d, max)
+	}
+}
+
+// retryableError 标记为可重试的错误
+type retryableError struct {
+	error
+}
+
+// Unwrap 返回原始错误
+func (e *retryableError) Unwrap() error {
+	return e.error
+}
+
+// Retryable 将错误标记为可重试，如下游返回的临时错误
+func Retryable(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &retryableError{error: err}
+}
+
+// IsRetryable 判断错误是否可以重试：通过 Retryable 标记的错误，或网络超时错误
+func IsRetryable(err error) bool {
+	var re *retryableError
+	if errors.As(err, &re) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// allows 判断 HTTP 方法是否允许重试
+func (p *RetryPolicy) allows(method string) bool {
+	return slices.ContainsFunc(p.Methods, func(m string) bool {
+		return strings.EqualFold(m, method)
+	})
+}
+
+// executeWithRetry 按重试策略调用业务处理函数
+func executeWithRetry[T any, R any](c *gin.Context, config *HandlerConfig, translator Translator, handleFunc HandleFunc[T, R], req *T) (*R, error) {
+	policy := config.Retry
+	ctx := c.Request.Context()
+	for attempt := 1; ; attempt++ {
+		resp, err := attemptHandleFunc(c, config, translator, handleFunc, req)
+		if err == nil || attempt >= policy.MaxAttempts || !policy.Retryable(err) {
+			return resp, err
+		}
+
+		timer := time.NewTimer(policy.Backoff(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return resp, err
+		case <-timer.C:
+		}
+		if policy.OnRetry != nil {
+			policy.OnRetry(c, attempt, err)
+		}
+	}
+}
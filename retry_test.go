@@ -0,0 +1,101 @@
+package apihandler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试可重试的错误按策略重试
+func TestRetry(t *testing.T) {
+	r := gin.New()
+
+	calls := 0
+	failures := 0
+	var handleErr error
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		calls++
+		if calls <= failures {
+			return nil, handleErr
+		}
+		return &testResponse{}, nil
+	}
+	var retries []int
+	policy := RetryPolicy{
+		MaxAttempts: 3,
+		Backoff:     func(attempt int) time.Duration { return time.Millisecond },
+		OnRetry: func(c *gin.Context, attempt int, err error) {
+			retries = append(retries, attempt)
+		},
+	}
+	r.GET("/users", Handler(handleFunc, WithRetry(policy)))
+	r.POST("/users", Handler(handleFunc, WithRetry(policy)))
+
+	tests := []struct {
+		name       string
+		method     string
+		failures   int
+		err        error
+		wantStatus int
+		wantCalls  int
+	}{
+		{name: "重试后成功", method: "GET", failures: 2, err: Retryable(errors.New("temporary")), wantStatus: http.StatusOK, wantCalls: 3},
+		{name: "达到最大调用次数", method: "GET", failures: 5, err: Retryable(errors.New("temporary")), wantStatus: http.StatusInternalServerError, wantCalls: 3},
+		{name: "不可重试的错误", method: "GET", failures: 1, err: errors.New("permanent"), wantStatus: http.StatusInternalServerError, wantCalls: 1},
+		{name: "非幂等方法不重试", method: "POST", failures: 1, err: Retryable(errors.New("temporary")), wantStatus: http.StatusInternalServerError, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls, failures, handleErr, retries = 0, tt.failures, tt.err, nil
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, httptest.NewRequest(tt.method, "/users", nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("期望状态码 %d, 实际得到 %d", tt.wantStatus, w.Code)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("期望业务处理函数调用 %d 次, 实际得到 %d", tt.wantCalls, calls)
+			}
+			if len(retries) != tt.wantCalls-1 {
+				t.Errorf("期望重试回调调用 %d 次, 实际得到 %v", tt.wantCalls-1, retries)
+			}
+		})
+	}
+}
+
+// 测试指数退避和可重试错误的判断
+func TestRetryHelpers(t *testing.T) {
+	backoff := ExponentialBackoff(100*time.Millisecond, time.Second)
+	for attempt, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 4: 800 * time.Millisecond, 10: time.Second} {
+		if got := backoff(attempt); got != want {
+			t.Errorf("期望第 %d 次失败后等待 %v, 实际得到 %v", attempt, want, got)
+		}
+	}
+
+	if Retryable(nil) != nil {
+		t.Error("期望 Retryable(nil) 返回 nil")
+	}
+	base := errors.New("temporary")
+	if err := Retryable(base); !IsRetryable(err) || !errors.Is(err, base) {
+		t.Error("期望标记的错误可以重试并保留原始错误")
+	}
+	if IsRetryable(errors.New("permanent")) || IsRetryable(ErrNotFound(404, "资源不存在")) {
+		t.Error("期望普通错误和业务错误不可重试")
+	}
+	if !IsRetryable(&timeoutNetError{}) {
+		t.Error("期望网络超时错误可以重试")
+	}
+}
+
+// timeoutNetError 测试用的网络超时错误
+type timeoutNetError struct{}
+
+func (e *timeoutNetError) Error() string   { return "i/o timeout" }
+func (e *timeoutNetError) Timeout() bool   { return true }
+func (e *timeoutNetError) Temporary() bool { return true }
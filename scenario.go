@@ -0,0 +1,100 @@
+package apihandler
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+)
+
+// ScenarioTagPrefix 验证场景 tag 的前缀，场景 create 的规则写在 binding_create tag 中
+const ScenarioTagPrefix = "binding_"
+
+// WithValidationScenario 设置验证场景，binding 验证通过后再按 binding_<scenario> tag 中的规则验证
+//
+// 同一个请求结构可以在创建和更新接口中使用不同的规则。场景规则在 binding tag 之外额外验证，
+// 只在某个场景中需要的规则不要写在 binding tag 中：
+//
+//	type SaveUserRequest struct {
+//		Name string `json:"name" binding_create:"required,min=2" binding_update:"omitempty,min=2"`
+//	}
+//
+//	apihandler.POST(api, "/users", createUser, apihandler.WithValidationScenario("create"))
+//	apihandler.PUT(api, "/users/:id", updateUser, apihandler.WithValidationScenario("update"))
+func WithValidationScenario(scenario string) Option {
+	return func(c *HandlerConfig) {
+		c.ValidationScenario = scenario
+	}
+}
+
+// validators 各验证场景的验证器，以及需要同步注册到场景验证器的自定义规则和翻译
+var validators struct {
+	mu          sync.Mutex
+	scenarios   map[string]*validator.Validate
+	validations []customValidation
+	uni         *ut.UniversalTranslator // 验证消息的翻译，首次使用时创建
+	locales     []validationLocale
+}
+
+// customValidation RegisterValidation 注册的自定义规则
+type customValidation struct {
+	tag string
+	fn  validator.Func
+}
+
+// scenarioValidator 返回验证场景的验证器，首次使用时创建并注册已有的自定义规则
+func scenarioValidator(scenario string) *validator.Validate {
+	validators.mu.Lock()
+	defer validators.mu.Unlock()
+
+	if v, ok := validators.scenarios[scenario]; ok {
+		return v
+	}
+	v := validator.New()
+	v.SetTagName(ScenarioTagPrefix + scenario)
+	for _, cv := range validators.validations {
+		v.RegisterValidation(cv.tag, cv.fn)
+	}
+	for _, l := range validators.locales {
+		l.registerOn(v)
+	}
+	if validators.scenarios == nil {
+		validators.scenarios = make(map[string]*validator.Validate)
+	}
+	validators.scenarios[scenario] = v
+	return v
+}
+
+// registerScenarioValidation 将自定义规则注册到已创建和之后创建的场景验证器
+func registerScenarioValidation(tag string, fn validator.Func) error {
+	validators.mu.Lock()
+	defer validators.mu.Unlock()
+
+	for _, v := range validators.scenarios {
+		if err := v.RegisterValidation(tag, fn); err != nil {
+			return err
+		}
+	}
+	validators.validations = append(validators.validations, customValidation{tag: tag, fn: fn})
+	return nil
+}
+
+// validateScenario 按验证场景的 tag 验证请求，请求不是结构体时不验证
+func validateScenario(req any, scenario string) error {
+	t := reflect.TypeOf(req)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	err := scenarioValidator(scenario).Struct(req)
+	var invalid *validator.InvalidValidationError
+	if errors.As(err, &invalid) {
+		return nil
+	}
+	return err
+}
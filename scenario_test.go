@@ -0,0 +1,70 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// scenarioSaveRequest 测试用的创建和更新共用的请求
+type scenarioSaveRequest struct {
+	ID    int64  `path:"id"`
+	Name  string `json:"name" binding:"max=8" binding_create:"required,min=2" binding_update:"omitempty,min=2"`
+	Count int    `json:"count" binding_create:"test_scenario_even" binding_update:"omitempty,test_scenario_even"`
+}
+
+// 测试按验证场景使用不同的验证规则
+func TestWithValidationScenario(t *testing.T) {
+	// 在场景验证器创建之后注册的自定义规则同样生效
+	scenarioValidator("update")
+	err := RegisterValidation("test_scenario_even", func(fl validator.FieldLevel) bool {
+		return fl.Field().Int()%2 == 0
+	}, nil)
+	if err != nil {
+		t.Fatalf("注册验证规则失败: %v", err)
+	}
+
+	r := gin.New()
+	save := func(ctx context.Context, req *scenarioSaveRequest) (*scenarioSaveRequest, error) { return req, nil }
+	r.POST("/users", Handler(save, WithValidationScenario("create")))
+	r.PUT("/users/:id", Handler(save, WithValidationScenario("update")))
+
+	send := func(method, path, body string) (int, []FieldError) {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var resp ErrorResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp.FieldErrors()
+	}
+
+	if code, errs := send(http.MethodPost, "/users", `{}`); code != http.StatusBadRequest || len(errs) != 1 || errs[0].Rule != "required" {
+		t.Errorf("期望创建场景要求 name 必填, 实际得到状态码 %d, 错误 %+v", code, errs)
+	}
+	if code, _ := send(http.MethodPut, "/users/1", `{}`); code != http.StatusOK {
+		t.Errorf("期望更新场景 name 可以为空, 实际得到状态码 %d", code)
+	}
+	if code, errs := send(http.MethodPut, "/users/1", `{"name":"a"}`); code != http.StatusBadRequest || len(errs) != 1 || errs[0].Rule != "min" {
+		t.Errorf("期望更新场景验证 name 长度, 实际得到状态码 %d, 错误 %+v", code, errs)
+	}
+	if code, errs := send(http.MethodPut, "/users/1", `{"name":"abcdefghij"}`); code != http.StatusBadRequest || len(errs) != 1 || errs[0].Rule != "max" {
+		t.Errorf("期望 binding tag 的规则在所有场景中生效, 实际得到状态码 %d, 错误 %+v", code, errs)
+	}
+
+	if code, errs := send(http.MethodPost, "/users", `{"name":"tom","count":3}`); code != http.StatusBadRequest || len(errs) != 1 || errs[0].Rule != "test_scenario_even" {
+		t.Errorf("期望场景验证使用自定义规则, 实际得到状态码 %d, 错误 %+v", code, errs)
+	}
+	if code, _ := send(http.MethodPost, "/users", `{"name":"tom","count":2}`); code != http.StatusOK {
+		t.Errorf("期望验证通过, 实际得到状态码 %d", code)
+	}
+	if code, errs := send(http.MethodPut, "/users/1", `{"count":3}`); code != http.StatusBadRequest || len(errs) != 1 || errs[0].Rule != "test_scenario_even" {
+		t.Errorf("期望已创建的场景验证器使用之后注册的规则, 实际得到状态码 %d, 错误 %+v", code, errs)
+	}
+}
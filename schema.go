@@ -0,0 +1,325 @@
+package apihandler
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DocTag 字段说明的 tag 名称，生成到 OpenAPI 文档、JSON Schema 和 TypeScript 代码中
+//
+//	Name string `json:"name" doc:"用户名，2 到 32 个字符"`
+const DocTag = "doc"
+
+// Schema JSON Schema（draft 2020-12，与 OpenAPI 3.1 的 Schema Object 一致）的常用子集
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Examples             []any              `json:"examples,omitempty"`
+}
+
+// schemaGenerator 根据 Go 类型生成 Schema，具名结构体保存到 schemas 中并通过 $ref 引用
+type schemaGenerator struct {
+	refPrefix string                  // $ref 的前缀，如 #/components/schemas/
+	schemas   map[string]*Schema      // 具名结构体的 Schema
+	names     map[reflect.Type]string // 类型对应的 Schema 名称
+}
+
+// newSchemaGenerator 创建 Schema 生成器
+func newSchemaGenerator(refPrefix string) *schemaGenerator {
+	return &schemaGenerator{
+		refPrefix: refPrefix,
+		schemas:   make(map[string]*Schema),
+		names:     make(map[reflect.Type]string),
+	}
+}
+
+var (
+	timeType       = reflect.TypeFor[time.Time]()
+	durationType   = reflect.TypeFor[time.Duration]()
+	rawMessageType = reflect.TypeFor[json.RawMessage]()
+)
+
+// schema 生成类型的 Schema
+func (g *schemaGenerator) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case durationType:
+		return &Schema{Type: "integer", Format: "int64"}
+	case rawMessageType:
+		return &Schema{}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return &Schema{Type: "integer", Minimum: ptrTo(0.0)}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t, nil)
+		}
+		return g.ref(t)
+	}
+	return &Schema{}
+}
+
+// ref 返回具名结构体的引用，首次引用时生成其 Schema
+func (g *schemaGenerator) ref(t reflect.Type) *Schema {
+	name, ok := g.names[t]
+	if !ok {
+		name = g.uniqueName(t)
+		g.names[t] = name
+		g.schemas[name] = &Schema{} // 占位，支持递归类型
+		*g.schemas[name] = *g.structSchema(t, nil)
+	}
+	return &Schema{Ref: g.refPrefix + name}
+}
+
+// genericArgPackage 匹配泛型类型参数中的包路径
+var genericArgPackage = regexp.MustCompile(`[\w./-]*\.`)
+
+// uniqueName 生成类型的 Schema 名称，名称冲突时追加序号
+func (g *schemaGenerator) uniqueName(t reflect.Type) string {
+	name := typeName(t)
+	unique := name
+	for i := 2; g.schemas[unique] != nil; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	return unique
+}
+
+// typeName 返回类型可用作标识符的名称，泛型类型的参数去掉包路径，如 Page[pkg.User] 为 Page_User
+func typeName(t reflect.Type) string {
+	name := genericArgPackage.ReplaceAllString(t.Name(), "")
+	name = strings.Map(func(r rune) rune {
+		if r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' {
+			return r
+		}
+		return '_'
+	}, name)
+	return strings.Trim(strings.ReplaceAll(name, "__", "_"), "_")
+}
+
+// structSchema 生成结构体的 Schema，include 不为空时只包含返回 true 的字段
+func (g *schemaGenerator) structSchema(t reflect.Type, include func(reflect.StructField) bool) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	g.addFields(s, t, include)
+	return s
+}
+
+// addFields 将结构体字段添加到 Schema，匿名嵌入的结构体字段展开到外层
+func (g *schemaGenerator) addFields(s *Schema, t reflect.Type, include func(reflect.StructField) bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitempty, ok := jsonFieldName(field)
+		if !ok {
+			continue
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && fieldType.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			g.addFields(s, fieldType, include)
+			continue
+		}
+		if !field.IsExported() || (include != nil && !include(field)) {
+			continue
+		}
+
+		fieldSchema := g.schema(field.Type)
+		required := applyBindingRules(fieldSchema, field)
+		if example, ok := fieldExample(field); ok {
+			fieldSchema.Examples = []any{example}
+		}
+		if doc := field.Tag.Get(DocTag); doc != "" {
+			fieldSchema.Description = doc
+		}
+		s.Properties[name] = fieldSchema
+		if required && !omitempty {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
+
+// jsonFieldName 返回字段的 JSON 名称，json:"-" 的字段返回 false
+func jsonFieldName(field reflect.StructField) (name string, omitempty bool, ok bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", false, false
+	}
+	name, opts, _ := strings.Cut(tag, ",")
+	if name == "" {
+		name = field.Name
+	}
+	return name, strings.Contains(","+opts+",", ",omitempty,"), true
+}
+
+// applyBindingRules 将 binding tag 中的验证规则转换为 Schema 约束，返回字段是否必填
+//
+// $ref 不能与其他约束并存，引用类型的字段只返回是否必填。dive 之后的规则作用于元素，不再处理。
+func applyBindingRules(s *Schema, field reflect.StructField) bool {
+	required := false
+	for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		if name == "dive" {
+			break
+		}
+		if name == "required" {
+			required = true
+			continue
+		}
+		if s.Ref != "" {
+			continue
+		}
+		applyRule(s, name, param)
+	}
+	return required
+}
+
+// applyRule 将单个验证规则转换为 Schema 约束
+func applyRule(s *Schema, name, param string) {
+	switch name {
+	case "min", "max", "len":
+		n, err := strconv.ParseFloat(param, 64)
+		if err != nil {
+			return
+		}
+		setBound(s, name, n)
+	case "gt":
+		if n, err := strconv.ParseFloat(param, 64); err == nil && isNumericSchema(s) {
+			s.ExclusiveMinimum = &n
+		}
+	case "gte":
+		if n, err := strconv.ParseFloat(param, 64); err == nil && isNumericSchema(s) {
+			s.Minimum = &n
+		}
+	case "lt":
+		if n, err := strconv.ParseFloat(param, 64); err == nil && isNumericSchema(s) {
+			s.ExclusiveMaximum = &n
+		}
+	case "lte":
+		if n, err := strconv.ParseFloat(param, 64); err == nil && isNumericSchema(s) {
+			s.Maximum = &n
+		}
+	case "oneof":
+		for _, v := range strings.Fields(param) {
+			s.Enum = append(s.Enum, enumValue(s, v))
+		}
+	case "email":
+		s.Format = "email"
+	case "url", "uri":
+		s.Format = "uri"
+	case "uuid", "uuid3", "uuid4", "uuid5":
+		s.Format = "uuid"
+	case "ipv4", "ipv6", "hostname":
+		s.Format = name
+	case "datetime":
+		s.Format = "date-time"
+	case "alpha":
+		s.Pattern = "^[a-zA-Z]+$"
+	case "alphanum":
+		s.Pattern = "^[a-zA-Z0-9]+$"
+	case "numeric":
+		s.Pattern = "^[-+]?[0-9]+(?:\\.[0-9]+)?$"
+	}
+}
+
+// setBound 设置 min、max、len 规则，字符串为长度，数组为元素个数，数字为取值范围
+func setBound(s *Schema, name string, n float64) {
+	switch s.Type {
+	case "string":
+		setIntBound(&s.MinLength, &s.MaxLength, name, int(n))
+	case "array", "object":
+		setIntBound(&s.MinItems, &s.MaxItems, name, int(n))
+	case "integer", "number":
+		if name != "max" {
+			s.Minimum = &n
+		}
+		if name != "min" {
+			s.Maximum = &n
+		}
+	}
+}
+
+// setIntBound 设置长度或个数的上下限
+func setIntBound(minField, maxField **int, name string, n int) {
+	if name != "max" {
+		*minField = &n
+	}
+	if name != "min" {
+		*maxField = &n
+	}
+}
+
+// isNumericSchema 判断 Schema 是否为数字类型
+func isNumericSchema(s *Schema) bool {
+	return s.Type == "integer" || s.Type == "number"
+}
+
+// enumValue 按 Schema 类型转换 oneof 的取值
+func enumValue(s *Schema, v string) any {
+	switch s.Type {
+	case "integer":
+		if n, err := strconv.ParseInt(v, 10, 64); err == nil {
+			return n
+		}
+	case "number":
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return v
+}
+
+// ptrTo 返回值的指针
+func ptrTo[V any](v V) *V {
+	return &v
+}
@@ -0,0 +1,80 @@
+package apihandler
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatusClientClosedRequest 客户端在服务端响应前关闭连接（nginx 定义的非标准状态码）
+const StatusClientClosedRequest = 499
+
+// SentinelError 哨兵错误到业务错误的映射
+type SentinelError struct {
+	Err      error      // 哨兵错误，通过 errors.Is 匹配
+	Code     any        // 业务错误码，为空时使用 HTTPCode
+	HTTPCode int        // HTTP 状态码
+	Message  MessageKey // 错误消息的消息键，按请求的语言环境翻译
+	Bind     bool       // 只映射绑定请求参数时产生的错误，业务处理函数返回的错误不受影响
+}
+
+// DefaultSentinelErrors 内置的常见哨兵错误映射，通过 WithSentinelErrors 开启
+var DefaultSentinelErrors = []SentinelError{
+	{Err: sql.ErrNoRows, HTTPCode: http.StatusNotFound, Message: MsgNotFound},
+	{Err: context.Canceled, HTTPCode: StatusClientClosedRequest, Message: MsgRequestCanceled},
+	{Err: context.DeadlineExceeded, HTTPCode: http.StatusGatewayTimeout, Message: MsgRequestTimeout},
+	{Err: io.EOF, HTTPCode: http.StatusBadRequest, Message: MsgUnexpectedEOF, Bind: true},
+	{Err: io.ErrUnexpectedEOF, HTTPCode: http.StatusBadRequest, Message: MsgUnexpectedEOF, Bind: true},
+}
+
+// WithSentinelErrors 开启哨兵错误映射，使用 DefaultSentinelErrors 和额外的映射（如 gorm.ErrRecordNotFound），
+// 额外的映射优先匹配
+func WithSentinelErrors(extra ...SentinelError) Option {
+	return func(c *HandlerConfig) {
+		sentinels := make([]SentinelError, 0, len(extra)+len(DefaultSentinelErrors))
+		sentinels = append(sentinels, extra...)
+		c.SentinelErrors = append(sentinels, DefaultSentinelErrors...)
+	}
+}
+
+// mapSentinelError 将未被识别的内部错误按 SentinelErrors 转换为业务错误
+//
+// 只处理其他方式都无法转换的错误，业务错误和错误映射函数的结果保持不变。
+func mapSentinelError(c *gin.Context, config *HandlerConfig, bizErr BizError) BizError {
+	internal, ok := bizErr.(*internalError)
+	if !ok {
+		return bizErr
+	}
+	for _, sentinel := range config.SentinelErrors {
+		if sentinel.Bind || !errors.Is(internal.cause, sentinel.Err) {
+			continue
+		}
+		return &causedBizError{BizError: sentinel.bizError(requestTranslator(c, config)), cause: internal.cause}
+	}
+	return bizErr
+}
+
+// mapBindSentinelError 将绑定请求参数时产生的错误按 Bind 为 true 的 SentinelErrors 转换为业务错误，没有匹配时返回 nil
+//
+// 如请求体被截断时 JSON 解码返回的 io.ErrUnexpectedEOF 属于客户端错误。
+func mapBindSentinelError(config *HandlerConfig, translator Translator, err error) BizError {
+	for _, sentinel := range config.SentinelErrors {
+		if sentinel.Bind && errors.Is(err, sentinel.Err) {
+			return &causedBizError{BizError: sentinel.bizError(translator), cause: err}
+		}
+	}
+	return nil
+}
+
+// bizError 返回映射后的业务错误，未指定 Code 时使用 HTTPCode 作为业务错误码
+func (s SentinelError) bizError(translator Translator) BizError {
+	code := s.Code
+	if code == nil {
+		code = s.HTTPCode
+	}
+	return NewBizError(code, translator.Translate(s.Message), s.HTTPCode)
+}
@@ -0,0 +1,141 @@
+package apihandler
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errRecordNotFound 模拟 gorm.ErrRecordNotFound
+var errRecordNotFound = errors.New("record not found")
+
+// 测试哨兵错误映射
+func TestSentinelErrors(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		lang         string
+		expectedHTTP int
+		expectedCode float64
+		expectedMsg  string
+	}{
+		{"sql.ErrNoRows", fmt.Errorf("query user: %w", sql.ErrNoRows), "zh", http.StatusNotFound, 404, "资源不存在"},
+		{"context.Canceled", context.Canceled, "en", StatusClientClosedRequest, 499, "Request canceled"},
+		{"context.DeadlineExceeded", fmt.Errorf("call upstream: %w", context.DeadlineExceeded), "zh", http.StatusGatewayTimeout, 504, "请求超时"},
+		{"业务处理中的 io.EOF 不是客户端错误", fmt.Errorf("read upstream: %w", io.EOF), "en", http.StatusInternalServerError, 500, "read upstream: EOF"},
+		{"额外的哨兵错误", errRecordNotFound, "zh", http.StatusNotFound, 40400, "资源不存在"},
+		{"未匹配的错误", errors.New("boom"), "zh", http.StatusInternalServerError, 500, "boom"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+				return nil, tt.err
+			}
+			r.GET("/test/:id", Handler(handleFunc, WithSentinelErrors(
+				SentinelError{Err: errRecordNotFound, Code: 40400, HTTPCode: http.StatusNotFound, Message: MsgNotFound},
+			)))
+
+			req := httptest.NewRequest("GET", "/test/1", nil)
+			req.Header.Set("Accept-Language", tt.lang)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.expectedHTTP {
+				t.Errorf("期望状态码 %d, 实际得到 %d", tt.expectedHTTP, w.Code)
+			}
+
+			var resp ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("解析响应失败: %v", err)
+			}
+			if resp.Code != tt.expectedCode {
+				t.Errorf("期望 code 为 %v, 实际得到 %v", tt.expectedCode, resp.Code)
+			}
+			if resp.Message != tt.expectedMsg {
+				t.Errorf("期望消息 '%s', 实际得到 '%s'", tt.expectedMsg, resp.Message)
+			}
+		})
+	}
+}
+
+// 测试绑定请求体时的 io.EOF 和 io.ErrUnexpectedEOF 映射为 400
+func TestSentinelErrorsBind(t *testing.T) {
+	type bodyRequest struct {
+		Name string `json:"name"`
+	}
+	tests := []struct {
+		name         string
+		options      []Option
+		body         string
+		expectedCode float64
+		expectedMsg  string
+	}{
+		{"截断的 JSON 请求体", []Option{WithSentinelErrors()}, `{"name":`, 400, "Unexpected end of request body"},
+		{"空的 JSON 请求体", []Option{WithSentinelErrors()}, ``, 400, "Unexpected end of request body"},
+		{"未开启哨兵错误映射", nil, `{"name":`, 400, "Parameter binding failed: unexpected EOF"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := gin.New()
+			handleFunc := func(ctx context.Context, req *bodyRequest) (*testResponse, error) {
+				return &testResponse{Name: req.Name}, nil
+			}
+			r.POST("/test", Handler(handleFunc, tt.options...))
+
+			req := httptest.NewRequest("POST", "/test", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set("Accept-Language", "en")
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusBadRequest, w.Code)
+			}
+
+			var resp ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("解析响应失败: %v", err)
+			}
+			if resp.Code != tt.expectedCode {
+				t.Errorf("期望 code 为 %v, 实际得到 %v", tt.expectedCode, resp.Code)
+			}
+			if resp.Message != tt.expectedMsg {
+				t.Errorf("期望消息 '%s', 实际得到 '%s'", tt.expectedMsg, resp.Message)
+			}
+		})
+	}
+}
+
+// 测试未开启哨兵错误映射时保持内部错误
+func TestSentinelErrorsDisabled(t *testing.T) {
+	r := gin.New()
+	var got error
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return nil, sql.ErrNoRows
+	}
+	r.GET("/test/:id", Handler(handleFunc, WithOnError(func(c *gin.Context, req any, err error) {
+		got = err
+	})))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/test/1", nil))
+
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusInternalServerError, w.Code)
+	}
+	if !errors.Is(got, sql.ErrNoRows) {
+		t.Errorf("期望错误链包含 sql.ErrNoRows")
+	}
+}
@@ -0,0 +1,96 @@
+package apihandler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WithSingleflight 合并相同的并发 GET 和 HEAD 请求，只调用一次业务处理函数并共享其结果
+//
+// keyFunc 为空时使用请求方法、URI 和 Authorization 头作为合并键，返回空字符串表示不合并该请求。
+// 响应依赖 Cookie 等其他请求信息时应自定义 keyFunc，避免不同用户共享响应。
+// 共享的响应对象不应被修改。
+func WithSingleflight(keyFunc CacheKeyFunc) Option {
+	return func(c *HandlerConfig) {
+		c.Singleflight = true
+		c.SingleflightKeyFunc = keyFunc
+	}
+}
+
+// defaultSingleflightKey 默认的合并键
+func defaultSingleflightKey(c *gin.Context) string {
+	return c.Request.Method + " " + c.Request.URL.RequestURI() + " " + c.GetHeader("Authorization")
+}
+
+// errAbortedCall 第一个请求被中止（如 http.ErrAbortHandler）时等待的请求得到的错误
+var errAbortedCall = errors.New("apihandler: shared call aborted")
+
+// singleflightCall 正在执行的业务处理函数调用
+type singleflightCall[R any] struct {
+	done chan struct{}
+	resp *R
+	err  error
+}
+
+// singleflightGroup 合并相同键的并发调用
+type singleflightGroup[R any] struct {
+	mu    sync.Mutex
+	calls map[string]*singleflightCall[R]
+}
+
+// newSingleflightGroup 配置了合并请求时创建合并组
+func newSingleflightGroup[R any](config *HandlerConfig) *singleflightGroup[R] {
+	if !config.Singleflight {
+		return nil
+	}
+	return &singleflightGroup[R]{calls: make(map[string]*singleflightCall[R])}
+}
+
+// invokeSingleflight 调用业务处理函数，相同键的并发请求等待第一个请求的结果
+//
+// 第一个请求被客户端取消时，其他仍在等待的请求各自调用业务处理函数。
+func invokeSingleflight[T any, R any](c *gin.Context, config *HandlerConfig, translator Translator, group *singleflightGroup[R], handleFunc HandleFunc[T, R], req *T) (*R, error) {
+	if group == nil || (c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead) {
+		return invokeHandleFunc(c, config, translator, handleFunc, req)
+	}
+	keyFunc := config.SingleflightKeyFunc
+	if keyFunc == nil {
+		keyFunc = defaultSingleflightKey
+	}
+	key := keyFunc(c)
+	if key == "" {
+		return invokeHandleFunc(c, config, translator, handleFunc, req)
+	}
+
+	group.mu.Lock()
+	if call, ok := group.calls[key]; ok {
+		group.mu.Unlock()
+		ctx := c.Request.Context()
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if errors.Is(call.err, context.Canceled) && ctx.Err() == nil {
+			return invokeHandleFunc(c, config, translator, handleFunc, req)
+		}
+		return call.resp, call.err
+	}
+	call := &singleflightCall[R]{done: make(chan struct{})}
+	group.calls[key] = call
+	group.mu.Unlock()
+
+	defer func() {
+		group.mu.Lock()
+		delete(group.calls, key)
+		group.mu.Unlock()
+		close(call.done)
+	}()
+	call.err = errAbortedCall
+	call.resp, call.err = invokeHandleFunc(c, config, translator, handleFunc, req)
+	return call.resp, call.err
+}
@@ -0,0 +1,94 @@
+package apihandler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试相同的并发 GET 请求共享一次业务处理函数调用
+func TestSingleflight(t *testing.T) {
+	r := gin.New()
+
+	var calls, keys atomic.Int32
+	release := make(chan struct{})
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		calls.Add(1)
+		<-release
+		return &testResponse{Name: req.Name}, nil
+	}
+	keyFunc := func(c *gin.Context) string {
+		keys.Add(1)
+		return defaultSingleflightKey(c)
+	}
+	r.GET("/users", Handler(handleFunc, WithSingleflight(keyFunc)))
+
+	const n = 5
+	var wg sync.WaitGroup
+	recorders := make([]*httptest.ResponseRecorder, n)
+	for i := range recorders {
+		recorders[i] = httptest.NewRecorder()
+		wg.Add(1)
+		go func(w *httptest.ResponseRecorder) {
+			defer wg.Done()
+			r.ServeHTTP(w, httptest.NewRequest("GET", "/users?name=test", nil))
+		}(recorders[i])
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for keys.Load() < n && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if got := calls.Load(); got != 1 {
+		t.Errorf("期望业务处理函数只调用 1 次, 实际得到 %d", got)
+	}
+	for i, w := range recorders {
+		if w.Code != http.StatusOK || !strings.Contains(w.Body.String(), `"name":"test"`) {
+			t.Errorf("期望第 %d 个请求得到共享的响应, 实际状态码 %d, 响应 %s", i, w.Code, w.Body.String())
+		}
+	}
+}
+
+// 测试不同的请求和非 GET 请求不合并
+func TestSingleflightDistinctRequests(t *testing.T) {
+	r := gin.New()
+
+	var calls atomic.Int32
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		calls.Add(1)
+		return &testResponse{Name: req.Name}, nil
+	}
+	r.GET("/users", Handler(handleFunc, WithSingleflight(nil)))
+	r.POST("/users", Handler(handleFunc, WithSingleflight(nil)))
+
+	for _, tt := range []struct {
+		method, url, auth string
+	}{
+		{"GET", "/users?name=a", ""},
+		{"GET", "/users?name=b", ""},
+		{"GET", "/users?name=a", "Bearer other"},
+		{"POST", "/users?name=a", ""},
+	} {
+		req := httptest.NewRequest(tt.method, tt.url, nil)
+		req.Header.Set("Authorization", tt.auth)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != http.StatusOK {
+			t.Errorf("期望 %s %s 状态码 %d, 实际得到 %d", tt.method, tt.url, http.StatusOK, w.Code)
+		}
+	}
+	if got := calls.Load(); got != 4 {
+		t.Errorf("期望业务处理函数调用 4 次, 实际得到 %d", got)
+	}
+}
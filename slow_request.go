@@ -0,0 +1,32 @@
+package apihandler
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SlowRequestFunc 慢请求回调函数，info 包含路由、耗时和绑定后的请求对象
+type SlowRequestFunc func(c *gin.Context, info RequestInfo)
+
+// WithSlowRequestThreshold 设置慢请求阈值，请求处理耗时超过 threshold 时在响应输出后调用 fn，
+// 用于按接口发现延迟退化并告警
+func WithSlowRequestThreshold(threshold time.Duration, fn SlowRequestFunc) Option {
+	return WithObserver(&slowRequestObserver{threshold: threshold, fn: fn})
+}
+
+// slowRequestObserver 检测慢请求的观察者
+type slowRequestObserver struct {
+	threshold time.Duration
+	fn        SlowRequestFunc
+}
+
+// ObserveStart 实现 RequestObserver 接口
+func (o *slowRequestObserver) ObserveStart(c *gin.Context) {}
+
+// ObserveEnd 实现 RequestObserver 接口
+func (o *slowRequestObserver) ObserveEnd(c *gin.Context, info RequestInfo) {
+	if info.Latency > o.threshold {
+		o.fn(c, info)
+	}
+}
@@ -0,0 +1,41 @@
+package apihandler
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试处理耗时超过阈值时调用慢请求回调
+func TestSlowRequestThreshold(t *testing.T) {
+	r := gin.New()
+
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		if req.Name == "slow" {
+			time.Sleep(30 * time.Millisecond)
+		}
+		return &testResponse{}, nil
+	}
+	var slow []RequestInfo
+	r.GET("/users/:id", Handler(handleFunc, WithSlowRequestThreshold(20*time.Millisecond, func(c *gin.Context, info RequestInfo) {
+		slow = append(slow, info)
+	})))
+
+	for _, url := range []string{"/users/1?name=fast", "/users/2?name=slow"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", url, nil))
+	}
+
+	if len(slow) != 1 {
+		t.Fatalf("期望记录 1 个慢请求, 实际得到 %d", len(slow))
+	}
+	info := slow[0]
+	if info.Route != "/users/:id" || info.Latency < 30*time.Millisecond {
+		t.Errorf("期望慢请求包含路由和耗时, 实际得到 %+v", info)
+	}
+	if req, ok := info.Request.(*testRequest); !ok || req.ID != 2 || req.Name != "slow" {
+		t.Errorf("期望慢请求包含绑定后的请求对象, 实际得到 %#v", info.Request)
+	}
+}
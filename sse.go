@@ -0,0 +1,110 @@
+package apihandler
+
+import (
+	"context"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+// SSEFunc SSE 业务处理函数类型
+//
+// events 用于向客户端发送事件，函数返回即结束事件流。客户端断开连接时 ctx 会被取消，
+// 发送事件时应同时监听 ctx.Done()。
+type SSEFunc[T any, E any] func(ctx context.Context, req *T, events chan<- E) error
+
+// SSEEventNamer 事件名称接口，事件实现该接口时输出 event 字段
+type SSEEventNamer interface {
+	EventName() string
+}
+
+// SSEHandler 创建 Server-Sent Events 处理器
+//
+// 参数绑定和验证与 Handler 相同，绑定失败时返回普通的错误响应；事件流开始后，
+// 业务函数返回的错误以 error 事件的形式发送，业务函数 panic 时调用 OnPanic 和 PanicAlert 并发送内部错误事件。
+func SSEHandler[T any, E any](sseFunc SSEFunc[T, E], opts ...Option) gin.HandlerFunc {
+	config := NewConfig(opts...)
+
+	return func(c *gin.Context) {
+		setRequestID(c, config)
+		translator := setRequestLocale(c, config)
+
+		req := new(T)
+		if err := bindWithHooks(c, config, translator, req); err != nil {
+			handleError(c, config, req, err)
+			return
+		}
+
+		if config.RequestLogger != nil {
+			config.RequestLogger(c.Request, req)
+		}
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+		c.Writer.Flush()
+
+		ctx, cancel := context.WithCancel(c.Request.Context())
+		defer cancel()
+
+		events := make(chan E)
+		done := make(chan error, 1)
+		go func() {
+			// 与 Handler 一样恢复业务函数的 panic，在处理器的 goroutine 中报告并以 error 事件结束事件流
+			defer func() {
+				if recovered := recover(); recovered != nil {
+					done <- &panicError{value: recovered, stack: debug.Stack()}
+				}
+			}()
+			done <- sseFunc(ctx, req, events)
+		}()
+
+		var heartbeat <-chan time.Time
+		if config.SSEHeartbeat > 0 {
+			ticker := time.NewTicker(config.SSEHeartbeat)
+			defer ticker.Stop()
+			heartbeat = ticker.C
+		}
+
+		for {
+			select {
+			case event := <-events:
+				name := ""
+				if namer, ok := any(event).(SSEEventNamer); ok {
+					name = namer.EventName()
+				}
+				c.Render(-1, sse.Event{Event: name, Data: event})
+				c.Writer.Flush()
+			case err := <-done:
+				if p, ok := err.(*panicError); ok {
+					err = recoverPanic(c, config, translator, req, p)
+				}
+				if err != nil {
+					c.Render(-1, sse.Event{Event: "error", Data: errorBody(c, config, resolveError(c, config, req, err))})
+					c.Writer.Flush()
+				}
+				return
+			case <-heartbeat:
+				c.Writer.WriteString(": heartbeat\n\n")
+				c.Writer.Flush()
+			case <-ctx.Done():
+				// 客户端断开连接，丢弃剩余事件直到业务函数退出
+				for {
+					select {
+					case <-events:
+					case err := <-done:
+						if p, ok := err.(*panicError); ok {
+							recoverPanic(c, config, translator, req, p)
+						}
+						return
+					}
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package apihandler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试事件
+type testEvent struct {
+	Seq int `json:"seq"`
+}
+
+func (e testEvent) EventName() string {
+	return "progress"
+}
+
+// 测试 SSE 事件输出和错误事件
+func TestSSEHandler(t *testing.T) {
+	r := gin.New()
+
+	sseFunc := func(ctx context.Context, req *testRequest, events chan<- testEvent) error {
+		for i := 1; i <= int(req.ID); i++ {
+			select {
+			case events <- testEvent{Seq: i}:
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+		return ErrConflict(40900, "任务已取消")
+	}
+
+	r.GET("/events/:id", SSEHandler(sseFunc))
+
+	req := httptest.NewRequest("GET", "/events/2", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusOK, w.Code)
+	}
+
+	if ct := w.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("期望 Content-Type 为 'text/event-stream', 实际得到 '%s'", ct)
+	}
+
+	expected := "event:progress\ndata:{\"seq\":1}\n\n" +
+		"event:progress\ndata:{\"seq\":2}\n\n" +
+		"event:error\ndata:{\"code\":40900,\"message\":\"任务已取消\"}\n\n"
+	if w.Body.String() != expected {
+		t.Errorf("期望响应为 %q, 实际得到 %q", expected, w.Body.String())
+	}
+}
+
+// 测试参数绑定失败时返回普通错误响应
+func TestSSEHandlerBindError(t *testing.T) {
+	r := gin.New()
+
+	sseFunc := func(ctx context.Context, req *testRequest, events chan<- testEvent) error {
+		return nil
+	}
+
+	r.GET("/events/:id", SSEHandler(sseFunc))
+
+	req := httptest.NewRequest("GET", "/events/abc", nil)
+	w := httptest.NewRecorder()
+
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusBadRequest, w.Code)
+	}
+
+	if !strings.Contains(w.Header().Get("Content-Type"), "application/json") {
+		t.Errorf("期望返回 JSON 错误响应, 实际 Content-Type 为 '%s'", w.Header().Get("Content-Type"))
+	}
+}
+
+// 测试客户端断开连接后处理器退出，且不监听 ctx 的业务函数也不会被阻塞
+func TestSSEHandlerClientDisconnect(t *testing.T) {
+	r := gin.New()
+
+	exited := make(chan struct{})
+	sseFunc := func(ctx context.Context, req *testRequest, events chan<- testEvent) error {
+		defer close(exited)
+		for i := 0; i < 100; i++ {
+			events <- testEvent{Seq: i}
+		}
+		return errors.New("不应输出")
+	}
+
+	r.GET("/events", SSEHandler(sseFunc, WithSSEHeartbeat(time.Millisecond)))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	req := httptest.NewRequest("GET", "/events", nil).WithContext(ctx)
+	w := httptest.NewRecorder()
+
+	finished := make(chan struct{})
+	go func() {
+		r.ServeHTTP(w, req)
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+	case <-time.After(time.Second):
+		t.Fatal("客户端断开后处理器未退出")
+	}
+
+	select {
+	case <-exited:
+	case <-time.After(time.Second):
+		t.Fatal("客户端断开后业务函数未退出")
+	}
+
+	if strings.Contains(w.Body.String(), "不应输出") {
+		t.Errorf("客户端断开后不应再输出错误事件")
+	}
+}
+
+// 测试业务函数 panic 时报告 panic 并以 error 事件结束事件流
+func TestSSEHandlerPanic(t *testing.T) {
+	r := gin.New()
+
+	sseFunc := func(ctx context.Context, req *testRequest, events chan<- testEvent) error {
+		events <- testEvent{Seq: 1}
+		panic("boom")
+	}
+
+	var recovered any
+	var alert PanicAlert
+	r.GET("/events", SSEHandler(sseFunc,
+		WithOnPanic(func(c *gin.Context, value any, stack []byte) {
+			recovered = value
+		}),
+		WithPanicAlert(func(c *gin.Context, a PanicAlert) {
+			alert = a
+		}),
+	))
+
+	req := httptest.NewRequest("GET", "/events", nil)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	expected := "event:progress\ndata:{\"seq\":1}\n\n" +
+		"event:error\ndata:{\"code\":500,\"message\":\"服务器内部错误\"}\n\n"
+	if w.Body.String() != expected {
+		t.Errorf("期望响应为 %q, 实际得到 %q", expected, w.Body.String())
+	}
+	if recovered != "boom" {
+		t.Errorf("期望 OnPanic 收到 boom, 实际得到 %v", recovered)
+	}
+	if alert.Recovered != "boom" || len(alert.Stack) == 0 || alert.Route != "/events" {
+		t.Errorf("期望 PanicAlert 收到 panic 信息, 实际得到 %+v", alert)
+	}
+}
@@ -0,0 +1,84 @@
+package apihandler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LateResultHandler 处理超时后才返回的业务处理结果，err 为业务处理函数返回的错误或 panic 转换得到的错误
+//
+// 调用时请求已经结束，不能再使用 gin.Context。
+type LateResultHandler func(req any, resp any, err error, elapsed time.Duration)
+
+// WithTimeout 设置业务处理函数的超时时间，超时后返回 504 错误响应
+//
+// 业务处理函数收到的 context 带有截止时间，应在 ctx.Done() 后尽快返回；
+// 超时后返回的结果会被丢弃，并交给 OnLateResult 记录。
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *HandlerConfig) {
+		c.Timeout = timeout
+	}
+}
+
+// WithOnLateResult 设置处理超时后才返回的业务处理结果的回调函数
+func WithOnLateResult(fn LateResultHandler) Option {
+	return func(c *HandlerConfig) {
+		c.OnLateResult = fn
+	}
+}
+
+// invokeWithTimeout 在带截止时间的 context 中调用业务处理函数，超时后立即返回 504 业务错误
+func invokeWithTimeout[T any, R any](c *gin.Context, config *HandlerConfig, translator Translator, handleFunc HandleFunc[T, R], req *T) (*R, error) {
+	parent := c.Request.Context()
+	ctx, cancel := context.WithTimeout(parent, config.Timeout)
+
+	start := time.Now()
+	done := make(chan handleResult[R], 1)
+	go func() {
+		done <- callHandleFunc(ctx, handleFunc, req)
+	}()
+
+	select {
+	case result := <-done:
+		defer cancel()
+		if result.panic != nil {
+			return nil, recoverPanic(c, config, translator, req, result.panic)
+		}
+		// 业务处理函数感知到截止时间后返回的错误同样视为超时
+		if result.err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) && parent.Err() == nil {
+			return nil, timeoutError(translator)
+		}
+		return result.resp, result.err
+	case <-ctx.Done():
+	}
+
+	// 超时或客户端断开连接，业务处理函数仍在执行，在后台等待并丢弃其结果
+	onLateResult := config.OnLateResult
+	go func() {
+		defer cancel()
+		result := <-done
+		if onLateResult == nil {
+			return
+		}
+		err := result.err
+		if result.panic != nil {
+			err = result.panic
+		}
+		onLateResult(req, result.resp, err, time.Since(start))
+	}()
+
+	if err := parent.Err(); err != nil {
+		return nil, err
+	}
+	return nil, timeoutError(translator)
+}
+
+// timeoutError 业务处理函数超时的 504 业务错误
+func timeoutError(translator Translator) error {
+	msg := translator.Translate(MsgRequestTimeout)
+	return WrapBizError(http.StatusGatewayTimeout, msg, http.StatusGatewayTimeout, context.DeadlineExceeded)
+}
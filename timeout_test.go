@@ -0,0 +1,99 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试业务处理函数超时
+func TestHandlerTimeout(t *testing.T) {
+	r := gin.New()
+
+	release := make(chan struct{})
+	late := make(chan error, 1)
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		if req.ID == 1 {
+			return &testResponse{ID: req.ID}, nil
+		}
+		<-release
+		return nil, errors.New("late failure")
+	}
+	r.GET("/test/:id", Handler(handleFunc,
+		WithTimeout(20*time.Millisecond),
+		WithOnLateResult(func(req any, resp any, err error, elapsed time.Duration) {
+			late <- err
+		}),
+	))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/test/1", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("期望未超时时状态码 %d, 实际得到 %d", http.StatusOK, w.Code)
+	}
+
+	req := httptest.NewRequest("GET", "/test/2", nil)
+	req.Header.Set("Accept-Language", "en")
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("期望状态码 %d, 实际得到 %d", http.StatusGatewayTimeout, w.Code)
+	}
+	var resp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	if resp.Message != "Request timed out" {
+		t.Errorf("期望翻译后的超时消息, 实际得到 '%s'", resp.Message)
+	}
+
+	close(release)
+	select {
+	case err := <-late:
+		if err == nil || err.Error() != "late failure" {
+			t.Errorf("期望 OnLateResult 收到超时后的结果, 实际得到 %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("期望调用 OnLateResult")
+	}
+}
+
+// 测试超时时间内 panic 和感知截止时间
+func TestHandlerTimeoutContext(t *testing.T) {
+	r := gin.New()
+
+	var panicked bool
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		if req.ID == 1 {
+			panic("boom")
+		}
+		if _, ok := ctx.Deadline(); !ok {
+			return nil, errors.New("期望 context 带有截止时间")
+		}
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	r.GET("/test/:id", Handler(handleFunc,
+		WithTimeout(20*time.Millisecond),
+		WithOnPanic(func(c *gin.Context, recovered any, stack []byte) { panicked = true }),
+	))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/test/1", nil))
+	if w.Code != http.StatusInternalServerError || !panicked {
+		t.Errorf("期望 panic 返回 500 并调用 OnPanic, 实际状态码 %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest("GET", "/test/2", nil))
+	if w.Code != http.StatusGatewayTimeout {
+		t.Errorf("期望状态码 %d, 实际得到 %d, 响应 %s", http.StatusGatewayTimeout, w.Code, w.Body.String())
+	}
+}
@@ -0,0 +1,34 @@
+package apihandler
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// responseTransform 类型擦除后的响应转换函数
+type responseTransform func(ctx context.Context, resp any) any
+
+// responseTransforms 按响应类型注册的转换函数，键为 *R 的类型
+var responseTransforms sync.Map
+
+// RegisterResponseTransform 注册响应类型 R 的转换函数
+//
+// 业务处理函数返回 *R 后、编码之前调用 transform，以其返回值作为 data 输出，
+// 用于集中处理 DTO 裁剪、单位换算和废弃字段兼容等逻辑。重复注册时覆盖之前的转换函数。
+func RegisterResponseTransform[R any](transform func(ctx context.Context, resp *R) any) {
+	responseTransforms.Store(reflect.TypeOf((*R)(nil)), responseTransform(func(ctx context.Context, resp any) any {
+		return transform(ctx, resp.(*R))
+	}))
+}
+
+// transformResponse 应用已注册的响应转换函数，未注册或响应为 nil 时原样返回
+func transformResponse(ctx context.Context, resp any) any {
+	if isNil(resp) {
+		return resp
+	}
+	if transform, ok := responseTransforms.Load(reflect.TypeOf(resp)); ok {
+		return transform.(responseTransform)(ctx, resp)
+	}
+	return resp
+}
@@ -0,0 +1,56 @@
+package apihandler
+
+import (
+	"context"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 价格响应，内部以分为单位
+type priceResponse struct {
+	Cents    int64  `json:"cents"`
+	Internal string `json:"internal"`
+}
+
+// 测试按响应类型注册的转换函数
+func TestResponseTransform(t *testing.T) {
+	RegisterResponseTransform(func(ctx context.Context, resp *priceResponse) any {
+		return map[string]any{"yuan": float64(resp.Cents) / 100}
+	})
+	defer responseTransforms.Delete(reflect.TypeOf((*priceResponse)(nil)))
+
+	r := gin.New()
+
+	r.GET("/price", Handler(func(ctx context.Context, req *testRequest) (*priceResponse, error) {
+		return &priceResponse{Cents: 1250, Internal: "secret"}, nil
+	}))
+	r.GET("/other", Handler(func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return &testResponse{ID: 1}, nil
+	}))
+	r.GET("/nil", Handler(func(ctx context.Context, req *testRequest) (*priceResponse, error) {
+		return nil, nil
+	}))
+
+	cases := []struct {
+		path string
+		body string
+	}{
+		{"/price", `{"code":0,"data":{"yuan":12.5}}`},
+		{"/other", `{"code":0,"data":{"id":1,"name":"","age":0,"message":""}}`},
+		{"/nil", `{"code":0,"data":null}`},
+	}
+
+	for _, tc := range cases {
+		req := httptest.NewRequest("GET", tc.path, nil)
+		w := httptest.NewRecorder()
+
+		r.ServeHTTP(w, req)
+
+		if w.Body.String() != tc.body {
+			t.Errorf("%s: 期望响应为 %s, 实际得到 %s", tc.path, tc.body, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,573 @@
+package apihandler
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// requestField 请求结构中的字段及其位置
+type requestField struct {
+	Name     string              // 参数名称或请求体中的字段名
+	In       string              // 字段位置：path、query、header、json 或 form
+	Required bool                // 是否必填
+	Field    reflect.StructField // 结构体字段
+}
+
+// requestLayout 按绑定规则返回请求结构的字段位置，与 OpenAPI 文档的参数和请求体一致
+func requestLayout(t reflect.Type, method string) []requestField {
+	fields := requestFields(t)
+
+	jsonBody := false
+	for _, field := range fields {
+		if !isParameterField(field) && field.Tag.Get("json") != "" {
+			jsonBody = true
+		}
+	}
+
+	var layout []requestField
+	for _, field := range fields {
+		f := requestField{Field: field, Required: bindingRequired(field)}
+		switch {
+		case tagName(field, PathTag) != "":
+			f.Name, f.In, f.Required = tagName(field, PathTag), "path", true
+		case tagName(field, "uri") != "":
+			f.Name, f.In, f.Required = tagName(field, "uri"), "path", true
+		case tagName(field, "header") != "":
+			f.Name, f.In = tagName(field, "header"), "header"
+		case !hasRequestBody(method):
+			if tagName(field, "form") == "" {
+				continue
+			}
+			f.Name, f.In = tagName(field, "form"), "query"
+		case jsonBody:
+			name, _, ok := jsonFieldName(field)
+			if !ok || (field.Tag.Get("json") == "" && tagName(field, "form") != "") {
+				continue
+			}
+			f.Name, f.In = name, "json"
+		case tagName(field, "form") != "":
+			f.Name, f.In = tagName(field, "form"), "form"
+		default:
+			continue
+		}
+		layout = append(layout, f)
+	}
+	return layout
+}
+
+// bindingRequired 判断字段的 binding tag 是否包含 required
+func bindingRequired(field reflect.StructField) bool {
+	for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+		if rule == "dive" {
+			return false
+		}
+		if rule == "required" {
+			return true
+		}
+	}
+	return false
+}
+
+// TypeScript 根据注册表中的路由生成 TypeScript 类型和客户端
+func (r *Registry) TypeScript() string {
+	return GenerateTypeScript(r.Routes())
+}
+
+// GenerateTypeScript 根据路由生成 TypeScript 代码，包括请求和响应类型的接口以及基于 fetch 的 Client 类
+//
+// 每个路由生成一个 Client 方法，方法名取自 Meta.Name，未设置时由 HTTP 方法和路径生成，
+// 如 GET /api/user/:id 为 getApiUserById。方法返回默认响应封装 SuccessResponse<T>，
+// 错误响应以 ApiError 抛出。
+//
+// 响应类型中带 omitempty 的字段为可选字段，没有 omitempty 的指针字段可能为 null；
+// 请求类型中 binding 不包含 required 的字段为可选字段。
+func GenerateTypeScript(routes []RouteInfo) string {
+	g := &tsGenerator{
+		names:    make(map[reflect.Type]string),
+		reqNames: make(map[reflect.Type]string),
+		used:     make(map[string]bool),
+	}
+	for _, name := range []string{"Link", "FieldError", "ErrorResponse", "SuccessResponse", "ApiError", "RequestOptions", "ClientOptions", "RequestConfig", "Client"} {
+		g.used[name] = true
+	}
+
+	var methods []string
+	methodNames := make(map[string]bool)
+	for _, route := range routes {
+		methods = append(methods, g.method(route, uniqueIdentifier(methodNames, clientMethodName(route))))
+	}
+
+	var b strings.Builder
+	b.WriteString("// Code generated by apihandler. DO NOT EDIT.\n\n")
+	b.WriteString(tsPreamble)
+	for _, decl := range g.decls {
+		b.WriteString("\n")
+		b.WriteString(decl)
+	}
+	b.WriteString("\n")
+	b.WriteString(tsClient)
+	for _, method := range methods {
+		b.WriteString("\n")
+		b.WriteString(method)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// tsGenerator 根据 Go 类型生成 TypeScript 接口
+type tsGenerator struct {
+	names    map[reflect.Type]string // 类型对应的接口名称
+	reqNames map[reflect.Type]string // 请求类型对应的接口名称
+	used     map[string]bool         // 已使用的名称
+	decls    []string                // 按首次引用顺序生成的接口声明
+}
+
+// typeOf 返回类型对应的 TypeScript 类型
+func (g *tsGenerator) typeOf(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return "string"
+	case durationType:
+		return "number"
+	case rawMessageType:
+		return "unknown"
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return "string"
+		}
+		elem := g.typeOf(t.Elem())
+		if strings.ContainsAny(elem, " |") {
+			elem = "(" + elem + ")"
+		}
+		return elem + "[]"
+	case reflect.Map:
+		return "Record<string, " + g.typeOf(t.Elem()) + ">"
+	case reflect.Struct:
+		if t.Name() == "" {
+			return tsObject(g.properties(t), " ")
+		}
+		return g.named(t)
+	}
+	return "unknown"
+}
+
+// named 返回具名结构体的接口名称，首次引用时生成接口声明
+func (g *tsGenerator) named(t reflect.Type) string {
+	if name, ok := g.names[t]; ok {
+		return name
+	}
+	name := uniqueIdentifier(g.used, typeName(t))
+	g.names[t] = name
+
+	index := len(g.decls)
+	g.decls = append(g.decls, "") // 占位，保持声明顺序并支持递归类型
+	g.decls[index] = tsInterface(name, g.properties(t))
+	return name
+}
+
+// properties 返回结构体的接口属性，匿名嵌入的结构体字段展开到外层
+func (g *tsGenerator) properties(t reflect.Type) []string {
+	var props []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, omitempty, ok := jsonFieldName(field)
+		if !ok {
+			continue
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Pointer {
+			fieldType = fieldType.Elem()
+		}
+		if field.Anonymous && fieldType.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			props = append(props, g.properties(fieldType)...)
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		typ := g.fieldType(field)
+		if field.Type.Kind() == reflect.Pointer && !omitempty {
+			typ += " | null"
+		}
+		props = append(props, tsDoc(field)+tsProperty(name, !omitempty, typ))
+	}
+	return props
+}
+
+// fieldType 返回字段的 TypeScript 类型，binding 中的 oneof 规则生成字面量联合类型
+func (g *tsGenerator) fieldType(field reflect.StructField) string {
+	for _, rule := range strings.Split(field.Tag.Get("binding"), ",") {
+		name, param, _ := strings.Cut(rule, "=")
+		if name == "dive" {
+			break
+		}
+		if name != "oneof" {
+			continue
+		}
+		kind := field.Type.Kind()
+		var values []string
+		for _, v := range strings.Fields(param) {
+			switch {
+			case kind == reflect.String:
+				values = append(values, strconv.Quote(v))
+			case kind >= reflect.Int && kind <= reflect.Float64 && tsNumber.MatchString(v):
+				values = append(values, v)
+			}
+		}
+		if len(values) > 0 {
+			return strings.Join(values, " | ")
+		}
+	}
+	return g.typeOf(field.Type)
+}
+
+// requestType 返回请求类型对应的接口名称，请求结构没有字段时返回空字符串
+func (g *tsGenerator) requestType(route RouteInfo, methodName string, layout []requestField) string {
+	if len(layout) == 0 {
+		return ""
+	}
+	t := route.Request
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if name, ok := g.reqNames[t]; ok {
+		return name
+	}
+
+	name := typeName(t)
+	if name == "" {
+		name = strings.ToUpper(methodName[:1]) + methodName[1:] + "Request"
+	}
+	name = uniqueIdentifier(g.used, name)
+	g.reqNames[t] = name
+
+	props := make([]string, 0, len(layout))
+	for _, f := range layout {
+		props = append(props, tsDoc(f.Field)+tsProperty(f.Name, f.Required, g.fieldType(f.Field)))
+	}
+	g.decls = append(g.decls, tsInterface(name, props))
+	return name
+}
+
+// method 生成路由对应的 Client 方法
+func (g *tsGenerator) method(route RouteInfo, methodName string) string {
+	var layout []requestField
+	if route.Request != nil {
+		layout = requestLayout(route.Request, route.Method)
+	}
+	reqType := g.requestType(route, methodName, layout)
+	respType := "unknown"
+	if route.Response != nil {
+		respType = g.typeOf(route.Response)
+	}
+
+	path := tsPath(route.Path, layout)
+	groups := map[string][]string{}
+	for _, f := range layout {
+		if f.In != "path" {
+			groups[f.In] = append(groups[f.In], tsKey(f.Name)+": "+tsAccess("req", f.Name))
+		}
+	}
+	var parts []string
+	for _, in := range []string{"query", "header", "json", "form"} {
+		if len(groups[in]) > 0 {
+			key := in
+			switch in {
+			case "header":
+				key = "headers"
+			case "json":
+				key = "body"
+			}
+			parts = append(parts, key+": { "+strings.Join(groups[in], ", ")+" }")
+		}
+	}
+
+	params := "options?: RequestOptions"
+	if reqType != "" {
+		params = "req: " + reqType + ", " + params
+	}
+
+	var b strings.Builder
+	var doc []string
+	if route.Meta.Summary != "" {
+		doc = append(doc, route.Meta.Summary)
+	}
+	if route.Deprecation != nil {
+		doc = append(doc, strings.TrimSpace("@deprecated "+deprecationNote(route.Deprecation)))
+	}
+	if len(doc) > 0 {
+		fmt.Fprintf(&b, "  /** %s */\n", strings.ReplaceAll(strings.Join(doc, " "), "*/", "* /"))
+	}
+	fmt.Fprintf(&b, "  %s(%s): Promise<SuccessResponse<%s>> {\n", methodName, params, respType)
+	fmt.Fprintf(&b, "    return this.request<%s>(%q, %s, %s, options);\n", respType, route.Method, path, tsObject(parts, ", "))
+	b.WriteString("  }\n")
+	return b.String()
+}
+
+// tsPath 生成请求路径的模板字符串，路径参数替换为请求中的字段
+func tsPath(fullPath string, layout []requestField) string {
+	params := make(map[string]bool)
+	for _, f := range layout {
+		if f.In == "path" {
+			params[f.Name] = true
+		}
+	}
+	segments := strings.Split(fullPath, "/")
+	for i, segment := range segments {
+		if segment == "" || (segment[0] != ':' && segment[0] != '*') {
+			segments[i] = strings.ReplaceAll(segments[i], "`", "\\`")
+			continue
+		}
+		name := segment[1:]
+		if !params[name] {
+			continue
+		}
+		if segment[0] == '*' {
+			segments[i] = "${String(" + tsAccess("req", name) + ").replace(/^\\//, \"\")}"
+		} else {
+			segments[i] = "${encodeURIComponent(String(" + tsAccess("req", name) + "))}"
+		}
+	}
+	return "`" + strings.Join(segments, "/") + "`"
+}
+
+// clientMethodName 返回路由对应的 Client 方法名称
+func clientMethodName(route RouteInfo) string {
+	if route.Meta.Name != "" {
+		return identifier(route.Meta.Name)
+	}
+	words := []string{strings.ToLower(route.Method)}
+	for _, segment := range strings.Split(route.Path, "/") {
+		if segment == "" {
+			continue
+		}
+		if segment[0] == ':' || segment[0] == '*' {
+			words = append(words, "By")
+			segment = segment[1:]
+		}
+		for _, word := range strings.FieldsFunc(segment, func(r rune) bool { return !isIdentifierRune(r) }) {
+			words = append(words, strings.ToUpper(word[:1])+word[1:])
+		}
+	}
+	return strings.Join(words, "")
+}
+
+var (
+	tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+	tsNumber     = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?$`)
+)
+
+// identifier 将名称转换为合法的标识符
+func identifier(name string) string {
+	name = strings.Map(func(r rune) rune {
+		if isIdentifierRune(r) {
+			return r
+		}
+		return '_'
+	}, name)
+	if name == "" || name[0] >= '0' && name[0] <= '9' {
+		name = "_" + name
+	}
+	return name
+}
+
+// isIdentifierRune 判断字符是否可用于标识符
+func isIdentifierRune(r rune) bool {
+	return r == '_' || r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
+
+// uniqueIdentifier 返回未使用的名称并标记为已使用，名称冲突时追加序号
+func uniqueIdentifier(used map[string]bool, name string) string {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	used[unique] = true
+	return unique
+}
+
+// tsInterface 生成接口声明
+func tsInterface(name string, props []string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "export interface %s {\n", name)
+	for _, prop := range props {
+		fmt.Fprintf(&b, "  %s\n", prop)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// tsObject 生成对象字面量或对象类型，没有属性时为 {}
+func tsObject(props []string, sep string) string {
+	if len(props) == 0 {
+		return "{}"
+	}
+	return "{ " + strings.Join(props, sep) + " }"
+}
+
+// tsDoc 根据字段的 doc tag 生成属性前的注释
+func tsDoc(field reflect.StructField) string {
+	doc := field.Tag.Get(DocTag)
+	if doc == "" {
+		return ""
+	}
+	return "/** " + strings.ReplaceAll(doc, "*/", "* /") + " */\n  "
+}
+
+// tsProperty 生成接口属性
+func tsProperty(name string, required bool, typ string) string {
+	optional := "?"
+	if required {
+		optional = ""
+	}
+	return tsKey(name) + optional + ": " + typ + ";"
+}
+
+// tsKey 返回属性名，不是合法标识符时加引号
+func tsKey(name string) string {
+	if tsIdentifier.MatchString(name) {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+// tsAccess 返回访问对象属性的表达式
+func tsAccess(object, name string) string {
+	if tsIdentifier.MatchString(name) {
+		return object + "." + name
+	}
+	return object + "[" + strconv.Quote(name) + "]"
+}
+
+// tsPreamble 默认响应封装和错误类型
+const tsPreamble = `export interface Link {
+  href: string;
+  method?: string;
+  title?: string;
+}
+
+export interface FieldError {
+  field: string;
+  rule?: string;
+  param?: string;
+  related?: string[];
+  message: string;
+  value?: unknown;
+}
+
+export interface ErrorResponse {
+  code: number | string;
+  message: string;
+  errors?: FieldError[];
+  request_id?: string;
+}
+
+export interface SuccessResponse<T> {
+  code: number | string;
+  data: T;
+  meta?: unknown;
+  links?: Record<string, Link>;
+  request_id?: string;
+}
+
+/** 错误响应，status 为 HTTP 状态码，body 为错误响应体 */
+export class ApiError extends Error {
+  constructor(public readonly status: number, public readonly body: ErrorResponse) {
+    super(body?.message ?? "HTTP " + status);
+  }
+}
+`
+
+// tsClient Client 类的公共部分
+const tsClient = `export interface RequestOptions {
+  headers?: Record<string, string>;
+  signal?: AbortSignal;
+}
+
+export interface ClientOptions {
+  baseURL?: string;
+  headers?: Record<string, string>;
+  fetch?: typeof fetch;
+}
+
+interface RequestConfig {
+  query?: Record<string, unknown>;
+  headers?: Record<string, unknown>;
+  body?: Record<string, unknown>;
+  form?: Record<string, unknown>;
+}
+
+function toSearchParams(values?: Record<string, unknown>): URLSearchParams {
+  const params = new URLSearchParams();
+  for (const [key, value] of Object.entries(values ?? {})) {
+    for (const item of Array.isArray(value) ? value : [value]) {
+      if (item !== undefined && item !== null) {
+        params.append(key, String(item));
+      }
+    }
+  }
+  return params;
+}
+
+export class Client {
+  private readonly baseURL: string;
+  private readonly headers: Record<string, string>;
+  private readonly fetchFn: typeof fetch;
+
+  constructor(options: ClientOptions = {}) {
+    this.baseURL = (options.baseURL ?? "").replace(/\/$/, "");
+    this.headers = options.headers ?? {};
+    this.fetchFn = options.fetch ?? globalThis.fetch.bind(globalThis);
+  }
+
+  protected async request<T>(method: string, path: string, config: RequestConfig, options: RequestOptions = {}): Promise<SuccessResponse<T>> {
+    let url = this.baseURL + path;
+    const query = toSearchParams(config.query).toString();
+    if (query) {
+      url += "?" + query;
+    }
+
+    const headers: Record<string, string> = { ...this.headers };
+    for (const [key, value] of Object.entries(config.headers ?? {})) {
+      if (value !== undefined && value !== null) {
+        headers[key] = String(value);
+      }
+    }
+    let body: BodyInit | undefined;
+    if (config.body) {
+      headers["Content-Type"] = "application/json";
+      body = JSON.stringify(config.body);
+    } else if (config.form) {
+      headers["Content-Type"] = "application/x-www-form-urlencoded";
+      body = toSearchParams(config.form);
+    }
+    Object.assign(headers, options.headers);
+
+    const response = await this.fetchFn(url, { method, headers, body, signal: options.signal });
+    const payload = await response.json().catch(() => undefined);
+    if (!response.ok) {
+      throw new ApiError(response.status, payload);
+    }
+    return payload as SuccessResponse<T>;
+  }
+`
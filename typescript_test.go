@@ -0,0 +1,79 @@
+package apihandler
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试根据注册表生成 TypeScript 类型和客户端
+func TestTypeScript(t *testing.T) {
+	r := gin.New()
+	registry := NewRegistry()
+	api := NewGroup(r.Group("/api"), WithRegistry(registry))
+
+	list := func(ctx context.Context, req *openAPIListRequest) (*[]openAPIUser, error) { return nil, nil }
+	update := func(ctx context.Context, req *openAPIUpdateRequest) (*openAPIUser, error) { return nil, nil }
+	ping := func(ctx context.Context, req *struct{}) (*openAPIPage[openAPIUser], error) { return nil, nil }
+	GET(api, "/users", list, WithMeta(Meta{Name: "listUsers", Summary: "用户列表"}))
+	PUT(api, "/users/:id", update)
+	POST(api, "/users", update, WithSuccessHTTPCode(http.StatusCreated))
+	GET(api, "/ping", ping)
+
+	code := registry.TypeScript()
+	for _, want := range []string{
+		"// Code generated by apihandler. DO NOT EDIT.",
+		// 响应类型：omitempty 字段可选
+		"export interface openAPIUser {\n  id: number;\n  name: string;\n  tags?: string[];\n  created_at: string;\n}",
+		// 请求类型：参数使用 tag 名称，oneof 生成联合类型，claim 字段不输出
+		"export interface openAPIListRequest {\n  page?: number;\n  status?: \"active\" | \"disabled\";\n  \"X-Trace-Id\"?: string;\n}",
+		"export interface openAPIUpdateRequest {\n  id: number;\n  name: string;\n  email?: string;\n  age?: number;\n}",
+		"export interface openAPIPage_openAPIUser {\n  items: openAPIUser[];\n  total: number;\n}",
+		// 客户端方法
+		"  /** 用户列表 */\n  listUsers(req: openAPIListRequest, options?: RequestOptions): Promise<SuccessResponse<openAPIUser[]>> {",
+		"this.request<openAPIUser[]>(\"GET\", `/api/users`, { query: { page: req.page, status: req.status }, headers: { \"X-Trace-Id\": req[\"X-Trace-Id\"] } }, options)",
+		"putApiUsersById(req: openAPIUpdateRequest, options?: RequestOptions): Promise<SuccessResponse<openAPIUser>>",
+		"`/api/users/${encodeURIComponent(String(req.id))}`, { body: { name: req.name, email: req.email, age: req.age } }",
+		"getApiPing(options?: RequestOptions): Promise<SuccessResponse<openAPIPage_openAPIUser>>",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("期望生成的代码包含 %q, 实际得到:\n%s", want, code)
+		}
+	}
+	if strings.Contains(code, "Operator") {
+		t.Error("期望 claim 字段不出现在生成的代码中")
+	}
+}
+
+// 测试指针字段、表单请求体和名称冲突
+func TestTypeScriptTypes(t *testing.T) {
+	type Client struct {
+		Parent  *Client           `json:"parent"`
+		Labels  map[string]string `json:"labels,omitempty"`
+		Scores  []*float64        `json:"scores"`
+		Payload []byte            `json:"payload"`
+	}
+	type loginRequest struct {
+		Username string `form:"username" binding:"required"`
+		Password string `form:"password" binding:"required"`
+	}
+
+	code := GenerateTypeScript([]RouteInfo{
+		{Method: http.MethodPost, Path: "/login", Request: reflect.TypeFor[loginRequest](), Response: reflect.TypeFor[Client]()},
+		{Method: http.MethodPost, Path: "/login", Meta: Meta{Name: "postLogin"}},
+	})
+	for _, want := range []string{
+		"export interface Client2 {\n  parent: Client2 | null;\n  labels?: Record<string, string>;\n  scores: number[];\n  payload: string;\n}",
+		"{ form: { username: req.username, password: req.password } }",
+		"postLogin(req: loginRequest",
+		"postLogin2(options?: RequestOptions): Promise<SuccessResponse<unknown>>",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("期望生成的代码包含 %q, 实际得到:\n%s", want, code)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package apihandler
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxUploadFormSize 上传请求中非文件表单字段的最大总字节数，与 net/http 解析表单的限制相同
+const maxUploadFormSize = 10 << 20
+
+// UploadFile 上传请求中的文件部分
+type UploadFile struct {
+	Field       string               // 表单字段名
+	Filename    string               // 客户端提供的文件名，不含目录
+	ContentType string               // 文件部分的 Content-Type
+	Header      textproto.MIMEHeader // 文件部分的全部头部
+}
+
+// StoredFile 已保存到存储的上传文件
+type StoredFile struct {
+	UploadFile
+	Key  string // UploadStorage.Save 返回的键
+	Size int64  // 文件的字节数
+}
+
+// UploadStorage 上传文件的存储，如本地目录和对象存储
+type UploadStorage interface {
+	// Save 保存文件内容，返回用于读取和删除文件的键
+	//
+	// r 直接读取请求体，读完即为文件的全部内容；超过 WithMaxUploadSize 的限制或请求体中断时读取返回错误。
+	// 返回错误时应清理已写入的内容。
+	Save(ctx context.Context, file UploadFile, r io.Reader) (key string, err error)
+	// Delete 删除已保存的文件
+	Delete(ctx context.Context, key string) error
+}
+
+// UploadFunc 上传业务处理函数类型，files 为按请求中的顺序保存到存储的文件
+type UploadFunc[T any, R any] func(ctx context.Context, req *T, files []StoredFile) (*R, error)
+
+// UploadHandler 创建流式的 multipart 上传处理器
+//
+// 文件部分依次直接写入 storage，不在内存或临时文件中缓冲；其他表单字段按 form tag 绑定到请求对象，
+// 路径参数、验证、授权和错误处理与 Handler 相同。业务处理函数被调用之前失败（如参数验证失败）时删除本次请求
+// 已保存的文件；业务处理函数被调用后文件由其负责，返回错误时需要自行删除。请求对象中的 *multipart.FileHeader
+// 字段不会被填充。
+func UploadHandler[T any, R any](storage UploadStorage, uploadFunc UploadFunc[T, R], opts ...Option) gin.HandlerFunc {
+	config := NewConfig(opts...)
+	receive := func(c *gin.Context, req any) error {
+		return receiveUpload(c, config, storage)
+	}
+	config.BeforeBind = append([]BindHook{receive}, config.BeforeBind...)
+
+	handler := HandlerWithConfig(func(ctx context.Context, req *T) (*R, error) {
+		u := ctx.Value(uploadContextKey{}).(*upload)
+		if !u.state.CompareAndSwap(uploadReceiving, uploadHandled) {
+			// 处理器已经返回并删除了文件（如业务处理函数开始前已超时）
+			return nil, context.Canceled
+		}
+		return uploadFunc(ctx, req, u.files)
+	}, config)
+
+	return func(c *gin.Context) {
+		u := &upload{}
+		ctx := c.Request.Context()
+		c.Request = c.Request.WithContext(context.WithValue(ctx, uploadContextKey{}, u))
+		defer u.cleanup(context.WithoutCancel(ctx), storage)
+		handler(c)
+	}
+}
+
+// WithMaxUploadSize 设置上传处理器中单个文件的最大字节数，超过时返回 413 错误
+func WithMaxUploadSize(size int64) Option {
+	return func(c *HandlerConfig) {
+		c.MaxUploadSize = size
+	}
+}
+
+// uploadContextKey 请求 context 中保存上传状态的键
+type uploadContextKey struct{}
+
+// 上传状态
+const (
+	uploadReceiving int32 = iota // 正在接收，业务处理函数尚未调用
+	uploadHandled                // 已调用业务处理函数，文件由其负责
+	uploadCleaned                // 已删除保存的文件
+)
+
+// upload 一次上传请求中已保存的文件
+type upload struct {
+	files []StoredFile
+	state atomic.Int32
+}
+
+// cleanup 业务处理函数没有被调用时删除已保存的文件
+func (u *upload) cleanup(ctx context.Context, storage UploadStorage) {
+	if !u.state.CompareAndSwap(uploadReceiving, uploadCleaned) {
+		return
+	}
+	for _, file := range u.files {
+		_ = storage.Delete(ctx, file.Key)
+	}
+}
+
+// receiveUpload 读取 multipart 请求体，将文件保存到存储，其他表单字段提供给参数绑定
+func receiveUpload(c *gin.Context, config *HandlerConfig, storage UploadStorage) error {
+	u := c.Request.Context().Value(uploadContextKey{}).(*upload)
+	translator := requestTranslator(c, config)
+	bindError := func(err error) error {
+		return NewBizError(config.BindErrorCode, translator.Translate(MsgBindErrorDetail, err), http.StatusBadRequest)
+	}
+
+	mr, err := c.Request.MultipartReader()
+	if err != nil {
+		return bindError(err)
+	}
+	values := make(url.Values)
+	remaining := int64(maxUploadFormSize)
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return bindError(err)
+		}
+		field := part.FormName()
+		if field == "" {
+			continue
+		}
+		if part.FileName() == "" {
+			var b strings.Builder
+			n, err := io.CopyN(&b, part, remaining+1)
+			if err != nil && err != io.EOF {
+				return bindError(err)
+			}
+			if remaining -= n; remaining < 0 {
+				return bindError(errors.New("multipart: form fields too large"))
+			}
+			values.Add(field, b.String())
+			continue
+		}
+
+		file, err := saveUploadFile(c, config, translator, storage, part)
+		if err != nil {
+			return err
+		}
+		u.files = append(u.files, file)
+	}
+
+	// 表单字段优先于同名的查询参数，与 net/http 解析的表单一致
+	form := make(url.Values, len(values))
+	for key, vs := range values {
+		form[key] = append(form[key], vs...)
+	}
+	for key, vs := range c.Request.URL.Query() {
+		form[key] = append(form[key], vs...)
+	}
+	c.Request.Form = form
+	c.Request.PostForm = values
+	c.Request.MultipartForm = &multipart.Form{Value: values, File: make(map[string][]*multipart.FileHeader)}
+	return nil
+}
+
+// saveUploadFile 将文件部分写入存储
+func saveUploadFile(c *gin.Context, config *HandlerConfig, translator Translator, storage UploadStorage, part *multipart.Part) (StoredFile, error) {
+	file := UploadFile{
+		Field:       part.FormName(),
+		Filename:    part.FileName(),
+		ContentType: part.Header.Get("Content-Type"),
+		Header:      part.Header,
+	}
+	r := &uploadReader{r: part, limit: config.MaxUploadSize}
+	key, err := storage.Save(c.Request.Context(), file, r)
+	if r.err != nil {
+		if err == nil {
+			_ = storage.Delete(context.WithoutCancel(c.Request.Context()), key)
+		}
+		if errors.Is(r.err, errUploadTooLarge) {
+			return StoredFile{}, NewBizError(http.StatusRequestEntityTooLarge, translator.Translate(MsgUploadTooLarge, file.Filename, config.MaxUploadSize), http.StatusRequestEntityTooLarge)
+		}
+		return StoredFile{}, NewBizError(config.BindErrorCode, translator.Translate(MsgBindErrorDetail, r.err), http.StatusBadRequest)
+	}
+	if err != nil {
+		return StoredFile{}, err
+	}
+	return StoredFile{UploadFile: file, Key: key, Size: r.n}, nil
+}
+
+// errUploadTooLarge 上传的文件超过 MaxUploadSize
+var errUploadTooLarge = errors.New("apihandler: upload too large")
+
+// uploadReader 统计文件部分的字节数并检查大小限制，记录读取请求体的错误
+type uploadReader struct {
+	r     io.Reader
+	limit int64 // 小于等于 0 表示不限制
+	n     int64
+	err   error
+}
+
+// Read 实现 io.Reader 接口
+func (r *uploadReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	if r.limit > 0 && r.n > r.limit {
+		r.err = errUploadTooLarge
+		return 0, r.err
+	}
+	if err != nil && err != io.EOF {
+		r.err = err
+	}
+	return n, err
+}
+
+// LocalStorage 将上传的文件保存到本地目录的存储
+type LocalStorage struct {
+	dir string
+}
+
+// NewLocalStorage 创建将上传的文件保存到 dir 的存储，目录不存在时创建
+func NewLocalStorage(dir string) (*LocalStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &LocalStorage{dir: dir}, nil
+}
+
+// Save 实现 UploadStorage 接口，文件名随机生成并保留原文件的扩展名，返回文件名作为键
+func (s *LocalStorage) Save(ctx context.Context, file UploadFile, r io.Reader) (string, error) {
+	f, err := os.CreateTemp(s.dir, "upload-*"+uploadExt(file.Filename))
+	if err != nil {
+		return "", err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+	return filepath.Base(f.Name()), nil
+}
+
+// Delete 实现 UploadStorage 接口，文件不存在时不返回错误
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	if err := os.Remove(s.Path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Path 返回键对应的文件路径
+func (s *LocalStorage) Path(key string) string {
+	return filepath.Join(s.dir, filepath.Base(key))
+}
+
+// uploadExt 返回文件名的扩展名，只保留由字母和数字组成的扩展名
+func uploadExt(filename string) string {
+	ext := filepath.Ext(filename)
+	if len(ext) < 2 || len(ext) > 16 {
+		return ""
+	}
+	for _, r := range ext[1:] {
+		if !('a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9') {
+			return ""
+		}
+	}
+	return ext
+}
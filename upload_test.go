@@ -0,0 +1,325 @@
+package apihandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// memoryStorage 测试用的存储，记录保存和删除的文件
+type memoryStorage struct {
+	mu      sync.Mutex
+	files   map[string][]byte
+	deleted []string
+	seq     int
+	onRead  func() // 读取到文件的第一块内容时调用
+	failure error  // 不为 nil 时 Save 返回该错误
+}
+
+func newMemoryStorage() *memoryStorage {
+	return &memoryStorage{files: make(map[string][]byte)}
+}
+
+// Save 实现 UploadStorage 接口
+func (s *memoryStorage) Save(ctx context.Context, file UploadFile, r io.Reader) (string, error) {
+	var buf bytes.Buffer
+	chunk := make([]byte, 4)
+	for {
+		n, err := r.Read(chunk)
+		buf.Write(chunk[:n])
+		if n > 0 && s.onRead != nil {
+			s.onRead()
+			s.onRead = nil
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	if s.failure != nil {
+		return "", s.failure
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.seq++
+	key := fmt.Sprintf("%d-%s", s.seq, file.Filename)
+	s.files[key] = buf.Bytes()
+	return key, nil
+}
+
+// Delete 实现 UploadStorage 接口
+func (s *memoryStorage) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.files, key)
+	s.deleted = append(s.deleted, key)
+	return nil
+}
+
+// uploadRequest 上传请求
+type uploadRequest struct {
+	AlbumID string   `path:"album_id" binding:"required"`
+	Title   string   `form:"title" binding:"required,max=10"`
+	Tags    []string `form:"tags"`
+}
+
+// uploadResponse 上传响应
+type uploadResponse struct {
+	Title string       `json:"title"`
+	Tags  []string     `json:"tags"`
+	Files []StoredFile `json:"files"`
+}
+
+// uploadPart multipart 请求体的一个部分，filename 为空时为普通字段
+type uploadPart struct {
+	field, filename, content string
+}
+
+// multipartBody 创建 multipart 请求体
+func multipartBody(t *testing.T, parts ...uploadPart) (*bytes.Buffer, string) {
+	t.Helper()
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for _, p := range parts {
+		var w io.Writer
+		var err error
+		if p.filename == "" {
+			w, err = mw.CreateFormField(p.field)
+		} else {
+			w, err = mw.CreateFormFile(p.field, p.filename)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(w, p.content)
+	}
+	mw.Close()
+	return &body, mw.FormDataContentType()
+}
+
+// echoUpload 返回请求和文件的上传业务处理函数
+func echoUpload(ctx context.Context, req *uploadRequest, files []StoredFile) (*uploadResponse, error) {
+	return &uploadResponse{Title: req.Title, Tags: req.Tags, Files: files}, nil
+}
+
+// 测试上传处理器保存文件并绑定其他表单字段
+func TestUploadHandler(t *testing.T) {
+	storage := newMemoryStorage()
+	r := gin.New()
+	r.POST("/albums/:album_id/photos", UploadHandler(storage, echoUpload))
+
+	// 表单字段在文件之后也能绑定
+	body, contentType := multipartBody(t,
+		uploadPart{"photos", "a.png", "first"},
+		uploadPart{"tags", "", "x"},
+		uploadPart{"photos", "../b.jpg", "second photo"},
+		uploadPart{"title", "", "trip"},
+		uploadPart{"tags", "", "y"},
+	)
+	req := httptest.NewRequest("POST", "/albums/7/photos?tags=query", body)
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("期望状态码 %d, 实际得到 %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var resp SuccessResponse[uploadResponse]
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Data.Title != "trip" || strings.Join(resp.Data.Tags, ",") != "x,y" {
+		t.Errorf("期望 title 为 trip、tags 为 x,y, 实际得到 %q %v", resp.Data.Title, resp.Data.Tags)
+	}
+	files := resp.Data.Files
+	if len(files) != 2 {
+		t.Fatalf("期望 2 个文件, 实际得到 %d", len(files))
+	}
+	expected := []struct {
+		filename, content string
+	}{{"a.png", "first"}, {"b.jpg", "second photo"}}
+	for i, e := range expected {
+		f := files[i]
+		if f.Field != "photos" || f.Filename != e.filename || f.Size != int64(len(e.content)) || f.ContentType != "application/octet-stream" {
+			t.Errorf("文件 %d 的信息不符合预期: %+v", i, f)
+		}
+		if got := string(storage.files[f.Key]); got != e.content {
+			t.Errorf("期望文件 %d 的内容为 %q, 实际得到 %q", i, e.content, got)
+		}
+	}
+	if len(storage.deleted) != 0 {
+		t.Errorf("期望成功时不删除文件, 实际删除了 %v", storage.deleted)
+	}
+}
+
+// 测试文件内容在请求体读完之前就写入存储
+func TestUploadHandlerStreaming(t *testing.T) {
+	storage := newMemoryStorage()
+	started := make(chan struct{})
+	storage.onRead = func() { close(started) }
+	r := gin.New()
+	r.POST("/albums/:album_id/photos", UploadHandler(storage, echoUpload))
+
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+	go func() {
+		fw, _ := mw.CreateFormFile("photo", "big.bin")
+		io.WriteString(fw, "head")
+		// 存储读取到第一块内容之后才写入请求体的剩余部分
+		select {
+		case <-started:
+		case <-time.After(5 * time.Second):
+			pw.CloseWithError(errors.New("storage did not receive data before the body ended"))
+			return
+		}
+		io.WriteString(fw, "tail")
+		ff, _ := mw.CreateFormField("title")
+		io.WriteString(ff, "stream")
+		mw.Close()
+		pw.Close()
+	}()
+
+	req := httptest.NewRequest("POST", "/albums/1/photos", pr)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("期望状态码 %d, 实际得到 %d: %s", http.StatusOK, w.Code, w.Body.String())
+	}
+	var resp SuccessResponse[uploadResponse]
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Data.Files) != 1 || string(storage.files[resp.Data.Files[0].Key]) != "headtail" {
+		t.Errorf("期望保存的文件内容为 headtail, 实际得到 %+v", resp.Data.Files)
+	}
+}
+
+// 测试上传失败时的错误响应和已保存文件的删除
+func TestUploadHandlerErrors(t *testing.T) {
+	bizErr := NewBizError(40901, "相册已满", http.StatusConflict)
+	testCases := []struct {
+		name        string
+		parts       []uploadPart
+		contentType string
+		failure     error
+		code        int
+		message     string
+		deleted     int
+	}{
+		{"参数验证失败", []uploadPart{{"photo", "a.png", "data"}, {"title", "", "a very long title"}}, "", nil, http.StatusBadRequest, defaultMessages[MsgBindError], 1},
+		{"文件超过大小限制", []uploadPart{{"photo", "a.png", "data"}, {"photo", "b.png", "too large"}, {"title", "", "t"}}, "", nil, http.StatusRequestEntityTooLarge, "上传的文件 b.png 超过 8 字节", 1},
+		{"不是 multipart 请求", nil, "application/json", nil, http.StatusBadRequest, "参数绑定失败: request Content-Type isn't multipart/form-data", 0},
+		{"存储失败", []uploadPart{{"photo", "a.png", "data"}, {"title", "", "t"}}, "", errors.New("disk full"), http.StatusInternalServerError, "disk full", 0},
+		{"业务错误时不删除文件", []uploadPart{{"photo", "a.png", "data"}, {"title", "", "full"}}, "", nil, http.StatusConflict, "相册已满", 0},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			storage := newMemoryStorage()
+			storage.failure = tc.failure
+			r := gin.New()
+			r.POST("/albums/:album_id/photos", UploadHandler(storage, func(ctx context.Context, req *uploadRequest, files []StoredFile) (*uploadResponse, error) {
+				if req.Title == "full" {
+					return nil, bizErr
+				}
+				return &uploadResponse{Files: files}, nil
+			}, WithMaxUploadSize(8)))
+
+			body, contentType := multipartBody(t, tc.parts...)
+			if tc.contentType != "" {
+				contentType = tc.contentType
+			}
+			req := httptest.NewRequest("POST", "/albums/1/photos", body)
+			req.Header.Set("Content-Type", contentType)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tc.code {
+				t.Fatalf("期望状态码 %d, 实际得到 %d: %s", tc.code, w.Code, w.Body.String())
+			}
+			var resp ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Message != tc.message {
+				t.Errorf("期望错误消息为 %q, 实际得到 %q", tc.message, resp.Message)
+			}
+			if len(storage.deleted) != tc.deleted {
+				t.Errorf("期望删除 %d 个文件, 实际删除了 %v", tc.deleted, storage.deleted)
+			}
+		})
+	}
+}
+
+// 测试本地目录存储
+func TestLocalStorage(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "uploads")
+	storage, err := NewLocalStorage(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := storage.Save(context.Background(), UploadFile{Filename: "photo.PNG"}, strings.NewReader("image"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Ext(key) != ".PNG" {
+		t.Errorf("期望键保留扩展名 .PNG, 实际得到 %q", key)
+	}
+	data, err := os.ReadFile(storage.Path(key))
+	if err != nil || string(data) != "image" {
+		t.Errorf("期望文件内容为 image, 实际得到 %q, %v", data, err)
+	}
+
+	// 不安全的扩展名被忽略
+	other, err := storage.Save(context.Background(), UploadFile{Filename: "x.p/hp"}, strings.NewReader(""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Ext(other) != "" {
+		t.Errorf("期望忽略不安全的扩展名, 实际得到 %q", other)
+	}
+
+	// 读取失败时不留下文件
+	if _, err := storage.Save(context.Background(), UploadFile{Filename: "broken.txt"}, io.MultiReader(strings.NewReader("x"), &failingReader{})); err == nil {
+		t.Error("期望读取失败时返回错误")
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 2 {
+		t.Errorf("期望目录中有 2 个文件, 实际得到 %d", len(entries))
+	}
+
+	if err := storage.Delete(context.Background(), key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(storage.Path(key)); !os.IsNotExist(err) {
+		t.Errorf("期望文件已删除, 实际得到 %v", err)
+	}
+	if err := storage.Delete(context.Background(), key); err != nil {
+		t.Errorf("期望删除不存在的文件时不返回错误, 实际得到 %v", err)
+	}
+}
+
+// failingReader 读取总是失败的 Reader
+type failingReader struct{}
+
+func (*failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("connection reset")
+}
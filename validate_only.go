@@ -0,0 +1,38 @@
+package apihandler
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultValidateOnlyHeader 默认的仅验证请求头
+const DefaultValidateOnlyHeader = "X-Validate-Only"
+
+// ValidationResult 仅验证请求的成功响应数据
+type ValidationResult struct {
+	Valid bool `json:"valid" xml:"valid" codec:"valid"`
+}
+
+// WithValidateOnly 启用仅验证模式，header 为空时使用 X-Validate-Only
+//
+// 请求头的值为 true 时只执行参数绑定、验证和授权检查，不调用业务处理函数：
+// 验证通过时返回 200 和 {"valid": true}，验证失败时返回正常的错误响应。
+// 仅验证请求不读取和写入响应缓存，也不占用幂等键。
+func WithValidateOnly(header string) Option {
+	return func(c *HandlerConfig) {
+		if header == "" {
+			header = DefaultValidateOnlyHeader
+		}
+		c.ValidateOnlyHeader = header
+	}
+}
+
+// isValidateOnly 判断请求是否为仅验证请求
+func isValidateOnly(c *gin.Context, config *HandlerConfig) bool {
+	if config.ValidateOnlyHeader == "" {
+		return false
+	}
+	validateOnly, _ := strconv.ParseBool(c.GetHeader(config.ValidateOnlyHeader))
+	return validateOnly
+}
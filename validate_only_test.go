@@ -0,0 +1,111 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试仅验证模式
+func TestValidateOnly(t *testing.T) {
+	r := gin.New()
+
+	type createRequest struct {
+		Name string `json:"name" binding:"required"`
+	}
+	calls := 0
+	handleFunc := func(ctx context.Context, req *createRequest) (*testResponse, error) {
+		calls++
+		return &testResponse{Name: req.Name}, nil
+	}
+	r.POST("/users", Handler(handleFunc, WithValidateOnly("")))
+	r.POST("/plain", Handler(handleFunc))
+
+	tests := []struct {
+		name       string
+		url        string
+		header     string
+		body       string
+		wantStatus int
+		wantCalls  int
+	}{
+		{name: "验证通过", url: "/users", header: "true", body: `{"name":"test"}`, wantStatus: http.StatusOK},
+		{name: "验证失败", url: "/users", header: "1", body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "请求头为 false", url: "/users", header: "false", body: `{"name":"test"}`, wantStatus: http.StatusOK, wantCalls: 1},
+		{name: "未启用仅验证模式", url: "/plain", header: "true", body: `{"name":"test"}`, wantStatus: http.StatusOK, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls = 0
+			req := httptest.NewRequest("POST", tt.url, strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			req.Header.Set(DefaultValidateOnlyHeader, tt.header)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("期望状态码 %d, 实际得到 %d, 响应 %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("期望业务处理函数调用 %d 次, 实际得到 %d", tt.wantCalls, calls)
+			}
+		})
+	}
+
+	req := httptest.NewRequest("POST", "/users", strings.NewReader(`{"name":"test"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(DefaultValidateOnlyHeader, "true")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	var resp SuccessResponse[ValidationResult]
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	if !resp.Data.Valid {
+		t.Errorf("期望响应数据 valid 为 true, 实际得到 %s", w.Body.String())
+	}
+}
+
+// 测试仅验证请求不使用响应缓存和幂等键
+func TestValidateOnlySkipsCacheAndIdempotency(t *testing.T) {
+	r := gin.New()
+
+	calls := 0
+	handleFunc := func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		calls++
+		return &testResponse{Name: req.Name}, nil
+	}
+	r.GET("/users", Handler(handleFunc, WithValidateOnly("X-Dry-Run"), WithResponseCache(NewMemoryCacheStore(), time.Minute, nil)))
+	r.POST("/users", Handler(handleFunc, WithValidateOnly("X-Dry-Run"), WithIdempotency(NewMemoryIdempotencyStore(), time.Minute)))
+
+	send := func(method string, dryRun bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/users?name=test", nil)
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		if dryRun {
+			req.Header.Set("X-Dry-Run", "true")
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w
+	}
+
+	for _, method := range []string{"GET", "POST"} {
+		calls = 0
+		send(method, true)
+		w := send(method, false)
+		if calls != 1 {
+			t.Errorf("%s: 期望仅验证请求后正常请求调用业务处理函数 1 次, 实际得到 %d", method, calls)
+		}
+		if !strings.Contains(w.Body.String(), `"name":"test"`) {
+			t.Errorf("%s: 期望正常请求返回业务数据, 实际得到 %s", method, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,249 @@
+package apihandler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+)
+
+// ValidationMessageKey 返回验证规则的错误消息键，如 mobile_cn 为 validation.mobile_cn
+//
+// 消息中的 {field}、{param}、{related} 和 {value} 替换为字段名、规则参数、跨字段规则涉及的其他字段和字段的实际值。自定义翻译器
+// 返回空字符串或消息键本身时，使用通用的字段验证失败消息。
+func ValidationMessageKey(tag string) MessageKey {
+	return MessageKey("validation." + tag)
+}
+
+// RegisterValidation 在 gin 的验证器上注册自定义验证规则，messages 为各语言环境下的错误消息
+//
+// 应在注册路由之前调用。messages 的键为语言环境，如 zh、en：
+//
+//	apihandler.RegisterValidation("mobile_cn", func(fl validator.FieldLevel) bool {
+//		return mobilePattern.MatchString(fl.Field().String())
+//	}, map[string]string{
+//		"zh": "{field} 必须是有效的手机号",
+//		"en": "{field} must be a valid mobile number",
+//	})
+func RegisterValidation(tag string, fn validator.Func, messages map[string]string) error {
+	v, err := validatorEngine()
+	if err != nil {
+		return err
+	}
+	if err := v.RegisterValidation(tag, fn); err != nil {
+		return err
+	}
+	if err := registerScenarioValidation(tag, fn); err != nil {
+		return err
+	}
+	return registerValidationMessages(tag, messages)
+}
+
+// RegisterStructValidation 在 gin 的验证器上注册结构体级别的验证函数，types 为结构体的值
+//
+// 验证函数通过 StructLevel.ReportError 报告的规则名称同样使用 RegisterValidationMessage 注册的消息。
+func RegisterStructValidation(fn validator.StructLevelFunc, types ...any) error {
+	v, err := validatorEngine()
+	if err != nil {
+		return err
+	}
+	v.RegisterStructValidation(fn, types...)
+	return nil
+}
+
+// RegisterValidationMessage 设置验证规则在语言环境下的错误消息，可用于内置规则和结构体级别验证报告的规则
+func RegisterValidationMessage(tag, locale, message string) error {
+	return OverrideMessage(locale, ValidationMessageKey(tag), message)
+}
+
+// WithValidationMessages 为处理器设置验证消息模板，优先于 RegisterValidationMessage 登记的消息和翻译的消息
+//
+// 键为字段路径（如 password、items[2].price 或不带下标的 items.price）或验证规则名称（如 min），
+// 字段路径优先于规则名称。模板中的占位符与 ValidationMessageKey 相同，多次调用时合并：
+//
+//	handler.WithValidationMessages(map[string]string{
+//		"password": "password must contain at least {param} characters",
+//		"required": "{field} is required",
+//	})
+func WithValidationMessages(messages map[string]string) Option {
+	return func(c *HandlerConfig) {
+		if c.ValidationMessages == nil {
+			c.ValidationMessages = make(map[string]string, len(messages))
+		}
+		maps.Copy(c.ValidationMessages, messages)
+	}
+}
+
+// applyValidationMessages 使用处理器的验证消息模板替换字段错误的消息
+func applyValidationMessages(details []any, messages map[string]string) []any {
+	if len(messages) == 0 {
+		return details
+	}
+	for i, detail := range details {
+		fe, ok := detail.(FieldError)
+		if !ok {
+			continue
+		}
+		if message, ok := validationTemplate(messages, fe); ok {
+			fe.Message = formatFieldMessage(message, fe)
+			details[i] = fe
+		}
+	}
+	return details
+}
+
+// validationTemplate 返回字段错误的消息模板，依次匹配字段路径、去掉下标的字段路径和验证规则名称
+func validationTemplate(messages map[string]string, fe FieldError) (string, bool) {
+	for _, key := range []string{fe.Field, stripIndices(fe.Field), fe.Rule} {
+		if message, ok := messages[key]; ok && key != "" {
+			return message, true
+		}
+	}
+	return "", false
+}
+
+// stripIndices 去掉字段路径中的下标，如 items[2].price 为 items.price
+func stripIndices(path string) string {
+	var b strings.Builder
+	depth := 0
+	for _, r := range path {
+		switch {
+		case r == '[':
+			depth++
+		case r == ']':
+			depth--
+		case depth == 0:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// registerValidationMessages 设置验证规则在各语言环境下的错误消息
+func registerValidationMessages(tag string, messages map[string]string) error {
+	for locale, message := range messages {
+		if err := RegisterValidationMessage(tag, locale, message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatFieldMessage 替换验证消息中的 {field}、{param}、{related} 和 {value}
+func formatFieldMessage(message string, fe FieldError) string {
+	return strings.NewReplacer(
+		"{field}", fe.Field,
+		"{param}", fe.Param,
+		"{related}", strings.Join(fe.Related, ", "),
+		"{value}", fmt.Sprint(fe.Value),
+	).Replace(message)
+}
+
+// ContextValidator 请求类型实现该接口时，处理器在 tag 验证通过、路径参数和认证声明绑定之后调用 Validate
+//
+// 返回业务错误时直接输出该错误；返回 FieldError（或 errors.Join 合并的多个 FieldError）时输出
+// 参数绑定失败的 400 错误并将其作为错误详情；返回其他错误时输出以错误消息为 message 的 400 错误。
+//
+//	func (r *CreateOrderRequest) Validate(ctx context.Context) error {
+//		if r.EndAt.Before(r.StartAt) {
+//			return apihandler.FieldError{Field: "end_at", Rule: "gtfield", Message: "结束时间不能早于开始时间"}
+//		}
+//		return nil
+//	}
+type ContextValidator interface {
+	Validate(ctx context.Context) error
+}
+
+// validateRequest 调用请求类型的 Validate 方法，将返回的错误转换为业务错误
+func validateRequest(c *gin.Context, config *HandlerConfig, translator Translator, req any) error {
+	v, ok := req.(ContextValidator)
+	if !ok {
+		return nil
+	}
+	err := v.Validate(c.Request.Context())
+	if err == nil {
+		return nil
+	}
+
+	var bizErr BizError
+	if errors.As(err, &bizErr) {
+		return err
+	}
+	if details := applyValidationMessages(collectFieldErrors(err), config.ValidationMessages); len(details) > 0 {
+		return NewBizErrorWithDetails(config.BindErrorCode, translator.Translate(MsgBindError), http.StatusBadRequest, details)
+	}
+	return NewBizError(config.BindErrorCode, err.Error(), http.StatusBadRequest)
+}
+
+// collectFieldErrors 收集错误链中的字段错误，支持 errors.Join 合并的多个错误
+func collectFieldErrors(err error) []any {
+	var fe FieldError
+	if errors.As(err, &fe) {
+		if joined, ok := err.(interface{ Unwrap() []error }); ok {
+			var details []any
+			for _, e := range joined.Unwrap() {
+				details = append(details, collectFieldErrors(e)...)
+			}
+			return details
+		}
+		return []any{fe}
+	}
+	return nil
+}
+
+// validatorEngine 返回 gin 使用的验证器
+func validatorEngine() (*validator.Validate, error) {
+	if binding.Validator == nil {
+		return nil, errors.New("apihandler: binding validator is disabled")
+	}
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return nil, fmt.Errorf("apihandler: binding validator engine %T is not *validator.Validate", binding.Validator.Engine())
+	}
+	return v, nil
+}
+
+// validationMessage 返回字段验证失败的消息
+//
+// 依次使用验证规则注册的消息、universal-translator 的翻译（trans 不为 nil 时）和通用的字段验证失败消息。
+// 注册的消息中 {field} 为客户端发送的字段名 field；universal-translator 的消息使用 Go 字段名。
+func validationMessage(e validator.FieldError, field string, translator Translator, trans ut.Translator) string {
+	fe := FieldError{Field: field, Rule: e.Tag(), Param: e.Param(), Value: e.Value()}
+	if message, ok := registeredMessage(fe, translator); ok {
+		return message
+	}
+	if trans != nil {
+		// 没有该规则的翻译时 Translate 返回错误本身的文本
+		if message := e.Translate(trans); message != "" && message != e.Error() {
+			return message
+		}
+	}
+	return fieldErrorMessage(fe, translator)
+}
+
+// fieldErrorMessage 返回字段错误的消息，依次使用验证规则注册的消息和通用的字段验证失败消息
+func fieldErrorMessage(fe FieldError, translator Translator) string {
+	if message, ok := registeredMessage(fe, translator); ok {
+		return message
+	}
+	if fe.Param != "" {
+		return translator.Translate(MsgFieldValidationFailedWithParam, fe.Rule, fe.Param)
+	}
+	return translator.Translate(MsgFieldValidationFailed, fe.Rule)
+}
+
+// registeredMessage 返回验证规则注册的消息，翻译器没有该规则的消息时返回 false
+func registeredMessage(fe FieldError, translator Translator) (string, bool) {
+	key := ValidationMessageKey(fe.Rule)
+	if message := translator.Translate(key); message != "" && message != string(key) {
+		return formatFieldMessage(message, fe), true
+	}
+	return "", false
+}
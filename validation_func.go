@@ -0,0 +1,58 @@
+package apihandler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WithValidationFunc 添加依赖外部数据的验证（如邮箱是否已注册、引用的资源是否存在），在参数绑定和授权检查之后、业务处理函数前执行
+//
+// 所有验证函数依次执行，返回的字段错误与 tag 验证一样输出为参数绑定失败的 400 错误详情。Message 为空时使用
+// ValidationMessageKey(Rule) 的消息，内置 unique 和 exists 规则的消息。tag 验证失败时不执行验证函数。
+// 验证函数的请求类型必须与处理器一致，否则创建处理器时 panic。
+//
+//	handler.WithValidationFunc(func(ctx context.Context, req *SignupRequest) []handler.FieldError {
+//		if users.EmailExists(ctx, req.Email) {
+//			return []handler.FieldError{{Field: "email", Rule: "unique", Value: req.Email}}
+//		}
+//		return nil
+//	})
+func WithValidationFunc[T any](validate func(ctx context.Context, req *T) []FieldError) Option {
+	return func(c *HandlerConfig) {
+		c.ValidationFuncs = append(c.ValidationFuncs[:len(c.ValidationFuncs):len(c.ValidationFuncs)], validate)
+	}
+}
+
+// validationFuncs 将配置的验证函数转换为处理器请求类型的验证函数，类型不匹配时 panic
+func validationFuncs[T any](items []any) []func(context.Context, *T) []FieldError {
+	funcs := make([]func(context.Context, *T) []FieldError, len(items))
+	for i, item := range items {
+		fn, ok := item.(func(context.Context, *T) []FieldError)
+		if !ok {
+			panic(fmt.Sprintf("apihandler: validation func %T does not match request type %T", item, new(T)))
+		}
+		funcs[i] = fn
+	}
+	return funcs
+}
+
+// runValidationFuncs 依次执行验证函数，有字段错误时返回参数绑定失败的业务错误
+func runValidationFuncs[T any](c *gin.Context, config *HandlerConfig, translator Translator, funcs []func(context.Context, *T) []FieldError, req *T) error {
+	var details []any
+	for _, validate := range funcs {
+		for _, fe := range validate(c.Request.Context(), req) {
+			if fe.Message == "" {
+				fe.Message = fieldErrorMessage(fe, translator)
+			}
+			details = append(details, fe)
+		}
+	}
+	if len(details) == 0 {
+		return nil
+	}
+	details = applyValidationMessages(details, config.ValidationMessages)
+	return NewBizErrorWithDetails(config.BindErrorCode, translator.Translate(MsgBindError), http.StatusBadRequest, details)
+}
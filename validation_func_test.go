@@ -0,0 +1,78 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 测试依赖外部数据的验证函数
+func TestWithValidationFunc(t *testing.T) {
+	type signupRequest struct {
+		Email     string `json:"email" binding:"required"`
+		InviterID int    `json:"inviter_id"`
+	}
+
+	registered := map[string]bool{"tom@example.com": true}
+	called := 0
+	r := gin.New()
+	r.POST("/signup", Handler(func(ctx context.Context, req *signupRequest) (*testResponse, error) {
+		called++
+		return &testResponse{}, nil
+	}, WithValidationFunc(func(ctx context.Context, req *signupRequest) []FieldError {
+		if registered[req.Email] {
+			return []FieldError{{Field: "email", Rule: "unique", Value: req.Email}}
+		}
+		return nil
+	}), WithValidationFunc(func(ctx context.Context, req *signupRequest) []FieldError {
+		if req.InviterID != 0 && req.InviterID != 1 {
+			return []FieldError{{Field: "inviter_id", Rule: "exists", Message: "邀请人不存在"}}
+		}
+		return nil
+	})))
+
+	send := func(body string) (*httptest.ResponseRecorder, []FieldError) {
+		req := httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var resp ErrorResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w, resp.FieldErrors()
+	}
+
+	w, errs := send(`{"email":"tom@example.com","inviter_id":2}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("期望状态码 %d, 实际得到 %d", http.StatusBadRequest, w.Code)
+	}
+	if len(errs) != 2 || errs[0].Message != "email 已存在" || errs[0].Value != "tom@example.com" || errs[1].Message != "邀请人不存在" {
+		t.Errorf("期望两个验证函数的字段错误, 实际得到 %+v", errs)
+	}
+
+	if _, errs := send(`{"inviter_id":2}`); len(errs) != 1 || errs[0].Rule != "required" {
+		t.Errorf("期望 tag 验证失败时不执行验证函数, 实际得到 %+v", errs)
+	}
+
+	if w, _ := send(`{"email":"jerry@example.com","inviter_id":1}`); w.Code != http.StatusOK || called != 1 {
+		t.Errorf("期望验证通过后调用业务处理函数, 实际状态码 %d, 调用 %d 次", w.Code, called)
+	}
+}
+
+// 测试验证函数的请求类型与处理器不一致时 panic
+func TestWithValidationFuncTypeMismatch(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Errorf("期望请求类型不一致时 panic")
+		}
+	}()
+	Handler(func(ctx context.Context, req *testRequest) (*testResponse, error) {
+		return &testResponse{}, nil
+	}, WithValidationFunc(func(ctx context.Context, req *testResponse) []FieldError {
+		return nil
+	}))
+}
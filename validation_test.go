@@ -0,0 +1,224 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// validationSignupRequest 测试用的注册请求
+type validationSignupRequest struct {
+	Mobile   string `json:"mobile" binding:"required,test_mobile_cn"`
+	Password string `json:"password" binding:"required"`
+	Confirm  string `json:"confirm"`
+}
+
+// postValidation 发送 JSON 请求并返回字段错误
+func postValidation(t *testing.T, r *gin.Engine, body, locale string) []FieldError {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", locale)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("期望状态码 %d, 实际得到 %d", http.StatusBadRequest, w.Code)
+	}
+	var resp ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("解析响应失败: %v", err)
+	}
+	return resp.FieldErrors()
+}
+
+// 测试注册自定义验证规则和结构体级别验证
+func TestRegisterValidation(t *testing.T) {
+	mobilePattern := regexp.MustCompile(`^1[3-9]\d{9}$`)
+	err := RegisterValidation("test_mobile_cn", func(fl validator.FieldLevel) bool {
+		return mobilePattern.MatchString(fl.Field().String())
+	}, map[string]string{
+		"zh": "{field} 必须是有效的手机号",
+		"en": "{field} must be a valid mobile number, got {value}",
+	})
+	if err != nil {
+		t.Fatalf("注册验证规则失败: %v", err)
+	}
+	err = RegisterStructValidation(func(sl validator.StructLevel) {
+		req := sl.Current().Interface().(validationSignupRequest)
+		if req.Password != req.Confirm {
+			sl.ReportError(req.Confirm, "Confirm", "confirm", "test_eqpassword", "")
+		}
+	}, validationSignupRequest{})
+	if err != nil {
+		t.Fatalf("注册结构体验证失败: %v", err)
+	}
+	if err := RegisterValidationMessage("test_eqpassword", "zh", "两次输入的密码不一致"); err != nil {
+		t.Fatalf("注册验证消息失败: %v", err)
+	}
+	t.Cleanup(func() {
+		for _, key := range []MessageKey{ValidationMessageKey("test_mobile_cn"), ValidationMessageKey("test_eqpassword")} {
+			delete(defaultMessages, key)
+			delete(englishMessages, key)
+		}
+	})
+
+	r := gin.New()
+	r.POST("/signup", Handler(func(ctx context.Context, req *validationSignupRequest) (*struct{}, error) {
+		return &struct{}{}, nil
+	}))
+
+	errs := postValidation(t, r, `{"mobile":"123","password":"a","confirm":"a"}`, "zh")
+	if len(errs) != 1 || errs[0].Rule != "test_mobile_cn" || errs[0].Message != "mobile 必须是有效的手机号" {
+		t.Errorf("期望使用注册的中文消息, 实际得到 %+v", errs)
+	}
+	errs = postValidation(t, r, `{"mobile":"123","password":"a","confirm":"a"}`, "en")
+	if len(errs) != 1 || errs[0].Message != "mobile must be a valid mobile number, got 123" {
+		t.Errorf("期望使用注册的英文消息, 实际得到 %+v", errs)
+	}
+
+	errs = postValidation(t, r, `{"mobile":"13800138000","password":"a","confirm":"b"}`, "en")
+	if len(errs) != 1 || errs[0].Rule != "test_eqpassword" || errs[0].Message != "两次输入的密码不一致" {
+		t.Errorf("期望结构体验证报告的规则使用注册的消息, 未注册的语言环境使用默认消息, 实际得到 %+v", errs)
+	}
+
+	errs = postValidation(t, r, `{"password":"a","confirm":"a"}`, "zh")
+	if len(errs) != 1 || errs[0].Message != "字段验证失败: required" {
+		t.Errorf("期望未注册消息的规则使用通用消息, 实际得到 %+v", errs)
+	}
+
+	if err := RegisterValidationMessage("test_eqpassword", "fr", "..."); err == nil {
+		t.Error("期望不支持的语言环境返回错误")
+	}
+}
+
+// validationRangeRequest 测试用的实现 Validate 方法的请求
+type validationRangeRequest struct {
+	ID    int64  `path:"id"`
+	Start int    `json:"start" binding:"required"`
+	End   int    `json:"end" binding:"required"`
+	Mode  string `json:"mode"`
+}
+
+// Validate 实现 ContextValidator 接口
+func (r *validationRangeRequest) Validate(ctx context.Context) error {
+	switch r.Mode {
+	case "biz":
+		return ErrConflict(40901, "区间已被占用")
+	case "plain":
+		return errors.New("区间不可用")
+	case "joined":
+		return errors.Join(
+			FieldError{Field: "start", Rule: "ltfield", Message: "开始不能大于结束"},
+			FieldError{Field: "end", Message: "结束超出范围"},
+		)
+	}
+	if r.ID == 0 {
+		return errors.New("期望 Validate 在路径参数绑定之后调用")
+	}
+	if r.Start > r.End {
+		return FieldError{Field: "start", Rule: "ltefield", Param: "end", Message: "开始不能大于结束"}
+	}
+	return nil
+}
+
+// 测试请求类型的 Validate 方法
+func TestContextValidator(t *testing.T) {
+	called := 0
+	r := gin.New()
+	r.POST("/ranges/:id", Handler(func(ctx context.Context, req *validationRangeRequest) (*struct{}, error) {
+		called++
+		return &struct{}{}, nil
+	}))
+
+	send := func(body string) (int, ErrorResponse) {
+		req := httptest.NewRequest(http.MethodPost, "/ranges/1", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var resp ErrorResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp
+	}
+
+	if code, _ := send(`{"start":1,"end":2}`); code != http.StatusOK || called != 1 {
+		t.Fatalf("期望验证通过后调用业务函数, 实际得到状态码 %d, 调用 %d 次", code, called)
+	}
+
+	code, resp := send(`{"start":3,"end":2}`)
+	errs := resp.FieldErrors()
+	if code != http.StatusBadRequest || resp.Message != "参数绑定失败" || len(errs) != 1 || errs[0].Field != "start" || errs[0].Param != "end" {
+		t.Errorf("期望字段错误作为错误详情返回, 实际得到状态码 %d, 响应 %+v", code, resp)
+	}
+
+	code, resp = send(`{"start":1,"end":2,"mode":"joined"}`)
+	if code != http.StatusBadRequest || len(resp.FieldErrors()) != 2 {
+		t.Errorf("期望合并的字段错误全部返回, 实际得到状态码 %d, 响应 %+v", code, resp)
+	}
+
+	code, resp = send(`{"start":1,"end":2,"mode":"biz"}`)
+	if code != http.StatusConflict || resp.Message != "区间已被占用" {
+		t.Errorf("期望直接返回业务错误, 实际得到状态码 %d, 响应 %+v", code, resp)
+	}
+
+	code, resp = send(`{"start":1,"end":2,"mode":"plain"}`)
+	if code != http.StatusBadRequest || resp.Message != "区间不可用" || len(resp.Errors) != 0 {
+		t.Errorf("期望普通错误返回 400 和错误消息, 实际得到状态码 %d, 响应 %+v", code, resp)
+	}
+
+	if code, _ := send(`{"mode":"biz"}`); code != http.StatusBadRequest {
+		t.Errorf("期望 tag 验证失败时不调用 Validate, 实际得到状态码 %d", code)
+	}
+	if called != 1 {
+		t.Errorf("期望验证失败时不调用业务函数, 实际调用 %d 次", called)
+	}
+}
+
+// 测试处理器的验证消息模板
+func TestWithValidationMessages(t *testing.T) {
+	type signupRequest struct {
+		Email    string `json:"email" binding:"required"`
+		Password string `json:"password" binding:"min=8"`
+		Tags     []struct {
+			Name string `json:"name" binding:"required"`
+		} `json:"tags" binding:"dive"`
+	}
+
+	r := gin.New()
+	r.POST("/signup", Handler(func(ctx context.Context, req *signupRequest) (*struct{}, error) {
+		return &struct{}{}, nil
+	}, WithValidationMessages(map[string]string{
+		"password": "password must contain at least {param} characters",
+	}), WithValidationMessages(map[string]string{
+		"required":  "{field} is required",
+		"tags.name": "tag name is required",
+	})))
+	r.POST("/plain", Handler(func(ctx context.Context, req *signupRequest) (*struct{}, error) {
+		return &struct{}{}, nil
+	}))
+
+	var got []string
+	for _, fe := range postValidation(t, r, `{"password":"a","tags":[{}]}`, "en") {
+		got = append(got, fe.Message)
+	}
+	expected := "email is required;password must contain at least 8 characters;tag name is required"
+	if strings.Join(got, ";") != expected {
+		t.Errorf("期望 %s, 实际得到 %v", expected, got)
+	}
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest("POST", "/plain", strings.NewReader(`{"email":"a@b.c","password":"a"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept-Language", "en")
+	r.ServeHTTP(w, req)
+	if strings.Contains(w.Body.String(), "characters") {
+		t.Errorf("期望其他处理器不受影响, 实际得到 %s", w.Body.String())
+	}
+}
@@ -0,0 +1,133 @@
+package apihandler
+
+import (
+	"strings"
+
+	"github.com/go-playground/locales"
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/zh"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	en_translations "github.com/go-playground/validator/v10/translations/en"
+	zh_translations "github.com/go-playground/validator/v10/translations/zh"
+)
+
+// ValidationTranslationsFunc 在验证器上注册某个语言环境的验证消息，如 validator 的 translations/fr 包中的 RegisterDefaultTranslations
+type ValidationTranslationsFunc func(v *validator.Validate, trans ut.Translator) error
+
+// WithValidationTranslations 使用 validator 的 universal-translator 翻译验证消息
+//
+// 每个验证规则输出完整的句子，如 "Age must be 18 or greater"、"Age最小只能为18"，语言环境由 LocaleFunc 确定，
+// 内置中文和英文，其他语言通过 RegisterValidationLocale 添加。RegisterValidationMessage 登记的消息优先使用，
+// 没有翻译的规则使用通用的字段验证失败消息。
+func WithValidationTranslations() Option {
+	return func(c *HandlerConfig) {
+		c.ValidationTranslations = true
+	}
+}
+
+// RegisterValidationLocale 添加验证消息的语言环境，应在注册路由之前调用
+//
+//	import (
+//		"github.com/go-playground/locales/fr"
+//		fr_translations "github.com/go-playground/validator/v10/translations/fr"
+//	)
+//
+//	apihandler.RegisterValidationLocale(fr.New(), fr_translations.RegisterDefaultTranslations)
+func RegisterValidationLocale(translator locales.Translator, register ValidationTranslationsFunc) error {
+	validators.mu.Lock()
+	defer validators.mu.Unlock()
+
+	initValidationTranslations()
+	if err := validators.uni.AddTranslator(translator, true); err != nil {
+		return err
+	}
+	trans, _ := validators.uni.GetTranslator(translator.Locale())
+	l := validationLocale{trans: sharedTranslator{trans}, register: register}
+	validators.locales = append(validators.locales, l)
+	return l.registerOnAll()
+}
+
+// validationLocale 验证消息的语言环境
+type validationLocale struct {
+	trans    ut.Translator
+	register ValidationTranslationsFunc
+}
+
+// registerOn 在验证器上注册该语言环境的验证消息
+func (l validationLocale) registerOn(v *validator.Validate) error {
+	return l.register(v, l.trans)
+}
+
+// registerOnAll 在 gin 的验证器和已创建的场景验证器上注册验证消息
+func (l validationLocale) registerOnAll() error {
+	if v, err := validatorEngine(); err == nil {
+		if err := l.registerOn(v); err != nil {
+			return err
+		}
+	}
+	for _, v := range validators.scenarios {
+		if err := l.registerOn(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// initValidationTranslations 创建内置中文和英文的验证消息翻译，调用时需持有 validators.mu
+func initValidationTranslations() {
+	if validators.uni != nil {
+		return
+	}
+	zhLocale := zh.New()
+	validators.uni = ut.New(zhLocale, zhLocale, en.New())
+	for locale, register := range map[string]ValidationTranslationsFunc{
+		"zh": zh_translations.RegisterDefaultTranslations,
+		"en": en_translations.RegisterDefaultTranslations,
+	} {
+		trans, _ := validators.uni.GetTranslator(locale)
+		l := validationLocale{trans: sharedTranslator{trans}, register: register}
+		validators.locales = append(validators.locales, l)
+		l.registerOnAll()
+	}
+}
+
+// validationTranslator 返回语言环境的验证消息翻译，依次匹配完整语言标签（如 zh_TW）、语言代码和中文
+func validationTranslator(locale string) ut.Translator {
+	validators.mu.Lock()
+	defer validators.mu.Unlock()
+
+	initValidationTranslations()
+	locale = strings.ReplaceAll(locale, "-", "_")
+	language, _, _ := strings.Cut(locale, "_")
+	trans, _ := validators.uni.FindTranslator(locale, strings.ToLower(language))
+	return sharedTranslator{trans}
+}
+
+// sharedTranslator 在多个验证器上注册同一语言环境的验证消息时使用的翻译
+//
+// 注册函数添加消息时不允许覆盖，第二个验证器注册时会因消息已存在而失败，这里总是覆盖相同的消息。
+// 验证器按翻译的值保存翻译函数，注册和翻译时必须使用相同的 sharedTranslator。
+type sharedTranslator struct {
+	ut.Translator
+}
+
+// Add 实现 ut.Translator 接口
+func (t sharedTranslator) Add(key any, text string, override bool) error {
+	return t.Translator.Add(key, text, true)
+}
+
+// AddCardinal 实现 ut.Translator 接口
+func (t sharedTranslator) AddCardinal(key any, text string, rule locales.PluralRule, override bool) error {
+	return t.Translator.AddCardinal(key, text, rule, true)
+}
+
+// AddOrdinal 实现 ut.Translator 接口
+func (t sharedTranslator) AddOrdinal(key any, text string, rule locales.PluralRule, override bool) error {
+	return t.Translator.AddOrdinal(key, text, rule, true)
+}
+
+// AddRange 实现 ut.Translator 接口
+func (t sharedTranslator) AddRange(key any, text string, rule locales.PluralRule, override bool) error {
+	return t.Translator.AddRange(key, text, rule, true)
+}
@@ -0,0 +1,75 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/locales/fr"
+	fr_translations "github.com/go-playground/validator/v10/translations/fr"
+)
+
+// translationsRequest 测试用的请求
+type translationsRequest struct {
+	Name string `json:"name" binding:"required"`
+	Age  int    `json:"age" binding:"min=18"`
+	Nick string `json:"nick" binding_create:"required"`
+}
+
+// 测试使用 universal-translator 翻译验证消息
+func TestWithValidationTranslations(t *testing.T) {
+	r := gin.New()
+	handle := func(ctx context.Context, req *translationsRequest) (*struct{}, error) { return &struct{}{}, nil }
+	r.POST("/users", Handler(handle, WithValidationTranslations(), WithValidationScenario("create")))
+	r.POST("/plain", Handler(handle))
+
+	send := func(path, body, locale string) []FieldError {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept-Language", locale)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		var resp ErrorResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return resp.FieldErrors()
+	}
+	messages := func(errs []FieldError) []string {
+		var m []string
+		for _, e := range errs {
+			m = append(m, e.Message)
+		}
+		return m
+	}
+
+	if got := messages(send("/users", `{"age":10}`, "en-US,en;q=0.9")); strings.Join(got, ";") != "Name is a required field;Age must be 18 or greater" {
+		t.Errorf("期望英文的验证消息, 实际得到 %v", got)
+	}
+	if got := messages(send("/users", `{"age":10}`, "zh-CN")); strings.Join(got, ";") != "Name为必填字段;Age最小只能为18" {
+		t.Errorf("期望中文的验证消息, 实际得到 %v", got)
+	}
+	if got := messages(send("/users", `{"name":"tom","age":18}`, "en")); strings.Join(got, ";") != "Nick is a required field" {
+		t.Errorf("期望验证场景的消息同样被翻译, 实际得到 %v", got)
+	}
+	if got := messages(send("/plain", `{"age":10}`, "en")); strings.Join(got, ";") != "Field validation failed: required;Field validation failed: min=18" {
+		t.Errorf("期望未开启时使用通用消息, 实际得到 %v", got)
+	}
+
+	if err := RegisterValidationLocale(fr.New(), fr_translations.RegisterDefaultTranslations); err != nil {
+		t.Fatalf("添加语言环境失败: %v", err)
+	}
+	if got := messages(send("/users", `{"age":18}`, "fr")); strings.Join(got, ";") != "Name est un champ obligatoire" {
+		t.Errorf("期望添加的语言环境的验证消息, 实际得到 %v", got)
+	}
+
+	if err := RegisterValidationMessage("min", "en", "{field} is too small"); err != nil {
+		t.Fatalf("注册验证消息失败: %v", err)
+	}
+	t.Cleanup(func() { delete(englishMessages, ValidationMessageKey("min")) })
+	if got := messages(send("/users", `{"name":"tom","age":10,"nick":"t"}`, "en")); strings.Join(got, ";") != "age is too small" {
+		t.Errorf("期望注册的消息优先使用, 实际得到 %v", got)
+	}
+}
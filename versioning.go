@@ -0,0 +1,214 @@
+package apihandler
+
+import (
+	"mime"
+	"net/http"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultVersionHeader 默认的 API 版本请求头
+const DefaultVersionHeader = "X-API-Version"
+
+// VersionStrategy API 版本的来源
+type VersionStrategy int
+
+const (
+	VersionByPath      VersionStrategy = iota // 路径前缀，每个版本注册为 /v1/...、/v2/... 的独立路由
+	VersionByHeader                           // 请求头，如 X-API-Version: 2
+	VersionByMediaType                        // Accept 媒体类型，如 application/json; version=2 或 application/vnd.example.v2+json
+)
+
+// VersionSettings 版本化分组的设置
+type VersionSettings struct {
+	Strategy VersionStrategy // 版本的来源，默认按路径前缀
+	Header   string          // VersionByHeader 读取的请求头，也是响应中返回实际版本的响应头，默认 X-API-Version
+	Default  int             // 请求未指定版本时使用的版本，0 表示使用路由的最新版本
+}
+
+// VersionedGroup 按 API 版本注册和分发处理器的路由分组
+//
+//	v := apihandler.Versioned(api, apihandler.VersionSettings{Strategy: apihandler.VersionByHeader})
+//	v.GET("/user/:id", apihandler.V(1, getUserV1), apihandler.V(2, getUserV2))
+type VersionedGroup struct {
+	group    *Group
+	settings VersionSettings
+}
+
+// VersionedHandler 某个版本的处理器，由 V 创建
+type VersionedHandler struct {
+	version int
+	build   func(g *Group, method, fullPath string) gin.HandlerFunc
+}
+
+// Versioned 创建版本化分组，处理器继承分组的选项
+func Versioned(g *Group, settings VersionSettings) *VersionedGroup {
+	if settings.Header == "" {
+		settings.Header = DefaultVersionHeader
+	}
+	return &VersionedGroup{group: g, settings: settings}
+}
+
+// V 创建某个版本的处理器，不同版本的请求和响应类型可以不同，version 从 1 开始
+func V[T any, R any](version int, handleFunc HandleFunc[T, R], opts ...Option) VersionedHandler {
+	if version <= 0 {
+		panic("apihandler: API version must be positive")
+	}
+	return VersionedHandler{
+		version: version,
+		build: func(g *Group, method, fullPath string) gin.HandlerFunc {
+			config := NewConfig(g.options(opts)...)
+			config.APIVersion = version
+			registerRoute[T, R](config, method, fullPath)
+			return HandlerWithConfig(handleFunc, config)
+		},
+	}
+}
+
+// Handle 注册路由的各版本处理器
+//
+// 按路径前缀时每个版本注册为独立的路由；按请求头或媒体类型时注册一个路由并在请求时分发，
+// 请求的版本没有对应的处理器时使用不高于该版本的最新处理器，只需为有变化的路由添加新版本。
+// 实际使用的版本通过 Header 响应头返回，找不到可用版本时返回 400。
+func (v *VersionedGroup) Handle(method, relativePath string, handlers ...VersionedHandler) gin.IRoutes {
+	if len(handlers) == 0 {
+		panic("apihandler: at least one versioned handler is required")
+	}
+	if v.settings.Strategy == VersionByPath {
+		var routes gin.IRoutes
+		for _, h := range handlers {
+			prefixed := joinPaths("/v"+strconv.Itoa(h.version), relativePath)
+			routes = v.group.RouterGroup.Handle(method, prefixed, h.build(v.group, method, joinPaths(v.group.BasePath(), prefixed)))
+		}
+		return routes
+	}
+
+	fullPath := joinPaths(v.group.BasePath(), relativePath)
+	dispatch := make(map[int]gin.HandlerFunc, len(handlers))
+	versions := make([]int, 0, len(handlers))
+	for _, h := range handlers {
+		if _, ok := dispatch[h.version]; !ok {
+			versions = append(versions, h.version)
+		}
+		dispatch[h.version] = h.build(v.group, method, fullPath)
+	}
+	sort.Ints(versions)
+
+	config := NewConfig(v.group.Options()...)
+	vary := v.settings.Header
+	if v.settings.Strategy == VersionByMediaType {
+		vary = "Accept"
+	}
+	return v.group.RouterGroup.Handle(method, relativePath, func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", vary)
+		requested, raw, ok := v.requestVersion(c.Request)
+		if ok && requested == 0 {
+			requested = v.settings.Default
+		}
+		version := resolveVersion(versions, requested)
+		if !ok || version == 0 {
+			setRequestID(c, config)
+			translator := requestTranslator(c, config)
+			handleError(c, config, nil, NewBizError(config.BindErrorCode, translator.Translate(MsgUnsupportedVersion, raw), http.StatusBadRequest))
+			return
+		}
+		c.Header(v.settings.Header, strconv.Itoa(version))
+		dispatch[version](c)
+	})
+}
+
+// GET 注册 GET 路由的各版本处理器
+func (v *VersionedGroup) GET(relativePath string, handlers ...VersionedHandler) gin.IRoutes {
+	return v.Handle(http.MethodGet, relativePath, handlers...)
+}
+
+// POST 注册 POST 路由的各版本处理器
+func (v *VersionedGroup) POST(relativePath string, handlers ...VersionedHandler) gin.IRoutes {
+	return v.Handle(http.MethodPost, relativePath, handlers...)
+}
+
+// PUT 注册 PUT 路由的各版本处理器
+func (v *VersionedGroup) PUT(relativePath string, handlers ...VersionedHandler) gin.IRoutes {
+	return v.Handle(http.MethodPut, relativePath, handlers...)
+}
+
+// PATCH 注册 PATCH 路由的各版本处理器
+func (v *VersionedGroup) PATCH(relativePath string, handlers ...VersionedHandler) gin.IRoutes {
+	return v.Handle(http.MethodPatch, relativePath, handlers...)
+}
+
+// DELETE 注册 DELETE 路由的各版本处理器
+func (v *VersionedGroup) DELETE(relativePath string, handlers ...VersionedHandler) gin.IRoutes {
+	return v.Handle(http.MethodDelete, relativePath, handlers...)
+}
+
+// resolveVersion 返回不高于请求版本的最新版本，requested 为 0 时返回最新版本，没有可用版本时返回 0
+func resolveVersion(versions []int, requested int) int {
+	if requested == 0 {
+		return versions[len(versions)-1]
+	}
+	resolved := 0
+	for _, version := range versions {
+		if version <= requested {
+			resolved = version
+		}
+	}
+	return resolved
+}
+
+// mediaTypeVersion 匹配媒体类型中的版本，如 application/vnd.example.v2+json
+var mediaTypeVersion = regexp.MustCompile(`\.v(\d+)(?:\+|$)`)
+
+// requestVersion 读取请求的版本，未指定时返回 0，格式错误时返回 false 和原始值
+func (v *VersionedGroup) requestVersion(r *http.Request) (version int, raw string, ok bool) {
+	if v.settings.Strategy == VersionByHeader {
+		raw = strings.TrimSpace(r.Header.Get(v.settings.Header))
+		if raw == "" {
+			return 0, "", true
+		}
+		version, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(raw), "v"))
+		return version, raw, err == nil && version > 0
+	}
+
+	for _, accept := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+		if err != nil {
+			continue
+		}
+		raw = params["version"]
+		if m := mediaTypeVersion.FindStringSubmatch(mediaType); raw == "" && m != nil {
+			raw = m[1]
+		}
+		if raw != "" {
+			version, err := strconv.Atoi(strings.TrimPrefix(strings.ToLower(raw), "v"))
+			return version, raw, err == nil && version > 0
+		}
+	}
+	return 0, "", true
+}
+
+// ForVersion 返回指定版本可用的路由：未版本化的路由，以及每个方法和路径不高于该版本的最新版本
+//
+// 与按请求头或媒体类型分发时选择处理器的规则一致，可用于为每个版本生成 OpenAPI 文档。
+func (r *Registry) ForVersion(version int) []RouteInfo {
+	routes := r.Routes()
+	best := make(map[string]int)
+	for _, route := range routes {
+		key := route.Method + " " + route.Path
+		if route.APIVersion <= version && route.APIVersion > best[key] {
+			best[key] = route.APIVersion
+		}
+	}
+
+	var matched []RouteInfo
+	for _, route := range routes {
+		if route.APIVersion == best[route.Method+" "+route.Path] {
+			matched = append(matched, route)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,139 @@
+package apihandler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// versionedUserV1 第 1 版的用户
+type versionedUserV1 struct {
+	Name string `json:"name"`
+}
+
+// versionedUserV2 第 2 版的用户
+type versionedUserV2 struct {
+	FirstName string `json:"first_name"`
+}
+
+// newVersionedRouter 创建注册了两个版本用户接口的路由
+func newVersionedRouter(settings VersionSettings) (*gin.Engine, *Registry) {
+	r := gin.New()
+	registry := NewRegistry()
+	api := NewGroup(r.Group("/api"), WithRegistry(registry))
+	v := Versioned(api, settings)
+
+	getUserV1 := func(ctx context.Context, req *testRequest) (*versionedUserV1, error) {
+		return &versionedUserV1{Name: "v1"}, nil
+	}
+	getUserV2 := func(ctx context.Context, req *testRequest) (*versionedUserV2, error) {
+		return &versionedUserV2{FirstName: "v2"}, nil
+	}
+	listV1 := func(ctx context.Context, req *struct{}) (*[]versionedUserV1, error) {
+		return &[]versionedUserV1{}, nil
+	}
+	v.GET("/user/:id", V(1, getUserV1), V(2, getUserV2))
+	v.GET("/users", V(1, listV1))
+	return r, registry
+}
+
+// 测试按请求头分发版本
+func TestVersionedByHeader(t *testing.T) {
+	r, registry := newVersionedRouter(VersionSettings{Strategy: VersionByHeader})
+
+	tests := []struct {
+		path    string
+		header  string
+		status  int
+		version string
+		body    string
+	}{
+		{"/api/user/1", "1", http.StatusOK, "1", `"name":"v1"`},
+		{"/api/user/1", "v2", http.StatusOK, "2", `"first_name":"v2"`},
+		{"/api/user/1", "", http.StatusOK, "2", `"first_name":"v2"`},
+		{"/api/user/1", "5", http.StatusOK, "2", `"first_name":"v2"`},
+		{"/api/users", "2", http.StatusOK, "1", `"data":[]`},
+		{"/api/user/1", "abc", http.StatusBadRequest, "", "abc"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.header != "" {
+			req.Header.Set(DefaultVersionHeader, tt.header)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if w.Code != tt.status {
+			t.Errorf("%s %q: 期望状态码 %d, 实际得到 %d", tt.path, tt.header, tt.status, w.Code)
+		}
+		if got := w.Header().Get(DefaultVersionHeader); got != tt.version {
+			t.Errorf("%s %q: 期望版本响应头 %q, 实际得到 %q", tt.path, tt.header, tt.version, got)
+		}
+		if !json.Valid(w.Body.Bytes()) || !strings.Contains(w.Body.String(), tt.body) {
+			t.Errorf("%s %q: 期望响应体包含 %s, 实际得到 %s", tt.path, tt.header, tt.body, w.Body.String())
+		}
+		if w.Header().Get("Vary") != DefaultVersionHeader {
+			t.Errorf("期望 Vary 响应头为 %s, 实际得到 %q", DefaultVersionHeader, w.Header().Get("Vary"))
+		}
+	}
+
+	if routes := registry.Routes(); len(routes) != 3 {
+		t.Errorf("期望注册表记录每个版本的路由, 实际得到 %d 个", len(routes))
+	}
+	if routes := registry.ForVersion(1); len(routes) != 2 || routes[0].APIVersion != 1 {
+		t.Errorf("期望第 1 版有 2 个路由, 实际得到 %+v", routes)
+	}
+	routes := registry.ForVersion(2)
+	if len(routes) != 2 || routes[0].Path != "/api/user/:id" || routes[0].APIVersion != 2 || routes[1].APIVersion != 1 {
+		t.Errorf("期望第 2 版使用 /api/users 的第 1 版, 实际得到 %+v", routes)
+	}
+	if op := GenerateOpenAPI(OpenAPIInfo{}, routes).Paths["/api/user/{id}"].Get; op.APIVersion != 2 {
+		t.Errorf("期望 OpenAPI 操作记录版本, 实际得到 %d", op.APIVersion)
+	}
+}
+
+// 测试按媒体类型分发版本和默认版本
+func TestVersionedByMediaType(t *testing.T) {
+	r, _ := newVersionedRouter(VersionSettings{Strategy: VersionByMediaType, Default: 1})
+
+	for accept, want := range map[string]string{
+		"application/json; version=2":          "2",
+		"application/vnd.example.v2+json":      "2",
+		"text/html, application/vnd.x.v1+json": "1",
+		"application/json":                     "1",
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/api/user/1", nil)
+		req.Header.Set("Accept", accept)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		if got := w.Header().Get(DefaultVersionHeader); w.Code != http.StatusOK || got != want {
+			t.Errorf("Accept %q: 期望使用第 %s 版, 实际得到状态码 %d, 版本 %q", accept, want, w.Code, got)
+		}
+	}
+}
+
+// 测试按路径前缀注册版本
+func TestVersionedByPath(t *testing.T) {
+	r, registry := newVersionedRouter(VersionSettings{})
+
+	for path, want := range map[string]int{
+		"/api/v1/user/1": http.StatusOK,
+		"/api/v2/user/1": http.StatusOK,
+		"/api/v1/users":  http.StatusOK,
+		"/api/v2/users":  http.StatusNotFound,
+		"/api/user/1":    http.StatusNotFound,
+	} {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		if w.Code != want {
+			t.Errorf("%s: 期望状态码 %d, 实际得到 %d", path, want, w.Code)
+		}
+	}
+	if route, ok := registry.Lookup(http.MethodGet, "/api/v2/user/:id"); !ok || route.APIVersion != 2 {
+		t.Errorf("期望注册表记录带前缀的路由和版本, 实际得到 %+v", route)
+	}
+}